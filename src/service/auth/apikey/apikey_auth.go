@@ -0,0 +1,90 @@
+package apikey
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+)
+
+//Scheme ... is the Authorization scheme API keys are sent with, "Authorization: ApiKey <key>".
+const Scheme = "ApiKey"
+
+//Header ... is the alternative header API keys can be sent in.
+const Header = "X-API-Key"
+
+//Auth ... implements auth.Interface on top of API keys.
+type Auth struct {
+	Keys Interface
+}
+
+//NewAuth ... creates an API key auth object
+func NewAuth(keys Interface) *Auth {
+	return &Auth{
+		Keys: keys,
+	}
+}
+
+//KeyFromRequest ... pulls an API key from the Authorization or X-API-Key header.
+func KeyFromRequest(req *http.Request) (string, bool) {
+	fields := strings.Fields(req.Header.Get("Authorization"))
+	if len(fields) == 2 && strings.EqualFold(fields[0], Scheme) {
+		return fields[1], true
+	}
+	if key := req.Header.Get(Header); key != "" {
+		return key, true
+	}
+	return "", false
+}
+
+//Authorize ... returns the visible prefix and the secret of the request's API key.
+func (a *Auth) Authorize(req *http.Request) (string, string, bool) {
+	apiKey, ok := KeyFromRequest(req)
+	if !ok {
+		return "", "", false
+	}
+	return parseKey(apiKey)
+}
+
+//Authenticate ... verifies the request's API key and returns it.
+func (a *Auth) Authenticate(req *http.Request) (*Key, error) {
+	apiKey, ok := KeyFromRequest(req)
+	if !ok {
+		return nil, ErrInvalid
+	}
+	return a.Keys.Verify(req.Context(), apiKey)
+}
+
+//ValidateTokenHeader ... verifies the request's API key, unknown keys return ErrInvalid.
+func (a *Auth) ValidateTokenHeader(req *http.Request) (bool, error) {
+	_, err := a.Authenticate(req)
+	return err == nil, err
+}
+
+//GenerateToken ... creates an API key from "sub" (the identity ID), "name",
+//"scope" ([]string) and an optional "expiresAt" (time.Time) and returns it in full.
+func (a *Auth) GenerateToken(ctx context.Context, input map[string]interface{}) (string,
+	error) {
+	identityID, _ := input["sub"].(string)
+	if identityID == "" {
+		return "", errors.New("an api key requires a sub")
+	}
+	name, _ := input["name"].(string)
+	scopes, _ := input["scope"].([]string)
+	var expiresAt *time.Time
+	if at, ok := input["expiresAt"].(time.Time); ok {
+		expiresAt = &at
+	}
+	apiKey, _, err := a.Keys.Create(ctx, identityID, name, scopes, expiresAt)
+	return apiKey, err
+}
+
+//RevokeTokenHeader ... revokes the API key the request was made with.
+func (a *Auth) RevokeTokenHeader(req *http.Request) error {
+	key, err := a.Authenticate(req)
+	if err != nil {
+		return err
+	}
+	return a.Keys.Revoke(req.Context(), key.IdentityID, key.ID)
+}
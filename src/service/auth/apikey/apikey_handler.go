@@ -0,0 +1,172 @@
+package apikey
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"service/auth/permission"
+	"service/handlers/loggederror"
+	"service/handlers/request"
+	"service/log"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi"
+	"go.uber.org/zap"
+)
+
+//HandlerObject ... holds elementals for managing an identity's API keys over http.
+type HandlerObject struct {
+	Log   log.ProdInterface
+	Keys  Interface
+	Roles permission.Store
+}
+
+//NewHandlerObject ... returns a pointer to a new API key HandlerObject.
+func NewHandlerObject(logClient log.ProdInterface, keys Interface,
+	roles permission.Store) *HandlerObject {
+	return &HandlerObject{
+		Log:   logClient,
+		Keys:  keys,
+		Roles: roles,
+	}
+}
+
+type createKeyPostBody struct {
+	Name      string     `json:"name"`
+	Scopes    []string   `json:"scopes"`
+	ExpiresAt *time.Time `json:"expiresAt"`
+}
+
+type createKeyResponse struct {
+	Status int    `json:"status"`
+	APIKey string `json:"apiKey"`
+	Key    *Key   `json:"key"`
+}
+
+type listKeysResponse struct {
+	Status int   `json:"status"`
+	Keys   []Key `json:"keys"`
+}
+
+//CreateKey ...
+//POST /identity/{id}/keys, only the identity itself or a caller holding
+//permission.IdentityAdmin may create one, and the requested scopes must be permissions both
+//the identity and the caller hold. The full key is only ever returned in this response.
+func (h *HandlerObject) CreateKey(w http.ResponseWriter, req *http.Request) {
+	id := chi.URLParam(req, "id")
+	claims, hasClaims := request.RetreiveClaims(req.Context())
+	if !hasClaims || (request.RetreiveSubject(req.Context()) != id &&
+		!claims.HasScope(permission.IdentityAdmin)) {
+		h.softError(http.StatusForbidden, "cannot create api keys for another identity",
+			"CreateKey", w, req)
+		return
+	}
+	var jsonDoc createKeyPostBody
+	if req.Body == nil || json.NewDecoder(req.Body).Decode(&jsonDoc) != nil {
+		h.softError(http.StatusBadRequest, "bad request", "CreateKey", w, req)
+		return
+	}
+	if jsonDoc.Name == "" || len(jsonDoc.Scopes) == 0 {
+		h.softError(http.StatusBadRequest, "missing required api key params", "CreateKey", w, req)
+		return
+	}
+	if jsonDoc.ExpiresAt != nil && !jsonDoc.ExpiresAt.After(time.Now()) {
+		h.softError(http.StatusBadRequest, "expiresAt must be in the future", "CreateKey", w, req)
+		return
+	}
+	granted, err := h.Roles.Permissions(req.Context(), id)
+	if err != nil {
+		h.internalServerError(err, "CreateKey", w, req)
+		return
+	}
+	if kept := intersect(jsonDoc.Scopes, granted); len(kept) != len(jsonDoc.Scopes) {
+		h.softError(http.StatusBadRequest, "scopes exceed the identity's permissions",
+			"CreateKey", w, req)
+		return
+	}
+	held := strings.Fields(claims.Scope)
+	if kept := intersect(jsonDoc.Scopes, held); len(kept) != len(jsonDoc.Scopes) {
+		h.softError(http.StatusForbidden, "scopes exceed the caller's permissions",
+			"CreateKey", w, req)
+		return
+	}
+	apiKey, key, err := h.Keys.Create(req.Context(), id, jsonDoc.Name, jsonDoc.Scopes,
+		jsonDoc.ExpiresAt)
+	if err != nil {
+		h.internalServerError(err, "CreateKey", w, req)
+		return
+	}
+	h.respond(http.StatusCreated, &createKeyResponse{
+		Status: http.StatusCreated,
+		APIKey: apiKey,
+		Key:    key,
+	}, "CreateKey", w, req)
+}
+
+//ListKeys ... GET /identity/{id}/keys
+func (h *HandlerObject) ListKeys(w http.ResponseWriter, req *http.Request) {
+	keys, err := h.Keys.List(req.Context(), chi.URLParam(req, "id"))
+	if err != nil {
+		h.internalServerError(err, "ListKeys", w, req)
+		return
+	}
+	h.respond(http.StatusOK, &listKeysResponse{
+		Status: http.StatusOK,
+		Keys:   keys,
+	}, "ListKeys", w, req)
+}
+
+//RevokeKey ... DELETE /identity/{id}/keys/{keyID}
+func (h *HandlerObject) RevokeKey(w http.ResponseWriter, req *http.Request) {
+	err := h.Keys.Revoke(req.Context(), chi.URLParam(req, "id"), chi.URLParam(req, "keyID"))
+	if err == sql.ErrNoRows {
+		h.softError(http.StatusNotFound, "api key not found", "RevokeKey", w, req)
+		return
+	}
+	if err != nil {
+		h.internalServerError(err, "RevokeKey", w, req)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *HandlerObject) respond(status int, response interface{}, source string,
+	w http.ResponseWriter, req *http.Request) {
+	bytesArray, marshalErr := json.Marshal(response)
+	if marshalErr != nil {
+		h.internalServerError(marshalErr, source, w, req)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if _, writeErr := w.Write(bytesArray); writeErr != nil {
+		h.Log.Error("apikey_handler::"+source, zap.Error(writeErr))
+	}
+}
+
+// internalServerError is used to wrap our loggederror for this route.
+func (h *HandlerObject) internalServerError(err error, source string, w http.ResponseWriter,
+	req *http.Request) {
+	loggederror.RespondWithProperErrorAndLogIt(
+		h.Log,
+		http.StatusInternalServerError,
+		err,
+		"apikey_handler::"+source,
+		w,
+		req,
+	)
+}
+
+// softError is used to wrap our loggederror for expected failures on this route.
+func (h *HandlerObject) softError(status int, message, source string, w http.ResponseWriter,
+	req *http.Request) {
+	loggederror.RespondWithWithExpectedSoftError(
+		h.Log,
+		status,
+		message,
+		"apikey_handler::"+source,
+		w,
+		req,
+	)
+}
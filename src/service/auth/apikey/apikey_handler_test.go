@@ -0,0 +1,121 @@
+package apikey_test
+
+import (
+	"bytes"
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"service/auth/apikey"
+	"service/auth/apikey/apikeyfakes"
+	"service/auth/permission/permissionfakes"
+	"service/auth/token/jwt"
+	"service/handlers/request"
+	"service/log"
+	"service/log/logfakes"
+	"time"
+
+	"github.com/go-chi/chi"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("API Key Handler Specs", func() {
+	var (
+		fakeKeys  *apikeyfakes.FakeInterface
+		fakeRoles *permissionfakes.FakeStore
+		router    *chi.Mux
+		recorder  *httptest.ResponseRecorder
+		caller    *jwt.IdentityClaims
+	)
+
+	BeforeEach(func() {
+		fakeKeys = &apikeyfakes.FakeInterface{}
+		fakeRoles = &permissionfakes.FakeStore{}
+		handler := apikey.NewHandlerObject(log.New(&logfakes.FakeProdInterface{}), fakeKeys, fakeRoles)
+		router = chi.NewRouter()
+		router.Get("/identity/{id}/keys", handler.ListKeys)
+		router.Post("/identity/{id}/keys", handler.CreateKey)
+		router.Delete("/identity/{id}/keys/{keyID}", handler.RevokeKey)
+		recorder = httptest.NewRecorder()
+		fakeRoles.PermissionsReturns([]string{"identity:read", "identity:write"}, nil)
+		caller = &jwt.IdentityClaims{Scope: "identity:read identity:write"}
+		caller.Subject = "test_id"
+	})
+
+	serve := func(method, path, body string) {
+		req := httptest.NewRequest(method, path, bytes.NewBufferString(body))
+		router.ServeHTTP(recorder, req.WithContext(request.WithClaims(req.Context(), caller)))
+	}
+
+	Context("CreateKey", func() {
+		It("should create a key and return it once", func() {
+			fakeKeys.CreateReturns("sk_abc123.the-secret", &apikey.Key{ID: "key_id", Prefix: "abc123"}, nil)
+			serve("POST", "/identity/test_id/keys", `{"name": "nightly", "scopes": ["identity:read"]}`)
+			Expect(recorder.Code).To(Equal(http.StatusCreated))
+			Expect(recorder.Body.String()).To(ContainSubstring(`"apiKey":"sk_abc123.the-secret"`))
+			_, identityID, name, scopes, _ := fakeKeys.CreateArgsForCall(0)
+			Expect(identityID).To(Equal("test_id"))
+			Expect(name).To(Equal("nightly"))
+			Expect(scopes).To(Equal([]string{"identity:read"}))
+		})
+
+		It("should refuse scopes the identity does not hold", func() {
+			serve("POST", "/identity/test_id/keys", `{"name": "nightly", "scopes": ["events:admin"]}`)
+			Expect(recorder.Code).To(Equal(http.StatusBadRequest))
+			Expect(fakeKeys.CreateCallCount()).To(Equal(0))
+		})
+
+		It("should refuse an editor minting a key for an admin", func() {
+			fakeRoles.PermissionsReturns([]string{"identity:read", "identity:write",
+				"identity:admin", "roles:admin"}, nil)
+			caller.Subject = "editor_id"
+			serve("POST", "/identity/admin_id/keys", `{"name": "escalate", "scopes": ["roles:admin"]}`)
+			Expect(recorder.Code).To(Equal(http.StatusForbidden))
+			Expect(fakeKeys.CreateCallCount()).To(Equal(0))
+		})
+
+		It("should let an admin create a key for another identity", func() {
+			caller = &jwt.IdentityClaims{Scope: "identity:read identity:write identity:admin"}
+			caller.Subject = "admin_id"
+			serve("POST", "/identity/test_id/keys", `{"name": "nightly", "scopes": ["identity:read"]}`)
+			Expect(recorder.Code).To(Equal(http.StatusCreated))
+			_, identityID, _, _, _ := fakeKeys.CreateArgsForCall(0)
+			Expect(identityID).To(Equal("test_id"))
+		})
+
+		It("should cap the scopes at the caller's own permissions", func() {
+			caller.Scope = "identity:read"
+			serve("POST", "/identity/test_id/keys", `{"name": "nightly", "scopes": ["identity:write"]}`)
+			Expect(recorder.Code).To(Equal(http.StatusForbidden))
+			Expect(fakeKeys.CreateCallCount()).To(Equal(0))
+		})
+
+		It("should require a name and scopes", func() {
+			serve("POST", "/identity/test_id/keys", `{"name": "nightly"}`)
+			Expect(recorder.Code).To(Equal(http.StatusBadRequest))
+		})
+
+		It("should refuse an expiry in the past", func() {
+			past := time.Now().Add(-time.Hour).Format(time.RFC3339)
+			serve("POST", "/identity/test_id/keys",
+				`{"name": "nightly", "scopes": ["identity:read"], "expiresAt": "`+past+`"}`)
+			Expect(recorder.Code).To(Equal(http.StatusBadRequest))
+		})
+	})
+
+	It("should list an identity's keys", func() {
+		fakeKeys.ListReturns([]apikey.Key{{ID: "key_id", Prefix: "abc123"}}, nil)
+		serve("GET", "/identity/test_id/keys", "")
+		Expect(recorder.Code).To(Equal(http.StatusOK))
+		Expect(recorder.Body.String()).To(ContainSubstring(`"prefix":"abc123"`))
+		_, identityID := fakeKeys.ListArgsForCall(0)
+		Expect(identityID).To(Equal("test_id"))
+	})
+
+	It("should respond 404 when revoking an unknown key", func() {
+		fakeKeys.RevokeReturns(sql.ErrNoRows)
+		serve("DELETE", "/identity/test_id/keys/key_id", "")
+		Expect(recorder.Code).To(Equal(http.StatusNotFound))
+	})
+})
@@ -0,0 +1,62 @@
+package apikey
+
+import (
+	"fmt"
+	"net/http"
+	"service/auth/permission"
+	"service/auth/token/jwt"
+	"service/handlers/request"
+	"service/log"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+//Realm ... the realm advertised in WWW-Authenticate challenges.
+const Realm = "Restricted"
+
+//AuthMiddleware ... verifies the request's API key and stores claims for its identity
+//in the request context, so permission.Require works as it does for bearer tokens.
+//The key's scopes are narrowed to what the identity's roles still grant.
+func AuthMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		key, err := keyAuth.Authenticate(req)
+		if err == ErrInvalid {
+			w.Header().Add("WWW-Authenticate", fmt.Sprintf("%s realm=%q", Scheme, Realm))
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+		if err != nil {
+			internalServerError(err, w, req)
+			return
+		}
+		granted, err := roleStore.Permissions(req.Context(), key.IdentityID)
+		if err != nil {
+			internalServerError(err, w, req)
+			return
+		}
+		claims := &jwt.IdentityClaims{Scope: strings.Join(intersect(key.Scopes, granted), " ")}
+		claims.Id = key.ID
+		claims.Subject = key.IdentityID
+		next.ServeHTTP(w, req.WithContext(request.WithClaims(req.Context(), claims)))
+	})
+}
+
+func internalServerError(err error, w http.ResponseWriter, req *http.Request) {
+	logClient.Error("apikey::AuthMiddleware",
+		zap.String("requestID", request.RetreiveRequestID(req.Context())),
+		zap.Error(err))
+	http.Error(w, http.StatusText(http.StatusInternalServerError),
+		http.StatusInternalServerError)
+}
+
+var keyAuth *Auth
+var roleStore permission.Store
+var logClient log.ProdInterface
+
+//SetupAuthMiddleware ... attaches a configured key auth and role store
+func SetupAuthMiddleware(auth *Auth, roles permission.Store, log log.ProdInterface) {
+	keyAuth = auth
+	roleStore = roles
+	logClient = log
+}
@@ -0,0 +1,166 @@
+package apikey_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"service/auth"
+	"service/auth/apikey"
+	"service/auth/apikey/apikeyfakes"
+	"service/auth/permission/permissionfakes"
+	"service/handlers/request"
+	"service/log/logfakes"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ auth.Interface = &apikey.Auth{}
+
+var _ = Describe("API Key Middleware Specs", func() {
+	var (
+		fakeKeys  *apikeyfakes.FakeInterface
+		fakeRoles *permissionfakes.FakeStore
+		fakeLog   *logfakes.FakeProdInterface
+		req       *http.Request
+		recorder  *httptest.ResponseRecorder
+		scopes    []string
+		subject   string
+		reached   bool
+	)
+
+	BeforeEach(func() {
+		fakeKeys = &apikeyfakes.FakeInterface{}
+		fakeRoles = &permissionfakes.FakeStore{}
+		fakeLog = &logfakes.FakeProdInterface{}
+		apikey.SetupAuthMiddleware(apikey.NewAuth(fakeKeys), fakeRoles, fakeLog)
+
+		fakeKeys.VerifyReturns(&apikey.Key{
+			ID:         "key_id",
+			IdentityID: "test_id",
+			Scopes:     []string{"identity:read", "identity:write"},
+		}, nil)
+		fakeRoles.PermissionsReturns([]string{"events:read", "identity:read"}, nil)
+
+		reached, scopes, subject = false, nil, ""
+		req = httptest.NewRequest("GET", "/identities", nil)
+		recorder = httptest.NewRecorder()
+	})
+
+	JustBeforeEach(func() {
+		apikey.AuthMiddleware(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			reached = true
+			claims, _ := request.RetreiveClaims(req.Context())
+			scopes = claims.Scopes()
+			subject = claims.Subject
+		})).ServeHTTP(recorder, req)
+	})
+
+	Context("when a valid key is sent in the Authorization header", func() {
+		BeforeEach(func() {
+			req.Header.Set("Authorization", "ApiKey sk_abc123.the-secret")
+		})
+
+		It("should put the key's identity in the request context", func() {
+			Expect(reached).To(BeTrue())
+			Expect(subject).To(Equal("test_id"))
+			_, apiKey := fakeKeys.VerifyArgsForCall(0)
+			Expect(apiKey).To(Equal("sk_abc123.the-secret"))
+		})
+
+		It("should narrow the key's scopes to the identity's current permissions", func() {
+			Expect(scopes).To(Equal([]string{"identity:read"}))
+		})
+	})
+
+	Context("when a valid key is sent in the X-API-Key header", func() {
+		BeforeEach(func() {
+			req.Header.Set("X-API-Key", "sk_abc123.the-secret")
+		})
+
+		It("should be accepted", func() {
+			Expect(reached).To(BeTrue())
+		})
+	})
+
+	Context("when the key is invalid", func() {
+		BeforeEach(func() {
+			req.Header.Set("X-API-Key", "sk_abc123.wrong")
+			fakeKeys.VerifyReturns(nil, apikey.ErrInvalid)
+		})
+
+		It("should respond 401 with an ApiKey challenge", func() {
+			Expect(reached).To(BeFalse())
+			Expect(recorder.Code).To(Equal(http.StatusUnauthorized))
+			Expect(recorder.Header().Get("WWW-Authenticate")).To(Equal(`ApiKey realm="Restricted"`))
+		})
+	})
+
+	Context("when no key is sent", func() {
+		It("should respond 401 without verifying anything", func() {
+			Expect(recorder.Code).To(Equal(http.StatusUnauthorized))
+			Expect(fakeKeys.VerifyCallCount()).To(Equal(0))
+		})
+	})
+
+	Context("when the key store fails", func() {
+		BeforeEach(func() {
+			req.Header.Set("X-API-Key", "sk_abc123.the-secret")
+			fakeKeys.VerifyReturns(nil, errors.New("db down"))
+		})
+
+		It("should respond 500 and log the error", func() {
+			Expect(recorder.Code).To(Equal(http.StatusInternalServerError))
+			Expect(fakeLog.ErrorCallCount()).To(Equal(1))
+		})
+	})
+})
+
+var _ = Describe("API Key Auth Specs", func() {
+	ctx := context.Background()
+	var (
+		fakeKeys *apikeyfakes.FakeInterface
+		keyAuth  *apikey.Auth
+		req      *http.Request
+	)
+
+	BeforeEach(func() {
+		fakeKeys = &apikeyfakes.FakeInterface{}
+		keyAuth = apikey.NewAuth(fakeKeys)
+		req = httptest.NewRequest("GET", "/", nil)
+		req.Header.Set("Authorization", "ApiKey sk_abc123.the-secret")
+	})
+
+	It("should split the key into its prefix and secret", func() {
+		prefix, secret, ok := keyAuth.Authorize(req)
+		Expect(ok).To(BeTrue())
+		Expect(prefix).To(Equal("abc123"))
+		Expect(secret).To(Equal("the-secret"))
+	})
+
+	It("should create keys from a token input map", func() {
+		fakeKeys.CreateReturns("sk_abc123.the-secret", &apikey.Key{}, nil)
+		apiKey, err := keyAuth.GenerateToken(ctx, map[string]interface{}{
+			"sub":   "test_id",
+			"name":  "nightly",
+			"scope": []string{"identity:read"},
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(apiKey).To(Equal("sk_abc123.the-secret"))
+		createCtx, identityID, name, scopes, expiresAt := fakeKeys.CreateArgsForCall(0)
+		Expect(createCtx).To(Equal(ctx))
+		Expect(identityID).To(Equal("test_id"))
+		Expect(name).To(Equal("nightly"))
+		Expect(scopes).To(Equal([]string{"identity:read"}))
+		Expect(expiresAt).To(BeNil())
+	})
+
+	It("should revoke the key the request was made with", func() {
+		fakeKeys.VerifyReturns(&apikey.Key{ID: "key_id", IdentityID: "test_id"}, nil)
+		Expect(keyAuth.RevokeTokenHeader(req)).To(Succeed())
+		_, identityID, keyID := fakeKeys.RevokeArgsForCall(0)
+		Expect(identityID).To(Equal("test_id"))
+		Expect(keyID).To(Equal("key_id"))
+	})
+})
@@ -0,0 +1,230 @@
+package apikey
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"service/database"
+	"service/log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+//KeyPrefix ... starts every API key so they are easy to spot in logs and secret scanners.
+const KeyPrefix = "sk_"
+
+//TouchInterval ... last_used_at is only written once per interval, so busy keys do not
+//cost a write per request.
+const TouchInterval = time.Minute
+
+//ErrInvalid ... is returned for API keys that are malformed, unknown, expired or revoked.
+var ErrInvalid = errors.New("invalid api key")
+
+//Key ... is the stored, non secret part of an API key.
+type Key struct {
+	ID         string     `json:"id"`
+	IdentityID string     `json:"identityId"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Scopes     []string   `json:"scopes"`
+	ExpiresAt  *time.Time `json:"expiresAt,omitempty"`
+	LastUsedAt *time.Time `json:"lastUsedAt,omitempty"`
+	CreatedAt  time.Time  `json:"createdAt"`
+}
+
+//Interface ... defines creating, verifying and revoking API keys.
+//go:generate counterfeiter . Interface
+type Interface interface {
+	Create(ctx context.Context, identityID, name string, scopes []string,
+		expiresAt *time.Time) (string, *Key, error)
+	Verify(ctx context.Context, apiKey string) (*Key, error)
+	List(ctx context.Context, identityID string) ([]Key, error)
+	Revoke(ctx context.Context, identityID, keyID string) error
+}
+
+//Service ...
+//persists API keys via a DBInterface. A key reads "sk_<prefix>.<secret>", the prefix is
+//stored in the clear to find the key and shown when listing, only a sha256 of the secret is kept.
+type Service struct {
+	log log.ProdInterface
+	db  database.DBInterface
+}
+
+//NewService ...
+//returns a pointer to a new API key Service.
+func NewService(logClient log.ProdInterface, db database.DBInterface) *Service {
+	return &Service{
+		log: logClient,
+		db:  db,
+	}
+}
+
+//Create ...
+//generates a key for identityID and returns it in full. This is the only time the
+//secret is available, callers must hand it to the client straight away.
+func (s *Service) Create(ctx context.Context, identityID, name string, scopes []string,
+	expiresAt *time.Time) (string, *Key, error) {
+	prefix, err := randomString(6, hex.EncodeToString)
+	if err != nil {
+		return "", nil, err
+	}
+	secret, err := randomString(32, base64.RawURLEncoding.EncodeToString)
+	if err != nil {
+		return "", nil, err
+	}
+	key := &Key{
+		ID:         uuid.New().String(),
+		IdentityID: identityID,
+		Name:       name,
+		Prefix:     prefix,
+		Scopes:     scopes,
+		ExpiresAt:  expiresAt,
+		CreatedAt:  time.Now(),
+	}
+	_, err = s.db.ExecContext(ctx, `INSERT INTO api_key
+		(id, identity_id, name, prefix, secret_hash, scope, expires_at, created_at) VALUES
+		($1, $2, $3, $4, $5, $6, $7, $8);`,
+		key.ID,
+		key.IdentityID,
+		key.Name,
+		key.Prefix,
+		hashSecret(secret),
+		strings.Join(key.Scopes, " "),
+		key.ExpiresAt,
+		key.CreatedAt)
+	if err != nil {
+		return "", nil, err
+	}
+	return KeyPrefix + prefix + "." + secret, key, nil
+}
+
+//Verify ...
+//checks a full API key and records that it was used, at most once per TouchInterval.
+func (s *Service) Verify(ctx context.Context, apiKey string) (*Key, error) {
+	prefix, secret, ok := parseKey(apiKey)
+	if !ok {
+		return nil, ErrInvalid
+	}
+	rightNow := time.Now()
+	var key Key
+	var secretHash, scope string
+	err := s.db.QueryRowContext(ctx,
+		`SELECT id, identity_id, name, prefix, secret_hash, scope,
+		expires_at, last_used_at, created_at FROM api_key
+		WHERE prefix = $1 AND revoked_at IS NULL AND (expires_at IS NULL OR expires_at > $2);`,
+		prefix, rightNow).Scan(&key.ID, &key.IdentityID, &key.Name, &key.Prefix, &secretHash,
+		&scope, &key.ExpiresAt, &key.LastUsedAt, &key.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, ErrInvalid
+	}
+	if err != nil {
+		return nil, err
+	}
+	if subtle.ConstantTimeCompare([]byte(secretHash), []byte(hashSecret(secret))) != 1 {
+		return nil, ErrInvalid
+	}
+	key.Scopes = strings.Fields(scope)
+	if key.LastUsedAt != nil && rightNow.Sub(*key.LastUsedAt) < TouchInterval {
+		return &key, nil
+	}
+	//Failing to record usage should not lock batch jobs out.
+	if _, err := s.db.ExecContext(ctx,
+		"UPDATE api_key SET last_used_at = $2 WHERE id = $1;",
+		key.ID, rightNow); err != nil {
+		s.log.Error("apikey::Verify", zap.String("keyID", key.ID), zap.Error(err))
+	}
+	key.LastUsedAt = &rightNow
+	return &key, nil
+}
+
+//List ...
+//returns the identity's active keys, newest first. Secrets are never returned.
+func (s *Service) List(ctx context.Context, identityID string) ([]Key, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT id, identity_id, name, prefix, scope,
+		expires_at, last_used_at, created_at FROM api_key
+		WHERE identity_id = $1 AND revoked_at IS NULL ORDER BY created_at DESC;`,
+		identityID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	keys := make([]Key, 0)
+	for rows.Next() {
+		var key Key
+		var scope string
+		if err := rows.Scan(&key.ID, &key.IdentityID, &key.Name, &key.Prefix, &scope,
+			&key.ExpiresAt, &key.LastUsedAt, &key.CreatedAt); err != nil {
+			return nil, err
+		}
+		key.Scopes = strings.Fields(scope)
+		keys = append(keys, key)
+	}
+	return keys, rows.Err()
+}
+
+//Revoke ...
+//revokes one of the identity's keys, returns sql.ErrNoRows if it has no such active key.
+func (s *Service) Revoke(ctx context.Context, identityID, keyID string) error {
+	result, err := s.db.ExecContext(ctx, `UPDATE api_key SET revoked_at = $3
+		WHERE id = $1 AND identity_id = $2 AND revoked_at IS NULL;`,
+		keyID, identityID, time.Now())
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+//parseKey splits "sk_<prefix>.<secret>" into its prefix and secret.
+func parseKey(apiKey string) (string, string, bool) {
+	if !strings.HasPrefix(apiKey, KeyPrefix) {
+		return "", "", false
+	}
+	parts := strings.SplitN(strings.TrimPrefix(apiKey, KeyPrefix), ".", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", false
+	}
+	return parts[0], parts[1], true
+}
+
+//intersect returns the scopes that are also in granted, keeping their order.
+func intersect(scopes, granted []string) []string {
+	allowed := make(map[string]bool, len(granted))
+	for _, permission := range granted {
+		allowed[permission] = true
+	}
+	kept := make([]string, 0, len(scopes))
+	for _, scope := range scopes {
+		if allowed[scope] {
+			kept = append(kept, scope)
+		}
+	}
+	return kept
+}
+
+func randomString(size int, encode func([]byte) string) (string, error) {
+	raw := make([]byte, size)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+	return encode(raw), nil
+}
+
+func hashSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
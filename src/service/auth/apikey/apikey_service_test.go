@@ -0,0 +1,159 @@
+package apikey_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"service/auth/apikey"
+	"service/log"
+	"service/log/logfakes"
+	"service/utils/sqltest"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	sqlmock "gopkg.in/DATA-DOG/go-sqlmock.v1"
+)
+
+var _ = Describe("API Key Service Specs", func() {
+	ctx := context.Background()
+	var (
+		service *apikey.Service
+		fakeLog *logfakes.FakeProdInterface
+		db      *sql.DB
+		mockDB  sqlmock.Sqlmock
+	)
+
+	columns := []string{"id", "identity_id", "name", "prefix", "secret_hash", "scope",
+		"expires_at", "last_used_at", "created_at"}
+
+	hash := func(secret string) string {
+		sum := sha256.Sum256([]byte(secret))
+		return hex.EncodeToString(sum[:])
+	}
+
+	BeforeEach(func() {
+		var sqlmockErr error
+		db, mockDB, sqlmockErr = sqlmock.New()
+		Expect(sqlmockErr).ToNot(HaveOccurred())
+		fakeLog = &logfakes.FakeProdInterface{}
+		service = apikey.NewService(log.New(fakeLog), db)
+	})
+
+	AfterEach(func() {
+		Expect(mockDB.ExpectationsWereMet()).To(Succeed())
+	})
+
+	Context("Create", func() {
+		It("should store only a hash of the secret and return the full key once", func() {
+			mockDB.ExpectExec("INSERT INTO api_key").
+				WithArgs(sqltest.AnyString{}, "test_id", "nightly", sqltest.AnyString{},
+					sqltest.AnyString{}, "events:read identity:read", nil, sqltest.AnyTime{}).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+
+			apiKey, key, err := service.Create(ctx, "test_id", "nightly",
+				[]string{"events:read", "identity:read"}, nil)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(apiKey).To(HavePrefix(apikey.KeyPrefix + key.Prefix + "."))
+			Expect(key.IdentityID).To(Equal("test_id"))
+			Expect(key.Scopes).To(Equal([]string{"events:read", "identity:read"}))
+		})
+	})
+
+	Context("Verify", func() {
+		var rows *sqlmock.Rows
+
+		BeforeEach(func() {
+			rows = sqlmock.NewRows(columns).AddRow("key_id", "test_id", "nightly", "abc123",
+				hash("the-secret"), "identity:read", nil, nil, time.Now())
+		})
+
+		It("should accept a matching secret and record its use", func() {
+			mockDB.ExpectQuery("SELECT (.+) FROM api_key").
+				WithArgs("abc123", sqltest.AnyTime{}).WillReturnRows(rows)
+			mockDB.ExpectExec("UPDATE api_key SET last_used_at").
+				WithArgs("key_id", sqltest.AnyTime{}).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+
+			key, err := service.Verify(ctx, "sk_abc123.the-secret")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(key.ID).To(Equal("key_id"))
+			Expect(key.Scopes).To(Equal([]string{"identity:read"}))
+			Expect(key.LastUsedAt).ToNot(BeNil())
+		})
+
+		It("should not record use again within the TouchInterval", func() {
+			lastUsedAt := time.Now().Add(-apikey.TouchInterval / 2)
+			rows = sqlmock.NewRows(columns).AddRow("key_id", "test_id", "nightly", "abc123",
+				hash("the-secret"), "identity:read", nil, lastUsedAt, time.Now())
+			mockDB.ExpectQuery("SELECT (.+) FROM api_key").
+				WithArgs("abc123", sqltest.AnyTime{}).WillReturnRows(rows)
+
+			key, err := service.Verify(ctx, "sk_abc123.the-secret")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(key.LastUsedAt).ToNot(BeNil())
+			Expect(*key.LastUsedAt).To(BeTemporally("==", lastUsedAt))
+			//An unexpected UPDATE fails in sqlmock and is only logged.
+			Expect(fakeLog.ErrorCallCount()).To(Equal(0))
+		})
+
+		It("should reject a wrong secret", func() {
+			mockDB.ExpectQuery("SELECT (.+) FROM api_key").
+				WithArgs("abc123", sqltest.AnyTime{}).WillReturnRows(rows)
+
+			_, err := service.Verify(ctx, "sk_abc123.wrong")
+			Expect(err).To(Equal(apikey.ErrInvalid))
+		})
+
+		It("should reject unknown, expired or revoked keys", func() {
+			mockDB.ExpectQuery("SELECT (.+) FROM api_key").
+				WithArgs("abc123", sqltest.AnyTime{}).WillReturnError(sql.ErrNoRows)
+
+			_, err := service.Verify(ctx, "sk_abc123.the-secret")
+			Expect(err).To(Equal(apikey.ErrInvalid))
+		})
+
+		It("should reject malformed keys without querying", func() {
+			for _, malformed := range []string{"", "abc123.the-secret", "sk_abc123", "sk_.secret"} {
+				_, err := service.Verify(ctx, malformed)
+				Expect(err).To(Equal(apikey.ErrInvalid))
+			}
+		})
+	})
+
+	Context("List", func() {
+		It("should return keys without their secrets", func() {
+			mockDB.ExpectQuery("SELECT (.+) FROM api_key").WithArgs("test_id").
+				WillReturnRows(sqlmock.NewRows([]string{"id", "identity_id", "name", "prefix",
+					"scope", "expires_at", "last_used_at", "created_at"}).
+					AddRow("key_id", "test_id", "nightly", "abc123", "identity:read",
+						nil, nil, time.Now()))
+
+			keys, err := service.List(ctx, "test_id")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(keys).To(HaveLen(1))
+			Expect(keys[0].Prefix).To(Equal("abc123"))
+		})
+	})
+
+	Context("Revoke", func() {
+		It("should report a key the identity does not have", func() {
+			mockDB.ExpectExec("UPDATE api_key SET revoked_at").
+				WithArgs("key_id", "test_id", sqltest.AnyTime{}).
+				WillReturnResult(sqlmock.NewResult(0, 0))
+			Expect(service.Revoke(ctx, "test_id", "key_id")).To(Equal(sql.ErrNoRows))
+		})
+	})
+
+	It("should generate distinct keys", func() {
+		mockDB.ExpectExec("INSERT INTO api_key").WillReturnResult(sqlmock.NewResult(0, 1))
+		mockDB.ExpectExec("INSERT INTO api_key").WillReturnResult(sqlmock.NewResult(0, 1))
+		first, _, err := service.Create(ctx, "test_id", "a", []string{"identity:read"}, nil)
+		Expect(err).ToNot(HaveOccurred())
+		second, _, err := service.Create(ctx, "test_id", "b", []string{"identity:read"}, nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(strings.Split(first, ".")[0]).ToNot(Equal(strings.Split(second, ".")[0]))
+	})
+})
@@ -1,29 +1,52 @@
 package auth
 
 import (
+	"context"
+	"errors"
 	"net/http"
+	"service/auth/revocation"
 	"service/auth/token"
+	"service/auth/token/jwt"
+	"strings"
+	"time"
 )
 
+//ErrInvalidToken ... is returned when a token header is missing, malformed or expired.
+var ErrInvalidToken = errors.New("invalid token")
+
+//ErrRevokedToken ... is returned when a token has been revoked, e.g. by logging out.
+var ErrRevokedToken = errors.New("token has been revoked")
+
+//ErrMissingToken ... is returned by ValidateBearer when the request carries no bearer token.
+var ErrMissingToken = errors.New("missing bearer token")
+
+//ErrMalformedToken ... is returned by ValidateBearer when the Authorization header can't be parsed.
+var ErrMalformedToken = errors.New("malformed bearer token")
+
 //Interface ... a interface definition for implementers
 //go:generate counterfeiter . Interface
 type Interface interface {
 	Authorize(req *http.Request) (string, string, bool)
 	ValidateTokenHeader(req *http.Request) (bool, error)
-	GenerateToken(map[string]interface{}) (string, error)
+	GenerateToken(ctx context.Context, input map[string]interface{}) (string, error)
+	RevokeTokenHeader(req *http.Request) error
 }
 
 //Client ... a client wrapper for basic and oauth.
 type Client struct {
-	I Interface
-	T token.Interface
+	I       Interface
+	T       token.Interface
+	Revoked revocation.Store
+	//Leeway is how long past exp T still accepts a token, revocations last that much longer.
+	Leeway time.Duration
 }
 
 //NewClient ... creates a new Auth DB and returns a pointer to it.
-func NewClient(auth Interface, token token.Interface) *Client {
+func NewClient(auth Interface, token token.Interface, revoked revocation.Store) *Client {
 	return &Client{
-		I: auth,
-		T: token,
+		I:       auth,
+		T:       token,
+		Revoked: revoked,
 	}
 }
 
@@ -34,15 +57,102 @@ func (c *Client) Authorize(req *http.Request) (string, string, bool) {
 
 // GenerateToken ...
 // generates a new JWT token with a given input map.
-func (c *Client) GenerateToken(input map[string]interface{}) (string, error) {
+func (c *Client) GenerateToken(ctx context.Context, input map[string]interface{}) (string,
+	error) {
 	return c.T.Generate(input)
 }
 
+//BearerToken ...
+//Pulls the token out of an "Authorization: Bearer <token>" header (RFC 6750 section 2.1).
+//The nonstandard "token" header is still read when no Authorization header is sent so
+//existing clients keep working.
+func BearerToken(req *http.Request) (string, error) {
+	header := req.Header.Get("Authorization")
+	if header == "" {
+		if token := req.Header.Get("token"); token != "" {
+			return token, nil
+		}
+		return "", ErrMissingToken
+	}
+	fields := strings.Fields(header)
+	if !strings.EqualFold(fields[0], "Bearer") {
+		return "", ErrMissingToken
+	}
+	if len(fields) != 2 {
+		return "", ErrMalformedToken
+	}
+	return fields[1], nil
+}
+
+//ValidateBearer ...
+//Validates the request's bearer token through the token interface and the revocation
+//list, returning its claims. Errors are ErrMissingToken, ErrMalformedToken,
+//ErrInvalidToken, ErrRevokedToken or a revocation store failure.
+func (c *Client) ValidateBearer(req *http.Request) (*jwt.IdentityClaims, error) {
+	token, err := BearerToken(req)
+	if err != nil {
+		return nil, err
+	}
+	return c.ValidateToken(req.Context(), token)
+}
+
+//ValidateToken ...
+//Validates a raw token the way ValidateBearer does, for tokens that don't arrive in a
+//header such as those sent for introspection.
+func (c *Client) ValidateToken(ctx context.Context, token string) (*jwt.IdentityClaims, error) {
+	tokenObj, isValid, err := c.T.ValidateToken(token)
+	if err != nil || !isValid {
+		return nil, ErrInvalidToken
+	}
+	claims, ok := jwt.ClaimsFrom(tokenObj)
+	if !ok {
+		return nil, ErrInvalidToken
+	}
+	//Every token we issue has a jti, one without could never be revoked.
+	if claims.Id == "" {
+		return nil, ErrInvalidToken
+	}
+	if c.Revoked == nil {
+		return claims, nil
+	}
+	revoked, err := c.Revoked.IsRevoked(ctx, claims.Id)
+	if err != nil {
+		return nil, err
+	}
+	if revoked {
+		return nil, ErrRevokedToken
+	}
+	return claims, nil
+}
+
 //ValidateTokenHeader ...
-//Takes in a request, pulls of a header value called "token", passes it through
-//jwt validation, returns the decoded object and/or whether or not the token is valid.
+//Takes in a request, pulls off its bearer token, passes it through jwt validation,
+//returns whether or not the token is valid. Missing and invalid tokens return
+//ErrInvalidToken and tokens whose jti is on the revocation list return ErrRevokedToken.
 func (c *Client) ValidateTokenHeader(req *http.Request) (bool, error) {
-	token := req.Header.Get("token")
-	_, isValid, err := c.T.ValidateToken(token)
-	return isValid, err
+	_, err := c.validate(req)
+	return err == nil, err
+}
+
+//RevokeTokenHeader ...
+//Validates the request's bearer token and adds its jti to the revocation list
+//until the token would have expired, Leeway included.
+func (c *Client) RevokeTokenHeader(req *http.Request) error {
+	claims, err := c.validate(req)
+	if err != nil {
+		return err
+	}
+	if claims.Id == "" {
+		return ErrInvalidToken
+	}
+	return c.Revoked.Revoke(req.Context(), claims.Id,
+		time.Unix(claims.ExpiresAt, 0).Add(c.Leeway))
+}
+
+func (c *Client) validate(req *http.Request) (*jwt.IdentityClaims, error) {
+	claims, err := c.ValidateBearer(req)
+	if err == ErrMissingToken || err == ErrMalformedToken {
+		return nil, ErrInvalidToken
+	}
+	return claims, err
 }
@@ -1,11 +1,17 @@
 package auth_test
 
 import (
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"service/auth"
 	"service/auth/authfakes"
+	"service/auth/revocation/revocationfakes"
+	"service/auth/token/jwt"
 	"service/auth/token/tokenfakes"
+	"time"
+
+	jwtGo "github.com/dgrijalva/jwt-go"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -13,16 +19,18 @@ import (
 
 var _ = Describe("Auth Client Specs", func() {
 	var (
-		authClient *auth.Client
-		fakeAuth   *authfakes.FakeInterface
-		fakeToken  *tokenfakes.FakeInterface
-		request    *http.Request
+		authClient  *auth.Client
+		fakeAuth    *authfakes.FakeInterface
+		fakeToken   *tokenfakes.FakeInterface
+		fakeRevoked *revocationfakes.FakeStore
+		request     *http.Request
 	)
 
 	BeforeEach(func() {
 		fakeToken = &tokenfakes.FakeInterface{}
 		fakeAuth = &authfakes.FakeInterface{}
-		authClient = auth.NewClient(fakeAuth, fakeToken)
+		fakeRevoked = &revocationfakes.FakeStore{}
+		authClient = auth.NewClient(fakeAuth, fakeToken, fakeRevoked)
 	})
 
 	Context("Authorize", func() {
@@ -57,4 +65,139 @@ var _ = Describe("Auth Client Specs", func() {
 			Expect(fakeToken.ValidateTokenCallCount()).To(Equal(1))
 		})
 	})
+
+	Context("BearerToken", func() {
+		BeforeEach(func() {
+			request = httptest.NewRequest("GET", "/", nil)
+		})
+
+		It("should read the Authorization header", func() {
+			request.Header.Set("Authorization", "Bearer abc.def.ghi")
+			Expect(auth.BearerToken(request)).To(Equal("abc.def.ghi"))
+		})
+
+		It("should prefer the Authorization header over the legacy token header", func() {
+			request.Header.Set("Authorization", "Bearer abc.def.ghi")
+			request.Header.Set("token", "legacy")
+			Expect(auth.BearerToken(request)).To(Equal("abc.def.ghi"))
+		})
+
+		It("should fall back to the legacy token header", func() {
+			request.Header.Set("token", "legacy")
+			Expect(auth.BearerToken(request)).To(Equal("legacy"))
+		})
+
+		It("should report missing and malformed tokens", func() {
+			_, err := auth.BearerToken(request)
+			Expect(err).To(Equal(auth.ErrMissingToken))
+
+			request.Header.Set("Authorization", "Basic dG9ueTpob3VzZQ==")
+			_, err = auth.BearerToken(request)
+			Expect(err).To(Equal(auth.ErrMissingToken))
+
+			request.Header.Set("Authorization", "Bearer two parts")
+			_, err = auth.BearerToken(request)
+			Expect(err).To(Equal(auth.ErrMalformedToken))
+		})
+	})
+
+	Context("ValidateTokenHeader with a revocation list", func() {
+		var (
+			isValid   bool
+			expectErr error
+			expiresAt time.Time
+		)
+
+		BeforeEach(func() {
+			request = httptest.NewRequest("POST", "/identity", nil)
+			request.Header.Set("token", "test-token-1abcdef")
+			expiresAt = time.Now().Add(time.Minute)
+			fakeToken.ValidateTokenReturns(&jwtGo.Token{
+				Claims: &jwt.IdentityClaims{
+					StandardClaims: jwtGo.StandardClaims{
+						Id:        "test-jti",
+						ExpiresAt: expiresAt.Unix(),
+					},
+				},
+			}, true, nil)
+		})
+
+		JustBeforeEach(func() {
+			isValid, expectErr = authClient.ValidateTokenHeader(request)
+		})
+
+		Context("when the jti is not revoked", func() {
+			It("should be valid", func() {
+				Expect(expectErr).ToNot(HaveOccurred())
+				Expect(isValid).To(BeTrue())
+				_, jti := fakeRevoked.IsRevokedArgsForCall(0)
+				Expect(jti).To(Equal("test-jti"))
+			})
+		})
+
+		Context("when the jti is revoked", func() {
+			BeforeEach(func() {
+				fakeRevoked.IsRevokedReturns(true, nil)
+			})
+
+			It("should be rejected", func() {
+				Expect(expectErr).To(Equal(auth.ErrRevokedToken))
+				Expect(isValid).To(BeFalse())
+			})
+		})
+
+		Context("when the revocation list errors", func() {
+			BeforeEach(func() {
+				fakeRevoked.IsRevokedReturns(false, errors.New("db down"))
+			})
+
+			It("should fail closed", func() {
+				Expect(expectErr).To(MatchError("db down"))
+				Expect(isValid).To(BeFalse())
+			})
+		})
+
+		Context("when the token itself is invalid", func() {
+			BeforeEach(func() {
+				fakeToken.ValidateTokenReturns(nil, false, errors.New("token is expired"))
+			})
+
+			It("should return ErrInvalidToken without checking the revocation list", func() {
+				Expect(expectErr).To(Equal(auth.ErrInvalidToken))
+				Expect(fakeRevoked.IsRevokedCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("when the token has no jti", func() {
+			BeforeEach(func() {
+				fakeToken.ValidateTokenReturns(&jwtGo.Token{
+					Claims: &jwt.IdentityClaims{
+						StandardClaims: jwtGo.StandardClaims{ExpiresAt: expiresAt.Unix()},
+					},
+				}, true, nil)
+			})
+
+			It("should return ErrInvalidToken without checking the revocation list", func() {
+				Expect(expectErr).To(Equal(auth.ErrInvalidToken))
+				Expect(isValid).To(BeFalse())
+				Expect(fakeRevoked.IsRevokedCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("RevokeTokenHeader", func() {
+			It("should revoke the jti until the token expires", func() {
+				Expect(authClient.RevokeTokenHeader(request)).To(Succeed())
+				_, jti, until := fakeRevoked.RevokeArgsForCall(0)
+				Expect(jti).To(Equal("test-jti"))
+				Expect(until.Unix()).To(Equal(expiresAt.Unix()))
+			})
+
+			It("should keep the jti revoked for as long as the leeway still accepts the token", func() {
+				authClient.Leeway = 30 * time.Second
+				Expect(authClient.RevokeTokenHeader(request)).To(Succeed())
+				_, _, until := fakeRevoked.RevokeArgsForCall(0)
+				Expect(until.Unix()).To(Equal(expiresAt.Add(30 * time.Second).Unix()))
+			})
+		})
+	})
 })
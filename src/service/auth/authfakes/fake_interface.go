@@ -2,6 +2,7 @@
 package authfakes
 
 import (
+	"context"
 	"net/http"
 	"service/auth"
 	"sync"
@@ -36,10 +37,11 @@ type FakeInterface struct {
 		result1 bool
 		result2 error
 	}
-	GenerateTokenStub        func(map[string]interface{}) (string, error)
+	GenerateTokenStub        func(ctx context.Context, input map[string]interface{}) (string, error)
 	generateTokenMutex       sync.RWMutex
 	generateTokenArgsForCall []struct {
-		arg1 map[string]interface{}
+		ctx   context.Context
+		input map[string]interface{}
 	}
 	generateTokenReturns struct {
 		result1 string
@@ -49,6 +51,17 @@ type FakeInterface struct {
 		result1 string
 		result2 error
 	}
+	RevokeTokenHeaderStub        func(req *http.Request) error
+	revokeTokenHeaderMutex       sync.RWMutex
+	revokeTokenHeaderArgsForCall []struct {
+		req *http.Request
+	}
+	revokeTokenHeaderReturns struct {
+		result1 error
+	}
+	revokeTokenHeaderReturnsOnCall map[int]struct {
+		result1 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
@@ -158,16 +171,17 @@ func (fake *FakeInterface) ValidateTokenHeaderReturnsOnCall(i int, result1 bool,
 	}{result1, result2}
 }
 
-func (fake *FakeInterface) GenerateToken(arg1 map[string]interface{}) (string, error) {
+func (fake *FakeInterface) GenerateToken(ctx context.Context, input map[string]interface{}) (string, error) {
 	fake.generateTokenMutex.Lock()
 	ret, specificReturn := fake.generateTokenReturnsOnCall[len(fake.generateTokenArgsForCall)]
 	fake.generateTokenArgsForCall = append(fake.generateTokenArgsForCall, struct {
-		arg1 map[string]interface{}
-	}{arg1})
-	fake.recordInvocation("GenerateToken", []interface{}{arg1})
+		ctx   context.Context
+		input map[string]interface{}
+	}{ctx, input})
+	fake.recordInvocation("GenerateToken", []interface{}{ctx, input})
 	fake.generateTokenMutex.Unlock()
 	if fake.GenerateTokenStub != nil {
-		return fake.GenerateTokenStub(arg1)
+		return fake.GenerateTokenStub(ctx, input)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -181,10 +195,10 @@ func (fake *FakeInterface) GenerateTokenCallCount() int {
 	return len(fake.generateTokenArgsForCall)
 }
 
-func (fake *FakeInterface) GenerateTokenArgsForCall(i int) map[string]interface{} {
+func (fake *FakeInterface) GenerateTokenArgsForCall(i int) (context.Context, map[string]interface{}) {
 	fake.generateTokenMutex.RLock()
 	defer fake.generateTokenMutex.RUnlock()
-	return fake.generateTokenArgsForCall[i].arg1
+	return fake.generateTokenArgsForCall[i].ctx, fake.generateTokenArgsForCall[i].input
 }
 
 func (fake *FakeInterface) GenerateTokenReturns(result1 string, result2 error) {
@@ -209,6 +223,54 @@ func (fake *FakeInterface) GenerateTokenReturnsOnCall(i int, result1 string, res
 	}{result1, result2}
 }
 
+func (fake *FakeInterface) RevokeTokenHeader(req *http.Request) error {
+	fake.revokeTokenHeaderMutex.Lock()
+	ret, specificReturn := fake.revokeTokenHeaderReturnsOnCall[len(fake.revokeTokenHeaderArgsForCall)]
+	fake.revokeTokenHeaderArgsForCall = append(fake.revokeTokenHeaderArgsForCall, struct {
+		req *http.Request
+	}{req})
+	fake.recordInvocation("RevokeTokenHeader", []interface{}{req})
+	fake.revokeTokenHeaderMutex.Unlock()
+	if fake.RevokeTokenHeaderStub != nil {
+		return fake.RevokeTokenHeaderStub(req)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fake.revokeTokenHeaderReturns.result1
+}
+
+func (fake *FakeInterface) RevokeTokenHeaderCallCount() int {
+	fake.revokeTokenHeaderMutex.RLock()
+	defer fake.revokeTokenHeaderMutex.RUnlock()
+	return len(fake.revokeTokenHeaderArgsForCall)
+}
+
+func (fake *FakeInterface) RevokeTokenHeaderArgsForCall(i int) *http.Request {
+	fake.revokeTokenHeaderMutex.RLock()
+	defer fake.revokeTokenHeaderMutex.RUnlock()
+	return fake.revokeTokenHeaderArgsForCall[i].req
+}
+
+func (fake *FakeInterface) RevokeTokenHeaderReturns(result1 error) {
+	fake.RevokeTokenHeaderStub = nil
+	fake.revokeTokenHeaderReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeInterface) RevokeTokenHeaderReturnsOnCall(i int, result1 error) {
+	fake.RevokeTokenHeaderStub = nil
+	if fake.revokeTokenHeaderReturnsOnCall == nil {
+		fake.revokeTokenHeaderReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.revokeTokenHeaderReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeInterface) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
@@ -218,6 +280,8 @@ func (fake *FakeInterface) Invocations() map[string][][]interface{} {
 	defer fake.validateTokenHeaderMutex.RUnlock()
 	fake.generateTokenMutex.RLock()
 	defer fake.generateTokenMutex.RUnlock()
+	fake.revokeTokenHeaderMutex.RLock()
+	defer fake.revokeTokenHeaderMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
 	for key, value := range fake.invocations {
 		copiedInvocations[key] = value
@@ -1,6 +1,9 @@
 package basic
 
-import "net/http"
+import (
+	"context"
+	"net/http"
+)
 
 //Auth ... a holder around our functions.
 type Auth struct {
@@ -21,6 +24,11 @@ func (a *Auth) ValidateTokenHeader(req *http.Request) (bool, error) {
 	return false, nil
 }
 
-func (a *Auth) GenerateToken(input map[string]interface{}) (string, error) {
+func (a *Auth) GenerateToken(ctx context.Context, input map[string]interface{}) (string, error) {
 	return "", nil
 }
+
+//RevokeTokenHeader ...
+func (a *Auth) RevokeTokenHeader(req *http.Request) error {
+	return nil
+}
@@ -3,27 +3,87 @@ package basic
 import (
 	"net/http"
 	"service/auth"
+	"service/auth/credential"
+	"service/auth/permission"
+	"service/auth/throttle"
+	"service/auth/token/jwt"
+	"service/handlers/request"
 	"service/log"
+	"strings"
+
+	"go.uber.org/zap"
 )
 
-//AuthMiddleware ... performs basic auth
+//AuthMiddleware ... performs basic auth against the configured credential store,
+//throttling failed attempts per username and per client IP. The permissions of the
+//credential's roles are stored as claims, so permission.Require works as it does for
+//bearer tokens.
 func AuthMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
 		u, p, hasAuth := authClient.Authorize(req)
-		if hasAuth && u == "tony" && p == "house" {
-			next.ServeHTTP(w, req)
-		} else {
-			w.Header().Set("WWW-Authenticate", "Basic realm=Restricted")
-			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		if hasAuth {
+			userKey, ipKey := throttle.UserKey(u), throttle.IPKey(throttle.ClientIP(req))
+			wait, err := loginThrottle.Check(req.Context(), userKey, ipKey)
+			if err != nil {
+				internalServerError(err, w, req)
+				return
+			}
+			if wait > 0 {
+				throttle.SetRetryAfter(w, wait)
+				http.Error(w, throttle.ErrTooManyAttempts, http.StatusTooManyRequests)
+				return
+			}
+			verified, err := credentialStore.Verify(req.Context(), u, p)
+			if err != nil {
+				internalServerError(err, w, req)
+				return
+			}
+			if verified {
+				if err := loginThrottle.Succeed(req.Context(), userKey); err != nil {
+					internalServerError(err, w, req)
+					return
+				}
+				granted, err := roleStore.CredentialPermissions(req.Context(), u)
+				if err != nil {
+					internalServerError(err, w, req)
+					return
+				}
+				ctx := request.WithBasicUser(req.Context(), u)
+				ctx = request.WithClaims(ctx, &jwt.IdentityClaims{Scope: strings.Join(granted, " ")})
+				next.ServeHTTP(w, req.WithContext(ctx))
+				return
+			}
+			if err := loginThrottle.Fail(req.Context(), userKey, ipKey); err != nil {
+				internalServerError(err, w, req)
+				return
+			}
 		}
+		w.Header().Set("WWW-Authenticate", "Basic realm=Restricted")
+		http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
 	})
 }
 
+func internalServerError(err error, w http.ResponseWriter, req *http.Request) {
+	logClient.Error("basic::AuthMiddleware",
+		zap.String("requestID", request.RetreiveRequestID(req.Context())),
+		zap.Error(err))
+	http.Error(w, http.StatusText(http.StatusInternalServerError),
+		http.StatusInternalServerError)
+}
+
 var authClient *auth.Client
+var credentialStore credential.Store
+var roleStore permission.Store
+var loginThrottle throttle.Interface
 var logClient log.ProdInterface
 
-//SetupAuthMiddleware ... attaches a configured authClient
-func SetupAuthMiddleware(auth *auth.Client, log log.ProdInterface) {
+//SetupAuthMiddleware ... attaches a configured authClient, credential store, role store and
+//login throttle
+func SetupAuthMiddleware(auth *auth.Client, store credential.Store, roles permission.Store,
+	throttle throttle.Interface, log log.ProdInterface) {
 	authClient = auth
+	credentialStore = store
+	roleStore = roles
+	loginThrottle = throttle
 	logClient = log
 }
@@ -1,6 +1,20 @@
 package basic_test
 
 import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"service/auth"
+	"service/auth/basic"
+	"service/auth/credential/credentialfakes"
+	"service/auth/permission"
+	"service/auth/permission/permissionfakes"
+	"service/auth/throttle/throttlefakes"
+	"service/auth/token/tokenfakes"
+	"service/handlers/request"
+	"service/log/logfakes"
+	"time"
+
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 )
@@ -11,4 +25,141 @@ var _ = Describe("Basic Auth Specs", func() {
 			Expect(true).To(BeTrue())
 		})
 	})
+
+	Context("AuthMiddleware", func() {
+		var (
+			fakeStore    *credentialfakes.FakeStore
+			fakeRoles    *permissionfakes.FakeStore
+			fakeThrottle *throttlefakes.FakeInterface
+			fakeLog      *logfakes.FakeProdInterface
+			req          *http.Request
+			recorder     *httptest.ResponseRecorder
+			reached      bool
+			reachedReq   *http.Request
+		)
+
+		BeforeEach(func() {
+			fakeStore = &credentialfakes.FakeStore{}
+			fakeRoles = &permissionfakes.FakeStore{}
+			fakeThrottle = &throttlefakes.FakeInterface{}
+			fakeLog = &logfakes.FakeProdInterface{}
+			authClient := auth.NewClient(basic.NewAuth(), &tokenfakes.FakeInterface{}, nil)
+			basic.SetupAuthMiddleware(authClient, fakeStore, fakeRoles, fakeThrottle, fakeLog)
+
+			reached = false
+			req = httptest.NewRequest("GET", "/", nil)
+			recorder = httptest.NewRecorder()
+		})
+
+		JustBeforeEach(func() {
+			basic.AuthMiddleware(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				reached = true
+				reachedReq = req
+			})).ServeHTTP(recorder, req)
+		})
+
+		Context("when no basic auth header is sent", func() {
+			It("should respond 401 without consulting the store", func() {
+				Expect(recorder.Code).To(Equal(http.StatusUnauthorized))
+				Expect(recorder.Header().Get("WWW-Authenticate")).To(ContainSubstring("Basic"))
+				Expect(fakeStore.VerifyCallCount()).To(Equal(0))
+				Expect(reached).To(BeFalse())
+			})
+		})
+
+		Context("when the store verifies the credentials", func() {
+			BeforeEach(func() {
+				req.SetBasicAuth("tony", "house")
+				fakeStore.VerifyReturns(true, nil)
+			})
+
+			It("should pass the credentials to the store and call the next handler", func() {
+				_, username, password := fakeStore.VerifyArgsForCall(0)
+				Expect(username).To(Equal("tony"))
+				Expect(password).To(Equal("house"))
+				Expect(reached).To(BeTrue())
+			})
+
+			It("should clear the username's failed attempts", func() {
+				_, keys := fakeThrottle.SucceedArgsForCall(0)
+				Expect(keys).To(Equal([]string{"user:tony"}))
+			})
+
+			It("should store the permissions of the credential's roles as claims", func() {
+				_, username := fakeRoles.CredentialPermissionsArgsForCall(0)
+				Expect(username).To(Equal("tony"))
+				Expect(request.RetreiveBasicUser(reachedReq.Context())).To(Equal("tony"))
+				claims, ok := request.RetreiveClaims(reachedReq.Context())
+				Expect(ok).To(BeTrue())
+				Expect(claims.HasScope(permission.IdentityRead)).To(BeFalse())
+				Expect(claims.Subject).To(BeEmpty())
+			})
+
+			Context("and the credential holds a role", func() {
+				BeforeEach(func() {
+					fakeRoles.CredentialPermissionsReturns([]string{permission.IdentityRead}, nil)
+				})
+
+				It("should grant its permissions", func() {
+					claims, _ := request.RetreiveClaims(reachedReq.Context())
+					Expect(claims.HasScope(permission.IdentityRead)).To(BeTrue())
+				})
+			})
+
+			Context("and the role store errors", func() {
+				BeforeEach(func() {
+					fakeRoles.CredentialPermissionsReturns(nil, errors.New("db down"))
+				})
+
+				It("should respond 500", func() {
+					Expect(recorder.Code).To(Equal(http.StatusInternalServerError))
+					Expect(reached).To(BeFalse())
+				})
+			})
+		})
+
+		Context("when the store rejects the credentials", func() {
+			BeforeEach(func() {
+				req.SetBasicAuth("tony", "house1")
+				fakeStore.VerifyReturns(false, nil)
+			})
+
+			It("should respond 401", func() {
+				Expect(recorder.Code).To(Equal(http.StatusUnauthorized))
+				Expect(reached).To(BeFalse())
+			})
+
+			It("should record a failure for the username and the client IP", func() {
+				_, keys := fakeThrottle.FailArgsForCall(0)
+				Expect(keys).To(Equal([]string{"user:tony", "ip:192.0.2.1"}))
+			})
+		})
+
+		Context("when the username or IP is throttled", func() {
+			BeforeEach(func() {
+				req.SetBasicAuth("tony", "house")
+				fakeThrottle.CheckReturns(90*time.Second, nil)
+			})
+
+			It("should respond 429 without consulting the store", func() {
+				Expect(recorder.Code).To(Equal(http.StatusTooManyRequests))
+				Expect(recorder.Header().Get("Retry-After")).To(Equal("90"))
+				Expect(fakeStore.VerifyCallCount()).To(Equal(0))
+				Expect(reached).To(BeFalse())
+			})
+		})
+
+		Context("when the store errors", func() {
+			BeforeEach(func() {
+				req.SetBasicAuth("tony", "house")
+				fakeStore.VerifyReturns(false, errors.New("db down"))
+			})
+
+			It("should respond 500 and log the error", func() {
+				Expect(recorder.Code).To(Equal(http.StatusInternalServerError))
+				Expect(fakeLog.ErrorCallCount()).To(Equal(1))
+				Expect(reached).To(BeFalse())
+			})
+		})
+	})
 })
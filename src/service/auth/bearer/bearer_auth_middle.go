@@ -0,0 +1,83 @@
+package bearer
+
+import (
+	"fmt"
+	"net/http"
+	"service/auth"
+	"service/handlers/request"
+	"service/log"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+//Realm ... the realm advertised in WWW-Authenticate challenges.
+const Realm = "Restricted"
+
+//RFC 6750 section 3.1 error codes.
+const (
+	ErrorInvalidRequest    = "invalid_request"
+	ErrorInvalidToken      = "invalid_token"
+	ErrorInsufficientScope = "insufficient_scope"
+)
+
+//AuthMiddleware ... validates the bearer token and stores its claims in the request
+//context, see request.RetreiveClaims.
+func AuthMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		claims, err := authClient.ValidateBearer(req)
+		switch err {
+		case nil:
+			next.ServeHTTP(w, req.WithContext(request.WithClaims(req.Context(), claims)))
+		case auth.ErrMissingToken:
+			//No credentials at all get a bare challenge, RFC 6750 section 3.1.
+			Challenge(w, http.StatusUnauthorized, "", "", "")
+		case auth.ErrMalformedToken:
+			Challenge(w, http.StatusBadRequest, ErrorInvalidRequest,
+				"the Authorization header is malformed", "")
+		case auth.ErrInvalidToken:
+			Challenge(w, http.StatusUnauthorized, ErrorInvalidToken,
+				"the access token is invalid or expired", "")
+		case auth.ErrRevokedToken:
+			Challenge(w, http.StatusUnauthorized, ErrorInvalidToken,
+				"the access token has been revoked", "")
+		default:
+			logClient.Error("bearer::AuthMiddleware",
+				zap.String("requestID", request.RetreiveRequestID(req.Context())),
+				zap.Error(err))
+			http.Error(w, http.StatusText(http.StatusInternalServerError),
+				http.StatusInternalServerError)
+		}
+	})
+}
+
+//Challenge ... responds with status and an RFC 6750 WWW-Authenticate header.
+func Challenge(w http.ResponseWriter, status int, errorCode, description, scope string) {
+	w.Header().Add("WWW-Authenticate", ChallengeHeader(errorCode, description, scope))
+	http.Error(w, http.StatusText(status), status)
+}
+
+//ChallengeHeader ... builds an RFC 6750 WWW-Authenticate value. Empty errorCode,
+//description and scope are left out of the challenge.
+func ChallengeHeader(errorCode, description, scope string) string {
+	params := []string{fmt.Sprintf("realm=%q", Realm)}
+	if errorCode != "" {
+		params = append(params, fmt.Sprintf("error=%q", errorCode))
+	}
+	if description != "" {
+		params = append(params, fmt.Sprintf("error_description=%q", description))
+	}
+	if scope != "" {
+		params = append(params, fmt.Sprintf("scope=%q", scope))
+	}
+	return "Bearer " + strings.Join(params, ", ")
+}
+
+var authClient *auth.Client
+var logClient log.ProdInterface
+
+//SetupAuthMiddleware ... attaches a configured authClient
+func SetupAuthMiddleware(auth *auth.Client, log log.ProdInterface) {
+	authClient = auth
+	logClient = log
+}
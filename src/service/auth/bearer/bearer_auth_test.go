@@ -0,0 +1,146 @@
+package bearer_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"service/auth"
+	"service/auth/basic"
+	"service/auth/bearer"
+	"service/auth/revocation/revocationfakes"
+	"service/auth/token/jwt"
+	"service/auth/token/tokenfakes"
+	"service/handlers/request"
+	"service/log/logfakes"
+	"time"
+
+	jwtGo "github.com/dgrijalva/jwt-go"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Bearer Auth Specs", func() {
+	var (
+		fakeToken   *tokenfakes.FakeInterface
+		fakeRevoked *revocationfakes.FakeStore
+		fakeLog     *logfakes.FakeProdInterface
+		req         *http.Request
+		recorder    *httptest.ResponseRecorder
+		subject     string
+		reached     bool
+	)
+
+	BeforeEach(func() {
+		fakeToken = &tokenfakes.FakeInterface{}
+		fakeRevoked = &revocationfakes.FakeStore{}
+		fakeLog = &logfakes.FakeProdInterface{}
+		authClient := auth.NewClient(basic.NewAuth(), fakeToken, fakeRevoked)
+		bearer.SetupAuthMiddleware(authClient, fakeLog)
+
+		fakeToken.ValidateTokenReturns(&jwtGo.Token{
+			Claims: &jwt.IdentityClaims{
+				StandardClaims: jwtGo.StandardClaims{
+					Id:        "test-jti",
+					Subject:   "test_id",
+					ExpiresAt: time.Now().Add(time.Minute).Unix(),
+				},
+			},
+		}, true, nil)
+
+		reached = false
+		subject = ""
+		req = httptest.NewRequest("GET", "/", nil)
+		recorder = httptest.NewRecorder()
+	})
+
+	JustBeforeEach(func() {
+		bearer.AuthMiddleware(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			reached = true
+			subject = request.RetreiveSubject(req.Context())
+		})).ServeHTTP(recorder, req)
+	})
+
+	Context("when a valid bearer token is sent", func() {
+		BeforeEach(func() {
+			req.Header.Set("Authorization", "Bearer test-token")
+		})
+
+		It("should put the verified claims in the request context", func() {
+			Expect(reached).To(BeTrue())
+			Expect(subject).To(Equal("test_id"))
+			Expect(fakeToken.ValidateTokenArgsForCall(0)).To(Equal("test-token"))
+		})
+	})
+
+	Context("when the scheme is lower case", func() {
+		BeforeEach(func() {
+			req.Header.Set("Authorization", "bearer test-token")
+		})
+
+		It("should still be accepted", func() {
+			Expect(reached).To(BeTrue())
+		})
+	})
+
+	Context("when no token is sent", func() {
+		It("should challenge without an error code", func() {
+			Expect(reached).To(BeFalse())
+			Expect(recorder.Code).To(Equal(http.StatusUnauthorized))
+			Expect(recorder.Header().Get("WWW-Authenticate")).To(Equal(`Bearer realm="Restricted"`))
+			Expect(fakeToken.ValidateTokenCallCount()).To(Equal(0))
+		})
+	})
+
+	Context("when the Authorization header is malformed", func() {
+		BeforeEach(func() {
+			req.Header.Set("Authorization", "Bearer")
+		})
+
+		It("should respond 400 invalid_request", func() {
+			Expect(reached).To(BeFalse())
+			Expect(recorder.Code).To(Equal(http.StatusBadRequest))
+			Expect(recorder.Header().Get("WWW-Authenticate")).To(ContainSubstring(`error="invalid_request"`))
+		})
+	})
+
+	Context("when the token is invalid", func() {
+		BeforeEach(func() {
+			req.Header.Set("Authorization", "Bearer test-token")
+			fakeToken.ValidateTokenReturns(nil, false, errors.New("token is expired"))
+		})
+
+		It("should respond 401 invalid_token", func() {
+			Expect(reached).To(BeFalse())
+			Expect(recorder.Code).To(Equal(http.StatusUnauthorized))
+			Expect(recorder.Header().Get("WWW-Authenticate")).To(Equal(
+				`Bearer realm="Restricted", error="invalid_token", error_description="the access token is invalid or expired"`))
+		})
+	})
+
+	Context("when the token is revoked", func() {
+		BeforeEach(func() {
+			req.Header.Set("Authorization", "Bearer test-token")
+			fakeRevoked.IsRevokedReturns(true, nil)
+		})
+
+		It("should respond 401 invalid_token", func() {
+			Expect(reached).To(BeFalse())
+			Expect(recorder.Code).To(Equal(http.StatusUnauthorized))
+			Expect(recorder.Header().Get("WWW-Authenticate")).To(ContainSubstring("revoked"))
+		})
+	})
+
+	Context("when the revocation list fails", func() {
+		BeforeEach(func() {
+			req.Header.Set("Authorization", "Bearer test-token")
+			fakeRevoked.IsRevokedReturns(false, errors.New("db down"))
+		})
+
+		It("should respond 500 and log the error", func() {
+			Expect(reached).To(BeFalse())
+			Expect(recorder.Code).To(Equal(http.StatusInternalServerError))
+			Expect(fakeLog.ErrorCallCount()).To(Equal(1))
+		})
+	})
+})
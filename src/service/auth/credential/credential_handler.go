@@ -0,0 +1,165 @@
+package credential
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"service/handlers/loggederror"
+	"service/log"
+
+	"github.com/go-chi/chi"
+	"go.uber.org/zap"
+)
+
+//HandlerObject ... holds elementals for managing credentials over http.
+type HandlerObject struct {
+	Log   log.ProdInterface
+	Store Store
+}
+
+//NewHandlerObject ... returns a pointer to a new credential HandlerObject.
+func NewHandlerObject(logClient log.ProdInterface, store Store) *HandlerObject {
+	return &HandlerObject{
+		Log:   logClient,
+		Store: store,
+	}
+}
+
+type credentialPostBody struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+type credentialResponse struct {
+	Status   int    `json:"status"`
+	Username string `json:"username"`
+}
+
+//AddCredential ... creates a new credential from a username and password in the POST body.
+func (h *HandlerObject) AddCredential(w http.ResponseWriter, req *http.Request) {
+	jsonDoc, ok := h.readBody("AddCredential", w, req)
+	if !ok {
+		return
+	}
+	if jsonDoc.Username == "" || jsonDoc.Password == "" {
+		h.softError(http.StatusBadRequest, "missing required credential params",
+			"AddCredential", w, req)
+		return
+	}
+	err := h.Store.Add(req.Context(), jsonDoc.Username, jsonDoc.Password)
+	if err == ErrExists {
+		h.softError(http.StatusConflict, err.Error(), "AddCredential", w, req)
+		return
+	}
+	if err != nil {
+		h.internalServerError(err, "AddCredential", w, req)
+		return
+	}
+	h.respond(http.StatusCreated, jsonDoc.Username, "AddCredential", w, req)
+}
+
+//RotateCredential ... replaces the password of the credential named in the path.
+func (h *HandlerObject) RotateCredential(w http.ResponseWriter, req *http.Request) {
+	username := chi.URLParam(req, "username")
+	jsonDoc, ok := h.readBody("RotateCredential", w, req)
+	if !ok {
+		return
+	}
+	if username == "" || jsonDoc.Password == "" {
+		h.softError(http.StatusBadRequest, "missing required credential params",
+			"RotateCredential", w, req)
+		return
+	}
+	err := h.Store.Rotate(req.Context(), username, jsonDoc.Password)
+	if err == ErrNotFound {
+		h.softError(http.StatusNotFound, err.Error(), "RotateCredential", w, req)
+		return
+	}
+	if err != nil {
+		h.internalServerError(err, "RotateCredential", w, req)
+		return
+	}
+	h.respond(http.StatusOK, username, "RotateCredential", w, req)
+}
+
+//DisableCredential ... disables the credential named in the path.
+func (h *HandlerObject) DisableCredential(w http.ResponseWriter, req *http.Request) {
+	username := chi.URLParam(req, "username")
+	if username == "" {
+		h.softError(http.StatusBadRequest, "missing required credential params",
+			"DisableCredential", w, req)
+		return
+	}
+	err := h.Store.Disable(req.Context(), username)
+	if err == ErrNotFound {
+		h.softError(http.StatusNotFound, err.Error(), "DisableCredential", w, req)
+		return
+	}
+	if err != nil {
+		h.internalServerError(err, "DisableCredential", w, req)
+		return
+	}
+	h.respond(http.StatusOK, username, "DisableCredential", w, req)
+}
+
+func (h *HandlerObject) readBody(source string, w http.ResponseWriter,
+	req *http.Request) (credentialPostBody, bool) {
+	var jsonDoc credentialPostBody
+	if req.Body == nil {
+		h.softError(http.StatusBadRequest, "bad request", source, w, req)
+		return jsonDoc, false
+	}
+	body, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		h.internalServerError(err, source, w, req)
+		return jsonDoc, false
+	}
+	if jsonErr := json.Unmarshal(body, &jsonDoc); jsonErr != nil {
+		h.softError(http.StatusBadRequest, "bad request", source, w, req)
+		return jsonDoc, false
+	}
+	return jsonDoc, true
+}
+
+func (h *HandlerObject) respond(status int, username, source string, w http.ResponseWriter,
+	req *http.Request) {
+	bytesArray, marshalErr := json.Marshal(&credentialResponse{
+		Status:   status,
+		Username: username,
+	})
+	if marshalErr != nil {
+		h.internalServerError(marshalErr, source, w, req)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if _, writeErr := w.Write(bytesArray); writeErr != nil {
+		h.Log.Error("credential_handler::"+source, zap.Error(writeErr))
+	}
+}
+
+// internalServerError is used to wrap our loggederror for this route.
+func (h *HandlerObject) internalServerError(err error, source string, w http.ResponseWriter,
+	req *http.Request) {
+	loggederror.RespondWithProperErrorAndLogIt(
+		h.Log,
+		http.StatusInternalServerError,
+		err,
+		"credential_handler::"+source,
+		w,
+		req,
+	)
+}
+
+// softError is used to wrap our loggederror for expected failures on this route.
+func (h *HandlerObject) softError(status int, message, source string, w http.ResponseWriter,
+	req *http.Request) {
+	loggederror.RespondWithWithExpectedSoftError(
+		h.Log,
+		status,
+		message,
+		"credential_handler::"+source,
+		w,
+		req,
+	)
+}
@@ -0,0 +1,95 @@
+package credential_test
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"service/auth/credential"
+	"service/auth/credential/credentialfakes"
+	"service/log/logfakes"
+
+	"github.com/go-chi/chi"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Credential Handler Specs", func() {
+	var (
+		handler   *credential.HandlerObject
+		fakeStore *credentialfakes.FakeStore
+		fakeLog   *logfakes.FakeProdInterface
+		router    *chi.Mux
+		recorder  *httptest.ResponseRecorder
+	)
+
+	BeforeEach(func() {
+		fakeStore = &credentialfakes.FakeStore{}
+		fakeLog = &logfakes.FakeProdInterface{}
+		handler = credential.NewHandlerObject(fakeLog, fakeStore)
+		router = chi.NewRouter()
+		router.Post("/credentials", handler.AddCredential)
+		router.Put("/credentials/{username}", handler.RotateCredential)
+		router.Delete("/credentials/{username}", handler.DisableCredential)
+		recorder = httptest.NewRecorder()
+	})
+
+	serve := func(method, path, body string) {
+		request := httptest.NewRequest(method, path, bytes.NewBufferString(body))
+		router.ServeHTTP(recorder, request)
+	}
+
+	Context("POST /credentials", func() {
+		It("should add the credential and respond 201", func() {
+			serve("POST", "/credentials", `{"username": "tony", "password": "house"}`)
+			Expect(recorder.Code).To(Equal(http.StatusCreated))
+			_, username, password := fakeStore.AddArgsForCall(0)
+			Expect(username).To(Equal("tony"))
+			Expect(password).To(Equal("house"))
+		})
+
+		It("should respond 400 when the password is missing", func() {
+			serve("POST", "/credentials", `{"username": "tony"}`)
+			Expect(recorder.Code).To(Equal(http.StatusBadRequest))
+			Expect(fakeStore.AddCallCount()).To(Equal(0))
+		})
+
+		It("should respond 409 when the username is taken", func() {
+			fakeStore.AddReturns(credential.ErrExists)
+			serve("POST", "/credentials", `{"username": "tony", "password": "house"}`)
+			Expect(recorder.Code).To(Equal(http.StatusConflict))
+		})
+
+		It("should respond 500 and log when the store errors", func() {
+			fakeStore.AddReturns(errors.New("db down"))
+			serve("POST", "/credentials", `{"username": "tony", "password": "house"}`)
+			Expect(recorder.Code).To(Equal(http.StatusInternalServerError))
+			Expect(fakeLog.ErrorCallCount()).To(Equal(1))
+		})
+	})
+
+	Context("PUT /credentials/{username}", func() {
+		It("should rotate the password of the named credential", func() {
+			serve("PUT", "/credentials/tony", `{"password": "garage"}`)
+			Expect(recorder.Code).To(Equal(http.StatusOK))
+			_, username, password := fakeStore.RotateArgsForCall(0)
+			Expect(username).To(Equal("tony"))
+			Expect(password).To(Equal("garage"))
+		})
+
+		It("should respond 404 for an unknown credential", func() {
+			fakeStore.RotateReturns(credential.ErrNotFound)
+			serve("PUT", "/credentials/adam", `{"password": "garage"}`)
+			Expect(recorder.Code).To(Equal(http.StatusNotFound))
+		})
+	})
+
+	Context("DELETE /credentials/{username}", func() {
+		It("should disable the named credential", func() {
+			serve("DELETE", "/credentials/tony", "")
+			Expect(recorder.Code).To(Equal(http.StatusOK))
+			_, username := fakeStore.DisableArgsForCall(0)
+			Expect(username).To(Equal("tony"))
+		})
+	})
+})
@@ -0,0 +1,45 @@
+package credential
+
+import (
+	"context"
+	"errors"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+//ErrNotFound ... is returned when a credential does not exist for a username.
+var ErrNotFound = errors.New("credential not found")
+
+//ErrExists ... is returned when adding a credential for a username already in use.
+var ErrExists = errors.New("credential already exists")
+
+//Store ... defines a backing store for basic auth credentials.
+//go:generate counterfeiter . Store
+type Store interface {
+	Verify(ctx context.Context, username, password string) (bool, error)
+	Add(ctx context.Context, username, password string) error
+	Disable(ctx context.Context, username string) error
+	Rotate(ctx context.Context, username, password string) error
+}
+
+//dummyHash is compared against when a username is unknown so that a miss takes
+//the same amount of time as a hit.
+var dummyHash, _ = bcrypt.GenerateFromPassword([]byte("dummy-password"), bcrypt.DefaultCost)
+
+//HashPassword ... salts and hashes a plaintext password with bcrypt.
+func HashPassword(password string) ([]byte, error) {
+	if password == "" {
+		return nil, errors.New("cannot hash an empty password")
+	}
+	return bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+}
+
+//ComparePassword ... compares a stored hash against a plaintext password in
+//constant time. A nil hash is compared against a dummy hash and always fails.
+func ComparePassword(hash []byte, password string) bool {
+	if len(hash) == 0 {
+		_ = bcrypt.CompareHashAndPassword(dummyHash, []byte(password))
+		return false
+	}
+	return bcrypt.CompareHashAndPassword(hash, []byte(password)) == nil
+}
@@ -0,0 +1,79 @@
+package credential
+
+import (
+	"context"
+	"sync"
+)
+
+type memoryCredential struct {
+	hash     []byte
+	disabled bool
+}
+
+//MemoryStore ... is an in-memory Store, used for tests and local development.
+type MemoryStore struct {
+	mutex       sync.RWMutex
+	credentials map[string]*memoryCredential
+}
+
+//NewMemoryStore ... returns a pointer to a new empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		credentials: make(map[string]*memoryCredential),
+	}
+}
+
+//Verify ... checks a username and password against the stored hash.
+func (m *MemoryStore) Verify(ctx context.Context, username, password string) (bool, error) {
+	m.mutex.RLock()
+	cred, ok := m.credentials[username]
+	m.mutex.RUnlock()
+	if !ok {
+		return ComparePassword(nil, password), nil
+	}
+	matches := ComparePassword(cred.hash, password)
+	return matches && !cred.disabled, nil
+}
+
+//Add ... stores a new credential for username.
+func (m *MemoryStore) Add(ctx context.Context, username, password string) error {
+	hash, err := HashPassword(password)
+	if err != nil {
+		return err
+	}
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if _, ok := m.credentials[username]; ok {
+		return ErrExists
+	}
+	m.credentials[username] = &memoryCredential{hash: hash}
+	return nil
+}
+
+//Disable ... prevents a credential from verifying without removing it.
+func (m *MemoryStore) Disable(ctx context.Context, username string) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	cred, ok := m.credentials[username]
+	if !ok {
+		return ErrNotFound
+	}
+	cred.disabled = true
+	return nil
+}
+
+//Rotate ... replaces the password for an existing credential.
+func (m *MemoryStore) Rotate(ctx context.Context, username, password string) error {
+	hash, err := HashPassword(password)
+	if err != nil {
+		return err
+	}
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	cred, ok := m.credentials[username]
+	if !ok {
+		return ErrNotFound
+	}
+	cred.hash = hash
+	return nil
+}
@@ -0,0 +1,66 @@
+package credential_test
+
+import (
+	"context"
+	"service/auth/credential"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Memory Store Specs", func() {
+	ctx := context.Background()
+	var store *credential.MemoryStore
+
+	BeforeEach(func() {
+		store = credential.NewMemoryStore()
+		Expect(store.Add(ctx, "tony", "house")).To(Succeed())
+	})
+
+	Context("Verify", func() {
+		It("should accept the stored password", func() {
+			Expect(store.Verify(ctx, "tony", "house")).To(BeTrue())
+		})
+
+		It("should reject a wrong password", func() {
+			Expect(store.Verify(ctx, "tony", "house1")).To(BeFalse())
+		})
+
+		It("should reject an unknown username", func() {
+			Expect(store.Verify(ctx, "adam", "house")).To(BeFalse())
+		})
+	})
+
+	Context("Add", func() {
+		It("should refuse a duplicate username", func() {
+			Expect(store.Add(ctx, "tony", "other")).To(MatchError(credential.ErrExists))
+		})
+
+		It("should refuse an empty password", func() {
+			Expect(store.Add(ctx, "adam", "")).ToNot(Succeed())
+		})
+	})
+
+	Context("Disable", func() {
+		It("should stop a credential from verifying", func() {
+			Expect(store.Disable(ctx, "tony")).To(Succeed())
+			Expect(store.Verify(ctx, "tony", "house")).To(BeFalse())
+		})
+
+		It("should return ErrNotFound for an unknown username", func() {
+			Expect(store.Disable(ctx, "adam")).To(MatchError(credential.ErrNotFound))
+		})
+	})
+
+	Context("Rotate", func() {
+		It("should replace the password", func() {
+			Expect(store.Rotate(ctx, "tony", "garage")).To(Succeed())
+			Expect(store.Verify(ctx, "tony", "house")).To(BeFalse())
+			Expect(store.Verify(ctx, "tony", "garage")).To(BeTrue())
+		})
+
+		It("should return ErrNotFound for an unknown username", func() {
+			Expect(store.Rotate(ctx, "adam", "garage")).To(MatchError(credential.ErrNotFound))
+		})
+	})
+})
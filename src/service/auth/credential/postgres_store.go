@@ -0,0 +1,95 @@
+package credential
+
+import (
+	"context"
+	"database/sql"
+	"service/database"
+	"time"
+)
+
+//PostgresStore ... is a Store backed by the credential table.
+type PostgresStore struct {
+	db database.DBInterface
+}
+
+//NewPostgresStore ... returns a pointer to a new PostgresStore using the passed in db.
+func NewPostgresStore(db database.DBInterface) *PostgresStore {
+	return &PostgresStore{
+		db: db,
+	}
+}
+
+//Verify ... checks a username and password against the stored hash.
+func (p *PostgresStore) Verify(ctx context.Context, username, password string) (bool, error) {
+	var hash []byte
+	var disabled bool
+	err := p.db.QueryRowContext(ctx,
+		"SELECT password_hash, disabled FROM credential WHERE username = $1;",
+		username).Scan(&hash, &disabled)
+	if err == sql.ErrNoRows {
+		return ComparePassword(nil, password), nil
+	}
+	if err != nil {
+		return false, err
+	}
+	matches := ComparePassword(hash, password)
+	return matches && !disabled, nil
+}
+
+//Add ... inserts a new credential for username.
+func (p *PostgresStore) Add(ctx context.Context, username, password string) error {
+	hash, err := HashPassword(password)
+	if err != nil {
+		return err
+	}
+	rightNow := time.Now()
+	result, err := p.db.ExecContext(ctx, `INSERT INTO credential
+		(username, password_hash, disabled, created_at, updated_at) VALUES
+		($1, $2, false, $3, $4)
+		ON CONFLICT (username) DO NOTHING;`,
+		username,
+		hash,
+		rightNow,
+		rightNow)
+	if err != nil {
+		return err
+	}
+	return expectOneRow(result, ErrExists)
+}
+
+//Disable ... flags a credential as disabled so it no longer verifies.
+func (p *PostgresStore) Disable(ctx context.Context, username string) error {
+	result, err := p.db.ExecContext(ctx,
+		"UPDATE credential SET disabled = true, updated_at = $2 WHERE username = $1;",
+		username, time.Now())
+	if err != nil {
+		return err
+	}
+	return expectOneRow(result, ErrNotFound)
+}
+
+//Rotate ... replaces the password hash for an existing credential.
+func (p *PostgresStore) Rotate(ctx context.Context, username, password string) error {
+	hash, err := HashPassword(password)
+	if err != nil {
+		return err
+	}
+	result, err := p.db.ExecContext(ctx,
+		"UPDATE credential SET password_hash = $2, updated_at = $3 WHERE username = $1;",
+		username, hash, time.Now())
+	if err != nil {
+		return err
+	}
+	return expectOneRow(result, ErrNotFound)
+}
+
+func expectOneRow(result sql.Result, noRowsErr error) error {
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == int64(0) {
+		return noRowsErr
+	}
+	return nil
+}
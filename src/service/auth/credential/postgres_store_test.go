@@ -0,0 +1,98 @@
+package credential_test
+
+import (
+	"context"
+	"database/sql"
+	"service/auth/credential"
+	"service/utils/sqltest"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	sqlmock "gopkg.in/DATA-DOG/go-sqlmock.v1"
+)
+
+var _ = Describe("Postgres Store Specs", func() {
+	ctx := context.Background()
+	var (
+		store  *credential.PostgresStore
+		db     *sql.DB
+		mockDB sqlmock.Sqlmock
+	)
+
+	BeforeEach(func() {
+		var sqlmockErr error
+		db, mockDB, sqlmockErr = sqlmock.New()
+		Expect(sqlmockErr).ToNot(HaveOccurred())
+		store = credential.NewPostgresStore(db)
+	})
+
+	AfterEach(func() {
+		Expect(mockDB.ExpectationsWereMet()).To(Succeed())
+	})
+
+	Context("Verify", func() {
+		var hash []byte
+
+		BeforeEach(func() {
+			var err error
+			hash, err = credential.HashPassword("house")
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should accept a matching password on an enabled credential", func() {
+			rows := sqlmock.NewRows([]string{"password_hash", "disabled"}).AddRow(hash, false)
+			mockDB.ExpectQuery("SELECT password_hash, disabled FROM credential").
+				WithArgs("tony").WillReturnRows(rows)
+			Expect(store.Verify(ctx, "tony", "house")).To(BeTrue())
+		})
+
+		It("should reject a matching password on a disabled credential", func() {
+			rows := sqlmock.NewRows([]string{"password_hash", "disabled"}).AddRow(hash, true)
+			mockDB.ExpectQuery("SELECT password_hash, disabled FROM credential").
+				WithArgs("tony").WillReturnRows(rows)
+			Expect(store.Verify(ctx, "tony", "house")).To(BeFalse())
+		})
+
+		It("should reject an unknown username without an error", func() {
+			mockDB.ExpectQuery("SELECT password_hash, disabled FROM credential").
+				WithArgs("adam").WillReturnError(sql.ErrNoRows)
+			verified, err := store.Verify(ctx, "adam", "house")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(verified).To(BeFalse())
+		})
+	})
+
+	Context("Add", func() {
+		It("should insert a hashed password", func() {
+			mockDB.ExpectExec("INSERT INTO credential").
+				WithArgs("tony", sqlmock.AnyArg(), sqltest.AnyTime{}, sqltest.AnyTime{}).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+			Expect(store.Add(ctx, "tony", "house")).To(Succeed())
+		})
+
+		It("should return ErrExists when the username is taken", func() {
+			mockDB.ExpectExec("INSERT INTO credential").
+				WithArgs("tony", sqlmock.AnyArg(), sqltest.AnyTime{}, sqltest.AnyTime{}).
+				WillReturnResult(sqlmock.NewResult(0, 0))
+			Expect(store.Add(ctx, "tony", "house")).To(MatchError(credential.ErrExists))
+		})
+	})
+
+	Context("Disable", func() {
+		It("should return ErrNotFound when no row is updated", func() {
+			mockDB.ExpectExec("UPDATE credential SET disabled = true").
+				WithArgs("adam", sqltest.AnyTime{}).
+				WillReturnResult(sqlmock.NewResult(0, 0))
+			Expect(store.Disable(ctx, "adam")).To(MatchError(credential.ErrNotFound))
+		})
+	})
+
+	Context("Rotate", func() {
+		It("should update the password hash", func() {
+			mockDB.ExpectExec("UPDATE credential SET password_hash").
+				WithArgs("tony", sqlmock.AnyArg(), sqltest.AnyTime{}).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+			Expect(store.Rotate(ctx, "tony", "garage")).To(Succeed())
+		})
+	})
+})
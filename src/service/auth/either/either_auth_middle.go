@@ -0,0 +1,53 @@
+package either
+
+import (
+	"net/http"
+	"service/auth/apikey"
+	"service/auth/basic"
+	"service/auth/bearer"
+	"service/auth/mtls"
+	"service/auth/session"
+	"service/auth/signature"
+	"strings"
+)
+
+//AuthMiddleware ... accepts basic, bearer, API key or HMAC signed request credentials,
+//dispatching on the Authorization scheme, or else a session cookie, whose unsafe requests
+//must pass the CSRF check, or a verified client certificate. Requests with none are
+//challenged for every scheme. The basic, bearer, apikey, signature, session and mtls
+//SetupAuthMiddleware funcs must all have been called.
+func AuthMiddleware(next http.Handler) http.Handler {
+	basicNext := basic.AuthMiddleware(next)
+	bearerNext := bearer.AuthMiddleware(next)
+	apiKeyNext := apikey.AuthMiddleware(next)
+	signatureNext := signature.AuthMiddleware(next)
+	sessionNext := session.AuthMiddleware(session.CSRFMiddleware(next))
+	mtlsNext := mtls.AuthMiddleware(next)
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		scheme := strings.SplitN(req.Header.Get("Authorization"), " ", 2)[0]
+		switch {
+		case strings.EqualFold(scheme, "Basic"):
+			basicNext.ServeHTTP(w, req)
+		case strings.EqualFold(scheme, "Bearer"), req.Header.Get("token") != "":
+			bearerNext.ServeHTTP(w, req)
+		case strings.EqualFold(scheme, apikey.Scheme), req.Header.Get(apikey.Header) != "":
+			apiKeyNext.ServeHTTP(w, req)
+		case strings.EqualFold(scheme, signature.Scheme):
+			signatureNext.ServeHTTP(w, req)
+		case session.HasCookie(req):
+			sessionNext.ServeHTTP(w, req)
+		case hasCertificate(req):
+			mtlsNext.ServeHTTP(w, req)
+		default:
+			w.Header().Add("WWW-Authenticate", "Basic realm=Restricted")
+			w.Header().Add("WWW-Authenticate", apikey.Scheme+` realm="`+apikey.Realm+`"`)
+			w.Header().Add("WWW-Authenticate", signature.Scheme+` realm="`+signature.Realm+`"`)
+			bearer.Challenge(w, http.StatusUnauthorized, "", "", "")
+		}
+	})
+}
+
+func hasCertificate(req *http.Request) bool {
+	_, ok := mtls.VerifiedCertificate(req)
+	return ok
+}
@@ -0,0 +1,198 @@
+package either_test
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"net/http"
+	"net/http/httptest"
+	"service/auth"
+	"service/auth/apikey"
+	"service/auth/apikey/apikeyfakes"
+	"service/auth/basic"
+	"service/auth/bearer"
+	"service/auth/credential/credentialfakes"
+	"service/auth/either"
+	"service/auth/mtls"
+	"service/auth/permission/permissionfakes"
+	"service/auth/session"
+	"service/auth/signature"
+	"service/auth/throttle/throttlefakes"
+	"service/auth/token/jwt"
+	"service/auth/token/tokenfakes"
+	"service/log/logfakes"
+	"service/utils/certtest"
+
+	jwtGo "github.com/dgrijalva/jwt-go"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Either Auth Specs", func() {
+	ctx := context.Background()
+	var (
+		fakeToken *tokenfakes.FakeInterface
+		fakeStore *credentialfakes.FakeStore
+		fakeKeys  *apikeyfakes.FakeInterface
+		certs     *mtls.MemoryStore
+		sessions  *session.Manager
+		signing   *signature.MemoryStore
+		request   *http.Request
+		recorder  *httptest.ResponseRecorder
+		reached   bool
+	)
+
+	BeforeEach(func() {
+		fakeToken = &tokenfakes.FakeInterface{}
+		fakeStore = &credentialfakes.FakeStore{}
+		fakeKeys = &apikeyfakes.FakeInterface{}
+		fakeLog := &logfakes.FakeProdInterface{}
+		authClient := auth.NewClient(basic.NewAuth(), fakeToken, nil)
+		basic.SetupAuthMiddleware(authClient, fakeStore, &permissionfakes.FakeStore{},
+			&throttlefakes.FakeInterface{}, fakeLog)
+		bearer.SetupAuthMiddleware(authClient, fakeLog)
+		apikey.SetupAuthMiddleware(apikey.NewAuth(fakeKeys), &permissionfakes.FakeStore{}, fakeLog)
+		certs = mtls.NewMemoryStore()
+		mtls.SetupAuthMiddleware(mtls.NewAuth(certs), &permissionfakes.FakeStore{}, fakeLog)
+		signing = signature.NewMemoryStore()
+		signature.SetupAuthMiddleware(signature.NewAuth(signing), &permissionfakes.FakeStore{}, fakeLog)
+		sessions = session.NewManager(fakeLog, session.NewMemoryStore(), session.Policy{})
+		session.SetupAuthMiddleware(sessions, &permissionfakes.FakeStore{}, fakeLog)
+
+		reached = false
+		request = httptest.NewRequest("GET", "/", nil)
+		recorder = httptest.NewRecorder()
+	})
+
+	JustBeforeEach(func() {
+		either.AuthMiddleware(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			reached = true
+		})).ServeHTTP(recorder, request)
+	})
+
+	Context("when basic credentials are sent", func() {
+		BeforeEach(func() {
+			request.SetBasicAuth("tony", "house")
+			fakeStore.VerifyReturns(true, nil)
+		})
+
+		It("should authenticate against the credential store", func() {
+			Expect(reached).To(BeTrue())
+			Expect(fakeStore.VerifyCallCount()).To(Equal(1))
+			Expect(fakeToken.ValidateTokenCallCount()).To(Equal(0))
+		})
+	})
+
+	Context("when a bearer token is sent", func() {
+		BeforeEach(func() {
+			request.Header.Set("Authorization", "Bearer test-token")
+			fakeToken.ValidateTokenReturns(&jwtGo.Token{Claims: &jwt.IdentityClaims{
+				StandardClaims: jwtGo.StandardClaims{Id: "test-jti"},
+			}}, true, nil)
+		})
+
+		It("should validate the token", func() {
+			Expect(reached).To(BeTrue())
+			Expect(fakeToken.ValidateTokenCallCount()).To(Equal(1))
+			Expect(fakeStore.VerifyCallCount()).To(Equal(0))
+		})
+	})
+
+	Context("when an API key is sent", func() {
+		BeforeEach(func() {
+			request.Header.Set("X-API-Key", "sk_abc123.the-secret")
+			fakeKeys.VerifyReturns(&apikey.Key{IdentityID: "test_id"}, nil)
+		})
+
+		It("should verify the key", func() {
+			Expect(reached).To(BeTrue())
+			Expect(fakeKeys.VerifyCallCount()).To(Equal(1))
+			Expect(fakeToken.ValidateTokenCallCount()).To(Equal(0))
+		})
+	})
+
+	Context("when a signed request is sent", func() {
+		BeforeEach(func() {
+			key, secret, err := signature.NewKey("test_id", "partner", []string{"events:read"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(signing.Create(ctx, key, secret)).To(Succeed())
+			Expect(signature.NewSigner(key.ID, secret).Sign(request)).To(Succeed())
+		})
+
+		It("should verify the signature", func() {
+			Expect(reached).To(BeTrue())
+			Expect(fakeToken.ValidateTokenCallCount()).To(Equal(0))
+		})
+	})
+
+	Context("when a session cookie is sent", func() {
+		var csrfToken string
+
+		BeforeEach(func() {
+			started := httptest.NewRecorder()
+			current, err := sessions.Start(started, httptest.NewRequest("POST", "/session", nil),
+				"test_id")
+			Expect(err).ToNot(HaveOccurred())
+			csrfToken = current.CSRFToken
+			for _, cookie := range started.Result().Cookies() {
+				request.AddCookie(cookie)
+			}
+		})
+
+		It("should load the session for safe methods", func() {
+			Expect(reached).To(BeTrue())
+			Expect(fakeToken.ValidateTokenCallCount()).To(Equal(0))
+		})
+
+		Context("on an unsafe method", func() {
+			BeforeEach(func() {
+				request.Method = "POST"
+			})
+
+			It("should require the CSRF token", func() {
+				Expect(reached).To(BeFalse())
+				Expect(recorder.Code).To(Equal(http.StatusForbidden))
+			})
+
+			Context("with the CSRF token", func() {
+				BeforeEach(func() {
+					request.Header.Set(session.CSRFHeader, csrfToken)
+				})
+
+				It("should pass", func() {
+					Expect(reached).To(BeTrue())
+				})
+			})
+		})
+	})
+
+	Context("when only a verified client certificate is sent", func() {
+		BeforeEach(func() {
+			ca, err := certtest.NewCA("test-ca")
+			Expect(err).ToNot(HaveOccurred())
+			leaf, err := ca.Client("reports")
+			Expect(err).ToNot(HaveOccurred())
+			request.TLS = &tls.ConnectionState{
+				VerifiedChains: [][]*x509.Certificate{{leaf.Cert, ca.Cert}},
+			}
+			_, err = certs.Bind(ctx, "test_id", "cn:reports")
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should authenticate the certificate", func() {
+			Expect(reached).To(BeTrue())
+			Expect(fakeToken.ValidateTokenCallCount()).To(Equal(0))
+		})
+	})
+
+	Context("when no credentials are sent", func() {
+		It("should challenge for every scheme", func() {
+			Expect(reached).To(BeFalse())
+			Expect(recorder.Code).To(Equal(http.StatusUnauthorized))
+			Expect(recorder.Header()["Www-Authenticate"]).To(ConsistOf(
+				"Basic realm=Restricted", `ApiKey realm="Restricted"`, `HMAC-SHA256 realm="Restricted"`,
+				`Bearer realm="Restricted"`))
+		})
+	})
+})
@@ -0,0 +1,124 @@
+package emailtoken
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"errors"
+	"service/database"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+//Token purposes, a token issued for one is never accepted for the other.
+const (
+	PurposePasswordReset = "password_reset"
+	PurposeEmailVerify   = "email_verify"
+)
+
+//Default lifetimes of each purpose.
+const (
+	DefaultResetTTL  = time.Hour
+	DefaultVerifyTTL = 48 * time.Hour
+)
+
+//ErrInvalid ... is returned for tokens that are forged, expired, used or superseded.
+var ErrInvalid = errors.New("invalid or expired token")
+
+//Claim ... is who a token was issued to.
+type Claim struct {
+	IdentityID string
+	Email      string
+}
+
+//Interface ... defines issuing and consuming single use email tokens.
+//go:generate counterfeiter . Interface
+type Interface interface {
+	Issue(ctx context.Context, purpose, identityID, email string) (string, time.Duration, error)
+	Consume(ctx context.Context, purpose, token string) (*Claim, error)
+}
+
+//Service ...
+//issues tokens reading "<id>.<expiry>.<signature>". The HMAC-SHA256 signature covers the
+//purpose too, so forged or repurposed tokens are rejected without touching the db, and
+//the email_token row makes each one single use.
+type Service struct {
+	db     database.DBInterface
+	secret []byte
+}
+
+//NewService ... returns a pointer to a new email token Service signing with secret.
+func NewService(db database.DBInterface, secret []byte) *Service {
+	return &Service{
+		db:     db,
+		secret: secret,
+	}
+}
+
+//Issue ...
+//returns a new token and how long it stays valid. Any earlier unused token of the same
+//purpose for the identity stops working.
+func (s *Service) Issue(ctx context.Context, purpose, identityID, email string) (string,
+	time.Duration, error) {
+	ttl := DefaultVerifyTTL
+	if purpose == PurposePasswordReset {
+		ttl = DefaultResetTTL
+	}
+	rightNow := time.Now()
+	_, err := s.db.ExecContext(ctx, `UPDATE email_token SET used_at = $3
+		WHERE identity_id = $1 AND purpose = $2 AND used_at IS NULL;`,
+		identityID, purpose, rightNow)
+	if err != nil {
+		return "", 0, err
+	}
+	id := uuid.New().String()
+	expiresAt := rightNow.Add(ttl)
+	_, err = s.db.ExecContext(ctx, `INSERT INTO email_token
+		(id, purpose, identity_id, email, expires_at, created_at) VALUES
+		($1, $2, $3, $4, $5, $6);`,
+		id, purpose, identityID, email, expiresAt, rightNow)
+	if err != nil {
+		return "", 0, err
+	}
+	payload := id + "." + strconv.FormatInt(expiresAt.Unix(), 10)
+	return payload + "." + s.sign(purpose, payload), ttl, nil
+}
+
+//Consume ... checks token was issued for purpose and spends it.
+func (s *Service) Consume(ctx context.Context, purpose, token string) (*Claim, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, ErrInvalid
+	}
+	payload := parts[0] + "." + parts[1]
+	if !hmac.Equal([]byte(parts[2]), []byte(s.sign(purpose, payload))) {
+		return nil, ErrInvalid
+	}
+	expiry, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil || time.Now().Unix() >= expiry {
+		return nil, ErrInvalid
+	}
+	var claim Claim
+	err = s.db.QueryRowContext(ctx, `UPDATE email_token SET used_at = $3
+		WHERE id = $1 AND purpose = $2 AND used_at IS NULL AND expires_at > $3
+		RETURNING identity_id, email;`,
+		parts[0], purpose, time.Now()).Scan(&claim.IdentityID, &claim.Email)
+	if err == sql.ErrNoRows {
+		return nil, ErrInvalid
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &claim, nil
+}
+
+func (s *Service) sign(purpose, payload string) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(purpose + "." + payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
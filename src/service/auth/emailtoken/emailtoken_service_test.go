@@ -0,0 +1,102 @@
+package emailtoken_test
+
+import (
+	"context"
+	"database/sql"
+	"service/auth/emailtoken"
+	"service/utils/sqltest"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	sqlmock "gopkg.in/DATA-DOG/go-sqlmock.v1"
+)
+
+var _ = Describe("Email Token Service Specs", func() {
+	ctx := context.Background()
+	var (
+		service *emailtoken.Service
+		db      *sql.DB
+		mockDB  sqlmock.Sqlmock
+	)
+
+	BeforeEach(func() {
+		var sqlmockErr error
+		db, mockDB, sqlmockErr = sqlmock.New()
+		Expect(sqlmockErr).ToNot(HaveOccurred())
+		service = emailtoken.NewService(db, []byte("test-secret"))
+	})
+
+	AfterEach(func() {
+		Expect(mockDB.ExpectationsWereMet()).To(Succeed())
+	})
+
+	issue := func(purpose string) string {
+		mockDB.ExpectExec("UPDATE email_token SET used_at").
+			WithArgs("test_id", purpose, sqltest.AnyTime{}).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mockDB.ExpectExec("INSERT INTO email_token").
+			WithArgs(sqltest.AnyString{}, purpose, "test_id", "tony@example.com",
+				sqltest.AnyTime{}, sqltest.AnyTime{}).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		token, _, err := service.Issue(ctx, purpose, "test_id", "tony@example.com")
+		Expect(err).ToNot(HaveOccurred())
+		return token
+	}
+
+	It("should supersede earlier tokens and use each purpose's lifetime", func() {
+		Expect(strings.Split(issue(emailtoken.PurposePasswordReset), ".")).To(HaveLen(3))
+		mockDB.ExpectExec("UPDATE email_token SET used_at").WillReturnResult(sqlmock.NewResult(0, 0))
+		mockDB.ExpectExec("INSERT INTO email_token").WillReturnResult(sqlmock.NewResult(0, 1))
+		_, ttl, err := service.Issue(ctx, emailtoken.PurposeEmailVerify, "test_id", "tony@example.com")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(ttl).To(Equal(emailtoken.DefaultVerifyTTL))
+	})
+
+	It("should spend a valid token and return its claim", func() {
+		token := issue(emailtoken.PurposePasswordReset)
+		id := strings.Split(token, ".")[0]
+		mockDB.ExpectQuery("UPDATE email_token SET used_at").
+			WithArgs(id, emailtoken.PurposePasswordReset, sqltest.AnyTime{}).
+			WillReturnRows(sqlmock.NewRows([]string{"identity_id", "email"}).
+				AddRow("test_id", "tony@example.com"))
+		claim, err := service.Consume(ctx, emailtoken.PurposePasswordReset, token)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(claim.IdentityID).To(Equal("test_id"))
+		Expect(claim.Email).To(Equal("tony@example.com"))
+	})
+
+	It("should reject a used or superseded token", func() {
+		token := issue(emailtoken.PurposePasswordReset)
+		mockDB.ExpectQuery("UPDATE email_token SET used_at").WillReturnError(sql.ErrNoRows)
+		_, err := service.Consume(ctx, emailtoken.PurposePasswordReset, token)
+		Expect(err).To(Equal(emailtoken.ErrInvalid))
+	})
+
+	It("should reject a token issued for another purpose without a query", func() {
+		token := issue(emailtoken.PurposeEmailVerify)
+		_, err := service.Consume(ctx, emailtoken.PurposePasswordReset, token)
+		Expect(err).To(Equal(emailtoken.ErrInvalid))
+	})
+
+	It("should reject tampered and malformed tokens without a query", func() {
+		token := issue(emailtoken.PurposePasswordReset)
+		parts := strings.Split(token, ".")
+		for _, bad := range []string{
+			parts[0] + ".9999999999." + parts[2],
+			"other-id." + parts[1] + "." + parts[2],
+			parts[0] + "." + parts[1],
+			"",
+		} {
+			_, err := service.Consume(ctx, emailtoken.PurposePasswordReset, bad)
+			Expect(err).To(Equal(emailtoken.ErrInvalid))
+		}
+	})
+
+	It("should reject a token signed with another secret", func() {
+		token := issue(emailtoken.PurposePasswordReset)
+		other := emailtoken.NewService(db, []byte("other-secret"))
+		_, err := other.Consume(ctx, emailtoken.PurposePasswordReset, token)
+		Expect(err).To(Equal(emailtoken.ErrInvalid))
+	})
+})
@@ -0,0 +1,209 @@
+package mfa
+
+import (
+	"database/sql"
+	"encoding/json"
+	"io"
+	"net/http"
+	"service/auth/permission"
+	"service/auth/throttle"
+	"service/handlers/loggederror"
+	"service/handlers/request"
+	"service/log"
+
+	"github.com/go-chi/chi"
+	"go.uber.org/zap"
+)
+
+//HandlerObject ... holds elementals for managing an identity's second factor over http.
+type HandlerObject struct {
+	Log      log.ProdInterface
+	MFA      Interface
+	Throttle throttle.Interface
+}
+
+//NewHandlerObject ... returns a pointer to a new MFA HandlerObject.
+func NewHandlerObject(logClient log.ProdInterface, mfa Interface,
+	throttle throttle.Interface) *HandlerObject {
+	return &HandlerObject{
+		Log:      logClient,
+		MFA:      mfa,
+		Throttle: throttle,
+	}
+}
+
+type enrollResponse struct {
+	Status int `json:"status"`
+	*Enrollment
+}
+
+type confirmPostBody struct {
+	Code string `json:"code"`
+}
+
+//Enroll ...
+//POST /identity/{id}/mfa, the secret and recovery codes are only ever returned in this
+//response. MFA stays off until confirmed.
+func (h *HandlerObject) Enroll(w http.ResponseWriter, req *http.Request) {
+	enrollment, err := h.MFA.Enroll(req.Context(), chi.URLParam(req, "id"))
+	if err == ErrEnabled {
+		h.softError(http.StatusConflict, err.Error(), "Enroll", w, req)
+		return
+	}
+	if err != nil {
+		h.internalServerError(err, "Enroll", w, req)
+		return
+	}
+	h.respond(http.StatusCreated, &enrollResponse{
+		Status:     http.StatusCreated,
+		Enrollment: enrollment,
+	}, "Enroll", w, req)
+}
+
+//Confirm ... POST /identity/{id}/mfa/confirm with a current code from the authenticator.
+func (h *HandlerObject) Confirm(w http.ResponseWriter, req *http.Request) {
+	var jsonDoc confirmPostBody
+	if req.Body == nil || json.NewDecoder(req.Body).Decode(&jsonDoc) != nil {
+		h.softError(http.StatusBadRequest, "bad request", "Confirm", w, req)
+		return
+	}
+	if jsonDoc.Code == "" {
+		h.softError(http.StatusBadRequest, "missing required mfa params", "Confirm", w, req)
+		return
+	}
+	err := h.MFA.Confirm(req.Context(), chi.URLParam(req, "id"), jsonDoc.Code)
+	if err == ErrNotEnrolled {
+		h.softError(http.StatusNotFound, err.Error(), "Confirm", w, req)
+		return
+	}
+	if err == ErrInvalidCode {
+		h.softError(http.StatusBadRequest, err.Error(), "Confirm", w, req)
+		return
+	}
+	if err != nil {
+		h.internalServerError(err, "Confirm", w, req)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+//Disable ...
+//DELETE /identity/{id}/mfa, with a current code from the authenticator or a recovery code
+//once MFA is on. Admins can turn it off for an identity that lost both.
+func (h *HandlerObject) Disable(w http.ResponseWriter, req *http.Request) {
+	id := chi.URLParam(req, "id")
+	claims, hasClaims := request.RetreiveClaims(req.Context())
+	if !hasClaims || !claims.HasScope(permission.IdentityAdmin) {
+		//An empty body is fine while MFA is only pending.
+		var jsonDoc confirmPostBody
+		if req.Body != nil {
+			if err := json.NewDecoder(req.Body).Decode(&jsonDoc); err != nil && err != io.EOF {
+				h.softError(http.StatusBadRequest, "bad request", "Disable", w, req)
+				return
+			}
+		}
+		if !h.proven(id, jsonDoc.Code, w, req) {
+			return
+		}
+	}
+	err := h.MFA.Disable(req.Context(), id)
+	if err == sql.ErrNoRows {
+		h.softError(http.StatusNotFound, ErrNotEnrolled.Error(), "Disable", w, req)
+		return
+	}
+	if err != nil {
+		h.internalServerError(err, "Disable", w, req)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+//proven checks code while MFA is enabled, a pending enrollment has nothing to prove. Wrong
+//codes count against the identity and client IP like a failed VerifyMFA, so a stolen token
+//or session cannot guess its way to turning MFA off.
+func (h *HandlerObject) proven(id, code string, w http.ResponseWriter, req *http.Request) bool {
+	enabled, err := h.MFA.Enabled(req.Context(), id)
+	if err != nil {
+		h.internalServerError(err, "Disable", w, req)
+		return false
+	}
+	if !enabled {
+		return true
+	}
+	if code == "" {
+		h.softError(http.StatusForbidden, "a current code is required to disable mfa",
+			"Disable", w, req)
+		return false
+	}
+	identityKey := throttle.IdentityKey(id)
+	ipKey := throttle.IPKey(throttle.ClientIP(req))
+	wait, err := h.Throttle.Check(req.Context(), identityKey, ipKey)
+	if err != nil {
+		h.internalServerError(err, "Disable", w, req)
+		return false
+	}
+	if wait > 0 {
+		throttle.SetRetryAfter(w, wait)
+		h.softError(http.StatusTooManyRequests, throttle.ErrTooManyAttempts, "Disable", w, req)
+		return false
+	}
+	verified, err := h.MFA.Verify(req.Context(), id, code)
+	if err != nil {
+		h.internalServerError(err, "Disable", w, req)
+		return false
+	}
+	if !verified {
+		if err := h.Throttle.Fail(req.Context(), identityKey, ipKey); err != nil {
+			h.internalServerError(err, "Disable", w, req)
+			return false
+		}
+		h.softError(http.StatusForbidden, ErrInvalidCode.Error(), "Disable", w, req)
+		return false
+	}
+	if err := h.Throttle.Succeed(req.Context(), identityKey); err != nil {
+		h.internalServerError(err, "Disable", w, req)
+		return false
+	}
+	return true
+}
+
+func (h *HandlerObject) respond(status int, response interface{}, source string,
+	w http.ResponseWriter, req *http.Request) {
+	bytesArray, marshalErr := json.Marshal(response)
+	if marshalErr != nil {
+		h.internalServerError(marshalErr, source, w, req)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	if _, writeErr := w.Write(bytesArray); writeErr != nil {
+		h.Log.Error("mfa_handler::"+source, zap.Error(writeErr))
+	}
+}
+
+// internalServerError is used to wrap our loggederror for this route.
+func (h *HandlerObject) internalServerError(err error, source string, w http.ResponseWriter,
+	req *http.Request) {
+	loggederror.RespondWithProperErrorAndLogIt(
+		h.Log,
+		http.StatusInternalServerError,
+		err,
+		"mfa_handler::"+source,
+		w,
+		req,
+	)
+}
+
+// softError is used to wrap our loggederror for expected failures on this route.
+func (h *HandlerObject) softError(status int, message, source string, w http.ResponseWriter,
+	req *http.Request) {
+	loggederror.RespondWithWithExpectedSoftError(
+		h.Log,
+		status,
+		message,
+		"mfa_handler::"+source,
+		w,
+		req,
+	)
+}
@@ -0,0 +1,177 @@
+package mfa_test
+
+import (
+	"bytes"
+	"database/sql"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"service/auth/mfa"
+	"service/auth/mfa/mfafakes"
+	"service/auth/permission"
+	"service/auth/throttle/throttlefakes"
+	"service/auth/token/jwt"
+	"service/handlers/request"
+	"service/log/logfakes"
+	"time"
+
+	"github.com/go-chi/chi"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("MFA Handler Specs", func() {
+	var (
+		handler      *mfa.HandlerObject
+		fakeMFA      *mfafakes.FakeInterface
+		fakeLog      *logfakes.FakeProdInterface
+		fakeThrottle *throttlefakes.FakeInterface
+		router       *chi.Mux
+		recorder     *httptest.ResponseRecorder
+	)
+
+	BeforeEach(func() {
+		fakeMFA = &mfafakes.FakeInterface{}
+		fakeLog = &logfakes.FakeProdInterface{}
+		fakeThrottle = &throttlefakes.FakeInterface{}
+		handler = mfa.NewHandlerObject(fakeLog, fakeMFA, fakeThrottle)
+		router = chi.NewRouter()
+		router.Post("/identity/{id}/mfa", handler.Enroll)
+		router.Post("/identity/{id}/mfa/confirm", handler.Confirm)
+		router.Delete("/identity/{id}/mfa", handler.Disable)
+		recorder = httptest.NewRecorder()
+	})
+
+	serve := func(method, path, body string) {
+		req := httptest.NewRequest(method, path, bytes.NewBufferString(body))
+		router.ServeHTTP(recorder, req)
+	}
+
+	serveAs := func(claims *jwt.IdentityClaims, method, path, body string) {
+		req := httptest.NewRequest(method, path, bytes.NewBufferString(body))
+		router.ServeHTTP(recorder, req.WithContext(request.WithClaims(req.Context(), claims)))
+	}
+
+	Context("POST /identity/{id}/mfa", func() {
+		It("should return the enrollment once and forbid caching it", func() {
+			fakeMFA.EnrollReturns(&mfa.Enrollment{
+				Secret:        "SECRET",
+				URI:           "otpauth://totp/Acme:test_id?secret=SECRET",
+				RecoveryCodes: []string{"abcde-fghij"},
+			}, nil)
+			serve("POST", "/identity/test_id/mfa", "")
+			Expect(recorder.Code).To(Equal(http.StatusCreated))
+			Expect(recorder.Header().Get("Cache-Control")).To(Equal("no-store"))
+			Expect(recorder.Body.String()).To(MatchJSON(`{"status": 201, "secret": "SECRET",
+				"uri": "otpauth://totp/Acme:test_id?secret=SECRET",
+				"recoveryCodes": ["abcde-fghij"]}`))
+			_, identityID := fakeMFA.EnrollArgsForCall(0)
+			Expect(identityID).To(Equal("test_id"))
+		})
+
+		It("should respond 409 when MFA is already enabled", func() {
+			fakeMFA.EnrollReturns(nil, mfa.ErrEnabled)
+			serve("POST", "/identity/test_id/mfa", "")
+			Expect(recorder.Code).To(Equal(http.StatusConflict))
+		})
+
+		It("should respond 500 and log when enrollment fails", func() {
+			fakeMFA.EnrollReturns(nil, errors.New("db down"))
+			serve("POST", "/identity/test_id/mfa", "")
+			Expect(recorder.Code).To(Equal(http.StatusInternalServerError))
+			Expect(fakeLog.ErrorCallCount()).To(Equal(1))
+		})
+	})
+
+	Context("POST /identity/{id}/mfa/confirm", func() {
+		It("should confirm with the code and respond 204", func() {
+			serve("POST", "/identity/test_id/mfa/confirm", `{"code": "123456"}`)
+			Expect(recorder.Code).To(Equal(http.StatusNoContent))
+			_, id, code := fakeMFA.ConfirmArgsForCall(0)
+			Expect(id).To(Equal("test_id"))
+			Expect(code).To(Equal("123456"))
+		})
+
+		It("should respond 400 without a code", func() {
+			serve("POST", "/identity/test_id/mfa/confirm", `{}`)
+			Expect(recorder.Code).To(Equal(http.StatusBadRequest))
+			Expect(fakeMFA.ConfirmCallCount()).To(Equal(0))
+		})
+
+		It("should respond 400 for a wrong code", func() {
+			fakeMFA.ConfirmReturns(mfa.ErrInvalidCode)
+			serve("POST", "/identity/test_id/mfa/confirm", `{"code": "000000"}`)
+			Expect(recorder.Code).To(Equal(http.StatusBadRequest))
+		})
+
+		It("should respond 404 without a pending enrollment", func() {
+			fakeMFA.ConfirmReturns(mfa.ErrNotEnrolled)
+			serve("POST", "/identity/test_id/mfa/confirm", `{"code": "123456"}`)
+			Expect(recorder.Code).To(Equal(http.StatusNotFound))
+		})
+	})
+
+	Context("DELETE /identity/{id}/mfa", func() {
+		It("should disable MFA and respond 204", func() {
+			serve("DELETE", "/identity/test_id/mfa", "")
+			Expect(recorder.Code).To(Equal(http.StatusNoContent))
+			_, identityID := fakeMFA.DisableArgsForCall(0)
+			Expect(identityID).To(Equal("test_id"))
+		})
+
+		It("should respond 404 when MFA was never set up", func() {
+			fakeMFA.DisableReturns(sql.ErrNoRows)
+			serve("DELETE", "/identity/test_id/mfa", "")
+			Expect(recorder.Code).To(Equal(http.StatusNotFound))
+		})
+
+		Context("when MFA is enabled", func() {
+			BeforeEach(func() {
+				fakeMFA.EnabledReturns(true, nil)
+			})
+
+			It("should respond 403 without a code", func() {
+				serve("DELETE", "/identity/test_id/mfa", "")
+				Expect(recorder.Code).To(Equal(http.StatusForbidden))
+				Expect(fakeMFA.DisableCallCount()).To(Equal(0))
+			})
+
+			It("should respond 403 for a wrong code and count it as a failure", func() {
+				serve("DELETE", "/identity/test_id/mfa", `{"code": "000000"}`)
+				Expect(recorder.Code).To(Equal(http.StatusForbidden))
+				Expect(fakeMFA.DisableCallCount()).To(Equal(0))
+				_, keys := fakeThrottle.FailArgsForCall(0)
+				Expect(keys).To(Equal([]string{"identity:test_id", "ip:192.0.2.1"}))
+			})
+
+			It("should respond 429 without checking the code once throttled", func() {
+				fakeThrottle.CheckReturns(time.Minute, nil)
+				serve("DELETE", "/identity/test_id/mfa", `{"code": "123456"}`)
+				Expect(recorder.Code).To(Equal(http.StatusTooManyRequests))
+				Expect(recorder.Header().Get("Retry-After")).To(Equal("60"))
+				Expect(fakeMFA.VerifyCallCount()).To(Equal(0))
+				Expect(fakeMFA.DisableCallCount()).To(Equal(0))
+			})
+
+			It("should disable MFA with a current code", func() {
+				fakeMFA.VerifyReturns(true, nil)
+				serve("DELETE", "/identity/test_id/mfa", `{"code": "123456"}`)
+				Expect(recorder.Code).To(Equal(http.StatusNoContent))
+				_, identityID, code := fakeMFA.VerifyArgsForCall(0)
+				Expect(identityID).To(Equal("test_id"))
+				Expect(code).To(Equal("123456"))
+				_, keys := fakeThrottle.SucceedArgsForCall(0)
+				Expect(keys).To(Equal([]string{"identity:test_id"}))
+			})
+
+			It("should let an admin disable MFA without a code", func() {
+				serveAs(&jwt.IdentityClaims{Scope: permission.IdentityAdmin},
+					"DELETE", "/identity/test_id/mfa", "")
+				Expect(recorder.Code).To(Equal(http.StatusNoContent))
+				Expect(fakeMFA.EnabledCallCount()).To(Equal(0))
+				Expect(fakeMFA.DisableCallCount()).To(Equal(1))
+				Expect(fakeThrottle.CheckCallCount()).To(Equal(0))
+			})
+		})
+	})
+})
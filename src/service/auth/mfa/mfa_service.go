@@ -0,0 +1,371 @@
+package mfa
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"service/database"
+	"service/log"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+//RecoveryCodeCount ... is how many recovery codes an enrollment hands out.
+const RecoveryCodeCount = 10
+
+//MaxChallengeAttempts ... is how many codes may be tried against one challenge token.
+const MaxChallengeAttempts = 5
+
+//DefaultChallengeTTL ... is how long a challenge token can be exchanged for a JWT.
+const DefaultChallengeTTL = 5 * time.Minute
+
+//ErrEnabled ... is returned when enrolling an identity that already has MFA turned on.
+var ErrEnabled = errors.New("mfa already enabled")
+
+//ErrNotEnrolled ... is returned when confirming an identity without a pending enrollment.
+var ErrNotEnrolled = errors.New("mfa enrollment not found")
+
+//ErrInvalidCode ... is returned for codes that are wrong, expired or already used.
+var ErrInvalidCode = errors.New("invalid mfa code")
+
+//ErrInvalidChallenge ... is returned for challenge tokens that are unknown, expired,
+//already exchanged or out of attempts.
+var ErrInvalidChallenge = errors.New("invalid mfa token")
+
+//Enrollment ... is everything an identity needs to set up its authenticator, only ever
+//returned once.
+type Enrollment struct {
+	Secret        string   `json:"secret"`
+	URI           string   `json:"uri"`
+	RecoveryCodes []string `json:"recoveryCodes"`
+}
+
+//Interface ... defines TOTP enrollment, verification and the login challenge.
+//go:generate counterfeiter . Interface
+type Interface interface {
+	Enroll(ctx context.Context, identityID string) (*Enrollment, error)
+	Confirm(ctx context.Context, identityID, code string) error
+	Enabled(ctx context.Context, identityID string) (bool, error)
+	Verify(ctx context.Context, identityID, code string) (bool, error)
+	Disable(ctx context.Context, identityID string) error
+	Challenge(ctx context.Context, identityID string) (string, error)
+	Redeem(ctx context.Context, challenge, code string) (string, error)
+}
+
+//Service ...
+//persists TOTP secrets via a DBInterface. Recovery codes and challenge tokens are only
+//stored as a sha256, each TOTP step is accepted once.
+type Service struct {
+	log    log.ProdInterface
+	db     database.DBInterface
+	issuer string
+	window int
+	ttl    time.Duration
+}
+
+//NewService ...
+//returns a pointer to a new MFA Service, issuer names the service in authenticator apps.
+func NewService(logClient log.ProdInterface, db database.DBInterface, issuer string) *Service {
+	return &Service{
+		log:    logClient,
+		db:     db,
+		issuer: issuer,
+		window: DefaultWindow,
+		ttl:    DefaultChallengeTTL,
+	}
+}
+
+//Enroll ...
+//starts or restarts enrollment with a fresh secret and recovery codes, stored in one
+//transaction so a failure never leaves a secret without its codes. MFA is not required at
+//login until the enrollment is confirmed with a code.
+func (s *Service) Enroll(ctx context.Context, identityID string) (*Enrollment, error) {
+	secret, err := GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+	codes, err := newRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	rightNow := time.Now()
+	err = database.InTx(ctx, s.db, func(tx database.DBInterface) error {
+		var enabledAt *time.Time
+		err := tx.QueryRowContext(ctx,
+			"SELECT enabled_at FROM mfa_secret WHERE identity_id = $1;",
+			identityID).Scan(&enabledAt)
+		if err != nil && err != sql.ErrNoRows {
+			return err
+		}
+		if enabledAt != nil {
+			return ErrEnabled
+		}
+		_, err = tx.ExecContext(ctx,
+			`INSERT INTO mfa_secret (identity_id, secret, last_step, created_at)
+			VALUES ($1, $2, 0, $3)
+			ON CONFLICT (identity_id) DO UPDATE SET
+				secret = $2, last_step = 0, enabled_at = NULL, created_at = $3;`,
+			identityID, secret, rightNow)
+		if err != nil {
+			return err
+		}
+		return replaceRecoveryCodes(ctx, tx, identityID, codes, rightNow)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &Enrollment{
+		Secret:        secret,
+		URI:           ProvisioningURI(s.issuer, identityID, secret),
+		RecoveryCodes: codes,
+	}, nil
+}
+
+//Confirm ... turns MFA on once the identity proves its authenticator produces valid codes.
+func (s *Service) Confirm(ctx context.Context, identityID, code string) error {
+	var secret string
+	err := s.db.QueryRowContext(ctx,
+		"SELECT secret FROM mfa_secret WHERE identity_id = $1 AND enabled_at IS NULL;",
+		identityID).Scan(&secret)
+	if err == sql.ErrNoRows {
+		return ErrNotEnrolled
+	}
+	if err != nil {
+		return err
+	}
+	rightNow := time.Now()
+	step, ok := Validate(secret, code, rightNow, s.window)
+	if !ok {
+		return ErrInvalidCode
+	}
+	_, err = s.db.ExecContext(ctx,
+		"UPDATE mfa_secret SET enabled_at = $2, last_step = $3 WHERE identity_id = $1;",
+		identityID, rightNow, step)
+	if err != nil {
+		return err
+	}
+	s.log.Info("mfa enabled",
+		zap.String("event", "auth.mfa_enabled"),
+		zap.String("identityID", identityID))
+	return nil
+}
+
+//Enabled ... reports whether identityID must present a code at login.
+func (s *Service) Enabled(ctx context.Context, identityID string) (bool, error) {
+	var enabled int
+	err := s.db.QueryRowContext(ctx,
+		"SELECT 1 FROM mfa_secret WHERE identity_id = $1 AND enabled_at IS NOT NULL;",
+		identityID).Scan(&enabled)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+//Verify ...
+//accepts a TOTP code newer than the last one used, or an unused recovery code which is
+//then spent.
+func (s *Service) Verify(ctx context.Context, identityID, code string) (bool, error) {
+	var secret string
+	var lastStep int64
+	err := s.db.QueryRowContext(ctx,
+		"SELECT secret, last_step FROM mfa_secret WHERE identity_id = $1 AND enabled_at IS NOT NULL;",
+		identityID).Scan(&secret, &lastStep)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if step, ok := Validate(secret, code, time.Now(), s.window); ok && step > lastStep {
+		//The conditional update stops two concurrent logins from both using the code.
+		result, err := s.db.ExecContext(ctx,
+			"UPDATE mfa_secret SET last_step = $2 WHERE identity_id = $1 AND last_step < $2;",
+			identityID, step)
+		if err != nil {
+			return false, err
+		}
+		return affectedOne(result)
+	}
+	normalized := normalizeRecoveryCode(code)
+	if normalized == "" {
+		return false, nil
+	}
+	result, err := s.db.ExecContext(ctx, `UPDATE mfa_recovery_code SET used_at = $3
+		WHERE identity_id = $1 AND code_hash = $2 AND used_at IS NULL;`,
+		identityID, hashSecret(normalized), time.Now())
+	if err != nil {
+		return false, err
+	}
+	used, err := affectedOne(result)
+	if used {
+		s.log.Warn("mfa recovery code used",
+			zap.String("event", "auth.mfa_recovery"),
+			zap.String("identityID", identityID))
+	}
+	return used, err
+}
+
+//Disable ... removes the identity's secret and recovery codes, sql.ErrNoRows if it had none.
+func (s *Service) Disable(ctx context.Context, identityID string) error {
+	//Both go together, MFA must never stay on without its recovery codes.
+	err := database.InTx(ctx, s.db, func(tx database.DBInterface) error {
+		_, err := tx.ExecContext(ctx,
+			"DELETE FROM mfa_recovery_code WHERE identity_id = $1;", identityID)
+		if err != nil {
+			return err
+		}
+		result, err := tx.ExecContext(ctx,
+			"DELETE FROM mfa_secret WHERE identity_id = $1;", identityID)
+		if err != nil {
+			return err
+		}
+		deleted, err := affectedOne(result)
+		if err != nil {
+			return err
+		}
+		if !deleted {
+			return sql.ErrNoRows
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	s.log.Warn("mfa disabled",
+		zap.String("event", "auth.mfa_disabled"),
+		zap.String("identityID", identityID))
+	return nil
+}
+
+//Challenge ...
+//returns a short lived token proving identityID passed its password check. It is only
+//good for exchanging with a code via Redeem.
+func (s *Service) Challenge(ctx context.Context, identityID string) (string, error) {
+	token, err := randomString(32, base64.RawURLEncoding.EncodeToString)
+	if err != nil {
+		return "", err
+	}
+	rightNow := time.Now()
+	_, err = s.db.ExecContext(ctx, `INSERT INTO mfa_challenge
+		(token_hash, identity_id, attempts, expires_at, created_at) VALUES
+		($1, $2, 0, $3, $4);`,
+		hashSecret(token),
+		identityID,
+		rightNow.Add(s.ttl),
+		rightNow)
+	if err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+//Redeem ...
+//spends a challenge token if code verifies, returning the identity ID. The ID is also
+//returned with ErrInvalidCode so callers can count the failure against the identity.
+func (s *Service) Redeem(ctx context.Context, challenge, code string) (string, error) {
+	if challenge == "" {
+		return "", ErrInvalidChallenge
+	}
+	tokenHash := hashSecret(challenge)
+	var identityID string
+	err := s.db.QueryRowContext(ctx, `UPDATE mfa_challenge SET attempts = attempts + 1
+		WHERE token_hash = $1 AND used_at IS NULL AND expires_at > $2 AND attempts < $3
+		RETURNING identity_id;`,
+		tokenHash, time.Now(), MaxChallengeAttempts).Scan(&identityID)
+	if err == sql.ErrNoRows {
+		return "", ErrInvalidChallenge
+	}
+	if err != nil {
+		return "", err
+	}
+	verified, err := s.Verify(ctx, identityID, code)
+	if err != nil {
+		return "", err
+	}
+	if !verified {
+		return identityID, ErrInvalidCode
+	}
+	result, err := s.db.ExecContext(ctx,
+		"UPDATE mfa_challenge SET used_at = $2 WHERE token_hash = $1 AND used_at IS NULL;",
+		tokenHash, time.Now())
+	if err != nil {
+		return "", err
+	}
+	spent, err := affectedOne(result)
+	if err != nil {
+		return "", err
+	}
+	if !spent {
+		return "", ErrInvalidChallenge
+	}
+	return identityID, nil
+}
+
+func newRecoveryCodes() ([]string, error) {
+	codes := make([]string, 0, RecoveryCodeCount)
+	for i := 0; i < RecoveryCodeCount; i++ {
+		//8 random bytes encode to 13 base32 characters, 10 of them keep 50 bits.
+		raw, err := randomString(8, encoding.EncodeToString)
+		if err != nil {
+			return nil, err
+		}
+		codes = append(codes, strings.ToLower(raw[:5]+"-"+raw[5:10]))
+	}
+	return codes, nil
+}
+
+func replaceRecoveryCodes(ctx context.Context, tx database.DBInterface, identityID string,
+	codes []string, rightNow time.Time) error {
+	_, err := tx.ExecContext(ctx,
+		"DELETE FROM mfa_recovery_code WHERE identity_id = $1;", identityID)
+	if err != nil {
+		return err
+	}
+	for _, code := range codes {
+		_, err = tx.ExecContext(ctx,
+			`INSERT INTO mfa_recovery_code (identity_id, code_hash, created_at)
+			VALUES ($1, $2, $3);`,
+			identityID, hashSecret(normalizeRecoveryCode(code)), rightNow)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//normalizeRecoveryCode lets users type codes with or without the dash and in any case.
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToLower(strings.TrimSpace(code))
+	return strings.Replace(code, "-", "", -1)
+}
+
+func affectedOne(result sql.Result) (bool, error) {
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected == 1, nil
+}
+
+func randomString(size int, encode func([]byte) string) (string, error) {
+	raw := make([]byte, size)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+	return encode(raw), nil
+}
+
+func hashSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,245 @@
+package mfa_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"service/auth/mfa"
+	"service/log/logfakes"
+	"service/utils/sqltest"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	sqlmock "gopkg.in/DATA-DOG/go-sqlmock.v1"
+)
+
+var _ = Describe("MFA Service Specs", func() {
+	ctx := context.Background()
+	const secret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+	var (
+		service *mfa.Service
+		fakeLog *logfakes.FakeProdInterface
+		db      *sql.DB
+		mockDB  sqlmock.Sqlmock
+	)
+
+	hash := func(value string) string {
+		sum := sha256.Sum256([]byte(value))
+		return hex.EncodeToString(sum[:])
+	}
+
+	currentCode := func() (string, int64) {
+		step := mfa.Step(time.Now())
+		code, err := mfa.Code(secret, step)
+		Expect(err).ToNot(HaveOccurred())
+		return code, step
+	}
+
+	BeforeEach(func() {
+		var sqlmockErr error
+		db, mockDB, sqlmockErr = sqlmock.New()
+		Expect(sqlmockErr).ToNot(HaveOccurred())
+		fakeLog = &logfakes.FakeProdInterface{}
+		service = mfa.NewService(fakeLog, db, "Acme")
+	})
+
+	AfterEach(func() {
+		Expect(mockDB.ExpectationsWereMet()).To(Succeed())
+	})
+
+	Context("Enroll", func() {
+		It("should store a new secret and hashed recovery codes in one transaction", func() {
+			mockDB.ExpectBegin()
+			mockDB.ExpectQuery("SELECT enabled_at FROM mfa_secret").WithArgs("test_id").
+				WillReturnError(sql.ErrNoRows)
+			mockDB.ExpectExec("INSERT INTO mfa_secret").
+				WithArgs("test_id", sqltest.AnyString{}, sqltest.AnyTime{}).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+			mockDB.ExpectExec("DELETE FROM mfa_recovery_code").WithArgs("test_id").
+				WillReturnResult(sqlmock.NewResult(0, 0))
+			for i := 0; i < mfa.RecoveryCodeCount; i++ {
+				mockDB.ExpectExec("INSERT INTO mfa_recovery_code").
+					WithArgs("test_id", sqltest.AnyString{}, sqltest.AnyTime{}).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			}
+			mockDB.ExpectCommit()
+
+			enrollment, err := service.Enroll(ctx, "test_id")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(enrollment.URI).To(HavePrefix("otpauth://totp/Acme:test_id?"))
+			Expect(enrollment.URI).To(ContainSubstring("secret=" + enrollment.Secret))
+			Expect(enrollment.RecoveryCodes).To(HaveLen(mfa.RecoveryCodeCount))
+			Expect(enrollment.RecoveryCodes[0]).To(MatchRegexp(`^[a-z2-7]{5}-[a-z2-7]{5}$`))
+		})
+
+		It("should roll back the secret when the recovery codes cannot be stored", func() {
+			mockDB.ExpectBegin()
+			mockDB.ExpectQuery("SELECT enabled_at FROM mfa_secret").WithArgs("test_id").
+				WillReturnError(sql.ErrNoRows)
+			mockDB.ExpectExec("INSERT INTO mfa_secret").
+				WillReturnResult(sqlmock.NewResult(0, 1))
+			mockDB.ExpectExec("DELETE FROM mfa_recovery_code").WithArgs("test_id").
+				WillReturnResult(sqlmock.NewResult(0, 0))
+			mockDB.ExpectExec("INSERT INTO mfa_recovery_code").
+				WillReturnError(sql.ErrConnDone)
+			mockDB.ExpectRollback()
+
+			_, err := service.Enroll(ctx, "test_id")
+			Expect(err).To(Equal(sql.ErrConnDone))
+		})
+
+		It("should refuse to replace an enabled secret", func() {
+			mockDB.ExpectBegin()
+			mockDB.ExpectQuery("SELECT enabled_at FROM mfa_secret").WithArgs("test_id").
+				WillReturnRows(sqlmock.NewRows([]string{"enabled_at"}).AddRow(time.Now()))
+			mockDB.ExpectRollback()
+			_, err := service.Enroll(ctx, "test_id")
+			Expect(err).To(Equal(mfa.ErrEnabled))
+		})
+	})
+
+	Context("Confirm", func() {
+		It("should enable MFA for a valid code", func() {
+			code, step := currentCode()
+			mockDB.ExpectQuery("SELECT secret FROM mfa_secret").WithArgs("test_id").
+				WillReturnRows(sqlmock.NewRows([]string{"secret"}).AddRow(secret))
+			mockDB.ExpectExec("UPDATE mfa_secret SET enabled_at").
+				WithArgs("test_id", sqltest.AnyTime{}, step).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+			Expect(service.Confirm(ctx, "test_id", code)).To(Succeed())
+		})
+
+		It("should reject a wrong code", func() {
+			mockDB.ExpectQuery("SELECT secret FROM mfa_secret").WithArgs("test_id").
+				WillReturnRows(sqlmock.NewRows([]string{"secret"}).AddRow(secret))
+			Expect(service.Confirm(ctx, "test_id", "abcdef")).To(Equal(mfa.ErrInvalidCode))
+		})
+
+		It("should report a missing enrollment", func() {
+			mockDB.ExpectQuery("SELECT secret FROM mfa_secret").WithArgs("test_id").
+				WillReturnError(sql.ErrNoRows)
+			Expect(service.Confirm(ctx, "test_id", "123456")).To(Equal(mfa.ErrNotEnrolled))
+		})
+	})
+
+	Context("Verify", func() {
+		expectSecret := func(lastStep int64) {
+			mockDB.ExpectQuery("SELECT secret, last_step FROM mfa_secret").WithArgs("test_id").
+				WillReturnRows(sqlmock.NewRows([]string{"secret", "last_step"}).
+					AddRow(secret, lastStep))
+		}
+
+		It("should accept a fresh TOTP code and remember its step", func() {
+			code, step := currentCode()
+			expectSecret(step - 5)
+			mockDB.ExpectExec("UPDATE mfa_secret SET last_step").WithArgs("test_id", step).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+			Expect(service.Verify(ctx, "test_id", code)).To(BeTrue())
+		})
+
+		It("should not accept the same TOTP code twice", func() {
+			code, step := currentCode()
+			expectSecret(step)
+			mockDB.ExpectExec("UPDATE mfa_recovery_code").
+				WithArgs("test_id", hash(code), sqltest.AnyTime{}).
+				WillReturnResult(sqlmock.NewResult(0, 0))
+			Expect(service.Verify(ctx, "test_id", code)).To(BeFalse())
+		})
+
+		It("should spend an unused recovery code", func() {
+			expectSecret(0)
+			mockDB.ExpectExec("UPDATE mfa_recovery_code").
+				WithArgs("test_id", hash("abcdefghij"), sqltest.AnyTime{}).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+			Expect(service.Verify(ctx, "test_id", "ABCDE-fghij")).To(BeTrue())
+			Expect(fakeLog.WarnCallCount()).To(Equal(1))
+		})
+
+		It("should not verify identities without MFA", func() {
+			mockDB.ExpectQuery("SELECT secret, last_step FROM mfa_secret").WithArgs("test_id").
+				WillReturnError(sql.ErrNoRows)
+			Expect(service.Verify(ctx, "test_id", "123456")).To(BeFalse())
+		})
+	})
+
+	Context("Redeem", func() {
+		It("should spend the challenge when the code verifies", func() {
+			code, step := currentCode()
+			mockDB.ExpectQuery("UPDATE mfa_challenge SET attempts").
+				WithArgs(hash("challenge"), sqltest.AnyTime{}, mfa.MaxChallengeAttempts).
+				WillReturnRows(sqlmock.NewRows([]string{"identity_id"}).AddRow("test_id"))
+			mockDB.ExpectQuery("SELECT secret, last_step FROM mfa_secret").WithArgs("test_id").
+				WillReturnRows(sqlmock.NewRows([]string{"secret", "last_step"}).AddRow(secret, 0))
+			mockDB.ExpectExec("UPDATE mfa_secret SET last_step").WithArgs("test_id", step).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+			mockDB.ExpectExec("UPDATE mfa_challenge SET used_at").
+				WithArgs(hash("challenge"), sqltest.AnyTime{}).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+
+			Expect(service.Redeem(ctx, "challenge", code)).To(Equal("test_id"))
+		})
+
+		It("should return the identity with a wrong code", func() {
+			mockDB.ExpectQuery("UPDATE mfa_challenge SET attempts").
+				WithArgs(hash("challenge"), sqltest.AnyTime{}, mfa.MaxChallengeAttempts).
+				WillReturnRows(sqlmock.NewRows([]string{"identity_id"}).AddRow("test_id"))
+			mockDB.ExpectQuery("SELECT secret, last_step FROM mfa_secret").WithArgs("test_id").
+				WillReturnRows(sqlmock.NewRows([]string{"secret", "last_step"}).AddRow(secret, 0))
+			mockDB.ExpectExec("UPDATE mfa_recovery_code").
+				WillReturnResult(sqlmock.NewResult(0, 0))
+
+			id, err := service.Redeem(ctx, "challenge", "wrong")
+			Expect(err).To(Equal(mfa.ErrInvalidCode))
+			Expect(id).To(Equal("test_id"))
+		})
+
+		It("should reject unknown, expired or exhausted challenges", func() {
+			mockDB.ExpectQuery("UPDATE mfa_challenge SET attempts").
+				WillReturnError(sql.ErrNoRows)
+			_, err := service.Redeem(ctx, "challenge", "123456")
+			Expect(err).To(Equal(mfa.ErrInvalidChallenge))
+		})
+	})
+
+	It("should store only a hash of a new challenge", func() {
+		mockDB.ExpectExec("INSERT INTO mfa_challenge").
+			WithArgs(sqltest.AnyString{}, "test_id", sqltest.AnyTime{}, sqltest.AnyTime{}).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		challenge, err := service.Challenge(ctx, "test_id")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(challenge).To(HaveLen(43))
+	})
+
+	It("should remove the secret and recovery codes together when disabling", func() {
+		mockDB.ExpectBegin()
+		mockDB.ExpectExec("DELETE FROM mfa_recovery_code").WithArgs("test_id").
+			WillReturnResult(sqlmock.NewResult(0, 10))
+		mockDB.ExpectExec("DELETE FROM mfa_secret").WithArgs("test_id").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mockDB.ExpectCommit()
+		Expect(service.Disable(ctx, "test_id")).To(Succeed())
+	})
+
+	It("should keep the recovery codes when the secret cannot be removed", func() {
+		mockDB.ExpectBegin()
+		mockDB.ExpectExec("DELETE FROM mfa_recovery_code").WithArgs("test_id").
+			WillReturnResult(sqlmock.NewResult(0, 10))
+		mockDB.ExpectExec("DELETE FROM mfa_secret").WithArgs("test_id").
+			WillReturnError(sql.ErrConnDone)
+		mockDB.ExpectRollback()
+		Expect(service.Disable(ctx, "test_id")).To(Equal(sql.ErrConnDone))
+	})
+
+	It("should report sql.ErrNoRows when disabling an identity without MFA", func() {
+		mockDB.ExpectBegin()
+		mockDB.ExpectExec("DELETE FROM mfa_recovery_code").WithArgs("test_id").
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mockDB.ExpectExec("DELETE FROM mfa_secret").WithArgs("test_id").
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mockDB.ExpectRollback()
+		Expect(service.Disable(ctx, "test_id")).To(Equal(sql.ErrNoRows))
+	})
+})
@@ -0,0 +1,70 @@
+package mfa_test
+
+import (
+	"net/url"
+	"service/auth/mfa"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("TOTP Specs", func() {
+	//The RFC 6238 SHA1 test secret "12345678901234567890", base32 encoded.
+	const secret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+	It("should produce the RFC 6238 test vectors", func() {
+		for at, expected := range map[int64]string{
+			59:         "287082",
+			1111111109: "081804",
+			1234567890: "005924",
+			2000000000: "279037",
+		} {
+			code, err := mfa.Code(secret, mfa.Step(time.Unix(at, 0)))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(code).To(Equal(expected))
+		}
+	})
+
+	It("should accept codes within the drift window and return their step", func() {
+		at := time.Unix(1111111109, 0)
+		previous, _ := mfa.Code(secret, mfa.Step(at)-1)
+		step, ok := mfa.Validate(secret, previous, at, 1)
+		Expect(ok).To(BeTrue())
+		Expect(step).To(Equal(mfa.Step(at) - 1))
+	})
+
+	It("should reject codes outside the drift window", func() {
+		at := time.Unix(1111111109, 0)
+		stale, _ := mfa.Code(secret, mfa.Step(at)-2)
+		_, ok := mfa.Validate(secret, stale, at, 1)
+		Expect(ok).To(BeFalse())
+	})
+
+	It("should reject malformed codes", func() {
+		_, ok := mfa.Validate(secret, "12345", time.Now(), 1)
+		Expect(ok).To(BeFalse())
+	})
+
+	It("should generate distinct base32 secrets", func() {
+		first, err := mfa.GenerateSecret()
+		Expect(err).ToNot(HaveOccurred())
+		second, _ := mfa.GenerateSecret()
+		Expect(first).To(HaveLen(32))
+		Expect(first).ToNot(Equal(second))
+		_, err = mfa.Code(first, 1)
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("should build an otpauth provisioning URI", func() {
+		uri, err := url.Parse(mfa.ProvisioningURI("Acme Co", "tony@example.com", secret))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(uri.Scheme).To(Equal("otpauth"))
+		Expect(uri.Host).To(Equal("totp"))
+		Expect(uri.Path).To(Equal("/Acme Co:tony@example.com"))
+		Expect(uri.Query().Get("secret")).To(Equal(secret))
+		Expect(uri.Query().Get("issuer")).To(Equal("Acme Co"))
+		Expect(uri.Query().Get("digits")).To(Equal("6"))
+		Expect(uri.Query().Get("period")).To(Equal("30"))
+	})
+})
@@ -0,0 +1,149 @@
+package mtls
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"strings"
+)
+
+//Name kinds, a certificate name is "<kind>:<value>" such as "dns:reports.internal".
+const (
+	KindURI   = "uri"
+	KindDNS   = "dns"
+	KindEmail = "email"
+	KindCN    = "cn"
+)
+
+//ErrNoCertificate ... is returned when the request has no verified client certificate.
+var ErrNoCertificate = errors.New("no verified client certificate")
+
+//ErrUnknownCertificate ... is returned when none of a certificate's names are bound to an identity.
+var ErrUnknownCertificate = errors.New("client certificate is not bound to an identity")
+
+//ErrNotSupported ... certificates are issued and revoked by the CA, not this service.
+var ErrNotSupported = errors.New("client certificates are managed by the certificate authority")
+
+//Principal ... is who a verified client certificate authenticates as.
+type Principal struct {
+	IdentityID  string
+	Name        string
+	Fingerprint string
+}
+
+//Names ...
+//returns the names a certificate can be bound by, SANs before the subject so a CN
+//never shadows a more specific name: URIs, DNS names, emails, then the common name.
+func Names(cert *x509.Certificate) []string {
+	names := make([]string, 0, len(cert.URIs)+len(cert.DNSNames)+len(cert.EmailAddresses)+1)
+	for _, uri := range cert.URIs {
+		names = append(names, KindURI+":"+uri.String())
+	}
+	for _, dns := range cert.DNSNames {
+		names = append(names, KindDNS+":"+strings.ToLower(dns))
+	}
+	for _, email := range cert.EmailAddresses {
+		names = append(names, KindEmail+":"+strings.ToLower(email))
+	}
+	if cert.Subject.CommonName != "" {
+		names = append(names, KindCN+":"+cert.Subject.CommonName)
+	}
+	return names
+}
+
+//NormalizeName ...
+//returns name the way Names would produce it, false if it has no known kind or value.
+//DNS names and emails are case insensitive so they are lower cased.
+func NormalizeName(name string) (string, bool) {
+	parts := strings.SplitN(name, ":", 2)
+	if len(parts) != 2 || parts[1] == "" {
+		return "", false
+	}
+	switch parts[0] {
+	case KindDNS, KindEmail:
+		return parts[0] + ":" + strings.ToLower(parts[1]), true
+	case KindURI, KindCN:
+		return name, true
+	}
+	return "", false
+}
+
+//Fingerprint ... the hex sha256 of the certificate, for logs.
+func Fingerprint(cert *x509.Certificate) string {
+	sum := sha256.Sum256(cert.Raw)
+	return hex.EncodeToString(sum[:])
+}
+
+//VerifiedCertificate ...
+//returns the request's client certificate, only if the TLS handshake verified it
+//against the client CA bundle. Unverified certificates are never trusted.
+func VerifiedCertificate(req *http.Request) (*x509.Certificate, bool) {
+	if req.TLS == nil || len(req.TLS.VerifiedChains) == 0 ||
+		len(req.TLS.VerifiedChains[0]) == 0 {
+		return nil, false
+	}
+	return req.TLS.VerifiedChains[0][0], true
+}
+
+//Auth ... implements auth.Interface on top of verified client certificates.
+type Auth struct {
+	Identities Store
+}
+
+//NewAuth ... creates a client certificate auth object
+func NewAuth(identities Store) *Auth {
+	return &Auth{
+		Identities: identities,
+	}
+}
+
+//Authenticate ... maps the request's verified certificate to the identity it is bound to.
+func (a *Auth) Authenticate(req *http.Request) (*Principal, error) {
+	cert, ok := VerifiedCertificate(req)
+	if !ok {
+		return nil, ErrNoCertificate
+	}
+	identityID, name, err := a.Identities.Lookup(req.Context(), Names(cert))
+	if err != nil {
+		return nil, err
+	}
+	return &Principal{
+		IdentityID:  identityID,
+		Name:        name,
+		Fingerprint: Fingerprint(cert),
+	}, nil
+}
+
+//Authorize ... returns the most specific name and the fingerprint of the request's
+//verified certificate.
+func (a *Auth) Authorize(req *http.Request) (string, string, bool) {
+	cert, ok := VerifiedCertificate(req)
+	if !ok {
+		return "", "", false
+	}
+	names := Names(cert)
+	if len(names) == 0 {
+		return "", "", false
+	}
+	return names[0], Fingerprint(cert), true
+}
+
+//ValidateTokenHeader ... verifies the request's certificate is bound to an identity.
+func (a *Auth) ValidateTokenHeader(req *http.Request) (bool, error) {
+	_, err := a.Authenticate(req)
+	return err == nil, err
+}
+
+//GenerateToken ... always fails, see ErrNotSupported.
+func (a *Auth) GenerateToken(ctx context.Context, input map[string]interface{}) (string,
+	error) {
+	return "", ErrNotSupported
+}
+
+//RevokeTokenHeader ... always fails, see ErrNotSupported.
+func (a *Auth) RevokeTokenHeader(req *http.Request) error {
+	return ErrNotSupported
+}
@@ -0,0 +1,97 @@
+package mtls_test
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"net/http/httptest"
+	"service/auth/mtls"
+	"service/utils/certtest"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("MTLS Auth Specs", func() {
+	ctx := context.Background()
+	var (
+		ca    *certtest.CA
+		leaf  *certtest.Leaf
+		store *mtls.MemoryStore
+		auth  *mtls.Auth
+	)
+
+	BeforeEach(func() {
+		var err error
+		ca, err = certtest.NewCA("test-ca")
+		Expect(err).ToNot(HaveOccurred())
+		leaf, err = ca.Client("reports", "spiffe://example.com/reports", "Reports.Internal",
+			"ops@example.com")
+		Expect(err).ToNot(HaveOccurred())
+		store = mtls.NewMemoryStore()
+		auth = mtls.NewAuth(store)
+	})
+
+	It("should list SANs before the common name", func() {
+		Expect(mtls.Names(leaf.Cert)).To(Equal([]string{
+			"uri:spiffe://example.com/reports",
+			"dns:reports.internal",
+			"email:ops@example.com",
+			"cn:reports",
+		}))
+	})
+
+	It("should normalize names the way Names produces them", func() {
+		name, ok := mtls.NormalizeName("dns:Reports.Internal")
+		Expect(ok).To(BeTrue())
+		Expect(name).To(Equal("dns:reports.internal"))
+		_, ok = mtls.NormalizeName("ou:reports")
+		Expect(ok).To(BeFalse())
+		_, ok = mtls.NormalizeName("cn:")
+		Expect(ok).To(BeFalse())
+	})
+
+	Context("Authenticate", func() {
+		It("should map a verified certificate to its most specific bound name", func() {
+			_, err := store.Bind(ctx, "service_id", "cn:reports")
+			Expect(err).ToNot(HaveOccurred())
+			_, err = store.Bind(ctx, "other_id", "dns:reports.internal")
+			Expect(err).ToNot(HaveOccurred())
+
+			req := httptest.NewRequest("GET", "/", nil)
+			req.TLS = &tls.ConnectionState{
+				VerifiedChains: [][]*x509.Certificate{{leaf.Cert, ca.Cert}},
+			}
+			principal, err := auth.Authenticate(req)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(principal.IdentityID).To(Equal("other_id"))
+			Expect(principal.Name).To(Equal("dns:reports.internal"))
+			Expect(principal.Fingerprint).To(Equal(mtls.Fingerprint(leaf.Cert)))
+		})
+
+		It("should refuse unbound certificates", func() {
+			req := httptest.NewRequest("GET", "/", nil)
+			req.TLS = &tls.ConnectionState{
+				VerifiedChains: [][]*x509.Certificate{{leaf.Cert, ca.Cert}},
+			}
+			_, err := auth.Authenticate(req)
+			Expect(err).To(Equal(mtls.ErrUnknownCertificate))
+		})
+
+		It("should never trust an unverified certificate", func() {
+			_, err := store.Bind(ctx, "service_id", "cn:reports")
+			Expect(err).ToNot(HaveOccurred())
+			req := httptest.NewRequest("GET", "/", nil)
+			req.TLS = &tls.ConnectionState{PeerCertificates: []*x509.Certificate{leaf.Cert}}
+			_, err = auth.Authenticate(req)
+			Expect(err).To(Equal(mtls.ErrNoCertificate))
+		})
+	})
+
+	It("should not generate or revoke credentials", func() {
+		_, err := auth.GenerateToken(ctx, map[string]interface{}{"sub": "service_id"})
+		Expect(err).To(Equal(mtls.ErrNotSupported))
+		Expect(auth.RevokeTokenHeader(httptest.NewRequest("GET", "/", nil))).
+			To(Equal(mtls.ErrNotSupported))
+	})
+})
@@ -0,0 +1,149 @@
+package mtls
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"service/handlers/loggederror"
+	"service/log"
+
+	"github.com/go-chi/chi"
+	"go.uber.org/zap"
+)
+
+//HandlerObject ... holds elementals for managing an identity's certificate bindings over http.
+type HandlerObject struct {
+	Log        log.ProdInterface
+	Identities Store
+}
+
+//NewHandlerObject ... returns a pointer to a new certificate HandlerObject.
+func NewHandlerObject(logClient log.ProdInterface, identities Store) *HandlerObject {
+	return &HandlerObject{
+		Log:        logClient,
+		Identities: identities,
+	}
+}
+
+type bindPostBody struct {
+	Name string `json:"name"`
+}
+
+type bindingResponse struct {
+	Status  int      `json:"status"`
+	Binding *Binding `json:"binding"`
+}
+
+type listBindingsResponse struct {
+	Status   int       `json:"status"`
+	Bindings []Binding `json:"bindings"`
+}
+
+//Bind ...
+//POST /identity/{id}/certificates, lets certificates carrying name, e.g.
+//"dns:reports.internal", authenticate as the identity.
+func (h *HandlerObject) Bind(w http.ResponseWriter, req *http.Request) {
+	var jsonDoc bindPostBody
+	if req.Body == nil || json.NewDecoder(req.Body).Decode(&jsonDoc) != nil {
+		h.softError(http.StatusBadRequest, "bad request", "Bind", w, req)
+		return
+	}
+	name, ok := NormalizeName(jsonDoc.Name)
+	if !ok {
+		h.softError(http.StatusBadRequest, "name must be uri:, dns:, email: or cn: and a value",
+			"Bind", w, req)
+		return
+	}
+	binding, err := h.Identities.Bind(req.Context(), chi.URLParam(req, "id"), name)
+	if err == ErrBound {
+		h.softError(http.StatusConflict, err.Error(), "Bind", w, req)
+		return
+	}
+	if err != nil {
+		h.internalServerError(err, "Bind", w, req)
+		return
+	}
+	h.Log.Info("client certificate bound",
+		zap.String("event", "auth.mtls.bind"),
+		zap.String("identityID", binding.IdentityID),
+		zap.String("name", binding.Name))
+	h.respond(http.StatusCreated, &bindingResponse{
+		Status:  http.StatusCreated,
+		Binding: binding,
+	}, "Bind", w, req)
+}
+
+//ListBindings ... GET /identity/{id}/certificates
+func (h *HandlerObject) ListBindings(w http.ResponseWriter, req *http.Request) {
+	bindings, err := h.Identities.List(req.Context(), chi.URLParam(req, "id"))
+	if err != nil {
+		h.internalServerError(err, "ListBindings", w, req)
+		return
+	}
+	h.respond(http.StatusOK, &listBindingsResponse{
+		Status:   http.StatusOK,
+		Bindings: bindings,
+	}, "ListBindings", w, req)
+}
+
+//Unbind ...
+//DELETE /identity/{id}/certificates?name=..., names can hold slashes so they are
+//not path segments.
+func (h *HandlerObject) Unbind(w http.ResponseWriter, req *http.Request) {
+	id := chi.URLParam(req, "id")
+	name, _ := NormalizeName(req.URL.Query().Get("name"))
+	err := h.Identities.Unbind(req.Context(), id, name)
+	if err == sql.ErrNoRows {
+		h.softError(http.StatusNotFound, "certificate binding not found", "Unbind", w, req)
+		return
+	}
+	if err != nil {
+		h.internalServerError(err, "Unbind", w, req)
+		return
+	}
+	h.Log.Info("client certificate unbound",
+		zap.String("event", "auth.mtls.unbind"),
+		zap.String("identityID", id),
+		zap.String("name", name))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *HandlerObject) respond(status int, response interface{}, source string,
+	w http.ResponseWriter, req *http.Request) {
+	bytesArray, marshalErr := json.Marshal(response)
+	if marshalErr != nil {
+		h.internalServerError(marshalErr, source, w, req)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if _, writeErr := w.Write(bytesArray); writeErr != nil {
+		h.Log.Error("mtls_handler::"+source, zap.Error(writeErr))
+	}
+}
+
+// internalServerError is used to wrap our loggederror for this route.
+func (h *HandlerObject) internalServerError(err error, source string, w http.ResponseWriter,
+	req *http.Request) {
+	loggederror.RespondWithProperErrorAndLogIt(
+		h.Log,
+		http.StatusInternalServerError,
+		err,
+		"mtls_handler::"+source,
+		w,
+		req,
+	)
+}
+
+// softError is used to wrap our loggederror for expected failures on this route.
+func (h *HandlerObject) softError(status int, message, source string, w http.ResponseWriter,
+	req *http.Request) {
+	loggederror.RespondWithWithExpectedSoftError(
+		h.Log,
+		status,
+		message,
+		"mtls_handler::"+source,
+		w,
+		req,
+	)
+}
@@ -0,0 +1,86 @@
+package mtls_test
+
+import (
+	"bytes"
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"service/auth/mtls"
+	"service/auth/mtls/mtlsfakes"
+	"service/log"
+	"service/log/logfakes"
+
+	"github.com/go-chi/chi"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("MTLS Handler Specs", func() {
+	var (
+		fakeStore *mtlsfakes.FakeStore
+		router    *chi.Mux
+		recorder  *httptest.ResponseRecorder
+	)
+
+	BeforeEach(func() {
+		fakeStore = &mtlsfakes.FakeStore{}
+		handler := mtls.NewHandlerObject(log.New(&logfakes.FakeProdInterface{}), fakeStore)
+		router = chi.NewRouter()
+		router.Get("/identity/{id}/certificates", handler.ListBindings)
+		router.Post("/identity/{id}/certificates", handler.Bind)
+		router.Delete("/identity/{id}/certificates", handler.Unbind)
+		recorder = httptest.NewRecorder()
+	})
+
+	serve := func(method, path, body string) {
+		router.ServeHTTP(recorder, httptest.NewRequest(method, path, bytes.NewBufferString(body)))
+	}
+
+	Context("Bind", func() {
+		It("should bind the normalized name", func() {
+			fakeStore.BindReturns(&mtls.Binding{Name: "dns:reports.internal",
+				IdentityID: "service_id"}, nil)
+			serve("POST", "/identity/service_id/certificates", `{"name": "dns:Reports.Internal"}`)
+			Expect(recorder.Code).To(Equal(http.StatusCreated))
+			_, identityID, name := fakeStore.BindArgsForCall(0)
+			Expect(identityID).To(Equal("service_id"))
+			Expect(name).To(Equal("dns:reports.internal"))
+		})
+
+		It("should refuse unknown name kinds", func() {
+			serve("POST", "/identity/service_id/certificates", `{"name": "reports"}`)
+			Expect(recorder.Code).To(Equal(http.StatusBadRequest))
+			Expect(fakeStore.BindCallCount()).To(Equal(0))
+		})
+
+		It("should conflict on a name another identity holds", func() {
+			fakeStore.BindReturns(nil, mtls.ErrBound)
+			serve("POST", "/identity/service_id/certificates", `{"name": "cn:reports"}`)
+			Expect(recorder.Code).To(Equal(http.StatusConflict))
+		})
+	})
+
+	It("should list an identity's bindings", func() {
+		fakeStore.ListReturns([]mtls.Binding{{Name: "cn:reports", IdentityID: "service_id"}}, nil)
+		serve("GET", "/identity/service_id/certificates", "")
+		Expect(recorder.Code).To(Equal(http.StatusOK))
+		Expect(recorder.Body.String()).To(ContainSubstring(`"name":"cn:reports"`))
+	})
+
+	Context("Unbind", func() {
+		It("should unbind names given as a query parameter", func() {
+			serve("DELETE", "/identity/service_id/certificates?name=uri%3Aspiffe%3A%2F%2Fexample.com%2Fr", "")
+			Expect(recorder.Code).To(Equal(http.StatusNoContent))
+			_, identityID, name := fakeStore.UnbindArgsForCall(0)
+			Expect(identityID).To(Equal("service_id"))
+			Expect(name).To(Equal("uri:spiffe://example.com/r"))
+		})
+
+		It("should 404 for missing bindings", func() {
+			fakeStore.UnbindReturns(sql.ErrNoRows)
+			serve("DELETE", "/identity/service_id/certificates?name=cn%3Areports", "")
+			Expect(recorder.Code).To(Equal(http.StatusNotFound))
+		})
+	})
+})
@@ -0,0 +1,65 @@
+package mtls
+
+import (
+	"net/http"
+	"service/auth/permission"
+	"service/auth/token/jwt"
+	"service/handlers/request"
+	"service/log"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+//AuthMiddleware ... maps the request's verified client certificate to its bound identity
+//and stores claims for it in the request context, so permission.Require works as it
+//does for bearer tokens. The identity is granted everything its roles grant.
+func AuthMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		principal, err := certAuth.Authenticate(req)
+		if err == ErrNoCertificate || err == ErrUnknownCertificate {
+			logClient.Info("client certificate rejected",
+				zap.String("requestID", request.RetreiveRequestID(req.Context())),
+				zap.String("event", "auth.mtls.rejected"),
+				zap.String("reason", err.Error()))
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+		if err != nil {
+			internalServerError(err, w, req)
+			return
+		}
+		granted, err := roleStore.Permissions(req.Context(), principal.IdentityID)
+		if err != nil {
+			internalServerError(err, w, req)
+			return
+		}
+		claims := &jwt.IdentityClaims{Scope: strings.Join(granted, " ")}
+		claims.Subject = principal.IdentityID
+		logClient.Debug("client certificate accepted",
+			zap.String("requestID", request.RetreiveRequestID(req.Context())),
+			zap.String("identityID", principal.IdentityID),
+			zap.String("name", principal.Name),
+			zap.String("fingerprint", principal.Fingerprint))
+		next.ServeHTTP(w, req.WithContext(request.WithClaims(req.Context(), claims)))
+	})
+}
+
+func internalServerError(err error, w http.ResponseWriter, req *http.Request) {
+	logClient.Error("mtls::AuthMiddleware",
+		zap.String("requestID", request.RetreiveRequestID(req.Context())),
+		zap.Error(err))
+	http.Error(w, http.StatusText(http.StatusInternalServerError),
+		http.StatusInternalServerError)
+}
+
+var certAuth *Auth
+var roleStore permission.Store
+var logClient log.ProdInterface
+
+//SetupAuthMiddleware ... attaches a configured certificate auth and role store
+func SetupAuthMiddleware(auth *Auth, roles permission.Store, log log.ProdInterface) {
+	certAuth = auth
+	roleStore = roles
+	logClient = log
+}
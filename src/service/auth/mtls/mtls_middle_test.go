@@ -0,0 +1,103 @@
+package mtls_test
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"service/auth/mtls"
+	"service/auth/permission/permissionfakes"
+	"service/handlers/request"
+	"service/log/logfakes"
+	"service/utils/certtest"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("MTLS Middleware Specs", func() {
+	ctx := context.Background()
+	var (
+		store     *mtls.MemoryStore
+		fakeRoles *permissionfakes.FakeStore
+		req       *http.Request
+		recorder  *httptest.ResponseRecorder
+		subject   string
+		scope     string
+		reached   bool
+	)
+
+	BeforeEach(func() {
+		store = mtls.NewMemoryStore()
+		fakeRoles = &permissionfakes.FakeStore{}
+		fakeRoles.PermissionsReturns([]string{"events:read", "identity:read"}, nil)
+		mtls.SetupAuthMiddleware(mtls.NewAuth(store), fakeRoles, &logfakes.FakeProdInterface{})
+
+		ca, err := certtest.NewCA("test-ca")
+		Expect(err).ToNot(HaveOccurred())
+		leaf, err := ca.Client("reports")
+		Expect(err).ToNot(HaveOccurred())
+		req = httptest.NewRequest("GET", "/", nil)
+		req.TLS = &tls.ConnectionState{
+			VerifiedChains: [][]*x509.Certificate{{leaf.Cert, ca.Cert}},
+		}
+		recorder = httptest.NewRecorder()
+		reached, subject, scope = false, "", ""
+	})
+
+	JustBeforeEach(func() {
+		mtls.AuthMiddleware(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			reached = true
+			subject = request.RetreiveSubject(req.Context())
+			if claims, ok := request.RetreiveClaims(req.Context()); ok {
+				scope = claims.Scope
+			}
+		})).ServeHTTP(recorder, req)
+	})
+
+	Context("when the certificate is bound", func() {
+		BeforeEach(func() {
+			_, err := store.Bind(ctx, "service_id", "cn:reports")
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should authenticate as the bound identity with its permissions", func() {
+			Expect(reached).To(BeTrue())
+			Expect(subject).To(Equal("service_id"))
+			Expect(scope).To(Equal("events:read identity:read"))
+			_, identityID := fakeRoles.PermissionsArgsForCall(0)
+			Expect(identityID).To(Equal("service_id"))
+		})
+
+		Context("when permissions can't be loaded", func() {
+			BeforeEach(func() {
+				fakeRoles.PermissionsReturns(nil, errors.New("db down"))
+			})
+
+			It("should 500", func() {
+				Expect(reached).To(BeFalse())
+				Expect(recorder.Code).To(Equal(http.StatusInternalServerError))
+			})
+		})
+	})
+
+	Context("when the certificate is not bound", func() {
+		It("should 401", func() {
+			Expect(reached).To(BeFalse())
+			Expect(recorder.Code).To(Equal(http.StatusUnauthorized))
+		})
+	})
+
+	Context("when there is no verified certificate", func() {
+		BeforeEach(func() {
+			req.TLS = nil
+		})
+
+		It("should 401", func() {
+			Expect(reached).To(BeFalse())
+			Expect(recorder.Code).To(Equal(http.StatusUnauthorized))
+		})
+	})
+})
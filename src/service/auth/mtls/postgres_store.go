@@ -0,0 +1,110 @@
+package mtls
+
+import (
+	"context"
+	"database/sql"
+	"service/database"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+//PostgresStore ... is a Store backed by the client_certificate table.
+type PostgresStore struct {
+	db database.DBInterface
+}
+
+//NewPostgresStore ... returns a pointer to a new PostgresStore using the passed in db.
+func NewPostgresStore(db database.DBInterface) *PostgresStore {
+	return &PostgresStore{
+		db: db,
+	}
+}
+
+//Lookup ... returns the identity and name of the first of names that is bound,
+//ErrUnknownCertificate when none are.
+func (p *PostgresStore) Lookup(ctx context.Context, names []string) (string, string, error) {
+	if len(names) == 0 {
+		return "", "", ErrUnknownCertificate
+	}
+	rows, err := p.db.QueryContext(ctx,
+		"SELECT name, identity_id FROM client_certificate WHERE name = ANY($1);",
+		pq.Array(names))
+	if err != nil {
+		return "", "", err
+	}
+	defer rows.Close()
+	bound := make(map[string]string)
+	for rows.Next() {
+		var name, identityID string
+		if err := rows.Scan(&name, &identityID); err != nil {
+			return "", "", err
+		}
+		bound[name] = identityID
+	}
+	if err := rows.Err(); err != nil {
+		return "", "", err
+	}
+	for _, name := range names {
+		if identityID, ok := bound[name]; ok {
+			return identityID, name, nil
+		}
+	}
+	return "", "", ErrUnknownCertificate
+}
+
+//Bind ... binds name to the identity, ErrBound when another identity holds it.
+func (p *PostgresStore) Bind(ctx context.Context, identityID, name string) (*Binding, error) {
+	binding := Binding{Name: name, IdentityID: identityID}
+	err := p.db.QueryRowContext(ctx,
+		`INSERT INTO client_certificate (name, identity_id, created_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (name) DO UPDATE SET name = EXCLUDED.name
+		RETURNING identity_id, created_at;`,
+		name, identityID, time.Now()).Scan(&binding.IdentityID, &binding.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if binding.IdentityID != identityID {
+		return nil, ErrBound
+	}
+	return &binding, nil
+}
+
+//Unbind ... removes the identity's binding, sql.ErrNoRows when it has none by that name.
+func (p *PostgresStore) Unbind(ctx context.Context, identityID, name string) error {
+	result, err := p.db.ExecContext(ctx,
+		"DELETE FROM client_certificate WHERE identity_id = $1 AND name = $2;",
+		identityID, name)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+//List ... returns the identity's bindings by name.
+func (p *PostgresStore) List(ctx context.Context, identityID string) ([]Binding, error) {
+	rows, err := p.db.QueryContext(ctx,
+		`SELECT name, identity_id, created_at FROM client_certificate
+		WHERE identity_id = $1 ORDER BY name;`, identityID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	bindings := make([]Binding, 0)
+	for rows.Next() {
+		var binding Binding
+		if err := rows.Scan(&binding.Name, &binding.IdentityID, &binding.CreatedAt); err != nil {
+			return nil, err
+		}
+		bindings = append(bindings, binding)
+	}
+	return bindings, rows.Err()
+}
@@ -0,0 +1,95 @@
+package mtls
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+)
+
+//ErrBound ... is returned when binding a name that already belongs to another identity.
+var ErrBound = errors.New("certificate name is bound to another identity")
+
+//Binding ... ties a certificate name to the identity it authenticates as.
+type Binding struct {
+	Name       string    `json:"name"`
+	IdentityID string    `json:"identityId"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+//Store ... defines a backing store for certificate name bindings.
+//go:generate counterfeiter . Store
+type Store interface {
+	Lookup(ctx context.Context, names []string) (string, string, error)
+	Bind(ctx context.Context, identityID, name string) (*Binding, error)
+	Unbind(ctx context.Context, identityID, name string) error
+	List(ctx context.Context, identityID string) ([]Binding, error)
+}
+
+//MemoryStore ... is a Store for a single instance, bindings are lost on restart.
+type MemoryStore struct {
+	mutex    sync.Mutex
+	bindings map[string]Binding
+}
+
+//NewMemoryStore ... returns a pointer to a new, empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		bindings: make(map[string]Binding),
+	}
+}
+
+//Lookup ... returns the identity and name of the first of names that is bound,
+//ErrUnknownCertificate when none are.
+func (m *MemoryStore) Lookup(ctx context.Context, names []string) (string, string, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	for _, name := range names {
+		if binding, ok := m.bindings[name]; ok {
+			return binding.IdentityID, binding.Name, nil
+		}
+	}
+	return "", "", ErrUnknownCertificate
+}
+
+//Bind ... binds name to the identity, ErrBound when another identity holds it.
+func (m *MemoryStore) Bind(ctx context.Context, identityID, name string) (*Binding, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if binding, ok := m.bindings[name]; ok {
+		if binding.IdentityID != identityID {
+			return nil, ErrBound
+		}
+		return &binding, nil
+	}
+	binding := Binding{Name: name, IdentityID: identityID, CreatedAt: time.Now()}
+	m.bindings[name] = binding
+	return &binding, nil
+}
+
+//Unbind ... removes the identity's binding, sql.ErrNoRows when it has none by that name.
+func (m *MemoryStore) Unbind(ctx context.Context, identityID, name string) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if binding, ok := m.bindings[name]; !ok || binding.IdentityID != identityID {
+		return sql.ErrNoRows
+	}
+	delete(m.bindings, name)
+	return nil
+}
+
+//List ... returns the identity's bindings by name.
+func (m *MemoryStore) List(ctx context.Context, identityID string) ([]Binding, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	bindings := make([]Binding, 0)
+	for _, binding := range m.bindings {
+		if binding.IdentityID == identityID {
+			bindings = append(bindings, binding)
+		}
+	}
+	sort.Slice(bindings, func(i, j int) bool { return bindings[i].Name < bindings[j].Name })
+	return bindings, nil
+}
@@ -0,0 +1,93 @@
+package mtls_test
+
+import (
+	"context"
+	"database/sql"
+	"service/auth/mtls"
+	"service/utils/sqltest"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	sqlmock "gopkg.in/DATA-DOG/go-sqlmock.v1"
+)
+
+var _ = Describe("Store Specs", func() {
+	ctx := context.Background()
+	Context("MemoryStore", func() {
+		var store *mtls.MemoryStore
+
+		BeforeEach(func() {
+			store = mtls.NewMemoryStore()
+			_, err := store.Bind(ctx, "service_id", "cn:reports")
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should refuse binding a name another identity holds", func() {
+			_, err := store.Bind(ctx, "other_id", "cn:reports")
+			Expect(err).To(Equal(mtls.ErrBound))
+			_, err = store.Bind(ctx, "service_id", "cn:reports")
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should only unbind the identity's own names", func() {
+			Expect(store.Unbind(ctx, "other_id", "cn:reports")).To(Equal(sql.ErrNoRows))
+			Expect(store.Unbind(ctx, "service_id", "cn:reports")).To(Succeed())
+			_, _, err := store.Lookup(ctx, []string{"cn:reports"})
+			Expect(err).To(Equal(mtls.ErrUnknownCertificate))
+		})
+	})
+
+	Context("PostgresStore", func() {
+		var (
+			store  *mtls.PostgresStore
+			mockDB sqlmock.Sqlmock
+		)
+
+		BeforeEach(func() {
+			db, mock, sqlmockErr := sqlmock.New()
+			Expect(sqlmockErr).ToNot(HaveOccurred())
+			mockDB = mock
+			store = mtls.NewPostgresStore(db)
+		})
+
+		AfterEach(func() {
+			Expect(mockDB.ExpectationsWereMet()).To(Succeed())
+		})
+
+		It("should prefer the earliest of the names that are bound", func() {
+			mockDB.ExpectQuery("SELECT name, identity_id FROM client_certificate").
+				WithArgs("{\"dns:reports.internal\",\"cn:reports\"}").
+				WillReturnRows(sqlmock.NewRows([]string{"name", "identity_id"}).
+					AddRow("cn:reports", "service_id").
+					AddRow("dns:reports.internal", "other_id"))
+			identityID, name, err := store.Lookup(ctx, []string{"dns:reports.internal", "cn:reports"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(identityID).To(Equal("other_id"))
+			Expect(name).To(Equal("dns:reports.internal"))
+		})
+
+		It("should report certificates with no bound names", func() {
+			mockDB.ExpectQuery("SELECT name, identity_id FROM client_certificate").
+				WillReturnRows(sqlmock.NewRows([]string{"name", "identity_id"}))
+			_, _, err := store.Lookup(ctx, []string{"cn:reports"})
+			Expect(err).To(Equal(mtls.ErrUnknownCertificate))
+		})
+
+		It("should refuse binding a name another identity holds", func() {
+			mockDB.ExpectQuery("INSERT INTO client_certificate").
+				WithArgs("cn:reports", "service_id", sqltest.AnyTime{}).
+				WillReturnRows(sqlmock.NewRows([]string{"identity_id", "created_at"}).
+					AddRow("other_id", time.Now()))
+			_, err := store.Bind(ctx, "service_id", "cn:reports")
+			Expect(err).To(Equal(mtls.ErrBound))
+		})
+
+		It("should report unbinding a missing name", func() {
+			mockDB.ExpectExec("DELETE FROM client_certificate").
+				WithArgs("service_id", "cn:reports").
+				WillReturnResult(sqlmock.NewResult(0, 0))
+			Expect(store.Unbind(ctx, "service_id", "cn:reports")).To(Equal(sql.ErrNoRows))
+		})
+	})
+})
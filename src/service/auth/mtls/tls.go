@@ -0,0 +1,35 @@
+package mtls
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"io/ioutil"
+)
+
+//ErrNoCertificates ... is returned for a client CA bundle without any PEM certificates.
+var ErrNoCertificates = errors.New("client CA bundle has no certificates")
+
+//ServerTLSConfig ...
+//returns the TLS config to serve with. With a clientCAFile, client certificates are
+//requested and verified against that bundle but not required, so routes can still
+//be reached with other credentials. Unverifiable certificates fail the handshake.
+func ServerTLSConfig(clientCAFile string) (*tls.Config, error) {
+	config := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+	}
+	if clientCAFile == "" {
+		return config, nil
+	}
+	bundle, err := ioutil.ReadFile(clientCAFile)
+	if err != nil {
+		return nil, err
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(bundle) {
+		return nil, ErrNoCertificates
+	}
+	config.ClientCAs = pool
+	config.ClientAuth = tls.VerifyClientCertIfGiven
+	return config, nil
+}
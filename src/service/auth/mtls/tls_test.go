@@ -0,0 +1,102 @@
+package mtls_test
+
+import (
+	"crypto/tls"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"service/auth/mtls"
+	"service/utils/certtest"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("TLS Specs", func() {
+	var (
+		dir    string
+		ca     *certtest.CA
+		server *httptest.Server
+		seen   string
+	)
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "mtls")
+		Expect(err).ToNot(HaveOccurred())
+		ca, err = certtest.NewCA("test-ca")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(ioutil.WriteFile(filepath.Join(dir, "ca.pem"), ca.PEM(), 0600)).To(Succeed())
+
+		config, err := mtls.ServerTLSConfig(filepath.Join(dir, "ca.pem"))
+		Expect(err).ToNot(HaveOccurred())
+		serverCert, err := ca.Server("127.0.0.1")
+		Expect(err).ToNot(HaveOccurred())
+		config.Certificates = []tls.Certificate{serverCert.TLS()}
+
+		seen = ""
+		server = httptest.NewUnstartedServer(http.HandlerFunc(
+			func(w http.ResponseWriter, req *http.Request) {
+				if cert, ok := mtls.VerifiedCertificate(req); ok {
+					seen = cert.Subject.CommonName
+				}
+			}))
+		server.TLS = config
+		server.StartTLS()
+	})
+
+	AfterEach(func() {
+		server.Close()
+		os.RemoveAll(dir)
+	})
+
+	client := func(certs ...tls.Certificate) *http.Client {
+		return &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{
+			RootCAs:      ca.Pool(),
+			Certificates: certs,
+		}}}
+	}
+
+	It("should verify client certificates issued by the bundle", func() {
+		leaf, err := ca.Client("reports")
+		Expect(err).ToNot(HaveOccurred())
+		response, err := client(leaf.TLS()).Get(server.URL)
+		Expect(err).ToNot(HaveOccurred())
+		response.Body.Close()
+		Expect(seen).To(Equal("reports"))
+	})
+
+	It("should still serve clients without a certificate", func() {
+		response, err := client().Get(server.URL)
+		Expect(err).ToNot(HaveOccurred())
+		response.Body.Close()
+		Expect(response.StatusCode).To(Equal(http.StatusOK))
+		Expect(seen).To(BeEmpty())
+	})
+
+	It("should fail the handshake for certificates from another CA", func() {
+		other, err := certtest.NewCA("other-ca")
+		Expect(err).ToNot(HaveOccurred())
+		leaf, err := other.Client("reports")
+		Expect(err).ToNot(HaveOccurred())
+		forged := client()
+		//Sent even though the server only asks for certificates from its own CA.
+		forged.Transport.(*http.Transport).TLSClientConfig.GetClientCertificate =
+			func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+				cert := leaf.TLS()
+				return &cert, nil
+			}
+		_, err = forged.Get(server.URL)
+		Expect(err).To(HaveOccurred())
+		Expect(seen).To(BeEmpty())
+	})
+
+	It("should refuse a bundle without certificates", func() {
+		Expect(ioutil.WriteFile(filepath.Join(dir, "empty.pem"), []byte("nothing"), 0600)).
+			To(Succeed())
+		_, err := mtls.ServerTLSConfig(filepath.Join(dir, "empty.pem"))
+		Expect(err).To(Equal(mtls.ErrNoCertificates))
+	})
+})
@@ -0,0 +1,251 @@
+package oauth
+
+import (
+	"context"
+	"html/template"
+	"net/http"
+	"net/url"
+	"service/auth/throttle"
+
+	"go.uber.org/zap"
+)
+
+//consentPage asks the user to sign in and approve the client's access. Everything the
+//client sent is carried through the form so the POST can be validated from scratch.
+var consentPage = template.Must(template.New("consent").Parse(`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>Authorize {{.ClientName}}</title></head>
+<body>
+{{if .Error}}<p role="alert">{{.Error}}</p>{{end}}
+{{if .ClientName}}
+<h1>{{.ClientName}} wants to access your account</h1>
+{{if .Scopes}}<ul>{{range .Scopes}}<li>{{.}}</li>{{end}}</ul>{{end}}
+<form method="post" action="/oauth/authorize">
+{{range $name, $value := .Params}}<input type="hidden" name="{{$name}}" value="{{$value}}">
+{{end}}<label>ID <input name="id" autocomplete="username" required></label>
+<label>Password <input name="password" type="password" autocomplete="current-password" required></label>
+<label>Authenticator code <input name="code" autocomplete="one-time-code"></label>
+<button name="decision" value="allow">Allow</button>
+<button name="decision" value="deny" formnovalidate>Deny</button>
+</form>
+{{end}}
+</body>
+</html>
+`))
+
+type consentData struct {
+	ClientName string
+	Scopes     []string
+	Params     map[string]string
+	Error      string
+}
+
+//authorizeParams are the authorization request parameters echoed through the consent form.
+var authorizeParams = []string{"response_type", "client_id", "redirect_uri", "scope", "state",
+	"code_challenge", "code_challenge_method"}
+
+type authorizeRequest struct {
+	client *Client
+	params url.Values
+	scopes []string
+}
+
+//Authorize ...
+//GET and POST /oauth/authorize, the authorization code grant (RFC 6749 section 4.1) with
+//mandatory PKCE. GET shows the consent page, POST signs the user in and, if they allow
+//it, redirects back to the client with a code.
+func (h *HandlerObject) Authorize(w http.ResponseWriter, req *http.Request) {
+	if err := req.ParseForm(); err != nil {
+		h.renderConsent(http.StatusBadRequest, &consentData{Error: "malformed request"}, w, req)
+		return
+	}
+	//On POST only the form body counts, the query string is ignored.
+	params := req.Form
+	if req.Method == http.MethodPost {
+		params = req.PostForm
+	}
+	authReq, ok := h.validateAuthorize(params, w, req)
+	if !ok {
+		return
+	}
+	if req.Method != http.MethodPost {
+		h.renderConsent(http.StatusOK, authReq.consent(""), w, req)
+		return
+	}
+	if params.Get("decision") != "allow" {
+		h.redirectError(authReq, &Error{Code: ErrorAccessDenied}, w, req)
+		return
+	}
+	h.approve(authReq, w, req)
+}
+
+//validateAuthorize only redirects errors once the client and redirect_uri are known good,
+//anything else would make the server an open redirector.
+func (h *HandlerObject) validateAuthorize(params url.Values, w http.ResponseWriter,
+	req *http.Request) (*authorizeRequest, bool) {
+	client, err := h.Clients.Get(req.Context(), params.Get("client_id"))
+	if err == ErrUnknownClient {
+		h.renderConsent(http.StatusBadRequest, &consentData{Error: "unknown client"}, w, req)
+		return nil, false
+	}
+	if err != nil {
+		h.internalServerError(err, "Authorize", w, req)
+		return nil, false
+	}
+	if !client.AllowsRedirect(params.Get("redirect_uri")) {
+		h.renderConsent(http.StatusBadRequest,
+			&consentData{Error: "redirect_uri is not registered for this client"}, w, req)
+		return nil, false
+	}
+	authReq := &authorizeRequest{client: client, params: params}
+	if params.Get("response_type") != "code" {
+		h.redirectError(authReq, &Error{Code: ErrorUnsupportedResponseType}, w, req)
+		return nil, false
+	}
+	if !client.Allows(GrantAuthorizationCode) {
+		h.redirectError(authReq, &Error{Code: ErrorUnauthorizedClient}, w, req)
+		return nil, false
+	}
+	if params.Get("code_challenge") == "" || params.Get("code_challenge_method") != PKCEMethodS256 {
+		h.redirectError(authReq, &Error{Code: ErrorInvalidRequest,
+			Description: "code_challenge with code_challenge_method S256 is required"}, w, req)
+		return nil, false
+	}
+	scopes, ok := narrow(ParseScope(params.Get("scope")), client.Scopes)
+	if !ok {
+		h.redirectError(authReq, &Error{Code: ErrorInvalidScope}, w, req)
+		return nil, false
+	}
+	authReq.scopes = scopes
+	return authReq, true
+}
+
+//approve checks the user's credentials the same way AuthIdentity does, throttling
+//included, before issuing a code.
+func (h *HandlerObject) approve(authReq *authorizeRequest, w http.ResponseWriter,
+	req *http.Request) {
+	id := authReq.params.Get("id")
+	password := authReq.params.Get("password")
+	if id == "" || password == "" {
+		h.renderConsent(http.StatusBadRequest, authReq.consent("missing id or password"), w, req)
+		return
+	}
+	identityKey := throttle.IdentityKey(id)
+	ipKey := throttle.IPKey(throttle.ClientIP(req))
+	wait, err := h.Throttle.Check(req.Context(), identityKey, ipKey)
+	if err != nil {
+		h.internalServerError(err, "Authorize", w, req)
+		return
+	}
+	if wait > 0 {
+		throttle.SetRetryAfter(w, wait)
+		h.renderConsent(http.StatusTooManyRequests, authReq.consent(throttle.ErrTooManyAttempts),
+			w, req)
+		return
+	}
+	verified, err := h.Identities.VerifyPassword(req.Context(), id, password)
+	if err != nil {
+		h.internalServerError(err, "Authorize", w, req)
+		return
+	}
+	if verified {
+		verified, err = h.verifySecondFactor(req.Context(), id, authReq.params.Get("code"))
+		if err != nil {
+			h.internalServerError(err, "Authorize", w, req)
+			return
+		}
+	}
+	if !verified {
+		if err := h.Throttle.Fail(req.Context(), identityKey, ipKey); err != nil {
+			h.internalServerError(err, "Authorize", w, req)
+			return
+		}
+		h.renderConsent(http.StatusUnauthorized, authReq.consent("invalid id, password or code"),
+			w, req)
+		return
+	}
+	if err := h.Throttle.Succeed(req.Context(), identityKey); err != nil {
+		h.internalServerError(err, "Authorize", w, req)
+		return
+	}
+	permissions, err := h.Roles.Permissions(req.Context(), id)
+	if err != nil {
+		h.internalServerError(err, "Authorize", w, req)
+		return
+	}
+	scope := FormatScope(intersect(authReq.scopes, permissions))
+	code, err := h.Codes.Issue(req.Context(), Code{
+		ClientID:            authReq.client.ID,
+		IdentityID:          id,
+		RedirectURI:         authReq.params.Get("redirect_uri"),
+		Scope:               scope,
+		CodeChallenge:       authReq.params.Get("code_challenge"),
+		CodeChallengeMethod: authReq.params.Get("code_challenge_method"),
+	})
+	if err != nil {
+		h.internalServerError(err, "Authorize", w, req)
+		return
+	}
+	h.Log.Info("oauth consent granted",
+		zap.String("event", "auth.oauth.consent"),
+		zap.String("identityID", id),
+		zap.String("clientID", authReq.client.ID),
+		zap.String("scope", scope))
+	h.redirect(authReq.params.Get("redirect_uri"), url.Values{
+		"code":  {code},
+		"state": {authReq.params.Get("state")},
+	}, w, req)
+}
+
+//verifySecondFactor passes identities without MFA, otherwise code must be a valid TOTP or
+//recovery code.
+func (h *HandlerObject) verifySecondFactor(ctx context.Context, id, code string) (bool, error) {
+	enabled, err := h.MFA.Enabled(ctx, id)
+	if err != nil || !enabled {
+		return err == nil, err
+	}
+	if code == "" {
+		return false, nil
+	}
+	return h.MFA.Verify(ctx, id, code)
+}
+
+func (h *HandlerObject) redirectError(authReq *authorizeRequest, oauthErr *Error,
+	w http.ResponseWriter, req *http.Request) {
+	h.logSoftError(oauthErr.Error(), "Authorize", req)
+	h.redirect(authReq.params.Get("redirect_uri"), url.Values{
+		"error":             {oauthErr.Code},
+		"error_description": {oauthErr.Description},
+		"state":             {authReq.params.Get("state")},
+	}, w, req)
+}
+
+func (h *HandlerObject) renderConsent(status int, data *consentData, w http.ResponseWriter,
+	req *http.Request) {
+	if data.Error != "" {
+		h.logSoftError(data.Error, "Authorize", req)
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("X-Frame-Options", "DENY")
+	w.Header().Set("Content-Security-Policy", "default-src 'none'; form-action 'self'; frame-ancestors 'none'")
+	w.WriteHeader(status)
+	if err := consentPage.Execute(w, data); err != nil {
+		h.Log.Error("oauth_handler::Authorize", zap.Error(err))
+	}
+}
+
+func (a *authorizeRequest) consent(message string) *consentData {
+	params := make(map[string]string, len(authorizeParams))
+	for _, name := range authorizeParams {
+		if value := a.params.Get(name); value != "" {
+			params[name] = value
+		}
+	}
+	return &consentData{
+		ClientName: a.client.Name,
+		Scopes:     a.scopes,
+		Params:     params,
+		Error:      message,
+	}
+}
@@ -0,0 +1,203 @@
+package oauth_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"service/auth/mfa/mfafakes"
+	"service/auth/oauth"
+	"service/auth/oauth/oauthfakes"
+	"service/auth/permission/permissionfakes"
+	"service/auth/throttle/throttlefakes"
+	"service/identity/identityfakes"
+	"service/log"
+	"service/log/logfakes"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Authorize Handler Specs", func() {
+	var (
+		handler        *oauth.HandlerObject
+		fakeClients    *oauthfakes.FakeClientStore
+		fakeCodes      *oauthfakes.FakeCodeStore
+		fakeIdentities *identityfakes.FakeServiceInterface
+		fakeRoles      *permissionfakes.FakeStore
+		fakeMFA        *mfafakes.FakeInterface
+		fakeThrottle   *throttlefakes.FakeInterface
+		recorder       *httptest.ResponseRecorder
+		params         url.Values
+	)
+
+	BeforeEach(func() {
+		fakeClients = &oauthfakes.FakeClientStore{}
+		fakeCodes = &oauthfakes.FakeCodeStore{}
+		fakeIdentities = &identityfakes.FakeServiceInterface{}
+		fakeRoles = &permissionfakes.FakeStore{}
+		fakeMFA = &mfafakes.FakeInterface{}
+		fakeThrottle = &throttlefakes.FakeInterface{}
+		handler = oauth.NewHandlerObject(log.New(&logfakes.FakeProdInterface{}), fakeClients,
+			fakeCodes, nil, time.Minute, nil, fakeIdentities, fakeRoles, fakeMFA, fakeThrottle)
+		recorder = httptest.NewRecorder()
+
+		fakeClients.GetReturns(&oauth.Client{
+			ID:           "spa",
+			Name:         "Example App",
+			RedirectURIs: []string{"https://app.example.com/cb"},
+			GrantTypes:   []string{oauth.GrantAuthorizationCode},
+			Scopes:       []string{"identity:read", "identity:write"},
+		}, nil)
+		fakeIdentities.VerifyPasswordReturns(true, nil)
+		fakeRoles.PermissionsReturns([]string{"identity:read"}, nil)
+		fakeCodes.IssueReturns("the-code", nil)
+
+		params = url.Values{
+			"response_type":         {"code"},
+			"client_id":             {"spa"},
+			"redirect_uri":          {"https://app.example.com/cb"},
+			"scope":                 {"identity:read identity:write"},
+			"state":                 {"xyz"},
+			"code_challenge":        {"the-challenge"},
+			"code_challenge_method": {"S256"},
+		}
+	})
+
+	get := func() {
+		handler.Authorize(recorder, httptest.NewRequest("GET", "/oauth/authorize?"+params.Encode(), nil))
+	}
+
+	post := func(extra url.Values) {
+		form := url.Values{}
+		for key, values := range params {
+			form[key] = values
+		}
+		for key, values := range extra {
+			form[key] = values
+		}
+		req := httptest.NewRequest("POST", "/oauth/authorize", strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		handler.Authorize(recorder, req)
+	}
+
+	redirected := func() url.Values {
+		Expect(recorder.Code).To(Equal(http.StatusFound))
+		location, err := url.Parse(recorder.Header().Get("Location"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(location.Scheme + "://" + location.Host + location.Path).
+			To(Equal("https://app.example.com/cb"))
+		return location.Query()
+	}
+
+	Context("GET", func() {
+		It("should render the consent page", func() {
+			get()
+			Expect(recorder.Code).To(Equal(http.StatusOK))
+			Expect(recorder.Header().Get("X-Frame-Options")).To(Equal("DENY"))
+			Expect(recorder.Header().Get("Content-Security-Policy")).
+				To(ContainSubstring("frame-ancestors 'none'"))
+			Expect(recorder.Body.String()).To(ContainSubstring("Example App"))
+			Expect(recorder.Body.String()).To(ContainSubstring(`name="state" value="xyz"`))
+		})
+
+		It("should not redirect to an unregistered redirect_uri", func() {
+			params.Set("redirect_uri", "https://evil.example.com/cb")
+			get()
+			Expect(recorder.Code).To(Equal(http.StatusBadRequest))
+			Expect(recorder.Header().Get("Location")).To(BeEmpty())
+		})
+
+		It("should not redirect for an unknown client", func() {
+			fakeClients.GetReturns(nil, oauth.ErrUnknownClient)
+			get()
+			Expect(recorder.Code).To(Equal(http.StatusBadRequest))
+			Expect(recorder.Header().Get("Location")).To(BeEmpty())
+		})
+
+		It("should require PKCE", func() {
+			params.Del("code_challenge")
+			get()
+			query := redirected()
+			Expect(query.Get("error")).To(Equal(oauth.ErrorInvalidRequest))
+			Expect(query.Get("state")).To(Equal("xyz"))
+		})
+
+		It("should refuse other response types", func() {
+			params.Set("response_type", "token")
+			get()
+			Expect(redirected().Get("error")).To(Equal(oauth.ErrorUnsupportedResponseType))
+		})
+
+		It("should refuse scopes the client was not registered for", func() {
+			params.Set("scope", "roles:admin")
+			get()
+			Expect(redirected().Get("error")).To(Equal(oauth.ErrorInvalidScope))
+		})
+	})
+
+	Context("POST", func() {
+		It("should issue a code for the permissions the identity holds", func() {
+			post(url.Values{"decision": {"allow"}, "id": {"test_id"}, "password": {"password"}})
+			query := redirected()
+			Expect(query.Get("code")).To(Equal("the-code"))
+			Expect(query.Get("state")).To(Equal("xyz"))
+			_, code := fakeCodes.IssueArgsForCall(0)
+			Expect(code).To(Equal(oauth.Code{
+				ClientID:            "spa",
+				IdentityID:          "test_id",
+				RedirectURI:         "https://app.example.com/cb",
+				Scope:               "identity:read",
+				CodeChallenge:       "the-challenge",
+				CodeChallengeMethod: "S256",
+			}))
+			Expect(fakeThrottle.SucceedCallCount()).To(Equal(1))
+		})
+
+		It("should redirect with access_denied when the user declines", func() {
+			post(url.Values{"decision": {"deny"}})
+			Expect(redirected().Get("error")).To(Equal(oauth.ErrorAccessDenied))
+			Expect(fakeIdentities.VerifyPasswordCallCount()).To(Equal(0))
+		})
+
+		It("should re-render the page and count a wrong password", func() {
+			fakeIdentities.VerifyPasswordReturns(false, nil)
+			post(url.Values{"decision": {"allow"}, "id": {"test_id"}, "password": {"wrong"}})
+			Expect(recorder.Code).To(Equal(http.StatusUnauthorized))
+			Expect(recorder.Body.String()).To(ContainSubstring("invalid id, password or code"))
+			Expect(fakeThrottle.FailCallCount()).To(Equal(1))
+			Expect(fakeCodes.IssueCallCount()).To(Equal(0))
+		})
+
+		It("should not check the password while throttled", func() {
+			fakeThrottle.CheckReturns(time.Minute, nil)
+			post(url.Values{"decision": {"allow"}, "id": {"test_id"}, "password": {"password"}})
+			Expect(recorder.Code).To(Equal(http.StatusTooManyRequests))
+			Expect(recorder.Header().Get("Retry-After")).To(Equal("60"))
+			Expect(fakeIdentities.VerifyPasswordCallCount()).To(Equal(0))
+		})
+
+		Context("when the identity has MFA enabled", func() {
+			BeforeEach(func() {
+				fakeMFA.EnabledReturns(true, nil)
+			})
+
+			It("should require a code", func() {
+				post(url.Values{"decision": {"allow"}, "id": {"test_id"}, "password": {"password"}})
+				Expect(recorder.Code).To(Equal(http.StatusUnauthorized))
+				Expect(fakeMFA.VerifyCallCount()).To(Equal(0))
+			})
+
+			It("should accept a valid code", func() {
+				fakeMFA.VerifyReturns(true, nil)
+				post(url.Values{"decision": {"allow"}, "id": {"test_id"}, "password": {"password"},
+					"code": {"123456"}})
+				Expect(redirected().Get("code")).To(Equal("the-code"))
+				_, id, code := fakeMFA.VerifyArgsForCall(0)
+				Expect(id).To(Equal("test_id"))
+				Expect(code).To(Equal("123456"))
+			})
+		})
+	})
+})
@@ -0,0 +1,120 @@
+package oauth
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+
+	"github.com/go-chi/chi"
+)
+
+type createClientPostBody struct {
+	Name         string   `json:"name"`
+	RedirectURIs []string `json:"redirectUris"`
+	GrantTypes   []string `json:"grantTypes"`
+	Scopes       []string `json:"scopes"`
+	Confidential bool     `json:"confidential"`
+}
+
+type createClientResponse struct {
+	Status       int     `json:"status"`
+	ClientSecret string  `json:"clientSecret,omitempty"`
+	Client       *Client `json:"client"`
+}
+
+type listClientsResponse struct {
+	Status  int      `json:"status"`
+	Clients []Client `json:"clients"`
+}
+
+//CreateClient ...
+//POST /oauth/clients, registers a client. The secret of a confidential client is only
+//ever returned in this response.
+func (h *HandlerObject) CreateClient(w http.ResponseWriter, req *http.Request) {
+	var jsonDoc createClientPostBody
+	if req.Body == nil || json.NewDecoder(req.Body).Decode(&jsonDoc) != nil {
+		h.softError(http.StatusBadRequest, "bad request", "CreateClient", w, req)
+		return
+	}
+	if message := validateClient(&jsonDoc); message != "" {
+		h.softError(http.StatusBadRequest, message, "CreateClient", w, req)
+		return
+	}
+	secret, client, err := h.Clients.Create(req.Context(), Client{
+		Name:         jsonDoc.Name,
+		RedirectURIs: jsonDoc.RedirectURIs,
+		GrantTypes:   jsonDoc.GrantTypes,
+		Scopes:       jsonDoc.Scopes,
+		Confidential: jsonDoc.Confidential,
+	})
+	if err != nil {
+		h.internalServerError(err, "CreateClient", w, req)
+		return
+	}
+	h.respond(http.StatusCreated, &createClientResponse{
+		Status:       http.StatusCreated,
+		ClientSecret: secret,
+		Client:       client,
+	}, "CreateClient", w, req)
+}
+
+//ListClients ... GET /oauth/clients
+func (h *HandlerObject) ListClients(w http.ResponseWriter, req *http.Request) {
+	clients, err := h.Clients.List(req.Context())
+	if err != nil {
+		h.internalServerError(err, "ListClients", w, req)
+		return
+	}
+	h.respond(http.StatusOK, &listClientsResponse{
+		Status:  http.StatusOK,
+		Clients: clients,
+	}, "ListClients", w, req)
+}
+
+//DeleteClient ...
+//DELETE /oauth/clients/{clientID}, tokens already issued to the client stay valid until
+//they expire.
+func (h *HandlerObject) DeleteClient(w http.ResponseWriter, req *http.Request) {
+	err := h.Clients.Delete(req.Context(), chi.URLParam(req, "clientID"))
+	if err == ErrUnknownClient {
+		h.softError(http.StatusNotFound, "client not found", "DeleteClient", w, req)
+		return
+	}
+	if err != nil {
+		h.internalServerError(err, "DeleteClient", w, req)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+//validateClient returns why a registration can't be accepted, or "".
+func validateClient(client *createClientPostBody) string {
+	if client.Name == "" || len(client.GrantTypes) == 0 {
+		return "missing required client params"
+	}
+	for _, grantType := range client.GrantTypes {
+		if grantType != GrantClientCredentials && grantType != GrantAuthorizationCode &&
+			grantType != GrantRefreshToken {
+			return "unsupported grant type " + grantType
+		}
+	}
+	if contains(client.GrantTypes, GrantClientCredentials) && !client.Confidential {
+		return "client_credentials requires a confidential client"
+	}
+	if contains(client.GrantTypes, GrantRefreshToken) &&
+		!contains(client.GrantTypes, GrantAuthorizationCode) {
+		return "refresh_token requires authorization_code"
+	}
+	if contains(client.GrantTypes, GrantAuthorizationCode) {
+		if len(client.RedirectURIs) == 0 {
+			return "authorization_code requires redirectUris"
+		}
+		for _, redirectURI := range client.RedirectURIs {
+			parsed, err := url.Parse(redirectURI)
+			if err != nil || !parsed.IsAbs() || parsed.Fragment != "" {
+				return "redirectUris must be absolute without a fragment"
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,105 @@
+package oauth_test
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"service/auth/oauth"
+	"service/auth/oauth/oauthfakes"
+	"service/log"
+	"service/log/logfakes"
+	"time"
+
+	"github.com/go-chi/chi"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Client Handler Specs", func() {
+	var (
+		fakeClients *oauthfakes.FakeClientStore
+		router      *chi.Mux
+		recorder    *httptest.ResponseRecorder
+	)
+
+	BeforeEach(func() {
+		fakeClients = &oauthfakes.FakeClientStore{}
+		handler := oauth.NewHandlerObject(log.New(&logfakes.FakeProdInterface{}), fakeClients,
+			nil, nil, time.Minute, nil, nil, nil, nil, nil)
+		router = chi.NewRouter()
+		router.Get("/oauth/clients", handler.ListClients)
+		router.Post("/oauth/clients", handler.CreateClient)
+		router.Delete("/oauth/clients/{clientID}", handler.DeleteClient)
+		recorder = httptest.NewRecorder()
+	})
+
+	serve := func(method, path, body string) {
+		router.ServeHTTP(recorder, httptest.NewRequest(method, path, bytes.NewBufferString(body)))
+	}
+
+	Context("CreateClient", func() {
+		It("should register a client and return its secret once", func() {
+			fakeClients.CreateReturns("the-secret", &oauth.Client{ID: "client_id"}, nil)
+			serve("POST", "/oauth/clients", `{"name": "reporting", "confidential": true,
+				"grantTypes": ["client_credentials"], "scopes": ["events:read"]}`)
+			Expect(recorder.Code).To(Equal(http.StatusCreated))
+			Expect(recorder.Body.String()).To(ContainSubstring(`"clientSecret":"the-secret"`))
+			_, client := fakeClients.CreateArgsForCall(0)
+			Expect(client.Name).To(Equal("reporting"))
+			Expect(client.Confidential).To(BeTrue())
+			Expect(client.Scopes).To(Equal([]string{"events:read"}))
+		})
+
+		refused := func(body string) {
+			serve("POST", "/oauth/clients", body)
+			Expect(recorder.Code).To(Equal(http.StatusBadRequest))
+			Expect(fakeClients.CreateCallCount()).To(Equal(0))
+		}
+
+		It("should require a name and grant types", func() {
+			refused(`{"grantTypes": ["authorization_code"],
+				"redirectUris": ["https://app.example.com/cb"]}`)
+		})
+
+		It("should refuse unsupported grants", func() {
+			refused(`{"name": "app", "grantTypes": ["password"]}`)
+		})
+
+		It("should refuse public client_credentials clients", func() {
+			refused(`{"name": "app", "grantTypes": ["client_credentials"]}`)
+		})
+
+		It("should refuse refresh_token without authorization_code", func() {
+			refused(`{"name": "app", "confidential": true, "grantTypes": ["refresh_token"]}`)
+		})
+
+		It("should require absolute redirectUris for authorization_code", func() {
+			refused(`{"name": "app", "grantTypes": ["authorization_code"]}`)
+			refused(`{"name": "app", "grantTypes": ["authorization_code"],
+				"redirectUris": ["/cb"]}`)
+		})
+	})
+
+	It("should list clients", func() {
+		fakeClients.ListReturns([]oauth.Client{{ID: "client_id", Name: "reporting"}}, nil)
+		serve("GET", "/oauth/clients", "")
+		Expect(recorder.Code).To(Equal(http.StatusOK))
+		Expect(recorder.Body.String()).To(ContainSubstring(`"clientId":"client_id"`))
+	})
+
+	Context("DeleteClient", func() {
+		It("should delete the client", func() {
+			serve("DELETE", "/oauth/clients/client_id", "")
+			Expect(recorder.Code).To(Equal(http.StatusNoContent))
+			_, clientID := fakeClients.DeleteArgsForCall(0)
+			Expect(clientID).To(Equal("client_id"))
+		})
+
+		It("should 404 for unknown clients", func() {
+			fakeClients.DeleteReturns(oauth.ErrUnknownClient)
+			serve("DELETE", "/oauth/clients/missing", "")
+			Expect(recorder.Code).To(Equal(http.StatusNotFound))
+		})
+	})
+})
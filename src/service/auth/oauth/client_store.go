@@ -0,0 +1,182 @@
+package oauth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"service/database"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+//ErrUnknownClient ... is returned for client IDs that are not registered, or wrong secrets.
+var ErrUnknownClient = errors.New("unknown client")
+
+//Client ...
+//is a registered application. Confidential clients authenticate with a secret, public
+//clients such as single page or mobile apps can only use the authorization code grant.
+type Client struct {
+	ID           string    `json:"clientId"`
+	Name         string    `json:"name"`
+	RedirectURIs []string  `json:"redirectUris"`
+	GrantTypes   []string  `json:"grantTypes"`
+	Scopes       []string  `json:"scopes"`
+	Confidential bool      `json:"confidential"`
+	CreatedAt    time.Time `json:"createdAt"`
+	secretHash   string
+}
+
+//Allows ... reports whether the client was registered for grantType.
+func (c *Client) Allows(grantType string) bool {
+	return contains(c.GrantTypes, grantType)
+}
+
+//AllowsRedirect ... reports whether redirectURI exactly matches a registered one.
+func (c *Client) AllowsRedirect(redirectURI string) bool {
+	return contains(c.RedirectURIs, redirectURI)
+}
+
+//ClientStore ... defines registering and looking up OAuth clients.
+//go:generate counterfeiter . ClientStore
+type ClientStore interface {
+	Create(ctx context.Context, client Client) (string, *Client, error)
+	Get(ctx context.Context, clientID string) (*Client, error)
+	Authenticate(ctx context.Context, clientID, secret string) (*Client, error)
+	List(ctx context.Context) ([]Client, error)
+	Delete(ctx context.Context, clientID string) error
+}
+
+//PostgresClientStore ...
+//keeps clients in the oauth_client table, only a sha256 of each generated secret is stored.
+type PostgresClientStore struct {
+	db database.DBInterface
+}
+
+//NewPostgresClientStore ... returns a pointer to a new PostgresClientStore using the passed in db.
+func NewPostgresClientStore(db database.DBInterface) *PostgresClientStore {
+	return &PostgresClientStore{
+		db: db,
+	}
+}
+
+const clientColumns = "id, name, secret_hash, redirect_uris, grant_types, scope, created_at"
+
+//Create ...
+//registers client under a new ID, returning its secret when confidential. This is the
+//only time the secret is available.
+func (p *PostgresClientStore) Create(ctx context.Context, client Client) (string, *Client, error) {
+	client.ID = uuid.New().String()
+	client.CreatedAt = time.Now()
+	var secret string
+	if client.Confidential {
+		raw := make([]byte, 32)
+		if _, err := rand.Read(raw); err != nil {
+			return "", nil, err
+		}
+		secret = base64.RawURLEncoding.EncodeToString(raw)
+		client.secretHash = hashSecret(secret)
+	}
+	_, err := p.db.ExecContext(ctx, `INSERT INTO oauth_client (`+clientColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7);`,
+		client.ID,
+		client.Name,
+		client.secretHash,
+		strings.Join(client.RedirectURIs, " "),
+		strings.Join(client.GrantTypes, " "),
+		strings.Join(client.Scopes, " "),
+		client.CreatedAt)
+	if err != nil {
+		return "", nil, err
+	}
+	return secret, &client, nil
+}
+
+//Get ... returns the client, ErrUnknownClient when it isn't registered.
+func (p *PostgresClientStore) Get(ctx context.Context, clientID string) (*Client, error) {
+	client, err := scanClient(p.db.QueryRowContext(ctx,
+		"SELECT "+clientColumns+" FROM oauth_client WHERE id = $1;", clientID))
+	if err == sql.ErrNoRows {
+		return nil, ErrUnknownClient
+	}
+	return client, err
+}
+
+//Authenticate ... returns the confidential client if secret is its secret.
+func (p *PostgresClientStore) Authenticate(ctx context.Context, clientID, secret string) (*Client,
+	error) {
+	client, err := p.Get(ctx, clientID)
+	if err != nil {
+		return nil, err
+	}
+	if !client.Confidential || subtle.ConstantTimeCompare(
+		[]byte(client.secretHash), []byte(hashSecret(secret))) != 1 {
+		return nil, ErrUnknownClient
+	}
+	return client, nil
+}
+
+//List ... returns every registered client, oldest first.
+func (p *PostgresClientStore) List(ctx context.Context) ([]Client, error) {
+	rows, err := p.db.QueryContext(ctx,
+		"SELECT "+clientColumns+" FROM oauth_client ORDER BY created_at;")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	clients := make([]Client, 0)
+	for rows.Next() {
+		client, err := scanClient(rows)
+		if err != nil {
+			return nil, err
+		}
+		clients = append(clients, *client)
+	}
+	return clients, rows.Err()
+}
+
+//Delete ... removes the client, ErrUnknownClient when it isn't registered.
+func (p *PostgresClientStore) Delete(ctx context.Context, clientID string) error {
+	result, err := p.db.ExecContext(ctx,
+		"DELETE FROM oauth_client WHERE id = $1;", clientID)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrUnknownClient
+	}
+	return nil
+}
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanClient(row scanner) (*Client, error) {
+	var client Client
+	var redirectURIs, grantTypes, scope string
+	if err := row.Scan(&client.ID, &client.Name, &client.secretHash, &redirectURIs,
+		&grantTypes, &scope, &client.CreatedAt); err != nil {
+		return nil, err
+	}
+	client.RedirectURIs = strings.Fields(redirectURIs)
+	client.GrantTypes = strings.Fields(grantTypes)
+	client.Scopes = strings.Fields(scope)
+	client.Confidential = client.secretHash != ""
+	return &client, nil
+}
+
+func hashSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
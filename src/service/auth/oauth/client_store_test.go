@@ -0,0 +1,114 @@
+package oauth_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"service/auth/oauth"
+	"service/utils/sqltest"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	sqlmock "gopkg.in/DATA-DOG/go-sqlmock.v1"
+)
+
+var _ = Describe("Client Store Specs", func() {
+	ctx := context.Background()
+	var (
+		store  *oauth.PostgresClientStore
+		db     *sql.DB
+		mockDB sqlmock.Sqlmock
+	)
+
+	columns := []string{"id", "name", "secret_hash", "redirect_uris", "grant_types", "scope",
+		"created_at"}
+
+	hash := func(secret string) string {
+		sum := sha256.Sum256([]byte(secret))
+		return hex.EncodeToString(sum[:])
+	}
+
+	BeforeEach(func() {
+		var sqlmockErr error
+		db, mockDB, sqlmockErr = sqlmock.New()
+		Expect(sqlmockErr).ToNot(HaveOccurred())
+		store = oauth.NewPostgresClientStore(db)
+	})
+
+	AfterEach(func() {
+		Expect(mockDB.ExpectationsWereMet()).To(Succeed())
+	})
+
+	Context("Create", func() {
+		It("should store only a hash of a confidential client's secret", func() {
+			mockDB.ExpectExec("INSERT INTO oauth_client").
+				WithArgs(sqltest.AnyString{}, "reporting", sqltest.AnyString{}, "",
+					"client_credentials", "events:read", sqltest.AnyTime{}).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+
+			secret, client, err := store.Create(ctx, oauth.Client{
+				Name:         "reporting",
+				GrantTypes:   []string{oauth.GrantClientCredentials},
+				Scopes:       []string{"events:read"},
+				Confidential: true,
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(secret).ToNot(BeEmpty())
+			Expect(client.ID).ToNot(BeEmpty())
+		})
+
+		It("should not give a public client a secret", func() {
+			mockDB.ExpectExec("INSERT INTO oauth_client").
+				WithArgs(sqltest.AnyString{}, "spa", "", "https://app.example.com/cb",
+					"authorization_code", "", sqltest.AnyTime{}).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+
+			secret, _, err := store.Create(ctx, oauth.Client{
+				Name:         "spa",
+				RedirectURIs: []string{"https://app.example.com/cb"},
+				GrantTypes:   []string{oauth.GrantAuthorizationCode},
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(secret).To(BeEmpty())
+		})
+	})
+
+	Context("Authenticate", func() {
+		BeforeEach(func() {
+			mockDB.ExpectQuery("SELECT (.+) FROM oauth_client WHERE id").
+				WithArgs("client_id").
+				WillReturnRows(sqlmock.NewRows(columns).AddRow("client_id", "reporting",
+					hash("the-secret"), "", "client_credentials", "events:read", time.Now()))
+		})
+
+		It("should return the client for its secret", func() {
+			client, err := store.Authenticate(ctx, "client_id", "the-secret")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(client.Confidential).To(BeTrue())
+			Expect(client.Scopes).To(Equal([]string{"events:read"}))
+			Expect(client.Allows(oauth.GrantClientCredentials)).To(BeTrue())
+		})
+
+		It("should refuse a wrong secret", func() {
+			_, err := store.Authenticate(ctx, "client_id", "wrong")
+			Expect(err).To(Equal(oauth.ErrUnknownClient))
+		})
+	})
+
+	It("should report unknown clients", func() {
+		mockDB.ExpectQuery("SELECT (.+) FROM oauth_client WHERE id").
+			WithArgs("missing").
+			WillReturnError(sql.ErrNoRows)
+		_, err := store.Get(ctx, "missing")
+		Expect(err).To(Equal(oauth.ErrUnknownClient))
+	})
+
+	It("should report deleting an unknown client", func() {
+		mockDB.ExpectExec("DELETE FROM oauth_client").
+			WithArgs("missing").
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		Expect(store.Delete(ctx, "missing")).To(Equal(oauth.ErrUnknownClient))
+	})
+})
@@ -0,0 +1,115 @@
+package oauth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"database/sql"
+	"encoding/base64"
+	"errors"
+	"service/database"
+	"time"
+)
+
+//CodeTTL ... is how long an authorization code can be exchanged, RFC 6749 recommends
+//no more than 10 minutes.
+const CodeTTL = time.Minute
+
+//ErrInvalidCode ... is returned for codes that are unknown, expired, used, or presented
+//with the wrong client, redirect URI or PKCE verifier.
+var ErrInvalidCode = errors.New("invalid authorization code")
+
+//Code ... is what an authorization code stands for.
+type Code struct {
+	ClientID            string
+	IdentityID          string
+	RedirectURI         string
+	Scope               string
+	CodeChallenge       string
+	CodeChallengeMethod string
+}
+
+//CodeStore ... defines issuing and redeeming single use authorization codes.
+//go:generate counterfeiter . CodeStore
+type CodeStore interface {
+	Issue(ctx context.Context, code Code) (string, error)
+	Redeem(ctx context.Context, code, clientID, redirectURI, verifier string) (*Code, error)
+}
+
+//PostgresCodeStore ... keeps a sha256 of each code in the oauth_code table.
+type PostgresCodeStore struct {
+	db database.DBInterface
+}
+
+//NewPostgresCodeStore ... returns a pointer to a new PostgresCodeStore using the passed in db.
+func NewPostgresCodeStore(db database.DBInterface) *PostgresCodeStore {
+	return &PostgresCodeStore{
+		db: db,
+	}
+}
+
+//Issue ... stores code and returns the value to hand the client.
+func (p *PostgresCodeStore) Issue(ctx context.Context, code Code) (string, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+	value := base64.RawURLEncoding.EncodeToString(raw)
+	rightNow := time.Now()
+	_, err := p.db.ExecContext(ctx, `INSERT INTO oauth_code
+		(code_hash, client_id, identity_id, redirect_uri, scope, code_challenge,
+			code_challenge_method, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9);`,
+		hashSecret(value),
+		code.ClientID,
+		code.IdentityID,
+		code.RedirectURI,
+		code.Scope,
+		code.CodeChallenge,
+		code.CodeChallengeMethod,
+		rightNow.Add(CodeTTL),
+		rightNow)
+	if err != nil {
+		return "", err
+	}
+	return value, nil
+}
+
+//Redeem ...
+//spends the code and checks it was issued to clientID for redirectURI, with a challenge
+//verifier answers. A code is spent by the first attempt whether or not that succeeds.
+func (p *PostgresCodeStore) Redeem(ctx context.Context, value, clientID, redirectURI,
+	verifier string) (*Code, error) {
+	if value == "" {
+		return nil, ErrInvalidCode
+	}
+	var code Code
+	err := p.db.QueryRowContext(ctx, `UPDATE oauth_code SET used_at = $2
+		WHERE code_hash = $1 AND used_at IS NULL AND expires_at > $2
+		RETURNING client_id, identity_id, redirect_uri, scope, code_challenge,
+			code_challenge_method;`,
+		hashSecret(value), time.Now()).Scan(&code.ClientID, &code.IdentityID,
+		&code.RedirectURI, &code.Scope, &code.CodeChallenge, &code.CodeChallengeMethod)
+	if err == sql.ErrNoRows {
+		return nil, ErrInvalidCode
+	}
+	if err != nil {
+		return nil, err
+	}
+	if code.ClientID != clientID || code.RedirectURI != redirectURI ||
+		!VerifyPKCE(code.CodeChallenge, code.CodeChallengeMethod, verifier) {
+		return nil, ErrInvalidCode
+	}
+	return &code, nil
+}
+
+//VerifyPKCE ... checks verifier against an RFC 7636 S256 code challenge.
+func VerifyPKCE(challenge, method, verifier string) bool {
+	if method != PKCEMethodS256 || len(verifier) < 43 || len(verifier) > 128 {
+		return false
+	}
+	sum := sha256.Sum256([]byte(verifier))
+	expected := base64.RawURLEncoding.EncodeToString(sum[:])
+	return subtle.ConstantTimeCompare([]byte(expected), []byte(challenge)) == 1
+}
@@ -0,0 +1,118 @@
+package oauth_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"service/auth/oauth"
+	"service/utils/sqltest"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	sqlmock "gopkg.in/DATA-DOG/go-sqlmock.v1"
+)
+
+var _ = Describe("Code Store Specs", func() {
+	ctx := context.Background()
+	var (
+		store  *oauth.PostgresCodeStore
+		db     *sql.DB
+		mockDB sqlmock.Sqlmock
+	)
+
+	verifier := strings.Repeat("v", 43)
+	sum := sha256.Sum256([]byte(verifier))
+	challenge := base64.RawURLEncoding.EncodeToString(sum[:])
+
+	columns := []string{"client_id", "identity_id", "redirect_uri", "scope", "code_challenge",
+		"code_challenge_method"}
+
+	BeforeEach(func() {
+		var sqlmockErr error
+		db, mockDB, sqlmockErr = sqlmock.New()
+		Expect(sqlmockErr).ToNot(HaveOccurred())
+		store = oauth.NewPostgresCodeStore(db)
+	})
+
+	AfterEach(func() {
+		Expect(mockDB.ExpectationsWereMet()).To(Succeed())
+	})
+
+	It("should store only a hash of an issued code", func() {
+		mockDB.ExpectExec("INSERT INTO oauth_code").
+			WithArgs(sqltest.AnyString{}, "client_id", "test_id", "https://app.example.com/cb",
+				"identity:read", challenge, oauth.PKCEMethodS256, sqltest.AnyTime{},
+				sqltest.AnyTime{}).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		code, err := store.Issue(ctx, oauth.Code{
+			ClientID:            "client_id",
+			IdentityID:          "test_id",
+			RedirectURI:         "https://app.example.com/cb",
+			Scope:               "identity:read",
+			CodeChallenge:       challenge,
+			CodeChallengeMethod: oauth.PKCEMethodS256,
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(code).ToNot(BeEmpty())
+	})
+
+	Context("Redeem", func() {
+		BeforeEach(func() {
+			mockDB.ExpectQuery("UPDATE oauth_code SET used_at").
+				WithArgs(sqltest.AnyString{}, sqltest.AnyTime{}).
+				WillReturnRows(sqlmock.NewRows(columns).AddRow("client_id", "test_id",
+					"https://app.example.com/cb", "identity:read", challenge,
+					oauth.PKCEMethodS256))
+		})
+
+		It("should return the code for its client, redirect and verifier", func() {
+			code, err := store.Redeem(ctx, "the-code", "client_id", "https://app.example.com/cb",
+				verifier)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(code.IdentityID).To(Equal("test_id"))
+			Expect(code.Scope).To(Equal("identity:read"))
+		})
+
+		It("should refuse a wrong verifier", func() {
+			_, err := store.Redeem(ctx, "the-code", "client_id", "https://app.example.com/cb",
+				strings.Repeat("w", 43))
+			Expect(err).To(Equal(oauth.ErrInvalidCode))
+		})
+
+		It("should refuse another client", func() {
+			_, err := store.Redeem(ctx, "the-code", "other_client", "https://app.example.com/cb",
+				verifier)
+			Expect(err).To(Equal(oauth.ErrInvalidCode))
+		})
+
+		It("should refuse another redirect_uri", func() {
+			_, err := store.Redeem(ctx, "the-code", "client_id", "https://evil.example.com/cb",
+				verifier)
+			Expect(err).To(Equal(oauth.ErrInvalidCode))
+		})
+	})
+
+	It("should refuse a spent or expired code", func() {
+		mockDB.ExpectQuery("UPDATE oauth_code SET used_at").
+			WillReturnError(sql.ErrNoRows)
+		_, err := store.Redeem(ctx, "the-code", "client_id", "https://app.example.com/cb", verifier)
+		Expect(err).To(Equal(oauth.ErrInvalidCode))
+	})
+
+	Context("VerifyPKCE", func() {
+		It("should only accept S256", func() {
+			Expect(oauth.VerifyPKCE(challenge, oauth.PKCEMethodS256, verifier)).To(BeTrue())
+			Expect(oauth.VerifyPKCE(verifier, "plain", verifier)).To(BeFalse())
+		})
+
+		It("should refuse verifiers outside RFC 7636 lengths", func() {
+			short := strings.Repeat("v", 42)
+			shortSum := sha256.Sum256([]byte(short))
+			Expect(oauth.VerifyPKCE(base64.RawURLEncoding.EncodeToString(shortSum[:]),
+				oauth.PKCEMethodS256, short)).To(BeFalse())
+		})
+	})
+})
@@ -0,0 +1,96 @@
+package oauth
+
+import (
+	"sort"
+	"strings"
+)
+
+//Grant types /oauth/token accepts.
+const (
+	GrantClientCredentials = "client_credentials"
+	GrantAuthorizationCode = "authorization_code"
+	GrantRefreshToken      = "refresh_token"
+)
+
+//Error codes from RFC 6749 section 5.2 and 4.1.2.1.
+const (
+	ErrorInvalidRequest          = "invalid_request"
+	ErrorInvalidClient           = "invalid_client"
+	ErrorInvalidGrant            = "invalid_grant"
+	ErrorUnauthorizedClient      = "unauthorized_client"
+	ErrorUnsupportedGrantType    = "unsupported_grant_type"
+	ErrorUnsupportedResponseType = "unsupported_response_type"
+	ErrorInvalidScope            = "invalid_scope"
+	ErrorAccessDenied            = "access_denied"
+)
+
+//PKCEMethodS256 ... is the only code_challenge_method accepted, plain gives no protection
+//against an intercepted authorization request.
+const PKCEMethodS256 = "S256"
+
+//Error ... is an OAuth error response.
+type Error struct {
+	Code        string `json:"error"`
+	Description string `json:"error_description,omitempty"`
+}
+
+func (e *Error) Error() string {
+	if e.Description == "" {
+		return e.Code
+	}
+	return e.Code + ": " + e.Description
+}
+
+//ParseScope ... splits a space delimited scope parameter, dropping duplicates.
+func ParseScope(scope string) []string {
+	seen := make(map[string]bool)
+	scopes := make([]string, 0)
+	for _, s := range strings.Fields(scope) {
+		if !seen[s] {
+			seen[s] = true
+			scopes = append(scopes, s)
+		}
+	}
+	return scopes
+}
+
+//FormatScope ... joins scopes into a scope parameter, sorted so equal sets compare equal.
+func FormatScope(scopes []string) string {
+	sorted := append([]string(nil), scopes...)
+	sort.Strings(sorted)
+	return strings.Join(sorted, " ")
+}
+
+//narrow returns the requested scopes, or all of allowed when none were requested, and
+//false if any requested scope is not allowed.
+func narrow(requested, allowed []string) ([]string, bool) {
+	if len(requested) == 0 {
+		return allowed, true
+	}
+	for _, scope := range requested {
+		if !contains(allowed, scope) {
+			return nil, false
+		}
+	}
+	return requested, true
+}
+
+//intersect keeps the scopes present in both lists, in the order of the first.
+func intersect(scopes, other []string) []string {
+	kept := make([]string, 0, len(scopes))
+	for _, scope := range scopes {
+		if contains(other, scope) {
+			kept = append(kept, scope)
+		}
+	}
+	return kept
+}
+
+func contains(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,133 @@
+package oauth
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"service/auth"
+	"service/auth/mfa"
+	"service/auth/permission"
+	"service/auth/refresh"
+	"service/auth/throttle"
+	"service/handlers/loggederror"
+	"service/handlers/request"
+	"service/identity"
+	"service/log"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+//HandlerObject ...
+//holds everything the authorization server needs: clients and codes, the jwt and refresh
+//token issuers, and the identity checks used on the consent page.
+type HandlerObject struct {
+	Log        log.ProdInterface
+	Clients    ClientStore
+	Codes      CodeStore
+	Tokens     *auth.Client
+	AccessTTL  time.Duration
+	Refresh    refresh.Interface
+	Identities identity.ServiceInterface
+	Roles      permission.Store
+	MFA        mfa.Interface
+	Throttle   throttle.Interface
+}
+
+//NewHandlerObject ...
+//accessTTL is only reported as expires_in, the token service decides the real lifetime.
+func NewHandlerObject(logClient log.ProdInterface, clients ClientStore, codes CodeStore,
+	tokens *auth.Client, accessTTL time.Duration, refresh refresh.Interface,
+	identities identity.ServiceInterface, roles permission.Store, mfa mfa.Interface,
+	throttle throttle.Interface) *HandlerObject {
+	return &HandlerObject{
+		Log:        logClient,
+		Clients:    clients,
+		Codes:      codes,
+		Tokens:     tokens,
+		AccessTTL:  accessTTL,
+		Refresh:    refresh,
+		Identities: identities,
+		Roles:      roles,
+		MFA:        mfa,
+		Throttle:   throttle,
+	}
+}
+
+//oauthError writes an RFC 6749 JSON error and logs it like any other expected failure.
+func (h *HandlerObject) oauthError(status int, err *Error, source string,
+	w http.ResponseWriter, req *http.Request) {
+	h.logSoftError(err.Error(), source, req)
+	h.respond(status, err, source, w, req)
+}
+
+//logSoftError logs like loggederror.RespondWithWithExpectedSoftError for responses that
+//are not plain text.
+func (h *HandlerObject) logSoftError(message, source string, req *http.Request) {
+	h.Log.Info("Logic Error->",
+		zap.String("requestID", request.RetreiveRequestID(req.Context())),
+		zap.String("message", message),
+		zap.String("source", "oauth_handler::"+source))
+}
+
+func (h *HandlerObject) respond(status int, response interface{}, source string,
+	w http.ResponseWriter, req *http.Request) {
+	bytesArray, marshalErr := json.Marshal(response)
+	if marshalErr != nil {
+		h.internalServerError(marshalErr, source, w, req)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Pragma", "no-cache")
+	w.WriteHeader(status)
+	if _, writeErr := w.Write(bytesArray); writeErr != nil {
+		h.Log.Error("oauth_handler::"+source, zap.Error(writeErr))
+	}
+}
+
+//redirect sends the user agent back to the client with params added to redirectURI.
+func (h *HandlerObject) redirect(redirectURI string, params url.Values, w http.ResponseWriter,
+	req *http.Request) {
+	target, err := url.Parse(redirectURI)
+	if err != nil {
+		h.internalServerError(err, "Authorize", w, req)
+		return
+	}
+	query := target.Query()
+	for key, values := range params {
+		for _, value := range values {
+			if value != "" {
+				query.Add(key, value)
+			}
+		}
+	}
+	target.RawQuery = query.Encode()
+	http.Redirect(w, req, target.String(), http.StatusFound)
+}
+
+// internalServerError is used to wrap our loggederror for this route.
+func (h *HandlerObject) internalServerError(err error, source string, w http.ResponseWriter,
+	req *http.Request) {
+	loggederror.RespondWithProperErrorAndLogIt(
+		h.Log,
+		http.StatusInternalServerError,
+		err,
+		"oauth_handler::"+source,
+		w,
+		req,
+	)
+}
+
+// softError is used to wrap our loggederror for expected failures on this route.
+func (h *HandlerObject) softError(status int, message, source string, w http.ResponseWriter,
+	req *http.Request) {
+	loggederror.RespondWithWithExpectedSoftError(
+		h.Log,
+		status,
+		message,
+		"oauth_handler::"+source,
+		w,
+		req,
+	)
+}
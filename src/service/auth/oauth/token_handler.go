@@ -0,0 +1,258 @@
+package oauth
+
+import (
+	"net/http"
+	"net/url"
+	"service/auth"
+	"service/auth/refresh"
+	"time"
+)
+
+type tokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int64  `json:"expires_in"`
+	RefreshToken string `json:"refresh_token,omitempty"`
+	Scope        string `json:"scope,omitempty"`
+}
+
+//IntrospectionResponse ... is the RFC 7662 answer about a token.
+type IntrospectionResponse struct {
+	Active    bool   `json:"active"`
+	Scope     string `json:"scope,omitempty"`
+	ClientID  string `json:"client_id,omitempty"`
+	Subject   string `json:"sub,omitempty"`
+	TokenType string `json:"token_type,omitempty"`
+	Issuer    string `json:"iss,omitempty"`
+	Audience  string `json:"aud,omitempty"`
+	ID        string `json:"jti,omitempty"`
+	ExpiresAt int64  `json:"exp,omitempty"`
+	IssuedAt  int64  `json:"iat,omitempty"`
+	NotBefore int64  `json:"nbf,omitempty"`
+}
+
+//Token ...
+//POST /oauth/token, the RFC 6749 token endpoint for the client_credentials,
+//authorization_code (PKCE required) and refresh_token grants.
+func (h *HandlerObject) Token(w http.ResponseWriter, req *http.Request) {
+	if err := req.ParseForm(); err != nil {
+		h.oauthError(http.StatusBadRequest, &Error{Code: ErrorInvalidRequest,
+			Description: "malformed form body"}, "Token", w, req)
+		return
+	}
+	client, ok := h.authenticateClient("Token", w, req)
+	if !ok {
+		return
+	}
+	grantType := req.PostForm.Get("grant_type")
+	if grantType != GrantClientCredentials && grantType != GrantAuthorizationCode &&
+		grantType != GrantRefreshToken {
+		h.oauthError(http.StatusBadRequest, &Error{Code: ErrorUnsupportedGrantType},
+			"Token", w, req)
+		return
+	}
+	if !client.Allows(grantType) {
+		h.oauthError(http.StatusBadRequest, &Error{Code: ErrorUnauthorizedClient,
+			Description: "client is not registered for " + grantType}, "Token", w, req)
+		return
+	}
+	switch grantType {
+	case GrantClientCredentials:
+		h.clientCredentials(client, w, req)
+	case GrantAuthorizationCode:
+		h.authorizationCode(client, w, req)
+	case GrantRefreshToken:
+		h.refreshToken(client, w, req)
+	}
+}
+
+//Introspect ...
+//POST /oauth/introspect, RFC 7662 introspection of access tokens for confidential
+//clients. Refresh tokens and anything unrecognised are reported inactive.
+func (h *HandlerObject) Introspect(w http.ResponseWriter, req *http.Request) {
+	if err := req.ParseForm(); err != nil {
+		h.oauthError(http.StatusBadRequest, &Error{Code: ErrorInvalidRequest,
+			Description: "malformed form body"}, "Introspect", w, req)
+		return
+	}
+	client, ok := h.authenticateClient("Introspect", w, req)
+	if !ok {
+		return
+	}
+	if !client.Confidential {
+		h.oauthError(http.StatusUnauthorized, &Error{Code: ErrorInvalidClient,
+			Description: "introspection requires client authentication"}, "Introspect", w, req)
+		return
+	}
+	token := req.PostForm.Get("token")
+	if token == "" {
+		h.oauthError(http.StatusBadRequest, &Error{Code: ErrorInvalidRequest,
+			Description: "missing token"}, "Introspect", w, req)
+		return
+	}
+	claims, err := h.Tokens.ValidateToken(req.Context(), token)
+	if err == auth.ErrInvalidToken || err == auth.ErrRevokedToken {
+		h.respond(http.StatusOK, &IntrospectionResponse{Active: false}, "Introspect", w, req)
+		return
+	}
+	if err != nil {
+		h.internalServerError(err, "Introspect", w, req)
+		return
+	}
+	clientID, _ := claims.Claim("client_id")
+	clientIDString, _ := clientID.(string)
+	h.respond(http.StatusOK, &IntrospectionResponse{
+		Active:    true,
+		Scope:     claims.Scope,
+		ClientID:  clientIDString,
+		Subject:   claims.Subject,
+		TokenType: "Bearer",
+		Issuer:    claims.Issuer,
+		Audience:  claims.Audience,
+		ID:        claims.Id,
+		ExpiresAt: claims.ExpiresAt,
+		IssuedAt:  claims.IssuedAt,
+		NotBefore: claims.NotBefore,
+	}, "Introspect", w, req)
+}
+
+//authenticateClient reads client_secret_basic or client_secret_post credentials, or a
+//bare client_id for public clients, responding itself when authentication fails.
+func (h *HandlerObject) authenticateClient(source string, w http.ResponseWriter,
+	req *http.Request) (*Client, bool) {
+	clientID, secret, usedBasic := req.BasicAuth()
+	if usedBasic {
+		//RFC 6749 section 2.3.1 form encodes both before base64.
+		var idErr, secretErr error
+		clientID, idErr = url.QueryUnescape(clientID)
+		secret, secretErr = url.QueryUnescape(secret)
+		if idErr != nil || secretErr != nil {
+			clientID, secret = "", ""
+		}
+	} else {
+		clientID, secret = req.PostForm.Get("client_id"), req.PostForm.Get("client_secret")
+	}
+	var client *Client
+	var err error
+	if secret != "" || usedBasic {
+		client, err = h.Clients.Authenticate(req.Context(), clientID, secret)
+	} else if clientID != "" {
+		client, err = h.Clients.Get(req.Context(), clientID)
+		if err == nil && client.Confidential {
+			err = ErrUnknownClient
+		}
+	} else {
+		err = ErrUnknownClient
+	}
+	if err == ErrUnknownClient {
+		if usedBasic {
+			w.Header().Set("WWW-Authenticate", `Basic realm="oauth"`)
+		}
+		h.oauthError(http.StatusUnauthorized, &Error{Code: ErrorInvalidClient}, source, w, req)
+		return nil, false
+	}
+	if err != nil {
+		h.internalServerError(err, source, w, req)
+		return nil, false
+	}
+	return client, true
+}
+
+func (h *HandlerObject) clientCredentials(client *Client, w http.ResponseWriter,
+	req *http.Request) {
+	if !client.Confidential {
+		h.oauthError(http.StatusBadRequest, &Error{Code: ErrorUnauthorizedClient,
+			Description: "public clients can't use client_credentials"}, "Token", w, req)
+		return
+	}
+	scopes, ok := narrow(ParseScope(req.PostForm.Get("scope")), client.Scopes)
+	if !ok {
+		h.oauthError(http.StatusBadRequest, &Error{Code: ErrorInvalidScope}, "Token", w, req)
+		return
+	}
+	h.issue(client, client.ID, scopes, "", w, req)
+}
+
+func (h *HandlerObject) authorizationCode(client *Client, w http.ResponseWriter,
+	req *http.Request) {
+	code, err := h.Codes.Redeem(req.Context(), req.PostForm.Get("code"), client.ID,
+		req.PostForm.Get("redirect_uri"), req.PostForm.Get("code_verifier"))
+	if err == ErrInvalidCode {
+		h.oauthError(http.StatusBadRequest, &Error{Code: ErrorInvalidGrant,
+			Description: err.Error()}, "Token", w, req)
+		return
+	}
+	if err != nil {
+		h.internalServerError(err, "Token", w, req)
+		return
+	}
+	var refreshToken string
+	if client.Allows(GrantRefreshToken) {
+		refreshToken, err = h.Refresh.IssueGrant(req.Context(), refresh.Grant{
+			IdentityID: code.IdentityID,
+			ClientID:   client.ID,
+			Scope:      code.Scope,
+		})
+		if err != nil {
+			h.internalServerError(err, "Token", w, req)
+			return
+		}
+	}
+	h.issueForIdentity(client, code.IdentityID, ParseScope(code.Scope), refreshToken, w, req)
+}
+
+func (h *HandlerObject) refreshToken(client *Client, w http.ResponseWriter,
+	req *http.Request) {
+	grant, refreshToken, err := h.Refresh.RotateGrant(req.Context(), req.PostForm.Get("refresh_token"),
+		client.ID)
+	if err == refresh.ErrInvalid || err == refresh.ErrReused {
+		h.oauthError(http.StatusBadRequest, &Error{Code: ErrorInvalidGrant,
+			Description: refresh.ErrInvalid.Error()}, "Token", w, req)
+		return
+	}
+	if err != nil {
+		h.internalServerError(err, "Token", w, req)
+		return
+	}
+	//The new refresh token keeps the original grant, only the access token is narrowed.
+	scopes, ok := narrow(ParseScope(req.PostForm.Get("scope")), ParseScope(grant.Scope))
+	if !ok {
+		h.oauthError(http.StatusBadRequest, &Error{Code: ErrorInvalidScope}, "Token", w, req)
+		return
+	}
+	h.issueForIdentity(client, grant.IdentityID, scopes, refreshToken, w, req)
+}
+
+//issueForIdentity drops any consented scope the identity has since lost.
+func (h *HandlerObject) issueForIdentity(client *Client, identityID string, scopes []string,
+	refreshToken string, w http.ResponseWriter, req *http.Request) {
+	permissions, err := h.Roles.Permissions(req.Context(), identityID)
+	if err != nil {
+		h.internalServerError(err, "Token", w, req)
+		return
+	}
+	h.issue(client, identityID, intersect(scopes, permissions), refreshToken, w, req)
+}
+
+func (h *HandlerObject) issue(client *Client, subject string, scopes []string,
+	refreshToken string, w http.ResponseWriter, req *http.Request) {
+	input := map[string]interface{}{
+		"sub":       subject,
+		"client_id": client.ID,
+	}
+	if len(scopes) > 0 {
+		input["scope"] = scopes
+	}
+	accessToken, err := h.Tokens.GenerateToken(req.Context(), input)
+	if err != nil {
+		h.internalServerError(err, "Token", w, req)
+		return
+	}
+	h.respond(http.StatusOK, &tokenResponse{
+		AccessToken:  accessToken,
+		TokenType:    "Bearer",
+		ExpiresIn:    int64(h.AccessTTL / time.Second),
+		RefreshToken: refreshToken,
+		Scope:        FormatScope(scopes),
+	}, "Token", w, req)
+}
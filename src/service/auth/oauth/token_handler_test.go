@@ -0,0 +1,297 @@
+package oauth_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"service/auth"
+	"service/auth/basic"
+	"service/auth/oauth"
+	"service/auth/oauth/oauthfakes"
+	"service/auth/permission/permissionfakes"
+	"service/auth/refresh"
+	"service/auth/refresh/refreshfakes"
+	"service/auth/revocation/revocationfakes"
+	"service/auth/token/jwt"
+	"service/auth/token/tokenfakes"
+	"service/log"
+	"service/log/logfakes"
+	"strings"
+	"time"
+
+	jwtGo "github.com/dgrijalva/jwt-go"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Token Handler Specs", func() {
+	var (
+		handler      *oauth.HandlerObject
+		fakeClients  *oauthfakes.FakeClientStore
+		fakeCodes    *oauthfakes.FakeCodeStore
+		fakeToken    *tokenfakes.FakeInterface
+		fakeRevoked  *revocationfakes.FakeStore
+		fakeRefresh  *refreshfakes.FakeInterface
+		fakeRoles    *permissionfakes.FakeStore
+		recorder     *httptest.ResponseRecorder
+		confidential *oauth.Client
+		public       *oauth.Client
+	)
+
+	BeforeEach(func() {
+		fakeClients = &oauthfakes.FakeClientStore{}
+		fakeCodes = &oauthfakes.FakeCodeStore{}
+		fakeToken = &tokenfakes.FakeInterface{}
+		fakeRevoked = &revocationfakes.FakeStore{}
+		fakeRefresh = &refreshfakes.FakeInterface{}
+		fakeRoles = &permissionfakes.FakeStore{}
+		handler = oauth.NewHandlerObject(log.New(&logfakes.FakeProdInterface{}), fakeClients,
+			fakeCodes, auth.NewClient(basic.NewAuth(), fakeToken, fakeRevoked), time.Minute,
+			fakeRefresh, nil, fakeRoles, nil, nil)
+		recorder = httptest.NewRecorder()
+
+		confidential = &oauth.Client{
+			ID:           "reporting",
+			GrantTypes:   []string{oauth.GrantClientCredentials},
+			Scopes:       []string{"events:read", "identity:read"},
+			Confidential: true,
+		}
+		public = &oauth.Client{
+			ID:           "spa",
+			RedirectURIs: []string{"https://app.example.com/cb"},
+			GrantTypes:   []string{oauth.GrantAuthorizationCode, oauth.GrantRefreshToken},
+			Scopes:       []string{"identity:read", "identity:write"},
+		}
+		fakeClients.AuthenticateReturns(confidential, nil)
+		fakeClients.GetReturns(public, nil)
+		fakeToken.GenerateReturns("access-token", nil)
+		fakeRoles.PermissionsReturns([]string{"identity:read"}, nil)
+	})
+
+	post := func(form url.Values, basicAuth ...string) map[string]interface{} {
+		req := httptest.NewRequest("POST", "/oauth/token", strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		if len(basicAuth) == 2 {
+			req.SetBasicAuth(basicAuth[0], basicAuth[1])
+		}
+		handler.Token(recorder, req)
+		body := make(map[string]interface{})
+		Expect(json.Unmarshal(recorder.Body.Bytes(), &body)).To(Succeed())
+		return body
+	}
+
+	Context("client_credentials", func() {
+		It("should issue an access token for the client itself", func() {
+			body := post(url.Values{"grant_type": {"client_credentials"},
+				"scope": {"events:read"}}, "reporting", "the-secret")
+			Expect(recorder.Code).To(Equal(http.StatusOK))
+			Expect(recorder.Header().Get("Cache-Control")).To(Equal("no-store"))
+			Expect(body["access_token"]).To(Equal("access-token"))
+			Expect(body["token_type"]).To(Equal("Bearer"))
+			Expect(body["expires_in"]).To(BeNumerically("==", 60))
+			Expect(body["scope"]).To(Equal("events:read"))
+			Expect(body).ToNot(HaveKey("refresh_token"))
+
+			_, clientID, secret := fakeClients.AuthenticateArgsForCall(0)
+			Expect(clientID).To(Equal("reporting"))
+			Expect(secret).To(Equal("the-secret"))
+			input := fakeToken.GenerateArgsForCall(0)
+			Expect(input["sub"]).To(Equal("reporting"))
+			Expect(input["client_id"]).To(Equal("reporting"))
+		})
+
+		It("should accept credentials in the body", func() {
+			post(url.Values{"grant_type": {"client_credentials"}, "client_id": {"reporting"},
+				"client_secret": {"the-secret"}})
+			Expect(recorder.Code).To(Equal(http.StatusOK))
+			Expect(fakeClients.AuthenticateCallCount()).To(Equal(1))
+		})
+
+		It("should grant every registered scope when none are requested", func() {
+			body := post(url.Values{"grant_type": {"client_credentials"}}, "reporting", "the-secret")
+			Expect(body["scope"]).To(Equal("events:read identity:read"))
+		})
+
+		It("should refuse scopes the client was not registered for", func() {
+			body := post(url.Values{"grant_type": {"client_credentials"},
+				"scope": {"roles:admin"}}, "reporting", "the-secret")
+			Expect(recorder.Code).To(Equal(http.StatusBadRequest))
+			Expect(body["error"]).To(Equal(oauth.ErrorInvalidScope))
+		})
+
+		It("should refuse a bad secret with a challenge", func() {
+			fakeClients.AuthenticateReturns(nil, oauth.ErrUnknownClient)
+			body := post(url.Values{"grant_type": {"client_credentials"}}, "reporting", "wrong")
+			Expect(recorder.Code).To(Equal(http.StatusUnauthorized))
+			Expect(recorder.Header().Get("WWW-Authenticate")).To(ContainSubstring("Basic"))
+			Expect(body["error"]).To(Equal(oauth.ErrorInvalidClient))
+		})
+
+		It("should refuse a client not registered for the grant", func() {
+			body := post(url.Values{"grant_type": {"client_credentials"}, "client_id": {"spa"}})
+			Expect(recorder.Code).To(Equal(http.StatusBadRequest))
+			Expect(body["error"]).To(Equal(oauth.ErrorUnauthorizedClient))
+		})
+	})
+
+	It("should refuse a confidential client that omits its secret", func() {
+		fakeClients.GetReturns(confidential, nil)
+		body := post(url.Values{"grant_type": {"client_credentials"}, "client_id": {"reporting"}})
+		Expect(recorder.Code).To(Equal(http.StatusUnauthorized))
+		Expect(body["error"]).To(Equal(oauth.ErrorInvalidClient))
+	})
+
+	It("should refuse unsupported grants", func() {
+		body := post(url.Values{"grant_type": {"password"}}, "reporting", "the-secret")
+		Expect(recorder.Code).To(Equal(http.StatusBadRequest))
+		Expect(body["error"]).To(Equal(oauth.ErrorUnsupportedGrantType))
+	})
+
+	Context("authorization_code", func() {
+		form := url.Values{"grant_type": {"authorization_code"}, "client_id": {"spa"},
+			"code": {"the-code"}, "redirect_uri": {"https://app.example.com/cb"},
+			"code_verifier": {"the-verifier"}}
+
+		BeforeEach(func() {
+			fakeCodes.RedeemReturns(&oauth.Code{
+				ClientID:   "spa",
+				IdentityID: "test_id",
+				Scope:      "identity:read identity:write",
+			}, nil)
+			fakeRefresh.IssueGrantReturns("refresh-token", nil)
+		})
+
+		It("should exchange the code for tokens", func() {
+			body := post(form)
+			Expect(recorder.Code).To(Equal(http.StatusOK))
+			Expect(body["refresh_token"]).To(Equal("refresh-token"))
+
+			_, code, clientID, redirectURI, verifier := fakeCodes.RedeemArgsForCall(0)
+			Expect(code).To(Equal("the-code"))
+			Expect(clientID).To(Equal("spa"))
+			Expect(redirectURI).To(Equal("https://app.example.com/cb"))
+			Expect(verifier).To(Equal("the-verifier"))
+			_, grant := fakeRefresh.IssueGrantArgsForCall(0)
+			Expect(grant).To(Equal(refresh.Grant{
+				IdentityID: "test_id",
+				ClientID:   "spa",
+				Scope:      "identity:read identity:write",
+			}))
+		})
+
+		It("should drop consented scopes the identity no longer holds", func() {
+			body := post(form)
+			Expect(body["scope"]).To(Equal("identity:read"))
+			input := fakeToken.GenerateArgsForCall(0)
+			Expect(input["sub"]).To(Equal("test_id"))
+			Expect(input["scope"]).To(Equal([]string{"identity:read"}))
+		})
+
+		It("should refuse an invalid code", func() {
+			fakeCodes.RedeemReturns(nil, oauth.ErrInvalidCode)
+			body := post(form)
+			Expect(recorder.Code).To(Equal(http.StatusBadRequest))
+			Expect(body["error"]).To(Equal(oauth.ErrorInvalidGrant))
+			Expect(fakeToken.GenerateCallCount()).To(Equal(0))
+		})
+	})
+
+	Context("refresh_token", func() {
+		form := url.Values{"grant_type": {"refresh_token"}, "client_id": {"spa"},
+			"refresh_token": {"old-refresh"}}
+
+		BeforeEach(func() {
+			fakeRefresh.RotateGrantReturns(&refresh.Grant{
+				IdentityID: "test_id",
+				ClientID:   "spa",
+				Scope:      "identity:read",
+			}, "new-refresh", nil)
+		})
+
+		It("should rotate the refresh token within the client", func() {
+			body := post(form)
+			Expect(recorder.Code).To(Equal(http.StatusOK))
+			Expect(body["refresh_token"]).To(Equal("new-refresh"))
+			_, token, clientID := fakeRefresh.RotateGrantArgsForCall(0)
+			Expect(token).To(Equal("old-refresh"))
+			Expect(clientID).To(Equal("spa"))
+		})
+
+		It("should refuse widening the original grant", func() {
+			widened := url.Values{"scope": {"identity:write"}}
+			for key, values := range form {
+				widened[key] = values
+			}
+			body := post(widened)
+			Expect(recorder.Code).To(Equal(http.StatusBadRequest))
+			Expect(body["error"]).To(Equal(oauth.ErrorInvalidScope))
+		})
+
+		It("should refuse a reused token", func() {
+			fakeRefresh.RotateGrantReturns(nil, "", refresh.ErrReused)
+			body := post(form)
+			Expect(recorder.Code).To(Equal(http.StatusBadRequest))
+			Expect(body["error"]).To(Equal(oauth.ErrorInvalidGrant))
+		})
+	})
+
+	Context("Introspect", func() {
+		introspect := func(form url.Values) map[string]interface{} {
+			req := httptest.NewRequest("POST", "/oauth/introspect",
+				strings.NewReader(form.Encode()))
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			if form.Get("client_id") == "" {
+				req.SetBasicAuth("reporting", "the-secret")
+			}
+			handler.Introspect(recorder, req)
+			body := make(map[string]interface{})
+			Expect(json.Unmarshal(recorder.Body.Bytes(), &body)).To(Succeed())
+			return body
+		}
+
+		BeforeEach(func() {
+			fakeToken.ValidateTokenReturns(&jwtGo.Token{
+				Claims: &jwt.IdentityClaims{
+					Scope:  "identity:read",
+					Custom: map[string]interface{}{"client_id": "spa"},
+					StandardClaims: jwtGo.StandardClaims{
+						Id:        "test-jti",
+						Subject:   "test_id",
+						ExpiresAt: time.Now().Add(time.Minute).Unix(),
+					},
+				},
+			}, true, nil)
+		})
+
+		It("should describe an active token", func() {
+			body := introspect(url.Values{"token": {"access-token"}})
+			Expect(recorder.Code).To(Equal(http.StatusOK))
+			Expect(body["active"]).To(BeTrue())
+			Expect(body["sub"]).To(Equal("test_id"))
+			Expect(body["client_id"]).To(Equal("spa"))
+			Expect(body["scope"]).To(Equal("identity:read"))
+			Expect(body["jti"]).To(Equal("test-jti"))
+		})
+
+		It("should report a revoked token as inactive", func() {
+			fakeRevoked.IsRevokedReturns(true, nil)
+			body := introspect(url.Values{"token": {"access-token"}})
+			Expect(recorder.Code).To(Equal(http.StatusOK))
+			Expect(body).To(Equal(map[string]interface{}{"active": false}))
+		})
+
+		It("should report an invalid token as inactive", func() {
+			fakeToken.ValidateTokenReturns(nil, false, nil)
+			body := introspect(url.Values{"token": {"garbage"}})
+			Expect(body).To(Equal(map[string]interface{}{"active": false}))
+		})
+
+		It("should refuse public clients", func() {
+			body := introspect(url.Values{"token": {"access-token"}, "client_id": {"spa"}})
+			Expect(recorder.Code).To(Equal(http.StatusUnauthorized))
+			Expect(body["error"]).To(Equal(oauth.ErrorInvalidClient))
+		})
+	})
+})
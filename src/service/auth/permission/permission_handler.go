@@ -0,0 +1,154 @@
+package permission
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"service/handlers/loggederror"
+	"service/log"
+
+	"github.com/go-chi/chi"
+	"go.uber.org/zap"
+)
+
+//HandlerObject ... holds elementals for managing identity roles over http.
+type HandlerObject struct {
+	Log   log.ProdInterface
+	Store Store
+}
+
+//NewHandlerObject ... returns a pointer to a new permission HandlerObject.
+func NewHandlerObject(logClient log.ProdInterface, store Store) *HandlerObject {
+	return &HandlerObject{
+		Log:   logClient,
+		Store: store,
+	}
+}
+
+type rolesResponse struct {
+	Status      int      `json:"status"`
+	ID          string   `json:"id"`
+	Roles       []string `json:"roles"`
+	Permissions []string `json:"permissions"`
+}
+
+//ListRoles ... GET /identity/{id}/roles, the identity's roles and the permissions they grant.
+func (h *HandlerObject) ListRoles(w http.ResponseWriter, req *http.Request) {
+	h.listRoles(chi.URLParam(req, "id"), h.Store.Roles, h.Store.Permissions, "ListRoles", w, req)
+}
+
+//AssignRole ... PUT /identity/{id}/roles/{role}
+func (h *HandlerObject) AssignRole(w http.ResponseWriter, req *http.Request) {
+	h.assignRole(chi.URLParam(req, "id"), h.Store.AssignRole, "AssignRole", w, req)
+}
+
+//RemoveRole ... DELETE /identity/{id}/roles/{role}
+func (h *HandlerObject) RemoveRole(w http.ResponseWriter, req *http.Request) {
+	h.removeRole(chi.URLParam(req, "id"), h.Store.RemoveRole, "RemoveRole", w, req)
+}
+
+//ListCredentialRoles ... GET /credentials/{username}/roles, the basic auth credential's roles
+//and the permissions they grant.
+func (h *HandlerObject) ListCredentialRoles(w http.ResponseWriter, req *http.Request) {
+	h.listRoles(chi.URLParam(req, "username"), h.Store.CredentialRoles,
+		h.Store.CredentialPermissions, "ListCredentialRoles", w, req)
+}
+
+//AssignCredentialRole ... PUT /credentials/{username}/roles/{role}
+func (h *HandlerObject) AssignCredentialRole(w http.ResponseWriter, req *http.Request) {
+	h.assignRole(chi.URLParam(req, "username"), h.Store.AssignCredentialRole,
+		"AssignCredentialRole", w, req)
+}
+
+//RemoveCredentialRole ... DELETE /credentials/{username}/roles/{role}
+func (h *HandlerObject) RemoveCredentialRole(w http.ResponseWriter, req *http.Request) {
+	h.removeRole(chi.URLParam(req, "username"), h.Store.RemoveCredentialRole,
+		"RemoveCredentialRole", w, req)
+}
+
+type lister func(ctx context.Context, holder string) ([]string, error)
+
+type changer func(ctx context.Context, holder, role string) error
+
+func (h *HandlerObject) listRoles(holder string, roles, permissions lister, source string,
+	w http.ResponseWriter, req *http.Request) {
+	held, err := roles(req.Context(), holder)
+	if err != nil {
+		h.internalServerError(err, source, w, req)
+		return
+	}
+	granted, err := permissions(req.Context(), holder)
+	if err != nil {
+		h.internalServerError(err, source, w, req)
+		return
+	}
+	bytesArray, marshalErr := json.Marshal(&rolesResponse{
+		Status:      http.StatusOK,
+		ID:          holder,
+		Roles:       held,
+		Permissions: granted,
+	})
+	if marshalErr != nil {
+		h.internalServerError(marshalErr, source, w, req)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if _, writeErr := w.Write(bytesArray); writeErr != nil {
+		h.Log.Error("permission_handler::"+source, zap.Error(writeErr))
+	}
+}
+
+func (h *HandlerObject) assignRole(holder string, assign changer, source string,
+	w http.ResponseWriter, req *http.Request) {
+	err := assign(req.Context(), holder, chi.URLParam(req, "role"))
+	if err == ErrUnknownRole {
+		h.softError(http.StatusNotFound, err.Error(), source, w, req)
+		return
+	}
+	if err != nil {
+		h.internalServerError(err, source, w, req)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *HandlerObject) removeRole(holder string, remove changer, source string,
+	w http.ResponseWriter, req *http.Request) {
+	err := remove(req.Context(), holder, chi.URLParam(req, "role"))
+	if err == sql.ErrNoRows {
+		h.softError(http.StatusNotFound, "role not assigned", source, w, req)
+		return
+	}
+	if err != nil {
+		h.internalServerError(err, source, w, req)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// internalServerError is used to wrap our loggederror for this route.
+func (h *HandlerObject) internalServerError(err error, source string, w http.ResponseWriter,
+	req *http.Request) {
+	loggederror.RespondWithProperErrorAndLogIt(
+		h.Log,
+		http.StatusInternalServerError,
+		err,
+		"permission_handler::"+source,
+		w,
+		req,
+	)
+}
+
+// softError is used to wrap our loggederror for expected failures on this route.
+func (h *HandlerObject) softError(status int, message, source string, w http.ResponseWriter,
+	req *http.Request) {
+	loggederror.RespondWithWithExpectedSoftError(
+		h.Log,
+		status,
+		message,
+		"permission_handler::"+source,
+		w,
+		req,
+	)
+}
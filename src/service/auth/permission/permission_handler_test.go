@@ -0,0 +1,96 @@
+package permission_test
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"service/auth/permission"
+	"service/auth/permission/permissionfakes"
+	"service/log"
+	"service/log/logfakes"
+
+	"github.com/go-chi/chi"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Permission Handler Specs", func() {
+	var (
+		fakeStore *permissionfakes.FakeStore
+		router    *chi.Mux
+		recorder  *httptest.ResponseRecorder
+	)
+
+	BeforeEach(func() {
+		fakeStore = &permissionfakes.FakeStore{}
+		handler := permission.NewHandlerObject(log.New(&logfakes.FakeProdInterface{}), fakeStore)
+		router = chi.NewRouter()
+		router.Get("/identity/{id}/roles", handler.ListRoles)
+		router.Put("/identity/{id}/roles/{role}", handler.AssignRole)
+		router.Delete("/identity/{id}/roles/{role}", handler.RemoveRole)
+		router.Get("/credentials/{username}/roles", handler.ListCredentialRoles)
+		router.Put("/credentials/{username}/roles/{role}", handler.AssignCredentialRole)
+		router.Delete("/credentials/{username}/roles/{role}", handler.RemoveCredentialRole)
+		recorder = httptest.NewRecorder()
+	})
+
+	serve := func(method, path string) {
+		router.ServeHTTP(recorder, httptest.NewRequest(method, path, nil))
+	}
+
+	It("should list an identity's roles and permissions", func() {
+		fakeStore.RolesReturns([]string{"viewer"}, nil)
+		fakeStore.PermissionsReturns([]string{"events:read", "identity:read"}, nil)
+		serve("GET", "/identity/test_id/roles")
+		Expect(recorder.Code).To(Equal(http.StatusOK))
+		Expect(recorder.Body.String()).To(MatchJSON(`{"status": 200, "id": "test_id",
+			"roles": ["viewer"], "permissions": ["events:read", "identity:read"]}`))
+	})
+
+	It("should assign a role", func() {
+		serve("PUT", "/identity/test_id/roles/viewer")
+		Expect(recorder.Code).To(Equal(http.StatusNoContent))
+		_, id, role := fakeStore.AssignRoleArgsForCall(0)
+		Expect(id).To(Equal("test_id"))
+		Expect(role).To(Equal("viewer"))
+	})
+
+	It("should respond 404 for an unknown role", func() {
+		fakeStore.AssignRoleReturns(permission.ErrUnknownRole)
+		serve("PUT", "/identity/test_id/roles/wizard")
+		Expect(recorder.Code).To(Equal(http.StatusNotFound))
+	})
+
+	It("should respond 500 when the store fails", func() {
+		fakeStore.RemoveRoleReturns(errors.New("db down"))
+		serve("DELETE", "/identity/test_id/roles/viewer")
+		Expect(recorder.Code).To(Equal(http.StatusInternalServerError))
+	})
+
+	Context("credential roles", func() {
+		It("should list a credential's roles and permissions", func() {
+			fakeStore.CredentialRolesReturns([]string{"viewer"}, nil)
+			fakeStore.CredentialPermissionsReturns([]string{"events:read", "identity:read"}, nil)
+			serve("GET", "/credentials/frontend/roles")
+			Expect(recorder.Code).To(Equal(http.StatusOK))
+			Expect(recorder.Body.String()).To(MatchJSON(`{"status": 200, "id": "frontend",
+				"roles": ["viewer"], "permissions": ["events:read", "identity:read"]}`))
+		})
+
+		It("should assign a role", func() {
+			serve("PUT", "/credentials/frontend/roles/viewer")
+			Expect(recorder.Code).To(Equal(http.StatusNoContent))
+			_, username, role := fakeStore.AssignCredentialRoleArgsForCall(0)
+			Expect(username).To(Equal("frontend"))
+			Expect(role).To(Equal("viewer"))
+		})
+
+		It("should respond 404 when removing a role the credential does not hold", func() {
+			fakeStore.RemoveCredentialRoleReturns(sql.ErrNoRows)
+			serve("DELETE", "/credentials/frontend/roles/admin")
+			Expect(recorder.Code).To(Equal(http.StatusNotFound))
+		})
+	})
+})
@@ -0,0 +1,68 @@
+package permission
+
+import (
+	"net/http"
+	"service/auth/bearer"
+	"service/handlers/loggederror"
+	"service/handlers/request"
+	"service/log"
+
+	"github.com/go-chi/chi"
+)
+
+//Require ... builds middleware that only lets a request through when its claims
+//carry every listed permission as a scope. It must run after the route's auth
+//middleware, which stores the permissions of a basic auth credential's roles too.
+func Require(permissions ...string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			claims, hasClaims := request.RetreiveClaims(req.Context())
+			for _, permission := range permissions {
+				if !hasClaims || !claims.HasScope(permission) {
+					forbidden(permission, hasClaims, w, req)
+					return
+				}
+			}
+			next.ServeHTTP(w, req)
+		})
+	}
+}
+
+//RequireSelfOr ... builds middleware that only lets a request for /identity/{id} through
+//when it is made by identity {id} itself or its claims carry the admin permission. It must
+//run after the route's auth middleware.
+func RequireSelfOr(admin string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			claims, hasClaims := request.RetreiveClaims(req.Context())
+			if hasClaims && (claims.HasScope(admin) ||
+				claims.Subject != "" && claims.Subject == chi.URLParam(req, "id")) {
+				next.ServeHTTP(w, req)
+				return
+			}
+			forbidden(admin, hasClaims, w, req)
+		})
+	}
+}
+
+func forbidden(permission string, hasClaims bool, w http.ResponseWriter, req *http.Request) {
+	if hasClaims && request.RetreiveBasicUser(req.Context()) == "" {
+		w.Header().Set("WWW-Authenticate",
+			bearer.ChallengeHeader(bearer.ErrorInsufficientScope, "", permission))
+	}
+	loggederror.RespondWithWithExpectedSoftError(
+		logClient,
+		http.StatusForbidden,
+		"missing permission "+permission,
+		"permission::Require",
+		w,
+		req,
+	)
+}
+
+var logClient log.ProdInterface
+
+//SetupPolicyMiddleware ... attaches a configured log client
+func SetupPolicyMiddleware(log log.ProdInterface) {
+	logClient = log
+}
@@ -0,0 +1,163 @@
+package permission_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"service/auth/permission"
+	"service/auth/token/jwt"
+	"service/handlers/request"
+	"service/log"
+	"service/log/logfakes"
+
+	"github.com/go-chi/chi"
+	"go.uber.org/zap"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Permission Middleware Specs", func() {
+	var (
+		fakeLog  *logfakes.FakeProdInterface
+		req      *http.Request
+		recorder *httptest.ResponseRecorder
+		reached  bool
+	)
+
+	BeforeEach(func() {
+		fakeLog = &logfakes.FakeProdInterface{}
+		permission.SetupPolicyMiddleware(log.New(fakeLog))
+		reached = false
+		req = httptest.NewRequest("PUT", "/identity/test_id", nil)
+		recorder = httptest.NewRecorder()
+	})
+
+	JustBeforeEach(func() {
+		permission.Require(permission.IdentityRead, permission.IdentityWrite)(
+			http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				reached = true
+			})).ServeHTTP(recorder, req)
+	})
+
+	withScope := func(scope string) {
+		req = req.WithContext(request.WithClaims(req.Context(), &jwt.IdentityClaims{Scope: scope}))
+	}
+
+	Context("when the token has every permission", func() {
+		BeforeEach(func() {
+			withScope("identity:read identity:write events:read")
+		})
+
+		It("should let the request through", func() {
+			Expect(reached).To(BeTrue())
+		})
+	})
+
+	Context("when the token is missing a permission", func() {
+		BeforeEach(func() {
+			withScope("identity:read")
+		})
+
+		It("should respond 403 naming the missing permission", func() {
+			Expect(reached).To(BeFalse())
+			Expect(recorder.Code).To(Equal(http.StatusForbidden))
+			Expect(recorder.Body.String()).To(Equal("missing permission identity:write\n"))
+			Expect(recorder.Header().Get("WWW-Authenticate")).To(Equal(
+				`Bearer realm="Restricted", error="insufficient_scope", scope="identity:write"`))
+		})
+
+		It("should log the missing permission", func() {
+			Expect(fakeLog.InfoCallCount()).To(Equal(1))
+			_, fields := fakeLog.InfoArgsForCall(0)
+			Expect(fields).To(ContainElement(zap.String("message", "missing permission identity:write")))
+		})
+	})
+
+	Context("when the request was authenticated with basic auth", func() {
+		BeforeEach(func() {
+			req = req.WithContext(request.WithBasicUser(req.Context(), "tony"))
+		})
+
+		Context("and the credential's roles grant every permission", func() {
+			BeforeEach(func() {
+				withScope("identity:read identity:write")
+			})
+
+			It("should let the request through", func() {
+				Expect(reached).To(BeTrue())
+			})
+		})
+
+		Context("and the credential's roles are missing a permission", func() {
+			BeforeEach(func() {
+				withScope("identity:read")
+			})
+
+			It("should respond 403 without a bearer challenge", func() {
+				Expect(reached).To(BeFalse())
+				Expect(recorder.Code).To(Equal(http.StatusForbidden))
+				Expect(recorder.Header().Get("WWW-Authenticate")).To(BeEmpty())
+			})
+		})
+	})
+
+	Context("when the request was not authenticated at all", func() {
+		It("should respond 403 without a bearer challenge", func() {
+			Expect(reached).To(BeFalse())
+			Expect(recorder.Code).To(Equal(http.StatusForbidden))
+			Expect(recorder.Header().Get("WWW-Authenticate")).To(BeEmpty())
+		})
+	})
+
+	Context("RequireSelfOr", func() {
+		serve := func(claims *jwt.IdentityClaims) {
+			router := chi.NewRouter()
+			router.With(permission.RequireSelfOr(permission.IdentityAdmin)).Put("/identity/{id}",
+				func(w http.ResponseWriter, req *http.Request) {
+					reached = true
+				})
+			req = httptest.NewRequest("PUT", "/identity/test_id", nil)
+			if claims != nil {
+				req = req.WithContext(request.WithClaims(req.Context(), claims))
+			}
+			recorder = httptest.NewRecorder()
+			reached = false
+			router.ServeHTTP(recorder, req)
+		}
+
+		It("should let an identity act on itself", func() {
+			claims := &jwt.IdentityClaims{}
+			claims.Subject = "test_id"
+			serve(claims)
+			Expect(reached).To(BeTrue())
+		})
+
+		It("should let an admin act on another identity", func() {
+			claims := &jwt.IdentityClaims{Scope: "identity:admin"}
+			claims.Subject = "admin_id"
+			serve(claims)
+			Expect(reached).To(BeTrue())
+		})
+
+		It("should forbid acting on another identity without the admin permission", func() {
+			claims := &jwt.IdentityClaims{Scope: "identity:read identity:write"}
+			claims.Subject = "editor_id"
+			serve(claims)
+			Expect(reached).To(BeFalse())
+			Expect(recorder.Code).To(Equal(http.StatusForbidden))
+			Expect(recorder.Body.String()).To(Equal("missing permission identity:admin\n"))
+		})
+
+		It("should forbid a basic credential without the admin permission", func() {
+			serve(&jwt.IdentityClaims{Scope: "identity:write"})
+			Expect(reached).To(BeFalse())
+			Expect(recorder.Code).To(Equal(http.StatusForbidden))
+		})
+
+		It("should forbid unauthenticated requests", func() {
+			serve(nil)
+			Expect(reached).To(BeFalse())
+			Expect(recorder.Code).To(Equal(http.StatusForbidden))
+		})
+	})
+})
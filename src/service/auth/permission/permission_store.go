@@ -0,0 +1,52 @@
+package permission
+
+import (
+	"context"
+	"errors"
+)
+
+//Permissions routes can be guarded by, see Require.
+const (
+	IdentityRead      = "identity:read"
+	IdentityWrite     = "identity:write"
+	EventsRead        = "events:read"
+	EventsAdmin       = "events:admin"
+	RolesAdmin        = "roles:admin"
+	ClientsAdmin      = "clients:admin"
+	CertificatesAdmin = "certificates:admin"
+	MetricsRead       = "metrics:read"
+	CredentialsAdmin  = "credentials:admin"
+	//IdentityAdmin lets a caller act on identities other than its own, see RequireSelfOr.
+	IdentityAdmin = "identity:admin"
+)
+
+//SelfRole ... is given to every new identity so it can read and update itself, the
+//identity routes still keep it to its own identity, see RequireSelfOr.
+const SelfRole = "self"
+
+//DefaultRoles ... are defined at startup so a fresh database has usable roles.
+var DefaultRoles = map[string][]string{
+	"admin": {IdentityRead, IdentityWrite, IdentityAdmin, EventsRead, EventsAdmin, RolesAdmin,
+		ClientsAdmin, CertificatesAdmin, MetricsRead, CredentialsAdmin},
+	"editor": {IdentityRead, IdentityWrite, EventsRead},
+	"viewer": {IdentityRead, EventsRead},
+	SelfRole: {IdentityRead, IdentityWrite},
+}
+
+//ErrUnknownRole ... is returned when assigning a role that has not been defined.
+var ErrUnknownRole = errors.New("unknown role")
+
+//Store ... defines a backing store for roles, the permissions they grant and which
+//identities and basic auth credentials hold them.
+//go:generate counterfeiter . Store
+type Store interface {
+	Permissions(ctx context.Context, identityID string) ([]string, error)
+	Roles(ctx context.Context, identityID string) ([]string, error)
+	AssignRole(ctx context.Context, identityID, role string) error
+	RemoveRole(ctx context.Context, identityID, role string) error
+	DefineRole(ctx context.Context, role string, permissions []string) error
+	CredentialPermissions(ctx context.Context, username string) ([]string, error)
+	CredentialRoles(ctx context.Context, username string) ([]string, error)
+	AssignCredentialRole(ctx context.Context, username, role string) error
+	RemoveCredentialRole(ctx context.Context, username, role string) error
+}
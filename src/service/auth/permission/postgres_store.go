@@ -0,0 +1,150 @@
+package permission
+
+import (
+	"context"
+	"database/sql"
+	"service/database"
+	"time"
+)
+
+//PostgresStore ... is a Store backed by the role, role_permission and identity_role tables.
+type PostgresStore struct {
+	db database.DBInterface
+}
+
+//NewPostgresStore ... returns a pointer to a new PostgresStore using the passed in db.
+func NewPostgresStore(db database.DBInterface) *PostgresStore {
+	return &PostgresStore{
+		db: db,
+	}
+}
+
+//Permissions ... returns every permission granted by the identity's roles, sorted.
+func (p *PostgresStore) Permissions(ctx context.Context, identityID string) ([]string, error) {
+	return p.strings(ctx, `SELECT DISTINCT rp.permission FROM identity_role ir
+		JOIN role_permission rp ON rp.role = ir.role
+		WHERE ir.identity_id = $1 ORDER BY rp.permission;`, identityID)
+}
+
+//Roles ... returns the roles assigned to the identity, sorted.
+func (p *PostgresStore) Roles(ctx context.Context, identityID string) ([]string, error) {
+	return p.strings(ctx,
+		"SELECT role FROM identity_role WHERE identity_id = $1 ORDER BY role;",
+		identityID)
+}
+
+//AssignRole ... gives the identity a defined role, assigning it twice is a no-op.
+func (p *PostgresStore) AssignRole(ctx context.Context, identityID, role string) error {
+	return p.assign(ctx, `INSERT INTO identity_role (identity_id, role, created_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (identity_id, role) DO NOTHING;`, identityID, role)
+}
+
+//RemoveRole ... takes a role away from the identity, returns sql.ErrNoRows if it was not held.
+func (p *PostgresStore) RemoveRole(ctx context.Context, identityID, role string) error {
+	return p.remove(ctx,
+		"DELETE FROM identity_role WHERE identity_id = $1 AND role = $2;",
+		identityID,
+		role)
+}
+
+//CredentialPermissions ... returns every permission granted by the basic auth credential's
+//roles, sorted.
+func (p *PostgresStore) CredentialPermissions(ctx context.Context, username string) ([]string,
+	error) {
+	return p.strings(ctx, `SELECT DISTINCT rp.permission FROM credential_role cr
+		JOIN role_permission rp ON rp.role = cr.role
+		WHERE cr.username = $1 ORDER BY rp.permission;`, username)
+}
+
+//CredentialRoles ... returns the roles assigned to the basic auth credential, sorted.
+func (p *PostgresStore) CredentialRoles(ctx context.Context, username string) ([]string, error) {
+	return p.strings(ctx,
+		"SELECT role FROM credential_role WHERE username = $1 ORDER BY role;",
+		username)
+}
+
+//AssignCredentialRole ... gives the basic auth credential a defined role, assigning it twice
+//is a no-op.
+func (p *PostgresStore) AssignCredentialRole(ctx context.Context, username, role string) error {
+	return p.assign(ctx, `INSERT INTO credential_role (username, role, created_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (username, role) DO NOTHING;`, username, role)
+}
+
+//RemoveCredentialRole ... takes a role away from the basic auth credential, returns
+//sql.ErrNoRows if it was not held.
+func (p *PostgresStore) RemoveCredentialRole(ctx context.Context, username, role string) error {
+	return p.remove(ctx,
+		"DELETE FROM credential_role WHERE username = $1 AND role = $2;",
+		username,
+		role)
+}
+
+func (p *PostgresStore) assign(ctx context.Context, insert, holder, role string) error {
+	var exists int
+	err := p.db.QueryRowContext(ctx,
+		"SELECT 1 FROM role WHERE name = $1;", role).Scan(&exists)
+	if err == sql.ErrNoRows {
+		return ErrUnknownRole
+	}
+	if err != nil {
+		return err
+	}
+	_, err = p.db.ExecContext(ctx, insert, holder, role, time.Now())
+	return err
+}
+
+func (p *PostgresStore) remove(ctx context.Context, delete, holder, role string) error {
+	result, err := p.db.ExecContext(ctx, delete, holder, role)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+//DefineRole ... creates the role if needed and grants it the permissions. Grants are
+//additive, permissions already held by the role are left alone.
+func (p *PostgresStore) DefineRole(ctx context.Context, role string, permissions []string) error {
+	_, err := p.db.ExecContext(ctx,
+		"INSERT INTO role (name) VALUES ($1) ON CONFLICT (name) DO NOTHING;", role)
+	if err != nil {
+		return err
+	}
+	for _, permission := range permissions {
+		_, err = p.db.ExecContext(ctx, `INSERT INTO role_permission (role, permission)
+			VALUES ($1, $2)
+			ON CONFLICT (role, permission) DO NOTHING;`,
+			role,
+			permission)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *PostgresStore) strings(ctx context.Context, query string, args ...interface{}) ([]string,
+	error) {
+	rows, err := p.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	values := make([]string, 0)
+	for rows.Next() {
+		var value string
+		if err := rows.Scan(&value); err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+	}
+	return values, rows.Err()
+}
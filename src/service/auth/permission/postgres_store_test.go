@@ -0,0 +1,103 @@
+package permission_test
+
+import (
+	"context"
+	"database/sql"
+	"service/auth/permission"
+	"service/utils/sqltest"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	sqlmock "gopkg.in/DATA-DOG/go-sqlmock.v1"
+)
+
+var _ = Describe("Postgres Store Specs", func() {
+	ctx := context.Background()
+	var (
+		store  *permission.PostgresStore
+		db     *sql.DB
+		mockDB sqlmock.Sqlmock
+	)
+
+	BeforeEach(func() {
+		var sqlmockErr error
+		db, mockDB, sqlmockErr = sqlmock.New()
+		Expect(sqlmockErr).ToNot(HaveOccurred())
+		store = permission.NewPostgresStore(db)
+	})
+
+	AfterEach(func() {
+		Expect(mockDB.ExpectationsWereMet()).To(Succeed())
+	})
+
+	It("should collect the permissions granted by an identity's roles", func() {
+		mockDB.ExpectQuery("SELECT DISTINCT rp.permission FROM identity_role").
+			WithArgs("test_id").
+			WillReturnRows(sqlmock.NewRows([]string{"permission"}).
+				AddRow("identity:read").AddRow("identity:write"))
+		Expect(store.Permissions(ctx, "test_id")).To(Equal([]string{"identity:read", "identity:write"}))
+	})
+
+	It("should return an empty list for an identity without roles", func() {
+		mockDB.ExpectQuery("SELECT role FROM identity_role").WithArgs("test_id").
+			WillReturnRows(sqlmock.NewRows([]string{"role"}))
+		Expect(store.Roles(ctx, "test_id")).To(BeEmpty())
+	})
+
+	Context("AssignRole", func() {
+		It("should assign a defined role", func() {
+			mockDB.ExpectQuery("SELECT 1 FROM role").WithArgs("viewer").
+				WillReturnRows(sqlmock.NewRows([]string{"?column?"}).AddRow(1))
+			mockDB.ExpectExec("INSERT INTO identity_role").
+				WithArgs("test_id", "viewer", sqltest.AnyTime{}).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+			Expect(store.AssignRole(ctx, "test_id", "viewer")).To(Succeed())
+		})
+
+		It("should reject an unknown role", func() {
+			mockDB.ExpectQuery("SELECT 1 FROM role").WithArgs("wizard").
+				WillReturnError(sql.ErrNoRows)
+			Expect(store.AssignRole(ctx, "test_id", "wizard")).To(Equal(permission.ErrUnknownRole))
+		})
+	})
+
+	It("should report removing a role that was not held", func() {
+		mockDB.ExpectExec("DELETE FROM identity_role").WithArgs("test_id", "viewer").
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		Expect(store.RemoveRole(ctx, "test_id", "viewer")).To(Equal(sql.ErrNoRows))
+	})
+
+	It("should define a role and grant its permissions", func() {
+		mockDB.ExpectExec("INSERT INTO role").WithArgs("viewer").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mockDB.ExpectExec("INSERT INTO role_permission").WithArgs("viewer", "identity:read").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mockDB.ExpectExec("INSERT INTO role_permission").WithArgs("viewer", "events:read").
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		Expect(store.DefineRole(ctx, "viewer", []string{"identity:read", "events:read"})).To(Succeed())
+	})
+
+	Context("credential roles", func() {
+		It("should collect the permissions granted by a credential's roles", func() {
+			mockDB.ExpectQuery("SELECT DISTINCT rp.permission FROM credential_role").
+				WithArgs("frontend").
+				WillReturnRows(sqlmock.NewRows([]string{"permission"}).AddRow("identity:read"))
+			Expect(store.CredentialPermissions(ctx, "frontend")).To(Equal([]string{"identity:read"}))
+		})
+
+		It("should assign a defined role", func() {
+			mockDB.ExpectQuery("SELECT 1 FROM role").WithArgs("admin").
+				WillReturnRows(sqlmock.NewRows([]string{"?column?"}).AddRow(1))
+			mockDB.ExpectExec("INSERT INTO credential_role").
+				WithArgs("frontend", "admin", sqltest.AnyTime{}).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+			Expect(store.AssignCredentialRole(ctx, "frontend", "admin")).To(Succeed())
+		})
+
+		It("should report a role the credential does not hold", func() {
+			mockDB.ExpectExec("DELETE FROM credential_role").WithArgs("frontend", "admin").
+				WillReturnResult(sqlmock.NewResult(0, 0))
+			Expect(store.RemoveCredentialRole(ctx, "frontend", "admin")).To(Equal(sql.ErrNoRows))
+		})
+	})
+})
@@ -0,0 +1,205 @@
+package refresh
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"service/database"
+	"service/log"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+//DefaultTTL ... is how long a refresh token stays usable when none is configured.
+const DefaultTTL = 30 * 24 * time.Hour
+
+//ErrInvalid ... is returned for refresh tokens that are unknown, expired or revoked.
+var ErrInvalid = errors.New("invalid refresh token")
+
+//ErrReused ... is returned when an already rotated refresh token is presented again. The
+//whole token family is revoked before it is returned.
+var ErrReused = errors.New("refresh token reused")
+
+//Grant ...
+//is what a refresh token stands for. Password logins have no ClientID or Scope, OAuth
+//clients get tokens bound to themselves and the scope the identity consented to.
+type Grant struct {
+	IdentityID string
+	ClientID   string
+	Scope      string
+}
+
+//Interface ... defines issuing and rotating opaque refresh tokens.
+//go:generate counterfeiter . Interface
+type Interface interface {
+	Issue(ctx context.Context, identityID string) (string, error)
+	Rotate(ctx context.Context, token string) (string, string, error)
+	IssueGrant(ctx context.Context, grant Grant) (string, error)
+	RotateGrant(ctx context.Context, token, clientID string) (*Grant, string, error)
+	RevokeAll(ctx context.Context, identityID string) error
+}
+
+//Service ...
+//persists refresh tokens via a DBInterface. Only a sha256 of each token is stored, and
+//every token belongs to a family that started with a password login.
+type Service struct {
+	log log.ProdInterface
+	db  database.DBInterface
+	ttl time.Duration
+}
+
+//NewService ...
+//returns a pointer to a new refresh token Service, a zero ttl uses DefaultTTL.
+func NewService(logClient log.ProdInterface, db database.DBInterface, ttl time.Duration) *Service {
+	if ttl <= 0 {
+		ttl = DefaultTTL
+	}
+	return &Service{
+		log: logClient,
+		db:  db,
+		ttl: ttl,
+	}
+}
+
+//Issue ... starts a new token family for identityID and returns its first refresh token.
+func (s *Service) Issue(ctx context.Context, identityID string) (string, error) {
+	return s.IssueGrant(ctx, Grant{IdentityID: identityID})
+}
+
+//Rotate ...
+//exchanges a password login's refresh token for a new one in the same family, returning
+//the identity ID and the new token. A token can only be rotated once, presenting it again
+//revokes the family.
+func (s *Service) Rotate(ctx context.Context, token string) (string, string, error) {
+	grant, newToken, err := s.RotateGrant(ctx, token, "")
+	if err != nil {
+		return "", "", err
+	}
+	return grant.IdentityID, newToken, nil
+}
+
+//IssueGrant ... starts a new token family for grant and returns its first refresh token.
+func (s *Service) IssueGrant(ctx context.Context, grant Grant) (string, error) {
+	return s.insert(ctx, s.db, grant, uuid.New().String())
+}
+
+//RotateGrant ...
+//is Rotate for tokens issued to clientID, a token issued to any other client, or to a
+//password login, is ErrInvalid.
+func (s *Service) RotateGrant(ctx context.Context, token, clientID string) (*Grant, string, error) {
+	if token == "" {
+		return nil, "", ErrInvalid
+	}
+	tokenHash := hashToken(token)
+	rightNow := time.Now()
+	grant := Grant{ClientID: clientID}
+	var newToken string
+	//Marking the token used and storing its replacement commit together, so a failed
+	//insert leaves the token usable instead of turning the retry into reuse.
+	err := database.InTx(ctx, s.db, func(tx database.DBInterface) error {
+		var familyID string
+		err := tx.QueryRowContext(ctx, `UPDATE refresh_token SET used_at = $2
+			WHERE token_hash = $1 AND used_at IS NULL AND revoked_at IS NULL AND expires_at > $2
+				AND client_id = $3
+			RETURNING identity_id, family_id, scope;`,
+			tokenHash, rightNow, clientID).Scan(&grant.IdentityID, &familyID, &grant.Scope)
+		if err != nil {
+			return err
+		}
+		var exists int
+		err = tx.QueryRowContext(ctx,
+			"SELECT 1 FROM identity WHERE id = $1;", grant.IdentityID).Scan(&exists)
+		if err == sql.ErrNoRows {
+			return ErrInvalid
+		}
+		if err != nil {
+			return err
+		}
+		newToken, err = s.insert(ctx, tx, grant, familyID)
+		return err
+	})
+	if err == sql.ErrNoRows {
+		return nil, "", s.rejected(ctx, tokenHash, rightNow)
+	}
+	if err != nil {
+		return nil, "", err
+	}
+	return &grant, newToken, nil
+}
+
+//RevokeAll ... revokes every refresh token of the identity, for example after its password
+//changes.
+func (s *Service) RevokeAll(ctx context.Context, identityID string) error {
+	_, err := s.db.ExecContext(ctx,
+		"UPDATE refresh_token SET revoked_at = $2 WHERE identity_id = $1 AND revoked_at IS NULL;",
+		identityID, time.Now())
+	return err
+}
+
+//rejected works out why a token could not be rotated, revoking its family on reuse.
+func (s *Service) rejected(ctx context.Context, tokenHash string, rightNow time.Time) error {
+	var familyID string
+	var usedAt, revokedAt *time.Time
+	err := s.db.QueryRowContext(ctx,
+		"SELECT family_id, used_at, revoked_at FROM refresh_token WHERE token_hash = $1;",
+		tokenHash).Scan(&familyID, &usedAt, &revokedAt)
+	if err == sql.ErrNoRows {
+		return ErrInvalid
+	}
+	if err != nil {
+		return err
+	}
+	if usedAt == nil {
+		return ErrInvalid
+	}
+	_, err = s.db.ExecContext(ctx,
+		"UPDATE refresh_token SET revoked_at = $2 WHERE family_id = $1 AND revoked_at IS NULL;",
+		familyID, rightNow)
+	if err != nil {
+		return err
+	}
+	s.log.Warn("refresh token reuse detected, family revoked", zap.String("familyID", familyID))
+	return ErrReused
+}
+
+func (s *Service) insert(ctx context.Context, db database.DBInterface, grant Grant,
+	familyID string) (string, error) {
+	token, err := generateToken()
+	if err != nil {
+		return "", err
+	}
+	rightNow := time.Now()
+	_, err = db.ExecContext(ctx, `INSERT INTO refresh_token
+		(token_hash, family_id, identity_id, expires_at, created_at, client_id, scope) VALUES
+		($1, $2, $3, $4, $5, $6, $7);`,
+		hashToken(token),
+		familyID,
+		grant.IdentityID,
+		rightNow.Add(s.ttl),
+		rightNow,
+		grant.ClientID,
+		grant.Scope)
+	if err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+func generateToken() (string, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(raw), nil
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
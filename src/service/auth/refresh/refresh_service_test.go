@@ -0,0 +1,187 @@
+package refresh_test
+
+import (
+	"context"
+	"database/sql"
+	"service/auth/refresh"
+	"service/log/logfakes"
+	"service/utils/sqltest"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	sqlmock "gopkg.in/DATA-DOG/go-sqlmock.v1"
+)
+
+var _ = Describe("Refresh Token Service Specs", func() {
+	ctx := context.Background()
+	var (
+		refreshService *refresh.Service
+		fakeLog        *logfakes.FakeProdInterface
+		db             *sql.DB
+		mockDB         sqlmock.Sqlmock
+	)
+
+	BeforeEach(func() {
+		var sqlmockErr error
+		db, mockDB, sqlmockErr = sqlmock.New()
+		Expect(sqlmockErr).ToNot(HaveOccurred())
+		fakeLog = &logfakes.FakeProdInterface{}
+		refreshService = refresh.NewService(fakeLog, db, time.Hour)
+	})
+
+	AfterEach(func() {
+		Expect(mockDB.ExpectationsWereMet()).To(Succeed())
+	})
+
+	Context("Issue", func() {
+		It("should store a hash of a new token in a new family", func() {
+			mockDB.ExpectExec("INSERT INTO refresh_token").
+				WithArgs(sqltest.AnyString{}, sqltest.AnyString{}, "test_id", sqltest.AnyTime{},
+					sqltest.AnyTime{}, "", "").
+				WillReturnResult(sqlmock.NewResult(0, 1))
+			token, err := refreshService.Issue(ctx, "test_id")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(token).To(HaveLen(43))
+		})
+
+		It("should bind a grant's token to its client and scope", func() {
+			mockDB.ExpectExec("INSERT INTO refresh_token").
+				WithArgs(sqltest.AnyString{}, sqltest.AnyString{}, "test_id", sqltest.AnyTime{},
+					sqltest.AnyTime{}, "test_client", "identity:read").
+				WillReturnResult(sqlmock.NewResult(0, 1))
+			_, err := refreshService.IssueGrant(ctx, refresh.Grant{IdentityID: "test_id",
+				ClientID: "test_client", Scope: "identity:read"})
+			Expect(err).ToNot(HaveOccurred())
+		})
+	})
+
+	Context("Rotate", func() {
+		It("should mark the token used and issue a new one in the same family", func() {
+			mockDB.ExpectBegin()
+			mockDB.ExpectQuery("UPDATE refresh_token SET used_at").
+				WithArgs(sqltest.AnyString{}, sqltest.AnyTime{}, "").
+				WillReturnRows(sqlmock.NewRows([]string{"identity_id", "family_id", "scope"}).
+					AddRow("test_id", "test_family", ""))
+			mockDB.ExpectQuery("SELECT 1 FROM identity").
+				WithArgs("test_id").
+				WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(1))
+			mockDB.ExpectExec("INSERT INTO refresh_token").
+				WithArgs(sqltest.AnyString{}, "test_family", "test_id", sqltest.AnyTime{},
+					sqltest.AnyTime{}, "", "").
+				WillReturnResult(sqlmock.NewResult(0, 1))
+			mockDB.ExpectCommit()
+
+			id, token, err := refreshService.Rotate(ctx, "old_token")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(id).To(Equal("test_id"))
+			Expect(token).ToNot(Equal("old_token"))
+		})
+
+		It("should only rotate a grant's token for its own client, keeping its scope", func() {
+			mockDB.ExpectBegin()
+			mockDB.ExpectQuery("UPDATE refresh_token SET used_at").
+				WithArgs(sqltest.AnyString{}, sqltest.AnyTime{}, "test_client").
+				WillReturnRows(sqlmock.NewRows([]string{"identity_id", "family_id", "scope"}).
+					AddRow("test_id", "test_family", "identity:read"))
+			mockDB.ExpectQuery("SELECT 1 FROM identity").
+				WithArgs("test_id").
+				WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(1))
+			mockDB.ExpectExec("INSERT INTO refresh_token").
+				WithArgs(sqltest.AnyString{}, "test_family", "test_id", sqltest.AnyTime{},
+					sqltest.AnyTime{}, "test_client", "identity:read").
+				WillReturnResult(sqlmock.NewResult(0, 1))
+			mockDB.ExpectCommit()
+
+			grant, _, err := refreshService.RotateGrant(ctx, "old_token", "test_client")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(*grant).To(Equal(refresh.Grant{IdentityID: "test_id",
+				ClientID: "test_client", Scope: "identity:read"}))
+		})
+
+		It("should return ErrInvalid for an unknown token", func() {
+			mockDB.ExpectBegin()
+			mockDB.ExpectQuery("UPDATE refresh_token SET used_at").
+				WillReturnError(sql.ErrNoRows)
+			mockDB.ExpectRollback()
+			mockDB.ExpectQuery("SELECT family_id, used_at, revoked_at FROM refresh_token").
+				WillReturnError(sql.ErrNoRows)
+
+			_, _, err := refreshService.Rotate(ctx, "unknown_token")
+			Expect(err).To(Equal(refresh.ErrInvalid))
+		})
+
+		It("should return ErrInvalid for an expired token that was never used", func() {
+			mockDB.ExpectBegin()
+			mockDB.ExpectQuery("UPDATE refresh_token SET used_at").
+				WillReturnError(sql.ErrNoRows)
+			mockDB.ExpectRollback()
+			mockDB.ExpectQuery("SELECT family_id, used_at, revoked_at FROM refresh_token").
+				WillReturnRows(sqlmock.NewRows([]string{"family_id", "used_at", "revoked_at"}).
+					AddRow("test_family", nil, nil))
+
+			_, _, err := refreshService.Rotate(ctx, "expired_token")
+			Expect(err).To(Equal(refresh.ErrInvalid))
+		})
+
+		It("should revoke the whole family when a used token is replayed", func() {
+			mockDB.ExpectBegin()
+			mockDB.ExpectQuery("UPDATE refresh_token SET used_at").
+				WillReturnError(sql.ErrNoRows)
+			mockDB.ExpectRollback()
+			mockDB.ExpectQuery("SELECT family_id, used_at, revoked_at FROM refresh_token").
+				WillReturnRows(sqlmock.NewRows([]string{"family_id", "used_at", "revoked_at"}).
+					AddRow("test_family", time.Now(), nil))
+			mockDB.ExpectExec("UPDATE refresh_token SET revoked_at").
+				WithArgs("test_family", sqltest.AnyTime{}).
+				WillReturnResult(sqlmock.NewResult(0, 3))
+
+			_, _, err := refreshService.Rotate(ctx, "replayed_token")
+			Expect(err).To(Equal(refresh.ErrReused))
+			Expect(fakeLog.WarnCallCount()).To(Equal(1))
+		})
+
+		It("should leave the token unused when the new one cannot be stored", func() {
+			mockDB.ExpectBegin()
+			mockDB.ExpectQuery("UPDATE refresh_token SET used_at").
+				WillReturnRows(sqlmock.NewRows([]string{"identity_id", "family_id", "scope"}).
+					AddRow("test_id", "test_family", ""))
+			mockDB.ExpectQuery("SELECT 1 FROM identity").
+				WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(1))
+			mockDB.ExpectExec("INSERT INTO refresh_token").
+				WillReturnError(sql.ErrConnDone)
+			mockDB.ExpectRollback()
+
+			_, _, err := refreshService.Rotate(ctx, "old_token")
+			Expect(err).To(Equal(sql.ErrConnDone))
+		})
+
+		It("should return ErrInvalid without issuing when the identity is gone", func() {
+			mockDB.ExpectBegin()
+			mockDB.ExpectQuery("UPDATE refresh_token SET used_at").
+				WillReturnRows(sqlmock.NewRows([]string{"identity_id", "family_id", "scope"}).
+					AddRow("deleted_id", "test_family", ""))
+			mockDB.ExpectQuery("SELECT 1 FROM identity").
+				WithArgs("deleted_id").
+				WillReturnError(sql.ErrNoRows)
+			mockDB.ExpectRollback()
+
+			_, _, err := refreshService.Rotate(ctx, "old_token")
+			Expect(err).To(Equal(refresh.ErrInvalid))
+		})
+
+		It("should return ErrInvalid for an empty token without querying", func() {
+			_, _, err := refreshService.Rotate(ctx, "")
+			Expect(err).To(Equal(refresh.ErrInvalid))
+		})
+	})
+
+	Context("RevokeAll", func() {
+		It("should revoke every live token of the identity", func() {
+			mockDB.ExpectExec("UPDATE refresh_token SET revoked_at").
+				WithArgs("test_id", sqltest.AnyTime{}).
+				WillReturnResult(sqlmock.NewResult(0, 2))
+			Expect(refreshService.RevokeAll(ctx, "test_id")).To(Succeed())
+		})
+	})
+})
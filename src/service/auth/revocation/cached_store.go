@@ -0,0 +1,96 @@
+package revocation
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+//DefaultMissTTL ... is how long a "not revoked" answer is trusted before asking again.
+const DefaultMissTTL = 10 * time.Second
+
+type cacheEntry struct {
+	revoked bool
+	until   time.Time
+}
+
+//CachedStore ...
+//wraps another Store with an in-memory cache. Local revocations are cached until the
+//token expires, while answers from the backing store are only cached for missTTL so
+//revocations made by other instances are picked up quickly.
+type CachedStore struct {
+	store     Store
+	missTTL   time.Duration
+	mutex     sync.RWMutex
+	entries   map[string]cacheEntry
+	lastSweep time.Time
+}
+
+//NewCachedStore ... returns a pointer to a new CachedStore in front of store, a zero
+//missTTL uses DefaultMissTTL.
+func NewCachedStore(store Store, missTTL time.Duration) *CachedStore {
+	if missTTL <= 0 {
+		missTTL = DefaultMissTTL
+	}
+	return &CachedStore{
+		store:   store,
+		missTTL: missTTL,
+		entries: make(map[string]cacheEntry),
+	}
+}
+
+//NewMemoryStore ... returns a CachedStore without a backing store, used for tests and
+//local development.
+func NewMemoryStore() *CachedStore {
+	return NewCachedStore(nil, 0)
+}
+
+//Revoke ... revokes jti in the backing store and caches the revocation.
+func (c *CachedStore) Revoke(ctx context.Context, jti string, expiresAt time.Time) error {
+	if c.store != nil {
+		if err := c.store.Revoke(ctx, jti, expiresAt); err != nil {
+			return err
+		}
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.sweep()
+	c.entries[jti] = cacheEntry{revoked: true, until: expiresAt}
+	return nil
+}
+
+//IsRevoked ... answers from the cache when it can, otherwise asks the backing store.
+func (c *CachedStore) IsRevoked(ctx context.Context, jti string) (bool, error) {
+	c.mutex.RLock()
+	entry, ok := c.entries[jti]
+	c.mutex.RUnlock()
+	if ok && time.Now().Before(entry.until) {
+		return entry.revoked, nil
+	}
+	if c.store == nil {
+		return false, nil
+	}
+	revoked, err := c.store.IsRevoked(ctx, jti)
+	if err != nil {
+		return false, err
+	}
+	c.mutex.Lock()
+	c.sweep()
+	c.entries[jti] = cacheEntry{revoked: revoked, until: time.Now().Add(c.missTTL)}
+	c.mutex.Unlock()
+	return revoked, nil
+}
+
+//sweep drops expired entries at most once per missTTL, callers must hold the write lock.
+func (c *CachedStore) sweep() {
+	rightNow := time.Now()
+	if rightNow.Sub(c.lastSweep) < c.missTTL {
+		return
+	}
+	c.lastSweep = rightNow
+	for jti, entry := range c.entries {
+		if !rightNow.Before(entry.until) {
+			delete(c.entries, jti)
+		}
+	}
+}
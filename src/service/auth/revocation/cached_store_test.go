@@ -0,0 +1,73 @@
+package revocation_test
+
+import (
+	"context"
+	"errors"
+	"service/auth/revocation"
+	"service/auth/revocation/revocationfakes"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Cached Store Specs", func() {
+	ctx := context.Background()
+	var (
+		backing *revocationfakes.FakeStore
+		store   *revocation.CachedStore
+	)
+
+	BeforeEach(func() {
+		backing = &revocationfakes.FakeStore{}
+		store = revocation.NewCachedStore(backing, 50*time.Millisecond)
+	})
+
+	Context("Revoke", func() {
+		It("should write through and answer later checks from the cache", func() {
+			Expect(store.Revoke(ctx, "jti", time.Now().Add(time.Hour))).To(Succeed())
+			Expect(backing.RevokeCallCount()).To(Equal(1))
+			Expect(store.IsRevoked(ctx, "jti")).To(BeTrue())
+			Expect(backing.IsRevokedCallCount()).To(Equal(0))
+		})
+
+		It("should not cache a revocation the backing store failed to record", func() {
+			backing.RevokeReturns(errors.New("db down"))
+			Expect(store.Revoke(ctx, "jti", time.Now().Add(time.Hour))).ToNot(Succeed())
+			Expect(store.IsRevoked(ctx, "jti")).To(BeFalse())
+			Expect(backing.IsRevokedCallCount()).To(Equal(1))
+		})
+	})
+
+	Context("IsRevoked", func() {
+		It("should cache answers from the backing store for the miss ttl", func() {
+			Expect(store.IsRevoked(ctx, "jti")).To(BeFalse())
+			Expect(store.IsRevoked(ctx, "jti")).To(BeFalse())
+			Expect(backing.IsRevokedCallCount()).To(Equal(1))
+
+			backing.IsRevokedReturns(true, nil)
+			Eventually(func() bool {
+				revoked, _ := store.IsRevoked(ctx, "jti")
+				return revoked
+			}).Should(BeTrue())
+		})
+
+		It("should not cache errors", func() {
+			backing.IsRevokedReturns(false, errors.New("db down"))
+			_, err := store.IsRevoked(ctx, "jti")
+			Expect(err).To(HaveOccurred())
+			backing.IsRevokedReturns(false, nil)
+			Expect(store.IsRevoked(ctx, "jti")).To(BeFalse())
+			Expect(backing.IsRevokedCallCount()).To(Equal(2))
+		})
+	})
+
+	Context("NewMemoryStore", func() {
+		It("should work without a backing store", func() {
+			memory := revocation.NewMemoryStore()
+			Expect(memory.IsRevoked(ctx, "jti")).To(BeFalse())
+			Expect(memory.Revoke(ctx, "jti", time.Now().Add(time.Hour))).To(Succeed())
+			Expect(memory.IsRevoked(ctx, "jti")).To(BeTrue())
+		})
+	})
+})
@@ -0,0 +1,53 @@
+package revocation
+
+import (
+	"context"
+	"database/sql"
+	"service/database"
+	"time"
+)
+
+//PostgresStore ... is a Store backed by the revoked_token table.
+type PostgresStore struct {
+	db database.DBInterface
+}
+
+//NewPostgresStore ... returns a pointer to a new PostgresStore using the passed in db.
+func NewPostgresStore(db database.DBInterface) *PostgresStore {
+	return &PostgresStore{
+		db: db,
+	}
+}
+
+//Revoke ... inserts jti into the revocation list, revoking an already revoked jti is a no-op.
+//Entries past their expiry are cleared first, the token they name is rejected as expired
+//anyway, so the list only holds tokens that are still live.
+func (p *PostgresStore) Revoke(ctx context.Context, jti string, expiresAt time.Time) error {
+	_, err := p.db.ExecContext(ctx,
+		"DELETE FROM revoked_token WHERE expires_at <= $1;", time.Now())
+	if err != nil {
+		return err
+	}
+	_, err = p.db.ExecContext(ctx,
+		`INSERT INTO revoked_token (jti, expires_at, revoked_at) VALUES
+		($1, $2, $3)
+		ON CONFLICT (jti) DO NOTHING;`,
+		jti,
+		expiresAt,
+		time.Now())
+	return err
+}
+
+//IsRevoked ... reports whether jti is on the revocation list.
+func (p *PostgresStore) IsRevoked(ctx context.Context, jti string) (bool, error) {
+	var found int
+	err := p.db.QueryRowContext(ctx,
+		"SELECT 1 FROM revoked_token WHERE jti = $1;", jti).Scan(&found)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
@@ -0,0 +1,64 @@
+package revocation_test
+
+import (
+	"context"
+	"database/sql"
+	"service/auth/revocation"
+	"service/utils/sqltest"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	sqlmock "gopkg.in/DATA-DOG/go-sqlmock.v1"
+)
+
+var _ = Describe("Postgres Store Specs", func() {
+	ctx := context.Background()
+	var (
+		store  *revocation.PostgresStore
+		db     *sql.DB
+		mockDB sqlmock.Sqlmock
+	)
+
+	BeforeEach(func() {
+		var sqlmockErr error
+		db, mockDB, sqlmockErr = sqlmock.New()
+		Expect(sqlmockErr).ToNot(HaveOccurred())
+		store = revocation.NewPostgresStore(db)
+	})
+
+	AfterEach(func() {
+		Expect(mockDB.ExpectationsWereMet()).To(Succeed())
+	})
+
+	It("should insert a revoked jti with its expiry", func() {
+		expiresAt := time.Now().Add(time.Hour)
+		mockDB.ExpectExec("DELETE FROM revoked_token WHERE expires_at").
+			WithArgs(sqltest.AnyTime{}).
+			WillReturnResult(sqlmock.NewResult(0, 3))
+		mockDB.ExpectExec("INSERT INTO revoked_token").
+			WithArgs("jti", expiresAt, sqltest.AnyTime{}).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		Expect(store.Revoke(ctx, "jti", expiresAt)).To(Succeed())
+	})
+
+	It("should not insert when pruning expired jtis fails", func() {
+		mockDB.ExpectExec("DELETE FROM revoked_token").
+			WithArgs(sqltest.AnyTime{}).
+			WillReturnError(sql.ErrConnDone)
+		Expect(store.Revoke(ctx, "jti", time.Now().Add(time.Hour))).
+			To(MatchError(sql.ErrConnDone))
+	})
+
+	It("should report a listed jti as revoked", func() {
+		mockDB.ExpectQuery("SELECT 1 FROM revoked_token").WithArgs("jti").
+			WillReturnRows(sqlmock.NewRows([]string{"?column?"}).AddRow(1))
+		Expect(store.IsRevoked(ctx, "jti")).To(BeTrue())
+	})
+
+	It("should report an unlisted jti as not revoked", func() {
+		mockDB.ExpectQuery("SELECT 1 FROM revoked_token").WithArgs("jti").
+			WillReturnError(sql.ErrNoRows)
+		Expect(store.IsRevoked(ctx, "jti")).To(BeFalse())
+	})
+})
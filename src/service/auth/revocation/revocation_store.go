@@ -0,0 +1,14 @@
+package revocation
+
+import (
+	"context"
+	"time"
+)
+
+//Store ... records revoked token IDs (jti) until the tokens would have expired anyway.
+//expiresAt must include any leeway validation still grants after a token's exp.
+//go:generate counterfeiter . Store
+type Store interface {
+	Revoke(ctx context.Context, jti string, expiresAt time.Time) error
+	IsRevoked(ctx context.Context, jti string) (bool, error)
+}
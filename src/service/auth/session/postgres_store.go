@@ -0,0 +1,112 @@
+package session
+
+import (
+	"context"
+	"database/sql"
+	"service/database"
+	"time"
+)
+
+//PostgresStore ... is a Store backed by the browser_session table, shared by every instance.
+type PostgresStore struct {
+	db database.DBInterface
+}
+
+//NewPostgresStore ... returns a pointer to a new PostgresStore using the passed in db.
+func NewPostgresStore(db database.DBInterface) *PostgresStore {
+	return &PostgresStore{
+		db: db,
+	}
+}
+
+const sessionColumns = "id, identity_id, csrf_token, user_agent, ip, created_at, last_seen_at, expires_at"
+
+//Create ... stores the session under tokenHash.
+func (p *PostgresStore) Create(ctx context.Context, session Session, tokenHash string) error {
+	_, err := p.db.ExecContext(ctx,
+		`INSERT INTO browser_session (token_hash, `+sessionColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9);`,
+		tokenHash,
+		session.ID,
+		session.IdentityID,
+		session.CSRFToken,
+		session.UserAgent,
+		session.IP,
+		session.CreatedAt,
+		session.LastSeenAt,
+		session.ExpiresAt)
+	return err
+}
+
+//Get ... returns the session stored under tokenHash, sql.ErrNoRows when there is none.
+func (p *PostgresStore) Get(ctx context.Context, tokenHash string) (*Session, error) {
+	return scanSession(p.db.QueryRowContext(ctx,
+		"SELECT "+sessionColumns+" FROM browser_session WHERE token_hash = $1;", tokenHash))
+}
+
+//Touch ... records activity on the session.
+func (p *PostgresStore) Touch(ctx context.Context, sessionID string, at time.Time) error {
+	_, err := p.db.ExecContext(ctx,
+		"UPDATE browser_session SET last_seen_at = $2 WHERE id = $1;",
+		sessionID, at)
+	return err
+}
+
+//Delete ... removes the identity's session, sql.ErrNoRows when it has no such session.
+func (p *PostgresStore) Delete(ctx context.Context, identityID, sessionID string) error {
+	result, err := p.db.ExecContext(ctx,
+		"DELETE FROM browser_session WHERE identity_id = $1 AND id = $2;",
+		identityID, sessionID)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+//DeleteAll ... removes every session of the identity.
+func (p *PostgresStore) DeleteAll(ctx context.Context, identityID string) error {
+	_, err := p.db.ExecContext(ctx,
+		"DELETE FROM browser_session WHERE identity_id = $1;", identityID)
+	return err
+}
+
+//List ... returns the identity's sessions that have not passed their absolute timeout,
+//newest first.
+func (p *PostgresStore) List(ctx context.Context, identityID string) ([]Session, error) {
+	rows, err := p.db.QueryContext(ctx, `SELECT `+sessionColumns+` FROM browser_session
+		WHERE identity_id = $1 AND expires_at > $2 ORDER BY created_at DESC;`,
+		identityID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	sessions := make([]Session, 0)
+	for rows.Next() {
+		session, err := scanSession(rows)
+		if err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, *session)
+	}
+	return sessions, rows.Err()
+}
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanSession(row scanner) (*Session, error) {
+	var session Session
+	if err := row.Scan(&session.ID, &session.IdentityID, &session.CSRFToken, &session.UserAgent,
+		&session.IP, &session.CreatedAt, &session.LastSeenAt, &session.ExpiresAt); err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
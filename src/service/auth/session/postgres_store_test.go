@@ -0,0 +1,81 @@
+package session_test
+
+import (
+	"context"
+	"database/sql"
+	"service/auth/session"
+	"service/utils/sqltest"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	sqlmock "gopkg.in/DATA-DOG/go-sqlmock.v1"
+)
+
+var _ = Describe("Postgres Store Specs", func() {
+	ctx := context.Background()
+	var (
+		store  *session.PostgresStore
+		mockDB sqlmock.Sqlmock
+	)
+
+	columns := []string{"id", "identity_id", "csrf_token", "user_agent", "ip", "created_at",
+		"last_seen_at", "expires_at"}
+
+	BeforeEach(func() {
+		db, mock, sqlmockErr := sqlmock.New()
+		Expect(sqlmockErr).ToNot(HaveOccurred())
+		mockDB = mock
+		store = session.NewPostgresStore(db)
+	})
+
+	AfterEach(func() {
+		Expect(mockDB.ExpectationsWereMet()).To(Succeed())
+	})
+
+	It("should create a session under its token hash", func() {
+		rightNow := time.Now()
+		mockDB.ExpectExec("INSERT INTO browser_session").
+			WithArgs("token_hash", "session_id", "test_id", "csrf", "test-browser", "10.0.0.1",
+				rightNow, rightNow, rightNow.Add(time.Hour)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		Expect(store.Create(ctx, session.Session{
+			ID:         "session_id",
+			IdentityID: "test_id",
+			CSRFToken:  "csrf",
+			UserAgent:  "test-browser",
+			IP:         "10.0.0.1",
+			CreatedAt:  rightNow,
+			LastSeenAt: rightNow,
+			ExpiresAt:  rightNow.Add(time.Hour),
+		}, "token_hash")).To(Succeed())
+	})
+
+	It("should get a session by its token hash", func() {
+		mockDB.ExpectQuery("SELECT (.+) FROM browser_session WHERE token_hash").
+			WithArgs("token_hash").
+			WillReturnRows(sqlmock.NewRows(columns).AddRow("session_id", "test_id", "csrf",
+				"test-browser", "10.0.0.1", time.Now(), time.Now(), time.Now().Add(time.Hour)))
+		stored, err := store.Get(ctx, "token_hash")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(stored.ID).To(Equal("session_id"))
+		Expect(stored.CSRFToken).To(Equal("csrf"))
+	})
+
+	It("should report deleting another identity's session", func() {
+		mockDB.ExpectExec("DELETE FROM browser_session WHERE identity_id").
+			WithArgs("other_id", "session_id").
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		Expect(store.Delete(ctx, "other_id", "session_id")).To(Equal(sql.ErrNoRows))
+	})
+
+	It("should list sessions that have not expired", func() {
+		mockDB.ExpectQuery("SELECT (.+) FROM browser_session").
+			WithArgs("test_id", sqltest.AnyTime{}).
+			WillReturnRows(sqlmock.NewRows(columns).AddRow("session_id", "test_id", "csrf",
+				"test-browser", "10.0.0.1", time.Now(), time.Now(), time.Now().Add(time.Hour)))
+		sessions, err := store.List(ctx, "test_id")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(sessions).To(HaveLen(1))
+	})
+})
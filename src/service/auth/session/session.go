@@ -0,0 +1,262 @@
+package session
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"service/auth/throttle"
+	"service/log"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+//Defaults for any zero field of a configured Policy.
+const (
+	DefaultIdleTimeout     = 30 * time.Minute
+	DefaultAbsoluteTimeout = 12 * time.Hour
+	DefaultCookieName      = "__Host-session"
+	InsecureCookieName     = "session"
+)
+
+//TouchInterval ... last_seen_at is only written once per interval, so idle timeouts are
+//enforced to within a minute without a write per request.
+const TouchInterval = time.Minute
+
+//ErrInvalid ... is returned for missing, unknown, idle or expired sessions.
+var ErrInvalid = errors.New("invalid or expired session")
+
+//Session ... is a signed in browser. The cookie value is never stored, only its sha256.
+type Session struct {
+	ID         string    `json:"id"`
+	IdentityID string    `json:"identityId"`
+	CSRFToken  string    `json:"-"`
+	UserAgent  string    `json:"userAgent"`
+	IP         string    `json:"ip"`
+	CreatedAt  time.Time `json:"createdAt"`
+	LastSeenAt time.Time `json:"lastSeenAt"`
+	ExpiresAt  time.Time `json:"expiresAt"`
+}
+
+//Policy ...
+//A session ends IdleTimeout after its last request or AbsoluteTimeout after sign in,
+//whichever comes first. Insecure drops the Secure attribute, and the __Host- cookie
+//name prefix that requires it, for local development over http.
+type Policy struct {
+	IdleTimeout     time.Duration
+	AbsoluteTimeout time.Duration
+	SameSite        http.SameSite
+	Insecure        bool
+}
+
+//Interface ... defines starting, loading and ending cookie sessions.
+//go:generate counterfeiter . Interface
+type Interface interface {
+	Start(w http.ResponseWriter, req *http.Request, identityID string) (*Session, error)
+	Load(req *http.Request) (*Session, error)
+	End(w http.ResponseWriter, req *http.Request) error
+	List(ctx context.Context, identityID string) ([]Session, error)
+	Revoke(ctx context.Context, identityID, sessionID string) error
+	RevokeAll(ctx context.Context, identityID string) error
+	CookieName() string
+}
+
+//Manager ... applies a Policy to sessions kept in a Store.
+type Manager struct {
+	log    log.ProdInterface
+	store  Store
+	policy Policy
+}
+
+//NewManager ... returns a pointer to a new Manager, zero policy fields use the defaults
+//and SameSite defaults to Lax.
+func NewManager(logClient log.ProdInterface, store Store, policy Policy) *Manager {
+	if policy.IdleTimeout <= 0 {
+		policy.IdleTimeout = DefaultIdleTimeout
+	}
+	if policy.AbsoluteTimeout <= 0 {
+		policy.AbsoluteTimeout = DefaultAbsoluteTimeout
+	}
+	if policy.SameSite == 0 || policy.SameSite == http.SameSiteDefaultMode {
+		policy.SameSite = http.SameSiteLaxMode
+	}
+	return &Manager{
+		log:    logClient,
+		store:  store,
+		policy: policy,
+	}
+}
+
+//CookieName ... the name of the session cookie.
+func (m *Manager) CookieName() string {
+	if m.policy.Insecure {
+		return InsecureCookieName
+	}
+	return DefaultCookieName
+}
+
+//Start ...
+//creates a session for the identity and sets its cookie. Any session the request
+//already carried is ended first, so a planted cookie can't be fixated.
+func (m *Manager) Start(w http.ResponseWriter, req *http.Request,
+	identityID string) (*Session, error) {
+	if previous, err := m.Load(req); err == nil {
+		if err := m.store.Delete(req.Context(), previous.IdentityID, previous.ID); err != nil &&
+			err != sql.ErrNoRows {
+			return nil, err
+		}
+	}
+	token, err := randomToken()
+	if err != nil {
+		return nil, err
+	}
+	csrfToken, err := randomToken()
+	if err != nil {
+		return nil, err
+	}
+	rightNow := time.Now()
+	session := Session{
+		ID:         uuid.New().String(),
+		IdentityID: identityID,
+		CSRFToken:  csrfToken,
+		UserAgent:  req.UserAgent(),
+		IP:         throttle.ClientIP(req),
+		CreatedAt:  rightNow,
+		LastSeenAt: rightNow,
+		ExpiresAt:  rightNow.Add(m.policy.AbsoluteTimeout),
+	}
+	if err := m.store.Create(req.Context(), session, hashToken(token)); err != nil {
+		return nil, err
+	}
+	http.SetCookie(w, m.cookie(token, int(m.policy.AbsoluteTimeout/time.Second)))
+	m.log.Info("session started",
+		zap.String("event", "auth.session.start"),
+		zap.String("identityID", identityID),
+		zap.String("sessionID", session.ID))
+	return &session, nil
+}
+
+//Load ...
+//returns the session the request's cookie belongs to, ending it if it has gone idle or
+//passed its absolute timeout. Errors are ErrInvalid or a store failure.
+func (m *Manager) Load(req *http.Request) (*Session, error) {
+	cookie, err := req.Cookie(m.CookieName())
+	if err != nil || cookie.Value == "" {
+		return nil, ErrInvalid
+	}
+	session, err := m.store.Get(req.Context(), hashToken(cookie.Value))
+	if err == sql.ErrNoRows {
+		return nil, ErrInvalid
+	}
+	if err != nil {
+		return nil, err
+	}
+	rightNow := time.Now()
+	if !rightNow.Before(session.ExpiresAt) ||
+		rightNow.Sub(session.LastSeenAt) >= m.policy.IdleTimeout {
+		if err := m.store.Delete(req.Context(), session.IdentityID, session.ID); err != nil &&
+			err != sql.ErrNoRows {
+			return nil, err
+		}
+		return nil, ErrInvalid
+	}
+	if rightNow.Sub(session.LastSeenAt) >= TouchInterval {
+		if err := m.store.Touch(req.Context(), session.ID, rightNow); err != nil {
+			return nil, err
+		}
+		session.LastSeenAt = rightNow
+	}
+	return session, nil
+}
+
+//End ... deletes the request's session, if any, and clears its cookie.
+func (m *Manager) End(w http.ResponseWriter, req *http.Request) error {
+	http.SetCookie(w, m.cookie("", -1))
+	session, err := m.Load(req)
+	if err == ErrInvalid {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if err := m.store.Delete(req.Context(), session.IdentityID, session.ID); err != nil &&
+		err != sql.ErrNoRows {
+		return err
+	}
+	m.log.Info("session ended",
+		zap.String("event", "auth.session.end"),
+		zap.String("identityID", session.IdentityID),
+		zap.String("sessionID", session.ID))
+	return nil
+}
+
+//List ... returns the identity's live sessions, newest first.
+func (m *Manager) List(ctx context.Context, identityID string) ([]Session, error) {
+	sessions, err := m.store.List(ctx, identityID)
+	if err != nil {
+		return nil, err
+	}
+	rightNow := time.Now()
+	live := make([]Session, 0, len(sessions))
+	for _, session := range sessions {
+		if rightNow.Before(session.ExpiresAt) &&
+			rightNow.Sub(session.LastSeenAt) < m.policy.IdleTimeout {
+			live = append(live, session)
+		}
+	}
+	return live, nil
+}
+
+//Revoke ... ends one of the identity's sessions, sql.ErrNoRows when it has no such session.
+func (m *Manager) Revoke(ctx context.Context, identityID, sessionID string) error {
+	if err := m.store.Delete(ctx, identityID, sessionID); err != nil {
+		return err
+	}
+	m.log.Info("session revoked",
+		zap.String("event", "auth.session.revoke"),
+		zap.String("identityID", identityID),
+		zap.String("sessionID", sessionID))
+	return nil
+}
+
+//RevokeAll ... ends every session of the identity.
+func (m *Manager) RevokeAll(ctx context.Context, identityID string) error {
+	if err := m.store.DeleteAll(ctx, identityID); err != nil {
+		return err
+	}
+	m.log.Info("sessions revoked",
+		zap.String("event", "auth.session.revoke_all"),
+		zap.String("identityID", identityID))
+	return nil
+}
+
+func (m *Manager) cookie(value string, maxAge int) *http.Cookie {
+	return &http.Cookie{
+		Name:     m.CookieName(),
+		Value:    value,
+		Path:     "/",
+		MaxAge:   maxAge,
+		HttpOnly: true,
+		Secure:   !m.policy.Insecure,
+		SameSite: m.policy.SameSite,
+	}
+}
+
+func randomToken() (string, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(raw), nil
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,225 @@
+package session
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"service/auth/mfa"
+	"service/auth/throttle"
+	"service/handlers/loggederror"
+	"service/identity"
+	"service/log"
+
+	"github.com/go-chi/chi"
+	"go.uber.org/zap"
+)
+
+//HandlerObject ... holds elementals for signing browsers in and managing their sessions.
+type HandlerObject struct {
+	Log        log.ProdInterface
+	Sessions   Interface
+	Identities identity.ServiceInterface
+	MFA        mfa.Interface
+	Throttle   throttle.Interface
+}
+
+//NewHandlerObject ... returns a pointer to a new session HandlerObject.
+func NewHandlerObject(logClient log.ProdInterface, sessions Interface,
+	identities identity.ServiceInterface, mfa mfa.Interface,
+	throttle throttle.Interface) *HandlerObject {
+	return &HandlerObject{
+		Log:        logClient,
+		Sessions:   sessions,
+		Identities: identities,
+		MFA:        mfa,
+		Throttle:   throttle,
+	}
+}
+
+type loginPostBody struct {
+	ID       string `json:"id"`
+	Password string `json:"password"`
+	Code     string `json:"code"`
+}
+
+type sessionResponse struct {
+	Status    int      `json:"status"`
+	CSRFToken string   `json:"csrfToken"`
+	Session   *Session `json:"session"`
+}
+
+type listSessionsResponse struct {
+	Status   int       `json:"status"`
+	Sessions []Session `json:"sessions"`
+}
+
+//Login ...
+//POST /session, checks the id, password and, when MFA is enabled, the TOTP or recovery
+//code, then sets the session cookie. The CSRF token for later unsafe requests is only
+//returned in the body.
+func (h *HandlerObject) Login(w http.ResponseWriter, req *http.Request) {
+	var jsonDoc loginPostBody
+	if req.Body == nil || json.NewDecoder(req.Body).Decode(&jsonDoc) != nil {
+		h.softError(http.StatusBadRequest, "bad request", "Login", w, req)
+		return
+	}
+	if jsonDoc.ID == "" || jsonDoc.Password == "" {
+		h.softError(http.StatusBadRequest, "missing required login params", "Login", w, req)
+		return
+	}
+	identityKey := throttle.IdentityKey(jsonDoc.ID)
+	ipKey := throttle.IPKey(throttle.ClientIP(req))
+	wait, err := h.Throttle.Check(req.Context(), identityKey, ipKey)
+	if err != nil {
+		h.internalServerError(err, "Login", w, req)
+		return
+	}
+	if wait > 0 {
+		throttle.SetRetryAfter(w, wait)
+		h.softError(http.StatusTooManyRequests, throttle.ErrTooManyAttempts, "Login", w, req)
+		return
+	}
+	verified, err := h.Identities.VerifyPassword(req.Context(), jsonDoc.ID, jsonDoc.Password)
+	if err != nil {
+		h.internalServerError(err, "Login", w, req)
+		return
+	}
+	if verified {
+		var enabled bool
+		if enabled, err = h.MFA.Enabled(req.Context(), jsonDoc.ID); err == nil && enabled {
+			if jsonDoc.Code == "" {
+				//The password failures are only cleared once the second factor is passed too.
+				h.softError(http.StatusUnauthorized, "mfa code required", "Login", w, req)
+				return
+			}
+			verified, err = h.MFA.Verify(req.Context(), jsonDoc.ID, jsonDoc.Code)
+		}
+		if err != nil {
+			h.internalServerError(err, "Login", w, req)
+			return
+		}
+	}
+	if !verified {
+		if err := h.Throttle.Fail(req.Context(), identityKey, ipKey); err != nil {
+			h.internalServerError(err, "Login", w, req)
+			return
+		}
+		h.softError(http.StatusUnauthorized, "invalid id, password or code", "Login", w, req)
+		return
+	}
+	if err := h.Throttle.Succeed(req.Context(), identityKey); err != nil {
+		h.internalServerError(err, "Login", w, req)
+		return
+	}
+	session, err := h.Sessions.Start(w, req, jsonDoc.ID)
+	if err != nil {
+		h.internalServerError(err, "Login", w, req)
+		return
+	}
+	h.respond(http.StatusCreated, &sessionResponse{
+		Status:    http.StatusCreated,
+		CSRFToken: session.CSRFToken,
+		Session:   session,
+	}, "Login", w, req)
+}
+
+//Current ...
+//GET /session, returns the caller's session and CSRF token, e.g. after a page reload.
+func (h *HandlerObject) Current(w http.ResponseWriter, req *http.Request) {
+	session, ok := FromContext(req.Context())
+	if !ok {
+		h.softError(http.StatusUnauthorized, ErrInvalid.Error(), "Current", w, req)
+		return
+	}
+	h.respond(http.StatusOK, &sessionResponse{
+		Status:    http.StatusOK,
+		CSRFToken: session.CSRFToken,
+		Session:   session,
+	}, "Current", w, req)
+}
+
+//Logout ... DELETE /session
+func (h *HandlerObject) Logout(w http.ResponseWriter, req *http.Request) {
+	if err := h.Sessions.End(w, req); err != nil {
+		h.internalServerError(err, "Logout", w, req)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+//ListSessions ... GET /identity/{id}/sessions
+func (h *HandlerObject) ListSessions(w http.ResponseWriter, req *http.Request) {
+	sessions, err := h.Sessions.List(req.Context(), chi.URLParam(req, "id"))
+	if err != nil {
+		h.internalServerError(err, "ListSessions", w, req)
+		return
+	}
+	h.respond(http.StatusOK, &listSessionsResponse{
+		Status:   http.StatusOK,
+		Sessions: sessions,
+	}, "ListSessions", w, req)
+}
+
+//RevokeSession ... DELETE /identity/{id}/sessions/{sessionID}
+func (h *HandlerObject) RevokeSession(w http.ResponseWriter, req *http.Request) {
+	err := h.Sessions.Revoke(req.Context(), chi.URLParam(req, "id"), chi.URLParam(req, "sessionID"))
+	if err == sql.ErrNoRows {
+		h.softError(http.StatusNotFound, "session not found", "RevokeSession", w, req)
+		return
+	}
+	if err != nil {
+		h.internalServerError(err, "RevokeSession", w, req)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+//RevokeSessions ... DELETE /identity/{id}/sessions, signs the identity out everywhere.
+func (h *HandlerObject) RevokeSessions(w http.ResponseWriter, req *http.Request) {
+	if err := h.Sessions.RevokeAll(req.Context(), chi.URLParam(req, "id")); err != nil {
+		h.internalServerError(err, "RevokeSessions", w, req)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *HandlerObject) respond(status int, response interface{}, source string,
+	w http.ResponseWriter, req *http.Request) {
+	bytesArray, marshalErr := json.Marshal(response)
+	if marshalErr != nil {
+		h.internalServerError(marshalErr, source, w, req)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	if _, writeErr := w.Write(bytesArray); writeErr != nil {
+		h.Log.Error("session_handler::"+source, zap.Error(writeErr))
+	}
+}
+
+// internalServerError is used to wrap our loggederror for this route.
+func (h *HandlerObject) internalServerError(err error, source string, w http.ResponseWriter,
+	req *http.Request) {
+	loggederror.RespondWithProperErrorAndLogIt(
+		h.Log,
+		http.StatusInternalServerError,
+		err,
+		"session_handler::"+source,
+		w,
+		req,
+	)
+}
+
+// softError is used to wrap our loggederror for expected failures on this route.
+func (h *HandlerObject) softError(status int, message, source string, w http.ResponseWriter,
+	req *http.Request) {
+	loggederror.RespondWithWithExpectedSoftError(
+		h.Log,
+		status,
+		message,
+		"session_handler::"+source,
+		w,
+		req,
+	)
+}
@@ -0,0 +1,144 @@
+package session_test
+
+import (
+	"bytes"
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"service/auth/mfa/mfafakes"
+	"service/auth/session"
+	"service/auth/session/sessionfakes"
+	"service/auth/throttle/throttlefakes"
+	"service/identity/identityfakes"
+	"service/log"
+	"service/log/logfakes"
+	"time"
+
+	"github.com/go-chi/chi"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Session Handler Specs", func() {
+	var (
+		fakeSessions   *sessionfakes.FakeInterface
+		fakeIdentities *identityfakes.FakeServiceInterface
+		fakeMFA        *mfafakes.FakeInterface
+		fakeThrottle   *throttlefakes.FakeInterface
+		router         *chi.Mux
+		recorder       *httptest.ResponseRecorder
+	)
+
+	BeforeEach(func() {
+		fakeSessions = &sessionfakes.FakeInterface{}
+		fakeIdentities = &identityfakes.FakeServiceInterface{}
+		fakeMFA = &mfafakes.FakeInterface{}
+		fakeThrottle = &throttlefakes.FakeInterface{}
+		handler := session.NewHandlerObject(log.New(&logfakes.FakeProdInterface{}),
+			fakeSessions, fakeIdentities, fakeMFA, fakeThrottle)
+		router = chi.NewRouter()
+		router.Post("/session", handler.Login)
+		router.Delete("/session", handler.Logout)
+		router.Get("/identity/{id}/sessions", handler.ListSessions)
+		router.Delete("/identity/{id}/sessions", handler.RevokeSessions)
+		router.Delete("/identity/{id}/sessions/{sessionID}", handler.RevokeSession)
+		recorder = httptest.NewRecorder()
+
+		fakeIdentities.VerifyPasswordReturns(true, nil)
+		fakeSessions.StartReturns(&session.Session{ID: "session_id", IdentityID: "test_id",
+			CSRFToken: "the-csrf-token"}, nil)
+	})
+
+	serve := func(method, path, body string) {
+		router.ServeHTTP(recorder, httptest.NewRequest(method, path, bytes.NewBufferString(body)))
+	}
+
+	Context("Login", func() {
+		It("should start a session and return its CSRF token", func() {
+			serve("POST", "/session", `{"id": "test_id", "password": "password"}`)
+			Expect(recorder.Code).To(Equal(http.StatusCreated))
+			Expect(recorder.Body.String()).To(ContainSubstring(`"csrfToken":"the-csrf-token"`))
+			_, _, identityID := fakeSessions.StartArgsForCall(0)
+			Expect(identityID).To(Equal("test_id"))
+			Expect(fakeThrottle.SucceedCallCount()).To(Equal(1))
+		})
+
+		It("should count a wrong password", func() {
+			fakeIdentities.VerifyPasswordReturns(false, nil)
+			serve("POST", "/session", `{"id": "test_id", "password": "wrong"}`)
+			Expect(recorder.Code).To(Equal(http.StatusUnauthorized))
+			Expect(fakeThrottle.FailCallCount()).To(Equal(1))
+			Expect(fakeSessions.StartCallCount()).To(Equal(0))
+		})
+
+		It("should not check the password while throttled", func() {
+			fakeThrottle.CheckReturns(time.Minute, nil)
+			serve("POST", "/session", `{"id": "test_id", "password": "password"}`)
+			Expect(recorder.Code).To(Equal(http.StatusTooManyRequests))
+			Expect(fakeIdentities.VerifyPasswordCallCount()).To(Equal(0))
+		})
+
+		Context("when the identity has MFA enabled", func() {
+			BeforeEach(func() {
+				fakeMFA.EnabledReturns(true, nil)
+			})
+
+			It("should ask for a code", func() {
+				serve("POST", "/session", `{"id": "test_id", "password": "password"}`)
+				Expect(recorder.Code).To(Equal(http.StatusUnauthorized))
+				Expect(recorder.Body.String()).To(ContainSubstring("mfa code required"))
+				Expect(fakeSessions.StartCallCount()).To(Equal(0))
+			})
+
+			It("should count a wrong code", func() {
+				serve("POST", "/session", `{"id": "test_id", "password": "password", "code": "000000"}`)
+				Expect(recorder.Code).To(Equal(http.StatusUnauthorized))
+				Expect(fakeThrottle.FailCallCount()).To(Equal(1))
+			})
+
+			It("should start a session with a valid code", func() {
+				fakeMFA.VerifyReturns(true, nil)
+				serve("POST", "/session", `{"id": "test_id", "password": "password", "code": "123456"}`)
+				Expect(recorder.Code).To(Equal(http.StatusCreated))
+			})
+		})
+	})
+
+	It("should end the session on logout", func() {
+		serve("DELETE", "/session", "")
+		Expect(recorder.Code).To(Equal(http.StatusNoContent))
+		Expect(fakeSessions.EndCallCount()).To(Equal(1))
+	})
+
+	It("should list an identity's sessions", func() {
+		fakeSessions.ListReturns([]session.Session{{ID: "session_id", UserAgent: "test-browser"}}, nil)
+		serve("GET", "/identity/test_id/sessions", "")
+		Expect(recorder.Code).To(Equal(http.StatusOK))
+		Expect(recorder.Body.String()).To(ContainSubstring(`"userAgent":"test-browser"`))
+		Expect(recorder.Body.String()).ToNot(ContainSubstring("csrf"))
+	})
+
+	Context("RevokeSession", func() {
+		It("should revoke the identity's session", func() {
+			serve("DELETE", "/identity/test_id/sessions/session_id", "")
+			Expect(recorder.Code).To(Equal(http.StatusNoContent))
+			_, identityID, sessionID := fakeSessions.RevokeArgsForCall(0)
+			Expect(identityID).To(Equal("test_id"))
+			Expect(sessionID).To(Equal("session_id"))
+		})
+
+		It("should 404 for unknown sessions", func() {
+			fakeSessions.RevokeReturns(sql.ErrNoRows)
+			serve("DELETE", "/identity/test_id/sessions/missing", "")
+			Expect(recorder.Code).To(Equal(http.StatusNotFound))
+		})
+	})
+
+	It("should revoke every session of an identity", func() {
+		serve("DELETE", "/identity/test_id/sessions", "")
+		Expect(recorder.Code).To(Equal(http.StatusNoContent))
+		_, identityID := fakeSessions.RevokeAllArgsForCall(0)
+		Expect(identityID).To(Equal("test_id"))
+	})
+})
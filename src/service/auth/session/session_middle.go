@@ -0,0 +1,116 @@
+package session
+
+import (
+	"context"
+	"crypto/subtle"
+	"net/http"
+	"service/auth/permission"
+	"service/auth/token/jwt"
+	"service/handlers/request"
+	"service/log"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+//CSRFHeader ... is the header unsafe requests carry the session's CSRF token in.
+const CSRFHeader = "X-CSRF-Token"
+
+//CSRFField ... is the form field HTML forms can carry the CSRF token in instead.
+const CSRFField = "csrf_token"
+
+type key string
+
+const sessionKey = key("session")
+
+//FromContext ... retrieves the session stored by AuthMiddleware.
+func FromContext(ctx context.Context) (*Session, bool) {
+	session, ok := ctx.Value(sessionKey).(*Session)
+	return session, ok && session != nil
+}
+
+//HasCookie ... reports whether the request carries a session cookie, valid or not.
+func HasCookie(req *http.Request) bool {
+	_, err := req.Cookie(sessions.CookieName())
+	return err == nil
+}
+
+//AuthMiddleware ... loads the request's cookie session and stores claims for its
+//identity in the request context, so permission.Require works as it does for bearer
+//tokens. The identity is granted everything its roles grant. Pair with CSRFMiddleware.
+func AuthMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		session, err := sessions.Load(req)
+		if err == ErrInvalid {
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+		if err != nil {
+			internalServerError(err, w, req)
+			return
+		}
+		granted, err := roleStore.Permissions(req.Context(), session.IdentityID)
+		if err != nil {
+			internalServerError(err, w, req)
+			return
+		}
+		claims := &jwt.IdentityClaims{Scope: strings.Join(granted, " ")}
+		claims.Id = session.ID
+		claims.Subject = session.IdentityID
+		ctx := request.WithClaims(req.Context(), claims)
+		ctx = context.WithValue(ctx, sessionKey, session)
+		next.ServeHTTP(w, req.WithContext(ctx))
+	})
+}
+
+//CSRFMiddleware ...
+//requires unsafe methods to echo the session's synchronizer token in the X-CSRF-Token
+//header or csrf_token form field. A cross site form can send the cookie but can't
+//read the token. Must run after AuthMiddleware.
+func CSRFMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch req.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace:
+			next.ServeHTTP(w, req)
+			return
+		}
+		session, ok := FromContext(req.Context())
+		if !ok {
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+		token := req.Header.Get(CSRFHeader)
+		if token == "" {
+			token = req.PostFormValue(CSRFField)
+		}
+		if token == "" ||
+			subtle.ConstantTimeCompare([]byte(token), []byte(session.CSRFToken)) != 1 {
+			logClient.Warn("csrf token mismatch",
+				zap.String("requestID", request.RetreiveRequestID(req.Context())),
+				zap.String("event", "auth.csrf.rejected"),
+				zap.String("sessionID", session.ID))
+			http.Error(w, "invalid csrf token", http.StatusForbidden)
+			return
+		}
+		next.ServeHTTP(w, req)
+	})
+}
+
+func internalServerError(err error, w http.ResponseWriter, req *http.Request) {
+	logClient.Error("session::AuthMiddleware",
+		zap.String("requestID", request.RetreiveRequestID(req.Context())),
+		zap.Error(err))
+	http.Error(w, http.StatusText(http.StatusInternalServerError),
+		http.StatusInternalServerError)
+}
+
+var sessions Interface
+var roleStore permission.Store
+var logClient log.ProdInterface
+
+//SetupAuthMiddleware ... attaches a configured session manager and role store
+func SetupAuthMiddleware(manager Interface, roles permission.Store, log log.ProdInterface) {
+	sessions = manager
+	roleStore = roles
+	logClient = log
+}
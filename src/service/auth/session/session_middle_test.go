@@ -0,0 +1,118 @@
+package session_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"service/auth/permission/permissionfakes"
+	"service/auth/session"
+	"service/auth/session/sessionfakes"
+	"service/handlers/request"
+	"service/log/logfakes"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Session Middleware Specs", func() {
+	var (
+		fakeSessions *sessionfakes.FakeInterface
+		fakeRoles    *permissionfakes.FakeStore
+		req          *http.Request
+		recorder     *httptest.ResponseRecorder
+		reached      bool
+		subject      string
+	)
+
+	BeforeEach(func() {
+		fakeSessions = &sessionfakes.FakeInterface{}
+		fakeRoles = &permissionfakes.FakeStore{}
+		session.SetupAuthMiddleware(fakeSessions, fakeRoles, &logfakes.FakeProdInterface{})
+		fakeSessions.LoadReturns(&session.Session{ID: "session_id", IdentityID: "test_id",
+			CSRFToken: "the-csrf-token"}, nil)
+		fakeRoles.PermissionsReturns([]string{"identity:read"}, nil)
+		req = httptest.NewRequest("GET", "/", nil)
+		recorder = httptest.NewRecorder()
+		reached, subject = false, ""
+	})
+
+	JustBeforeEach(func() {
+		session.AuthMiddleware(session.CSRFMiddleware(http.HandlerFunc(
+			func(w http.ResponseWriter, req *http.Request) {
+				reached = true
+				subject = request.RetreiveSubject(req.Context())
+			}))).ServeHTTP(recorder, req)
+	})
+
+	It("should authenticate as the session's identity", func() {
+		Expect(reached).To(BeTrue())
+		Expect(subject).To(Equal("test_id"))
+	})
+
+	Context("when the session is invalid", func() {
+		BeforeEach(func() {
+			fakeSessions.LoadReturns(nil, session.ErrInvalid)
+		})
+
+		It("should 401", func() {
+			Expect(reached).To(BeFalse())
+			Expect(recorder.Code).To(Equal(http.StatusUnauthorized))
+		})
+	})
+
+	Context("when the store fails", func() {
+		BeforeEach(func() {
+			fakeSessions.LoadReturns(nil, errors.New("db down"))
+		})
+
+		It("should 500", func() {
+			Expect(recorder.Code).To(Equal(http.StatusInternalServerError))
+		})
+	})
+
+	Context("on an unsafe method", func() {
+		BeforeEach(func() {
+			req = httptest.NewRequest("DELETE", "/session", nil)
+		})
+
+		It("should refuse a missing CSRF token", func() {
+			Expect(reached).To(BeFalse())
+			Expect(recorder.Code).To(Equal(http.StatusForbidden))
+		})
+
+		Context("with a wrong CSRF token", func() {
+			BeforeEach(func() {
+				req.Header.Set(session.CSRFHeader, "forged")
+			})
+
+			It("should refuse the request", func() {
+				Expect(reached).To(BeFalse())
+				Expect(recorder.Code).To(Equal(http.StatusForbidden))
+			})
+		})
+
+		Context("with the CSRF token in the header", func() {
+			BeforeEach(func() {
+				req.Header.Set(session.CSRFHeader, "the-csrf-token")
+			})
+
+			It("should pass", func() {
+				Expect(reached).To(BeTrue())
+			})
+		})
+
+		Context("with the CSRF token in a form", func() {
+			BeforeEach(func() {
+				req = httptest.NewRequest("POST", "/",
+					strings.NewReader(url.Values{session.CSRFField: {"the-csrf-token"}}.Encode()))
+				req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			})
+
+			It("should pass", func() {
+				Expect(reached).To(BeTrue())
+			})
+		})
+	})
+})
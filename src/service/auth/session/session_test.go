@@ -0,0 +1,171 @@
+package session_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"service/auth/session"
+	"service/log"
+	"service/log/logfakes"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Session Manager Specs", func() {
+	ctx := context.Background()
+	var (
+		store   *session.MemoryStore
+		manager *session.Manager
+	)
+
+	hash := func(token string) string {
+		sum := sha256.Sum256([]byte(token))
+		return hex.EncodeToString(sum[:])
+	}
+
+	withCookie := func(value string) *http.Request {
+		req := httptest.NewRequest("GET", "/", nil)
+		req.AddCookie(&http.Cookie{Name: session.DefaultCookieName, Value: value})
+		return req
+	}
+
+	seed := func(token string, lastSeenAt, expiresAt time.Time) {
+		Expect(store.Create(ctx, session.Session{
+			ID:         "session_id",
+			IdentityID: "test_id",
+			CreatedAt:  lastSeenAt,
+			LastSeenAt: lastSeenAt,
+			ExpiresAt:  expiresAt,
+		}, hash(token))).To(Succeed())
+	}
+
+	BeforeEach(func() {
+		store = session.NewMemoryStore()
+		manager = session.NewManager(log.New(&logfakes.FakeProdInterface{}), store,
+			session.Policy{IdleTimeout: 10 * time.Minute, AbsoluteTimeout: time.Hour})
+	})
+
+	Context("Start", func() {
+		var (
+			recorder *httptest.ResponseRecorder
+			cookie   *http.Cookie
+			started  *session.Session
+		)
+
+		BeforeEach(func() {
+			recorder = httptest.NewRecorder()
+			req := httptest.NewRequest("POST", "/session", nil)
+			req.Header.Set("User-Agent", "test-browser")
+			var err error
+			started, err = manager.Start(recorder, req, "test_id")
+			Expect(err).ToNot(HaveOccurred())
+			cookie = recorder.Result().Cookies()[0]
+		})
+
+		It("should set a locked down host cookie", func() {
+			Expect(cookie.Name).To(Equal("__Host-session"))
+			Expect(cookie.Path).To(Equal("/"))
+			Expect(cookie.HttpOnly).To(BeTrue())
+			Expect(cookie.Secure).To(BeTrue())
+			Expect(cookie.SameSite).To(Equal(http.SameSiteLaxMode))
+			Expect(cookie.MaxAge).To(Equal(3600))
+		})
+
+		It("should store only a hash of the cookie", func() {
+			_, err := store.Get(ctx, cookie.Value)
+			Expect(err).To(HaveOccurred())
+			stored, err := store.Get(ctx, hash(cookie.Value))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(stored.ID).To(Equal(started.ID))
+			Expect(stored.UserAgent).To(Equal("test-browser"))
+			Expect(stored.CSRFToken).ToNot(BeEmpty())
+		})
+
+		It("should load the session from its cookie", func() {
+			loaded, err := manager.Load(withCookie(cookie.Value))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(loaded.IdentityID).To(Equal("test_id"))
+		})
+
+		It("should end a session the request already carried", func() {
+			req := withCookie(cookie.Value)
+			_, err := manager.Start(httptest.NewRecorder(), req, "test_id")
+			Expect(err).ToNot(HaveOccurred())
+			_, err = manager.Load(withCookie(cookie.Value))
+			Expect(err).To(Equal(session.ErrInvalid))
+		})
+	})
+
+	Context("Load", func() {
+		It("should refuse requests without a cookie", func() {
+			_, err := manager.Load(httptest.NewRequest("GET", "/", nil))
+			Expect(err).To(Equal(session.ErrInvalid))
+		})
+
+		It("should end idle sessions", func() {
+			seed("idle", time.Now().Add(-11*time.Minute), time.Now().Add(time.Hour))
+			_, err := manager.Load(withCookie("idle"))
+			Expect(err).To(Equal(session.ErrInvalid))
+			_, err = store.Get(ctx, hash("idle"))
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should end sessions past their absolute timeout", func() {
+			seed("old", time.Now(), time.Now().Add(-time.Second))
+			_, err := manager.Load(withCookie("old"))
+			Expect(err).To(Equal(session.ErrInvalid))
+		})
+
+		It("should record activity at most once per TouchInterval", func() {
+			lastSeenAt := time.Now().Add(-2 * time.Minute)
+			seed("active", lastSeenAt, time.Now().Add(time.Hour))
+			loaded, err := manager.Load(withCookie("active"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(loaded.LastSeenAt).To(BeTemporally("~", time.Now(), time.Second))
+			stored, _ := store.Get(ctx, hash("active"))
+			Expect(stored.LastSeenAt).To(Equal(loaded.LastSeenAt))
+		})
+	})
+
+	It("should end the session and clear its cookie", func() {
+		seed("current", time.Now(), time.Now().Add(time.Hour))
+		recorder := httptest.NewRecorder()
+		Expect(manager.End(recorder, withCookie("current"))).To(Succeed())
+		Expect(recorder.Result().Cookies()[0].MaxAge).To(BeNumerically("<", 0))
+		_, err := store.Get(ctx, hash("current"))
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should only list live sessions", func() {
+		seed("idle", time.Now().Add(-11*time.Minute), time.Now().Add(time.Hour))
+		Expect(store.Create(ctx, session.Session{ID: "live_id", IdentityID: "test_id",
+			LastSeenAt: time.Now(), ExpiresAt: time.Now().Add(time.Hour)}, hash("live"))).
+			To(Succeed())
+		sessions, err := manager.List(ctx, "test_id")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(sessions).To(HaveLen(1))
+		Expect(sessions[0].ID).To(Equal("live_id"))
+	})
+
+	It("should revoke every session of an identity", func() {
+		seed("current", time.Now(), time.Now().Add(time.Hour))
+		Expect(manager.RevokeAll(ctx, "test_id")).To(Succeed())
+		_, err := manager.Load(withCookie("current"))
+		Expect(err).To(Equal(session.ErrInvalid))
+	})
+
+	It("should use a plain cookie name when insecure", func() {
+		insecure := session.NewManager(log.New(&logfakes.FakeProdInterface{}), store,
+			session.Policy{Insecure: true})
+		recorder := httptest.NewRecorder()
+		_, err := insecure.Start(recorder, httptest.NewRequest("POST", "/session", nil), "test_id")
+		Expect(err).ToNot(HaveOccurred())
+		cookie := recorder.Result().Cookies()[0]
+		Expect(cookie.Name).To(Equal(session.InsecureCookieName))
+		Expect(cookie.Secure).To(BeFalse())
+	})
+})
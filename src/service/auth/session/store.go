@@ -0,0 +1,109 @@
+package session
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+	"sync"
+	"time"
+)
+
+//Store ... defines a backing store for sessions, looked up by the sha256 of their cookie.
+//go:generate counterfeiter . Store
+type Store interface {
+	Create(ctx context.Context, s Session, tokenHash string) error
+	Get(ctx context.Context, tokenHash string) (*Session, error)
+	Touch(ctx context.Context, sessionID string, at time.Time) error
+	Delete(ctx context.Context, identityID, sessionID string) error
+	DeleteAll(ctx context.Context, identityID string) error
+	List(ctx context.Context, identityID string) ([]Session, error)
+}
+
+//MemoryStore ... is a Store for a single instance, sessions are lost on restart.
+type MemoryStore struct {
+	mutex    sync.Mutex
+	sessions map[string]Session
+	hashes   map[string]string
+}
+
+//NewMemoryStore ... returns a pointer to a new, empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		sessions: make(map[string]Session),
+		hashes:   make(map[string]string),
+	}
+}
+
+//Create ... stores the session under tokenHash.
+func (m *MemoryStore) Create(ctx context.Context, session Session, tokenHash string) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.sessions[tokenHash] = session
+	m.hashes[session.ID] = tokenHash
+	return nil
+}
+
+//Get ... returns the session stored under tokenHash, sql.ErrNoRows when there is none.
+func (m *MemoryStore) Get(ctx context.Context, tokenHash string) (*Session, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	session, ok := m.sessions[tokenHash]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	return &session, nil
+}
+
+//Touch ... records activity on the session.
+func (m *MemoryStore) Touch(ctx context.Context, sessionID string, at time.Time) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if tokenHash, ok := m.hashes[sessionID]; ok {
+		session := m.sessions[tokenHash]
+		session.LastSeenAt = at
+		m.sessions[tokenHash] = session
+	}
+	return nil
+}
+
+//Delete ... removes the identity's session, sql.ErrNoRows when it has no such session.
+func (m *MemoryStore) Delete(ctx context.Context, identityID, sessionID string) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	tokenHash, ok := m.hashes[sessionID]
+	if !ok || m.sessions[tokenHash].IdentityID != identityID {
+		return sql.ErrNoRows
+	}
+	delete(m.sessions, tokenHash)
+	delete(m.hashes, sessionID)
+	return nil
+}
+
+//DeleteAll ... removes every session of the identity.
+func (m *MemoryStore) DeleteAll(ctx context.Context, identityID string) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	for tokenHash, session := range m.sessions {
+		if session.IdentityID == identityID {
+			delete(m.sessions, tokenHash)
+			delete(m.hashes, session.ID)
+		}
+	}
+	return nil
+}
+
+//List ... returns the identity's sessions, newest first.
+func (m *MemoryStore) List(ctx context.Context, identityID string) ([]Session, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	sessions := make([]Session, 0)
+	for _, session := range m.sessions {
+		if session.IdentityID == identityID {
+			sessions = append(sessions, session)
+		}
+	}
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].CreatedAt.After(sessions[j].CreatedAt)
+	})
+	return sessions, nil
+}
@@ -0,0 +1,113 @@
+package signature
+
+import (
+	"context"
+	"database/sql"
+	"service/database"
+	"strings"
+	"time"
+)
+
+//PostgresStore ... is a Store backed by the signing_key and request_nonce tables.
+type PostgresStore struct {
+	db database.DBInterface
+}
+
+//NewPostgresStore ... returns a pointer to a new PostgresStore using the passed in db.
+func NewPostgresStore(db database.DBInterface) *PostgresStore {
+	return &PostgresStore{
+		db: db,
+	}
+}
+
+//Create ... stores key with its secret.
+func (p *PostgresStore) Create(ctx context.Context, key Key, secret string) error {
+	_, err := p.db.ExecContext(ctx,
+		`INSERT INTO signing_key (id, identity_id, name, scope, secret, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6);`,
+		key.ID, key.IdentityID, key.Name, strings.Join(key.Scopes, " "), secret, key.CreatedAt)
+	return err
+}
+
+//Lookup ... returns the key and its secret, sql.ErrNoRows when there is no such key.
+func (p *PostgresStore) Lookup(ctx context.Context, keyID string) (*Key, string, error) {
+	var key Key
+	var scope, secret string
+	err := p.db.QueryRowContext(ctx,
+		`SELECT id, identity_id, name, scope, secret, created_at FROM signing_key
+		WHERE id = $1;`, keyID).Scan(&key.ID, &key.IdentityID, &key.Name, &scope, &secret,
+		&key.CreatedAt)
+	if err != nil {
+		return nil, "", err
+	}
+	key.Scopes = strings.Fields(scope)
+	return &key, secret, nil
+}
+
+//List ... returns the identity's keys, oldest first.
+func (p *PostgresStore) List(ctx context.Context, identityID string) ([]Key, error) {
+	rows, err := p.db.QueryContext(ctx,
+		`SELECT id, identity_id, name, scope, created_at FROM signing_key
+		WHERE identity_id = $1 ORDER BY created_at;`, identityID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	keys := make([]Key, 0)
+	for rows.Next() {
+		var key Key
+		var scope string
+		if err := rows.Scan(&key.ID, &key.IdentityID, &key.Name, &scope,
+			&key.CreatedAt); err != nil {
+			return nil, err
+		}
+		key.Scopes = strings.Fields(scope)
+		keys = append(keys, key)
+	}
+	return keys, rows.Err()
+}
+
+//Delete ... removes the identity's key, sql.ErrNoRows when it has none by that ID.
+func (p *PostgresStore) Delete(ctx context.Context, identityID, keyID string) error {
+	result, err := p.db.ExecContext(ctx,
+		"DELETE FROM signing_key WHERE identity_id = $1 AND id = $2;",
+		identityID, keyID)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+//UseNonce ... records nonce for the key until expiresAt, ErrReplayed when it is
+//already recorded. The key's expired nonces are cleared first so they can't collide.
+func (p *PostgresStore) UseNonce(ctx context.Context, keyID, nonce string,
+	expiresAt time.Time) error {
+	_, err := p.db.ExecContext(ctx,
+		"DELETE FROM request_nonce WHERE key_id = $1 AND expires_at <= $2;",
+		keyID, time.Now())
+	if err != nil {
+		return err
+	}
+	result, err := p.db.ExecContext(ctx,
+		`INSERT INTO request_nonce (key_id, nonce, expires_at)
+		VALUES ($1, $2, $3) ON CONFLICT (key_id, nonce) DO NOTHING;`,
+		keyID, nonce, expiresAt)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrReplayed
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package signature_test
+
+import (
+	"context"
+	"database/sql"
+	"service/auth/signature"
+	"service/utils/sqltest"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	sqlmock "gopkg.in/DATA-DOG/go-sqlmock.v1"
+)
+
+var _ = Describe("Postgres Store Specs", func() {
+	ctx := context.Background()
+	var (
+		store  *signature.PostgresStore
+		mockDB sqlmock.Sqlmock
+	)
+
+	BeforeEach(func() {
+		db, mock, sqlmockErr := sqlmock.New()
+		Expect(sqlmockErr).ToNot(HaveOccurred())
+		mockDB = mock
+		store = signature.NewPostgresStore(db)
+	})
+
+	AfterEach(func() {
+		Expect(mockDB.ExpectationsWereMet()).To(Succeed())
+	})
+
+	It("should create a key with its secret", func() {
+		rightNow := time.Now()
+		mockDB.ExpectExec("INSERT INTO signing_key").
+			WithArgs("hk_abc", "test_id", "partner", "events:read identity:read", "secret",
+				rightNow).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		Expect(store.Create(ctx, signature.Key{ID: "hk_abc", IdentityID: "test_id", Name: "partner",
+			Scopes: []string{"events:read", "identity:read"}, CreatedAt: rightNow},
+			"secret")).To(Succeed())
+	})
+
+	It("should look up a key's secret", func() {
+		mockDB.ExpectQuery("SELECT (.+) FROM signing_key WHERE id").
+			WithArgs("hk_abc").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "identity_id", "name", "scope", "secret",
+				"created_at"}).AddRow("hk_abc", "test_id", "partner", "events:read", "secret",
+				time.Now()))
+		key, secret, err := store.Lookup(ctx, "hk_abc")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(key.IdentityID).To(Equal("test_id"))
+		Expect(key.Scopes).To(Equal([]string{"events:read"}))
+		Expect(secret).To(Equal("secret"))
+	})
+
+	It("should report deleting another identity's key", func() {
+		mockDB.ExpectExec("DELETE FROM signing_key").
+			WithArgs("other_id", "hk_abc").
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		Expect(store.Delete(ctx, "other_id", "hk_abc")).To(Equal(sql.ErrNoRows))
+	})
+
+	Context("UseNonce", func() {
+		BeforeEach(func() {
+			mockDB.ExpectExec("DELETE FROM request_nonce").
+				WithArgs("hk_abc", sqltest.AnyTime{}).
+				WillReturnResult(sqlmock.NewResult(0, 0))
+		})
+
+		It("should record a new nonce", func() {
+			mockDB.ExpectExec("INSERT INTO request_nonce").
+				WithArgs("hk_abc", "0123456789abcdef", sqltest.AnyTime{}).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+			Expect(store.UseNonce(ctx, "hk_abc", "0123456789abcdef", time.Now())).To(Succeed())
+		})
+
+		It("should report a nonce it already has", func() {
+			mockDB.ExpectExec("INSERT INTO request_nonce").
+				WithArgs("hk_abc", "0123456789abcdef", sqltest.AnyTime{}).
+				WillReturnResult(sqlmock.NewResult(0, 0))
+			Expect(store.UseNonce(ctx, "hk_abc", "0123456789abcdef", time.Now())).
+				To(Equal(signature.ErrReplayed))
+		})
+	})
+})
@@ -0,0 +1,145 @@
+package signature
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//Scheme ... is the Authorization scheme of signed requests, e.g.
+//Authorization: HMAC-SHA256 keyId="hk_0a1b2c3d4e5f", timestamp="1700000000",
+//nonce="9f86d081884c7d65", signature="<base64>"
+const Scheme = "HMAC-SHA256"
+
+//Realm ... is sent in the WWW-Authenticate challenge for signed requests.
+const Realm = "Restricted"
+
+//KeyPrefix ... starts every signing key ID so they are easy to tell apart from API keys.
+const KeyPrefix = "hk_"
+
+//DefaultMaxSkew ... is how far a request's timestamp may be from our clock, nonces are
+//remembered for as long so a captured request can never be replayed.
+const DefaultMaxSkew = 5 * time.Minute
+
+//DefaultMaxBodyBytes ... is the largest body that will be read to check its digest.
+const DefaultMaxBodyBytes = 1 << 20
+
+//Nonces must be long enough to be unique per request and short enough to store.
+const (
+	minNonceLength = 16
+	maxNonceLength = 128
+)
+
+//ErrInvalid ... is returned for signatures that are missing, malformed, made with an
+//unknown key or that do not match the request.
+var ErrInvalid = errors.New("invalid request signature")
+
+//ErrStale ... is returned when a request's timestamp is outside the allowed skew.
+var ErrStale = errors.New("request timestamp is outside the allowed window")
+
+//ErrReplayed ... is returned when a request's nonce has already been used with its key.
+var ErrReplayed = errors.New("request nonce has already been used")
+
+//ErrBodyTooLarge ... is returned when a body is too large to be digested.
+var ErrBodyTooLarge = errors.New("request body is too large to verify")
+
+//ErrNotSupported ... signed requests carry no token to generate or revoke.
+var ErrNotSupported = errors.New("signed requests do not use tokens")
+
+//Params ... are the values carried in a signed request's Authorization header.
+type Params struct {
+	KeyID     string
+	Timestamp int64
+	Nonce     string
+	Signature string
+}
+
+//Header ... formats params as an Authorization header value.
+func (p *Params) Header() string {
+	return fmt.Sprintf(`%s keyId="%s", timestamp="%d", nonce="%s", signature="%s"`,
+		Scheme, p.KeyID, p.Timestamp, p.Nonce, p.Signature)
+}
+
+//ParseHeader ... reads the params out of an Authorization header value,
+//ErrInvalid when it is not a complete HMAC-SHA256 header.
+func ParseHeader(header string) (*Params, error) {
+	parts := strings.SplitN(header, " ", 2)
+	if len(parts) != 2 || !strings.EqualFold(parts[0], Scheme) {
+		return nil, ErrInvalid
+	}
+	values := make(map[string]string)
+	for _, pair := range strings.Split(parts[1], ",") {
+		nameValue := strings.SplitN(strings.TrimSpace(pair), "=", 2)
+		if len(nameValue) != 2 {
+			return nil, ErrInvalid
+		}
+		value, err := strconv.Unquote(nameValue[1])
+		if err != nil {
+			return nil, ErrInvalid
+		}
+		values[nameValue[0]] = value
+	}
+	timestamp, err := strconv.ParseInt(values["timestamp"], 10, 64)
+	if err != nil {
+		return nil, ErrInvalid
+	}
+	params := &Params{
+		KeyID:     values["keyId"],
+		Timestamp: timestamp,
+		Nonce:     values["nonce"],
+		Signature: values["signature"],
+	}
+	if params.KeyID == "" || params.Signature == "" || !validNonce(params.Nonce) {
+		return nil, ErrInvalid
+	}
+	return params, nil
+}
+
+//Digest ... is the hex sha256 of a request body, the empty body included.
+func Digest(body []byte) string {
+	sum := sha256.Sum256(body)
+	return hex.EncodeToString(sum[:])
+}
+
+//StringToSign ...
+//joins everything a signature covers with newlines: the upper cased method, the request
+//URI with its query, the unix timestamp, the nonce and the body digest.
+func StringToSign(method, requestURI string, timestamp int64, nonce, bodyDigest string) string {
+	return strings.Join([]string{
+		strings.ToUpper(method),
+		requestURI,
+		strconv.FormatInt(timestamp, 10),
+		nonce,
+		bodyDigest,
+	}, "\n")
+}
+
+//Compute ... returns the base64 HMAC-SHA256 of stringToSign under secret.
+func Compute(secret, stringToSign string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(stringToSign))
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+//Equal ... compares two signatures in constant time.
+func Equal(expected, actual string) bool {
+	return hmac.Equal([]byte(expected), []byte(actual))
+}
+
+func validNonce(nonce string) bool {
+	if len(nonce) < minNonceLength || len(nonce) > maxNonceLength {
+		return false
+	}
+	for _, r := range nonce {
+		if r <= ' ' || r > '~' || r == '"' || r == ',' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,138 @@
+package signature
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"time"
+)
+
+//Auth ... implements auth.Interface on top of HMAC signed requests.
+type Auth struct {
+	Keys         Store
+	MaxSkew      time.Duration
+	MaxBodyBytes int64
+}
+
+//NewAuth ... creates a signed request auth object with the default skew and body limit.
+func NewAuth(keys Store) *Auth {
+	return &Auth{
+		Keys:         keys,
+		MaxSkew:      DefaultMaxSkew,
+		MaxBodyBytes: DefaultMaxBodyBytes,
+	}
+}
+
+//HasSignature ... reports whether the request claims to be signed.
+func HasSignature(req *http.Request) bool {
+	_, err := ParseHeader(req.Header.Get("Authorization"))
+	return err == nil
+}
+
+//NewKey ...
+//generates a signing key for identityID, limited to scopes, and its secret. Nothing is
+//stored, see Store.Create.
+func NewKey(identityID, name string, scopes []string) (Key, string, error) {
+	id := make([]byte, 6)
+	if _, err := rand.Read(id); err != nil {
+		return Key{}, "", err
+	}
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return Key{}, "", err
+	}
+	return Key{
+		ID:         KeyPrefix + hex.EncodeToString(id),
+		IdentityID: identityID,
+		Name:       name,
+		Scopes:     scopes,
+		CreatedAt:  time.Now(),
+	}, base64.RawURLEncoding.EncodeToString(secret), nil
+}
+
+//Verify ...
+//checks the request's signature against its key's secret and uses up its nonce. The body
+//is read to be digested and put back so handlers can still read it.
+func (a *Auth) Verify(req *http.Request) (*Key, error) {
+	params, err := ParseHeader(req.Header.Get("Authorization"))
+	if err != nil {
+		return nil, err
+	}
+	signedAt := time.Unix(params.Timestamp, 0)
+	if skew := time.Since(signedAt); skew > a.MaxSkew || skew < -a.MaxSkew {
+		return nil, ErrStale
+	}
+	body, err := a.readBody(req)
+	if err != nil {
+		return nil, err
+	}
+	key, secret, err := a.Keys.Lookup(req.Context(), params.KeyID)
+	if err == sql.ErrNoRows {
+		return nil, ErrInvalid
+	}
+	if err != nil {
+		return nil, err
+	}
+	expected := Compute(secret, StringToSign(req.Method, req.URL.RequestURI(),
+		params.Timestamp, params.Nonce, Digest(body)))
+	if !Equal(expected, params.Signature) {
+		return nil, ErrInvalid
+	}
+	//Only nonces of valid signatures are stored, so junk requests can't fill the store.
+	if err := a.Keys.UseNonce(req.Context(), key.ID, params.Nonce,
+		signedAt.Add(a.MaxSkew)); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+func (a *Auth) readBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+	body, err := ioutil.ReadAll(io.LimitReader(req.Body, a.MaxBodyBytes+1))
+	closeErr := req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	if closeErr != nil {
+		return nil, closeErr
+	}
+	if int64(len(body)) > a.MaxBodyBytes {
+		return nil, ErrBodyTooLarge
+	}
+	req.Body = ioutil.NopCloser(bytes.NewReader(body))
+	return body, nil
+}
+
+//Authorize ... returns the key ID and signature the request was signed with.
+func (a *Auth) Authorize(req *http.Request) (string, string, bool) {
+	params, err := ParseHeader(req.Header.Get("Authorization"))
+	if err != nil {
+		return "", "", false
+	}
+	return params.KeyID, params.Signature, true
+}
+
+//ValidateTokenHeader ... verifies the request's signature, see Verify.
+func (a *Auth) ValidateTokenHeader(req *http.Request) (bool, error) {
+	_, err := a.Verify(req)
+	return err == nil, err
+}
+
+//GenerateToken ... always fails, see ErrNotSupported.
+func (a *Auth) GenerateToken(ctx context.Context, input map[string]interface{}) (string,
+	error) {
+	return "", ErrNotSupported
+}
+
+//RevokeTokenHeader ... always fails, see ErrNotSupported.
+func (a *Auth) RevokeTokenHeader(req *http.Request) error {
+	return ErrNotSupported
+}
@@ -0,0 +1,178 @@
+package signature
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"service/auth/permission"
+	"service/handlers/loggederror"
+	"service/handlers/request"
+	"service/log"
+	"strings"
+
+	"github.com/go-chi/chi"
+	"go.uber.org/zap"
+)
+
+//HandlerObject ... holds elementals for managing an identity's signing keys over http.
+type HandlerObject struct {
+	Log   log.ProdInterface
+	Keys  Store
+	Roles permission.Store
+}
+
+//NewHandlerObject ... returns a pointer to a new signing key HandlerObject.
+func NewHandlerObject(logClient log.ProdInterface, keys Store,
+	roles permission.Store) *HandlerObject {
+	return &HandlerObject{
+		Log:   logClient,
+		Keys:  keys,
+		Roles: roles,
+	}
+}
+
+type createKeyPostBody struct {
+	Name   string   `json:"name"`
+	Scopes []string `json:"scopes"`
+}
+
+type createKeyResponse struct {
+	Status int    `json:"status"`
+	Secret string `json:"secret"`
+	Key    *Key   `json:"key"`
+}
+
+type listKeysResponse struct {
+	Status int   `json:"status"`
+	Keys   []Key `json:"keys"`
+}
+
+//CreateKey ...
+//POST /identity/{id}/signing-keys, the requested scopes must be permissions both the
+//identity and the caller hold, like an API key's. The shared secret is only ever returned in
+//this response.
+func (h *HandlerObject) CreateKey(w http.ResponseWriter, req *http.Request) {
+	id := chi.URLParam(req, "id")
+	claims, hasClaims := request.RetreiveClaims(req.Context())
+	if !hasClaims {
+		h.softError(http.StatusForbidden, "cannot create signing keys without claims",
+			"CreateKey", w, req)
+		return
+	}
+	var jsonDoc createKeyPostBody
+	if req.Body == nil || json.NewDecoder(req.Body).Decode(&jsonDoc) != nil {
+		h.softError(http.StatusBadRequest, "bad request", "CreateKey", w, req)
+		return
+	}
+	if jsonDoc.Name == "" || len(jsonDoc.Scopes) == 0 {
+		h.softError(http.StatusBadRequest, "missing required signing key params",
+			"CreateKey", w, req)
+		return
+	}
+	granted, err := h.Roles.Permissions(req.Context(), id)
+	if err != nil {
+		h.internalServerError(err, "CreateKey", w, req)
+		return
+	}
+	if kept := intersect(jsonDoc.Scopes, granted); len(kept) != len(jsonDoc.Scopes) {
+		h.softError(http.StatusBadRequest, "scopes exceed the identity's permissions",
+			"CreateKey", w, req)
+		return
+	}
+	held := strings.Fields(claims.Scope)
+	if kept := intersect(jsonDoc.Scopes, held); len(kept) != len(jsonDoc.Scopes) {
+		h.softError(http.StatusForbidden, "scopes exceed the caller's permissions",
+			"CreateKey", w, req)
+		return
+	}
+	key, secret, err := NewKey(id, jsonDoc.Name, jsonDoc.Scopes)
+	if err != nil {
+		h.internalServerError(err, "CreateKey", w, req)
+		return
+	}
+	if err := h.Keys.Create(req.Context(), key, secret); err != nil {
+		h.internalServerError(err, "CreateKey", w, req)
+		return
+	}
+	h.Log.Info("signing key created",
+		zap.String("event", "auth.signature.create"),
+		zap.String("identityID", key.IdentityID),
+		zap.String("keyID", key.ID))
+	h.respond(http.StatusCreated, &createKeyResponse{
+		Status: http.StatusCreated,
+		Secret: secret,
+		Key:    &key,
+	}, "CreateKey", w, req)
+}
+
+//ListKeys ... GET /identity/{id}/signing-keys
+func (h *HandlerObject) ListKeys(w http.ResponseWriter, req *http.Request) {
+	keys, err := h.Keys.List(req.Context(), chi.URLParam(req, "id"))
+	if err != nil {
+		h.internalServerError(err, "ListKeys", w, req)
+		return
+	}
+	h.respond(http.StatusOK, &listKeysResponse{
+		Status: http.StatusOK,
+		Keys:   keys,
+	}, "ListKeys", w, req)
+}
+
+//DeleteKey ... DELETE /identity/{id}/signing-keys/{keyID}
+func (h *HandlerObject) DeleteKey(w http.ResponseWriter, req *http.Request) {
+	id, keyID := chi.URLParam(req, "id"), chi.URLParam(req, "keyID")
+	err := h.Keys.Delete(req.Context(), id, keyID)
+	if err == sql.ErrNoRows {
+		h.softError(http.StatusNotFound, "signing key not found", "DeleteKey", w, req)
+		return
+	}
+	if err != nil {
+		h.internalServerError(err, "DeleteKey", w, req)
+		return
+	}
+	h.Log.Info("signing key deleted",
+		zap.String("event", "auth.signature.delete"),
+		zap.String("identityID", id),
+		zap.String("keyID", keyID))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *HandlerObject) respond(status int, response interface{}, source string,
+	w http.ResponseWriter, req *http.Request) {
+	bytesArray, marshalErr := json.Marshal(response)
+	if marshalErr != nil {
+		h.internalServerError(marshalErr, source, w, req)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if _, writeErr := w.Write(bytesArray); writeErr != nil {
+		h.Log.Error("signature_handler::"+source, zap.Error(writeErr))
+	}
+}
+
+// internalServerError is used to wrap our loggederror for this route.
+func (h *HandlerObject) internalServerError(err error, source string, w http.ResponseWriter,
+	req *http.Request) {
+	loggederror.RespondWithProperErrorAndLogIt(
+		h.Log,
+		http.StatusInternalServerError,
+		err,
+		"signature_handler::"+source,
+		w,
+		req,
+	)
+}
+
+// softError is used to wrap our loggederror for expected failures on this route.
+func (h *HandlerObject) softError(status int, message, source string, w http.ResponseWriter,
+	req *http.Request) {
+	loggederror.RespondWithWithExpectedSoftError(
+		h.Log,
+		status,
+		message,
+		"signature_handler::"+source,
+		w,
+		req,
+	)
+}
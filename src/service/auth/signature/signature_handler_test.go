@@ -0,0 +1,107 @@
+package signature_test
+
+import (
+	"bytes"
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"service/auth/permission/permissionfakes"
+	"service/auth/signature"
+	"service/auth/signature/signaturefakes"
+	"service/auth/token/jwt"
+	"service/handlers/request"
+	"service/log/logfakes"
+	"strings"
+
+	"github.com/go-chi/chi"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Signature Handler Specs", func() {
+	var (
+		fakeKeys  *signaturefakes.FakeStore
+		fakeRoles *permissionfakes.FakeStore
+		claims    *jwt.IdentityClaims
+		router    *chi.Mux
+		recorder  *httptest.ResponseRecorder
+	)
+
+	BeforeEach(func() {
+		fakeKeys = &signaturefakes.FakeStore{}
+		fakeRoles = &permissionfakes.FakeStore{}
+		fakeRoles.PermissionsReturns([]string{"events:read", "identity:read", "identity:write"}, nil)
+		claims = &jwt.IdentityClaims{Scope: "events:read identity:write"}
+		claims.Subject = "test_id"
+		handler := signature.NewHandlerObject(&logfakes.FakeProdInterface{}, fakeKeys, fakeRoles)
+		router = chi.NewRouter()
+		router.Get("/identity/{id}/signing-keys", handler.ListKeys)
+		router.Post("/identity/{id}/signing-keys", handler.CreateKey)
+		router.Delete("/identity/{id}/signing-keys/{keyID}", handler.DeleteKey)
+		recorder = httptest.NewRecorder()
+	})
+
+	serve := func(method, path, body string) {
+		req := httptest.NewRequest(method, path, bytes.NewBufferString(body))
+		router.ServeHTTP(recorder, req.WithContext(request.WithClaims(req.Context(), claims)))
+	}
+
+	Context("CreateKey", func() {
+		It("should store a new key and return its secret once", func() {
+			serve("POST", "/identity/test_id/signing-keys",
+				`{"name": "partner", "scopes": ["events:read"]}`)
+			Expect(recorder.Code).To(Equal(http.StatusCreated))
+			_, key, secret := fakeKeys.CreateArgsForCall(0)
+			Expect(key.IdentityID).To(Equal("test_id"))
+			Expect(key.Scopes).To(Equal([]string{"events:read"}))
+			Expect(strings.HasPrefix(key.ID, signature.KeyPrefix)).To(BeTrue())
+			Expect(recorder.Body.String()).To(ContainSubstring(`"secret":"` + secret + `"`))
+		})
+
+		It("should require a name and scopes", func() {
+			serve("POST", "/identity/test_id/signing-keys", `{"name": "partner"}`)
+			Expect(recorder.Code).To(Equal(http.StatusBadRequest))
+			Expect(fakeKeys.CreateCallCount()).To(Equal(0))
+		})
+
+		It("should refuse scopes the identity does not hold", func() {
+			serve("POST", "/identity/test_id/signing-keys",
+				`{"name": "partner", "scopes": ["roles:admin"]}`)
+			Expect(recorder.Code).To(Equal(http.StatusBadRequest))
+			Expect(fakeKeys.CreateCallCount()).To(Equal(0))
+		})
+
+		It("should not let a narrowly scoped token mint a wider key", func() {
+			claims.Scope = "identity:write"
+			serve("POST", "/identity/test_id/signing-keys",
+				`{"name": "partner", "scopes": ["identity:write", "identity:read"]}`)
+			Expect(recorder.Code).To(Equal(http.StatusForbidden))
+			Expect(fakeKeys.CreateCallCount()).To(Equal(0))
+		})
+	})
+
+	It("should list keys without their secrets", func() {
+		fakeKeys.ListReturns([]signature.Key{{ID: "hk_abc", Name: "partner"}}, nil)
+		serve("GET", "/identity/test_id/signing-keys", "")
+		Expect(recorder.Code).To(Equal(http.StatusOK))
+		Expect(recorder.Body.String()).To(ContainSubstring(`"id":"hk_abc"`))
+		Expect(recorder.Body.String()).ToNot(ContainSubstring("secret"))
+	})
+
+	Context("DeleteKey", func() {
+		It("should delete the identity's key", func() {
+			serve("DELETE", "/identity/test_id/signing-keys/hk_abc", "")
+			Expect(recorder.Code).To(Equal(http.StatusNoContent))
+			_, identityID, keyID := fakeKeys.DeleteArgsForCall(0)
+			Expect(identityID).To(Equal("test_id"))
+			Expect(keyID).To(Equal("hk_abc"))
+		})
+
+		It("should 404 for unknown keys", func() {
+			fakeKeys.DeleteReturns(sql.ErrNoRows)
+			serve("DELETE", "/identity/test_id/signing-keys/hk_missing", "")
+			Expect(recorder.Code).To(Equal(http.StatusNotFound))
+		})
+	})
+})
@@ -0,0 +1,85 @@
+package signature
+
+import (
+	"net/http"
+	"service/auth/permission"
+	"service/auth/token/jwt"
+	"service/handlers/request"
+	"service/log"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+//AuthMiddleware ... verifies the request's HMAC signature and stores claims for the key's
+//identity in the request context, so permission.Require works as it does for bearer
+//tokens. The key's scopes are narrowed to what the identity's roles still grant.
+func AuthMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		key, err := signatureAuth.Verify(req)
+		switch err {
+		case nil:
+		case ErrInvalid, ErrStale, ErrReplayed:
+			logClient.Info("request signature rejected",
+				zap.String("requestID", request.RetreiveRequestID(req.Context())),
+				zap.String("event", "auth.signature.rejected"),
+				zap.String("reason", err.Error()))
+			w.Header().Set("WWW-Authenticate", Scheme+` realm="`+Realm+`"`)
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		case ErrBodyTooLarge:
+			http.Error(w, http.StatusText(http.StatusRequestEntityTooLarge),
+				http.StatusRequestEntityTooLarge)
+			return
+		default:
+			internalServerError(err, w, req)
+			return
+		}
+		granted, err := roleStore.Permissions(req.Context(), key.IdentityID)
+		if err != nil {
+			internalServerError(err, w, req)
+			return
+		}
+		claims := &jwt.IdentityClaims{Scope: strings.Join(intersect(key.Scopes, granted), " ")}
+		claims.Subject = key.IdentityID
+		logClient.Debug("request signature accepted",
+			zap.String("requestID", request.RetreiveRequestID(req.Context())),
+			zap.String("identityID", key.IdentityID),
+			zap.String("keyID", key.ID))
+		next.ServeHTTP(w, req.WithContext(request.WithClaims(req.Context(), claims)))
+	})
+}
+
+//intersect keeps the scopes that are also in granted.
+func intersect(scopes, granted []string) []string {
+	allowed := make(map[string]bool, len(granted))
+	for _, permission := range granted {
+		allowed[permission] = true
+	}
+	kept := make([]string, 0, len(scopes))
+	for _, scope := range scopes {
+		if allowed[scope] {
+			kept = append(kept, scope)
+		}
+	}
+	return kept
+}
+
+func internalServerError(err error, w http.ResponseWriter, req *http.Request) {
+	logClient.Error("signature::AuthMiddleware",
+		zap.String("requestID", request.RetreiveRequestID(req.Context())),
+		zap.Error(err))
+	http.Error(w, http.StatusText(http.StatusInternalServerError),
+		http.StatusInternalServerError)
+}
+
+var signatureAuth *Auth
+var roleStore permission.Store
+var logClient log.ProdInterface
+
+//SetupAuthMiddleware ... attaches a configured signed request auth and role store
+func SetupAuthMiddleware(auth *Auth, roles permission.Store, log log.ProdInterface) {
+	signatureAuth = auth
+	roleStore = roles
+	logClient = log
+}
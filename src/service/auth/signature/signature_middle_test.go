@@ -0,0 +1,96 @@
+package signature_test
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"service/auth/permission/permissionfakes"
+	"service/auth/signature"
+	"service/auth/signature/signaturefakes"
+	"service/handlers/request"
+	"service/log/logfakes"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Signature Middleware Specs", func() {
+	var (
+		fakeKeys  *signaturefakes.FakeStore
+		fakeRoles *permissionfakes.FakeStore
+		req       *http.Request
+		recorder  *httptest.ResponseRecorder
+		subject   string
+		scope     string
+		reached   bool
+	)
+
+	BeforeEach(func() {
+		fakeKeys = &signaturefakes.FakeStore{}
+		fakeKeys.LookupReturns(&signature.Key{ID: "hk_abc", IdentityID: "partner_id",
+			Scopes: []string{"events:read", "identity:write"}}, "secret", nil)
+		fakeRoles = &permissionfakes.FakeStore{}
+		fakeRoles.PermissionsReturns([]string{"events:read", "identity:read"}, nil)
+		signature.SetupAuthMiddleware(signature.NewAuth(fakeKeys), fakeRoles,
+			&logfakes.FakeProdInterface{})
+
+		req = httptest.NewRequest("POST", "/", bytes.NewBufferString(`{"order": 42}`))
+		Expect(signature.NewSigner("hk_abc", "secret").Sign(req)).To(Succeed())
+		recorder = httptest.NewRecorder()
+		reached, subject, scope = false, "", ""
+	})
+
+	JustBeforeEach(func() {
+		signature.AuthMiddleware(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			reached = true
+			subject = request.RetreiveSubject(req.Context())
+			if claims, ok := request.RetreiveClaims(req.Context()); ok {
+				scope = claims.Scope
+			}
+		})).ServeHTTP(recorder, req)
+	})
+
+	It("should authenticate as the key's identity with the scopes its roles still grant", func() {
+		Expect(reached).To(BeTrue())
+		Expect(subject).To(Equal("partner_id"))
+		Expect(scope).To(Equal("events:read"))
+		_, keyID, nonce, _ := fakeKeys.UseNonceArgsForCall(0)
+		Expect(keyID).To(Equal("hk_abc"))
+		Expect(nonce).ToNot(BeEmpty())
+	})
+
+	Context("when the nonce was already used", func() {
+		BeforeEach(func() {
+			fakeKeys.UseNonceReturns(signature.ErrReplayed)
+		})
+
+		It("should 401 with a challenge", func() {
+			Expect(reached).To(BeFalse())
+			Expect(recorder.Code).To(Equal(http.StatusUnauthorized))
+			Expect(recorder.Header().Get("WWW-Authenticate")).
+				To(Equal(`HMAC-SHA256 realm="Restricted"`))
+		})
+	})
+
+	Context("when the signature does not match", func() {
+		BeforeEach(func() {
+			fakeKeys.LookupReturns(&signature.Key{ID: "hk_abc"}, "rotated", nil)
+		})
+
+		It("should 401 without using the nonce", func() {
+			Expect(recorder.Code).To(Equal(http.StatusUnauthorized))
+			Expect(fakeKeys.UseNonceCallCount()).To(Equal(0))
+		})
+	})
+
+	Context("when the store fails", func() {
+		BeforeEach(func() {
+			fakeKeys.LookupReturns(nil, "", errors.New("db down"))
+		})
+
+		It("should 500", func() {
+			Expect(recorder.Code).To(Equal(http.StatusInternalServerError))
+		})
+	})
+})
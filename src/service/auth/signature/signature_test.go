@@ -0,0 +1,144 @@
+package signature_test
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"service/auth/signature"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Signature Specs", func() {
+	ctx := context.Background()
+	var (
+		store  *signature.MemoryStore
+		auth   *signature.Auth
+		signer *signature.Signer
+		key    signature.Key
+		req    *http.Request
+	)
+
+	BeforeEach(func() {
+		store = signature.NewMemoryStore()
+		auth = signature.NewAuth(store)
+		var secret string
+		var err error
+		key, secret, err = signature.NewKey("test_id", "partner", []string{"events:read"})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(store.Create(ctx, key, secret)).To(Succeed())
+		signer = signature.NewSigner(key.ID, secret)
+		req = httptest.NewRequest("POST", "/webhooks/orders?source=partner",
+			bytes.NewBufferString(`{"order": 42}`))
+	})
+
+	Context("ParseHeader", func() {
+		It("should read back a formatted header", func() {
+			params := &signature.Params{KeyID: "hk_abc", Timestamp: 1700000000,
+				Nonce: "0123456789abcdef", Signature: "c2lnbmF0dXJl"}
+			parsed, err := signature.ParseHeader(params.Header())
+			Expect(err).ToNot(HaveOccurred())
+			Expect(parsed).To(Equal(params))
+		})
+
+		It("should refuse other schemes", func() {
+			_, err := signature.ParseHeader("Bearer test-token")
+			Expect(err).To(Equal(signature.ErrInvalid))
+		})
+
+		It("should refuse short nonces", func() {
+			_, err := signature.ParseHeader(
+				`HMAC-SHA256 keyId="hk_abc", timestamp="1700000000", nonce="1", signature="c2ln"`)
+			Expect(err).To(Equal(signature.ErrInvalid))
+		})
+	})
+
+	It("should sign method, path, timestamp, nonce and body digest", func() {
+		Expect(signature.StringToSign("post", "/webhooks?a=b", 1700000000, "nonce",
+			signature.Digest(nil))).To(Equal("POST\n/webhooks?a=b\n1700000000\nnonce\n" +
+			"e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"))
+	})
+
+	Context("Verify", func() {
+		It("should accept a signed request and leave its body readable", func() {
+			Expect(signer.Sign(req)).To(Succeed())
+			verified, err := auth.Verify(req)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(verified.IdentityID).To(Equal("test_id"))
+			body, _ := ioutil.ReadAll(req.Body)
+			Expect(string(body)).To(Equal(`{"order": 42}`))
+		})
+
+		It("should refuse a replayed request", func() {
+			Expect(signer.Sign(req)).To(Succeed())
+			replay := httptest.NewRequest("POST", "/webhooks/orders?source=partner",
+				bytes.NewBufferString(`{"order": 42}`))
+			replay.Header.Set("Authorization", req.Header.Get("Authorization"))
+			_, err := auth.Verify(req)
+			Expect(err).ToNot(HaveOccurred())
+			_, err = auth.Verify(replay)
+			Expect(err).To(Equal(signature.ErrReplayed))
+		})
+
+		It("should refuse a tampered body", func() {
+			Expect(signer.Sign(req)).To(Succeed())
+			req.Body = ioutil.NopCloser(strings.NewReader(`{"order": 43}`))
+			_, err := auth.Verify(req)
+			Expect(err).To(Equal(signature.ErrInvalid))
+		})
+
+		It("should refuse a different path", func() {
+			Expect(signer.Sign(req)).To(Succeed())
+			req.URL.RawQuery = "source=other"
+			_, err := auth.Verify(req)
+			Expect(err).To(Equal(signature.ErrInvalid))
+		})
+
+		It("should refuse a stale timestamp", func() {
+			signer.Now = func() time.Time { return time.Now().Add(-10 * time.Minute) }
+			Expect(signer.Sign(req)).To(Succeed())
+			_, err := auth.Verify(req)
+			Expect(err).To(Equal(signature.ErrStale))
+		})
+
+		It("should refuse an unknown key", func() {
+			signer.KeyID = "hk_unknown"
+			Expect(signer.Sign(req)).To(Succeed())
+			_, err := auth.Verify(req)
+			Expect(err).To(Equal(signature.ErrInvalid))
+		})
+
+		It("should refuse the wrong secret", func() {
+			signer.Secret = "not-the-secret"
+			Expect(signer.Sign(req)).To(Succeed())
+			_, err := auth.Verify(req)
+			Expect(err).To(Equal(signature.ErrInvalid))
+		})
+
+		It("should refuse a body too large to digest", func() {
+			auth.MaxBodyBytes = 4
+			Expect(signer.Sign(req)).To(Succeed())
+			_, err := auth.Verify(req)
+			Expect(err).To(Equal(signature.ErrBodyTooLarge))
+		})
+	})
+
+	It("should sign requests sent through its Transport", func() {
+		var verifyErr error
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			_, verifyErr = auth.Verify(req)
+		}))
+		defer server.Close()
+		client := &http.Client{Transport: signer.Transport(nil)}
+		resp, err := client.Post(server.URL+"/webhooks/orders", "application/json",
+			bytes.NewBufferString(`{"order": 42}`))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(resp.Body.Close()).To(Succeed())
+		Expect(verifyErr).ToNot(HaveOccurred())
+	})
+})
@@ -0,0 +1,88 @@
+package signature
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"time"
+)
+
+//Signer ...
+//signs outgoing requests for our Go clients:
+//
+//	signer := signature.NewSigner(keyID, secret)
+//	client := &http.Client{Transport: signer.Transport(nil)}
+type Signer struct {
+	KeyID  string
+	Secret string
+	Now    func() time.Time
+}
+
+//NewSigner ... returns a pointer to a Signer for the key.
+func NewSigner(keyID, secret string) *Signer {
+	return &Signer{
+		KeyID:  keyID,
+		Secret: secret,
+		Now:    time.Now,
+	}
+}
+
+//Sign ...
+//sets the request's Authorization header. The body is read to be digested and put back,
+//GetBody included, so the request can still be sent and redirected.
+func (s *Signer) Sign(req *http.Request) error {
+	var body []byte
+	if req.Body != nil && req.Body != http.NoBody {
+		var err error
+		body, err = ioutil.ReadAll(req.Body)
+		if err != nil {
+			return err
+		}
+		if err := req.Body.Close(); err != nil {
+			return err
+		}
+		req.Body = ioutil.NopCloser(bytes.NewReader(body))
+		req.GetBody = func() (io.ReadCloser, error) {
+			return ioutil.NopCloser(bytes.NewReader(body)), nil
+		}
+	}
+	nonce := make([]byte, 16)
+	if _, err := rand.Read(nonce); err != nil {
+		return err
+	}
+	params := &Params{
+		KeyID:     s.KeyID,
+		Timestamp: s.Now().Unix(),
+		Nonce:     hex.EncodeToString(nonce),
+	}
+	params.Signature = Compute(s.Secret, StringToSign(req.Method, req.URL.RequestURI(),
+		params.Timestamp, params.Nonce, Digest(body)))
+	req.Header.Set("Authorization", params.Header())
+	return nil
+}
+
+//Transport ... returns a RoundTripper signing every request before handing it to base,
+//http.DefaultTransport when nil.
+func (s *Signer) Transport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &transport{signer: s, base: base}
+}
+
+type transport struct {
+	signer *Signer
+	base   http.RoundTripper
+}
+
+//RoundTrip signs a clone of req, RoundTrippers must not modify the caller's request.
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	signed := req.Clone(req.Context())
+	if err := t.signer.Sign(signed); err != nil {
+		return nil, err
+	}
+	return t.base.RoundTrip(signed)
+}
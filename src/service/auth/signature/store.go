@@ -0,0 +1,113 @@
+package signature
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+	"sync"
+	"time"
+)
+
+//Key ... is the stored, non secret part of a signing key.
+type Key struct {
+	ID         string    `json:"id"`
+	IdentityID string    `json:"identityId"`
+	Name       string    `json:"name"`
+	Scopes     []string  `json:"scopes"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+//Store ... defines a backing store for shared signing secrets and the nonces they've signed.
+//Secrets are kept as is, verifying an HMAC needs the secret itself.
+//go:generate counterfeiter . Store
+type Store interface {
+	Create(ctx context.Context, key Key, secret string) error
+	Lookup(ctx context.Context, keyID string) (*Key, string, error)
+	List(ctx context.Context, identityID string) ([]Key, error)
+	Delete(ctx context.Context, identityID, keyID string) error
+	UseNonce(ctx context.Context, keyID, nonce string, expiresAt time.Time) error
+}
+
+type storedKey struct {
+	key    Key
+	secret string
+}
+
+//MemoryStore ... is a Store for a single instance, keys and nonces are lost on restart.
+type MemoryStore struct {
+	mutex  sync.Mutex
+	keys   map[string]storedKey
+	nonces map[string]time.Time
+}
+
+//NewMemoryStore ... returns a pointer to a new, empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		keys:   make(map[string]storedKey),
+		nonces: make(map[string]time.Time),
+	}
+}
+
+//Create ... stores key with its secret.
+func (m *MemoryStore) Create(ctx context.Context, key Key, secret string) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.keys[key.ID] = storedKey{key: key, secret: secret}
+	return nil
+}
+
+//Lookup ... returns the key and its secret, sql.ErrNoRows when there is no such key.
+func (m *MemoryStore) Lookup(ctx context.Context, keyID string) (*Key, string, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	stored, ok := m.keys[keyID]
+	if !ok {
+		return nil, "", sql.ErrNoRows
+	}
+	return &stored.key, stored.secret, nil
+}
+
+//List ... returns the identity's keys, oldest first.
+func (m *MemoryStore) List(ctx context.Context, identityID string) ([]Key, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	keys := make([]Key, 0)
+	for _, stored := range m.keys {
+		if stored.key.IdentityID == identityID {
+			keys = append(keys, stored.key)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].CreatedAt.Before(keys[j].CreatedAt) })
+	return keys, nil
+}
+
+//Delete ... removes the identity's key, sql.ErrNoRows when it has none by that ID.
+func (m *MemoryStore) Delete(ctx context.Context, identityID, keyID string) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if stored, ok := m.keys[keyID]; !ok || stored.key.IdentityID != identityID {
+		return sql.ErrNoRows
+	}
+	delete(m.keys, keyID)
+	return nil
+}
+
+//UseNonce ... records nonce for the key until expiresAt, ErrReplayed when it is
+//already recorded. Expired nonces are forgotten as it goes.
+func (m *MemoryStore) UseNonce(ctx context.Context, keyID, nonce string,
+	expiresAt time.Time) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	rightNow := time.Now()
+	for seen, until := range m.nonces {
+		if !until.After(rightNow) {
+			delete(m.nonces, seen)
+		}
+	}
+	seen := keyID + "\n" + nonce
+	if _, ok := m.nonces[seen]; ok {
+		return ErrReplayed
+	}
+	m.nonces[seen] = expiresAt
+	return nil
+}
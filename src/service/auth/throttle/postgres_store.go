@@ -0,0 +1,69 @@
+package throttle
+
+import (
+	"context"
+	"database/sql"
+	"service/database"
+	"time"
+)
+
+//PostgresStore ... is a Store backed by the login_attempt table, shared by every instance.
+type PostgresStore struct {
+	db database.DBInterface
+}
+
+//NewPostgresStore ... returns a pointer to a new PostgresStore using the passed in db.
+func NewPostgresStore(db database.DBInterface) *PostgresStore {
+	return &PostgresStore{
+		db: db,
+	}
+}
+
+//Get ... returns the key's record, a zero Record when it has none.
+func (p *PostgresStore) Get(ctx context.Context, key string) (Record, error) {
+	var record Record
+	var lockedUntil *time.Time
+	err := p.db.QueryRowContext(ctx,
+		"SELECT failures, last_failure_at, locked_until FROM login_attempt WHERE key = $1;",
+		key).Scan(&record.Failures, &record.LastFailureAt, &lockedUntil)
+	if err == sql.ErrNoRows {
+		return Record{}, nil
+	}
+	if err != nil {
+		return Record{}, err
+	}
+	if lockedUntil != nil {
+		record.LockedUntil = *lockedUntil
+	}
+	return record, nil
+}
+
+//RecordFailure ... atomically counts a failure at the given time, starting over if the
+//last one was before forgetBefore, and returns the new count.
+func (p *PostgresStore) RecordFailure(ctx context.Context, key string, at,
+	forgetBefore time.Time) (int, error) {
+	var failures int
+	err := p.db.QueryRowContext(ctx,
+		`INSERT INTO login_attempt (key, failures, last_failure_at)
+		VALUES ($1, 1, $2)
+		ON CONFLICT (key) DO UPDATE SET
+			failures = CASE WHEN login_attempt.last_failure_at < $3 THEN 1
+				ELSE login_attempt.failures + 1 END,
+			last_failure_at = $2
+		RETURNING failures;`,
+		key, at, forgetBefore).Scan(&failures)
+	return failures, err
+}
+
+//Lock ... locks the key out until the given time.
+func (p *PostgresStore) Lock(ctx context.Context, key string, until time.Time) error {
+	_, err := p.db.ExecContext(ctx,
+		"UPDATE login_attempt SET locked_until = $2 WHERE key = $1;", key, until)
+	return err
+}
+
+//Reset ... forgets the key.
+func (p *PostgresStore) Reset(ctx context.Context, key string) error {
+	_, err := p.db.ExecContext(ctx, "DELETE FROM login_attempt WHERE key = $1;", key)
+	return err
+}
@@ -0,0 +1,72 @@
+package throttle_test
+
+import (
+	"context"
+	"database/sql"
+	"service/auth/throttle"
+	"service/utils/sqltest"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	sqlmock "gopkg.in/DATA-DOG/go-sqlmock.v1"
+)
+
+var _ = Describe("Postgres Store Specs", func() {
+	ctx := context.Background()
+	var (
+		store  *throttle.PostgresStore
+		db     *sql.DB
+		mockDB sqlmock.Sqlmock
+	)
+
+	BeforeEach(func() {
+		var sqlmockErr error
+		db, mockDB, sqlmockErr = sqlmock.New()
+		Expect(sqlmockErr).ToNot(HaveOccurred())
+		store = throttle.NewPostgresStore(db)
+	})
+
+	AfterEach(func() {
+		Expect(mockDB.ExpectationsWereMet()).To(Succeed())
+	})
+
+	It("should return a zero record for an unknown key", func() {
+		mockDB.ExpectQuery("SELECT failures, last_failure_at, locked_until FROM login_attempt").
+			WithArgs("user:tony").WillReturnError(sql.ErrNoRows)
+		Expect(store.Get(ctx, "user:tony")).To(Equal(throttle.Record{}))
+	})
+
+	It("should read a locked record", func() {
+		lastFailure := time.Now()
+		lockedUntil := lastFailure.Add(time.Hour)
+		mockDB.ExpectQuery("SELECT failures, last_failure_at, locked_until FROM login_attempt").
+			WithArgs("user:tony").
+			WillReturnRows(sqlmock.NewRows([]string{"failures", "last_failure_at", "locked_until"}).
+				AddRow(3, lastFailure, lockedUntil))
+		record, err := store.Get(ctx, "user:tony")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(record.Failures).To(Equal(3))
+		Expect(record.LockedUntil).To(Equal(lockedUntil))
+	})
+
+	It("should upsert a failure and return the new count", func() {
+		mockDB.ExpectQuery("INSERT INTO login_attempt").
+			WithArgs("user:tony", sqltest.AnyTime{}, sqltest.AnyTime{}).
+			WillReturnRows(sqlmock.NewRows([]string{"failures"}).AddRow(2))
+		Expect(store.RecordFailure(ctx, "user:tony", time.Now(), time.Now().Add(-time.Hour))).To(Equal(2))
+	})
+
+	It("should lock a key until the given time", func() {
+		until := time.Now().Add(time.Hour)
+		mockDB.ExpectExec("UPDATE login_attempt SET locked_until").WithArgs("user:tony", until).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		Expect(store.Lock(ctx, "user:tony", until)).To(Succeed())
+	})
+
+	It("should delete a key on reset", func() {
+		mockDB.ExpectExec("DELETE FROM login_attempt").WithArgs("user:tony").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		Expect(store.Reset(ctx, "user:tony")).To(Succeed())
+	})
+})
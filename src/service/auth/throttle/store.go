@@ -0,0 +1,77 @@
+package throttle
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+//Record ... is what is kept per throttled key.
+type Record struct {
+	Failures      int
+	LastFailureAt time.Time
+	LockedUntil   time.Time
+}
+
+//Store ... defines a backing store for failed login records.
+//go:generate counterfeiter . Store
+type Store interface {
+	Get(ctx context.Context, key string) (Record, error)
+	RecordFailure(ctx context.Context, key string, at, forgetBefore time.Time) (int, error)
+	Lock(ctx context.Context, key string, until time.Time) error
+	Reset(ctx context.Context, key string) error
+}
+
+//MemoryStore ... is a Store for a single instance, records are lost on restart.
+type MemoryStore struct {
+	mutex   sync.Mutex
+	records map[string]Record
+}
+
+//NewMemoryStore ... returns a pointer to a new, empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		records: make(map[string]Record),
+	}
+}
+
+//Get ... returns the key's record, a zero Record when it has none.
+func (m *MemoryStore) Get(ctx context.Context, key string) (Record, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return m.records[key], nil
+}
+
+//RecordFailure ... counts a failure at the given time, starting over if the last
+//one was before forgetBefore, and returns the new count.
+func (m *MemoryStore) RecordFailure(ctx context.Context, key string, at,
+	forgetBefore time.Time) (int, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	record := m.records[key]
+	if record.LastFailureAt.Before(forgetBefore) {
+		record.Failures = 0
+	}
+	record.Failures++
+	record.LastFailureAt = at
+	m.records[key] = record
+	return record.Failures, nil
+}
+
+//Lock ... locks the key out until the given time.
+func (m *MemoryStore) Lock(ctx context.Context, key string, until time.Time) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	record := m.records[key]
+	record.LockedUntil = until
+	m.records[key] = record
+	return nil
+}
+
+//Reset ... forgets the key.
+func (m *MemoryStore) Reset(ctx context.Context, key string) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	delete(m.records, key)
+	return nil
+}
@@ -0,0 +1,214 @@
+package throttle
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"service/log"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+//Key kinds, a key is "<kind>:<value>".
+const (
+	KindIdentity = "identity"
+	KindUser     = "user"
+	KindIP       = "ip"
+	KindEmail    = "email"
+	KindResetIP  = "reset-ip"
+)
+
+//IdentityKey ... tracks failed password logins for an identity ID.
+func IdentityKey(id string) string {
+	return KindIdentity + ":" + id
+}
+
+//UserKey ... tracks failed basic auth logins for a username.
+func UserKey(username string) string {
+	return KindUser + ":" + username
+}
+
+//IPKey ... tracks failed logins of any kind from a client IP.
+func IPKey(ip string) string {
+	return KindIP + ":" + ip
+}
+
+//EmailKey ... tracks password reset requests for an email, however it is cased.
+func EmailKey(email string) string {
+	return KindEmail + ":" + strings.ToLower(strings.TrimSpace(email))
+}
+
+//ResetIPKey ... tracks password reset requests from a client IP, apart from its logins so
+//reset requests never slow down or lock out logins from that address.
+func ResetIPKey(ip string) string {
+	return KindResetIP + ":" + ip
+}
+
+//ClientIP ... the IP the request came from, without its port.
+func ClientIP(req *http.Request) string {
+	host, _, err := net.SplitHostPort(req.RemoteAddr)
+	if err != nil {
+		return req.RemoteAddr
+	}
+	return host
+}
+
+//Policy ...
+//After each failure the next attempt must wait BaseDelay doubled per prior failure, up
+//to MaxDelay. Threshold failures lock a key out for Lockout, IP and reset IP keys use
+//IPThreshold as many users can share an address. Failures are forgotten Lockout after the last one.
+type Policy struct {
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+	Threshold   int
+	IPThreshold int
+	Lockout     time.Duration
+}
+
+//DefaultPolicy ... is used for any zero field of a configured Policy.
+var DefaultPolicy = Policy{
+	BaseDelay:   time.Second,
+	MaxDelay:    time.Minute,
+	Threshold:   10,
+	IPThreshold: 50,
+	Lockout:     15 * time.Minute,
+}
+
+//Interface ... defines login throttling.
+//go:generate counterfeiter . Interface
+type Interface interface {
+	Check(ctx context.Context, keys ...string) (time.Duration, error)
+	Fail(ctx context.Context, keys ...string) error
+	Succeed(ctx context.Context, keys ...string) error
+	Unlock(ctx context.Context, key string) error
+}
+
+//Throttle ... applies a Policy to failure records kept in a Store.
+type Throttle struct {
+	log    log.ProdInterface
+	store  Store
+	policy Policy
+}
+
+//NewThrottle ... returns a pointer to a new Throttle, zero policy fields use DefaultPolicy.
+func NewThrottle(logClient log.ProdInterface, store Store, policy Policy) *Throttle {
+	if policy.BaseDelay <= 0 {
+		policy.BaseDelay = DefaultPolicy.BaseDelay
+	}
+	if policy.MaxDelay <= 0 {
+		policy.MaxDelay = DefaultPolicy.MaxDelay
+	}
+	if policy.Threshold <= 0 {
+		policy.Threshold = DefaultPolicy.Threshold
+	}
+	if policy.IPThreshold <= 0 {
+		policy.IPThreshold = DefaultPolicy.IPThreshold
+	}
+	if policy.Lockout <= 0 {
+		policy.Lockout = DefaultPolicy.Lockout
+	}
+	return &Throttle{
+		log:    logClient,
+		store:  store,
+		policy: policy,
+	}
+}
+
+//Check ...
+//returns how long the caller must wait before another attempt for any of the keys is
+//allowed, zero when it may go ahead.
+func (t *Throttle) Check(ctx context.Context, keys ...string) (time.Duration, error) {
+	rightNow := time.Now()
+	var wait time.Duration
+	for _, key := range keys {
+		record, err := t.store.Get(ctx, key)
+		if err != nil {
+			return 0, err
+		}
+		allowedAt := record.LockedUntil
+		if record.Failures > 0 && rightNow.Sub(record.LastFailureAt) < t.policy.Lockout {
+			if backoffUntil := record.LastFailureAt.Add(t.delay(record.Failures)); backoffUntil.After(allowedAt) {
+				allowedAt = backoffUntil
+			}
+		}
+		if until := allowedAt.Sub(rightNow); until > wait {
+			wait = until
+		}
+	}
+	return wait, nil
+}
+
+//Fail ... records a failed attempt against every key, locking out those over their threshold.
+func (t *Throttle) Fail(ctx context.Context, keys ...string) error {
+	rightNow := time.Now()
+	for _, key := range keys {
+		failures, err := t.store.RecordFailure(ctx, key, rightNow, rightNow.Add(-t.policy.Lockout))
+		if err != nil {
+			return err
+		}
+		if failures < t.threshold(key) {
+			continue
+		}
+		lockedUntil := rightNow.Add(t.policy.Lockout)
+		if err := t.store.Lock(ctx, key, lockedUntil); err != nil {
+			return err
+		}
+		t.log.Warn("login lockout",
+			zap.String("event", "auth.lockout"),
+			zap.String("key", key),
+			zap.Int("failures", failures),
+			zap.Time("lockedUntil", lockedUntil))
+	}
+	return nil
+}
+
+//Succeed ...
+//clears the failures of every key after a successful login. Keys without a record are
+//left alone, so a login that never failed does not write.
+func (t *Throttle) Succeed(ctx context.Context, keys ...string) error {
+	for _, key := range keys {
+		record, err := t.store.Get(ctx, key)
+		if err != nil {
+			return err
+		}
+		if record == (Record{}) {
+			continue
+		}
+		if err := t.store.Reset(ctx, key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//Unlock ... lifts a lockout and clears the key's failures, for admins.
+func (t *Throttle) Unlock(ctx context.Context, key string) error {
+	if err := t.store.Reset(ctx, key); err != nil {
+		return err
+	}
+	t.log.Warn("login lockout lifted",
+		zap.String("event", "auth.unlock"),
+		zap.String("key", key))
+	return nil
+}
+
+//delay is the backoff after failures consecutive failures.
+func (t *Throttle) delay(failures int) time.Duration {
+	delay := t.policy.BaseDelay
+	for i := 1; i < failures && delay < t.policy.MaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > t.policy.MaxDelay {
+		return t.policy.MaxDelay
+	}
+	return delay
+}
+
+func (t *Throttle) threshold(key string) int {
+	if strings.HasPrefix(key, KindIP+":") || strings.HasPrefix(key, KindResetIP+":") {
+		return t.policy.IPThreshold
+	}
+	return t.policy.Threshold
+}
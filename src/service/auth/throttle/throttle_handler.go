@@ -0,0 +1,61 @@
+package throttle
+
+import (
+	"net/http"
+	"service/handlers/loggederror"
+	"service/log"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi"
+)
+
+//ErrTooManyAttempts ... is the public message for throttled logins.
+const ErrTooManyAttempts = "too many failed login attempts"
+
+//SetRetryAfter ... sets the Retry-After header to wait, rounded up to whole seconds.
+func SetRetryAfter(w http.ResponseWriter, wait time.Duration) {
+	seconds := int((wait + time.Second - 1) / time.Second)
+	w.Header().Set("Retry-After", strconv.Itoa(seconds))
+}
+
+//HandlerObject ... holds elementals for managing lockouts over http.
+type HandlerObject struct {
+	Log      log.ProdInterface
+	Throttle Interface
+}
+
+//NewHandlerObject ... returns a pointer to a new throttle HandlerObject.
+func NewHandlerObject(logClient log.ProdInterface, throttle Interface) *HandlerObject {
+	return &HandlerObject{
+		Log:      logClient,
+		Throttle: throttle,
+	}
+}
+
+//Unlock ...
+//DELETE /auth/lockouts/{kind}/{value}, kind is identity, user, ip, email or reset-ip.
+//Emails are normalized as EmailKey records them.
+func (h *HandlerObject) Unlock(w http.ResponseWriter, req *http.Request) {
+	kind, value := chi.URLParam(req, "kind"), chi.URLParam(req, "value")
+	if value == "" || !knownKind(kind) {
+		loggederror.RespondWithWithExpectedSoftError(h.Log, http.StatusBadRequest,
+			"unknown lockout", "throttle_handler::Unlock", w, req)
+		return
+	}
+	key := kind + ":" + value
+	if kind == KindEmail {
+		key = EmailKey(value)
+	}
+	if err := h.Throttle.Unlock(req.Context(), key); err != nil {
+		loggederror.RespondWithProperErrorAndLogIt(h.Log, http.StatusInternalServerError,
+			err, "throttle_handler::Unlock", w, req)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func knownKind(kind string) bool {
+	return kind == KindIdentity || kind == KindUser || kind == KindIP || kind == KindEmail ||
+		kind == KindResetIP
+}
@@ -0,0 +1,77 @@
+package throttle_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"service/auth/throttle"
+	"service/auth/throttle/throttlefakes"
+	"service/log/logfakes"
+	"time"
+
+	"github.com/go-chi/chi"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Throttle Handler Specs", func() {
+	var (
+		handler      *throttle.HandlerObject
+		fakeThrottle *throttlefakes.FakeInterface
+		fakeLog      *logfakes.FakeProdInterface
+		router       *chi.Mux
+		recorder     *httptest.ResponseRecorder
+	)
+
+	BeforeEach(func() {
+		fakeThrottle = &throttlefakes.FakeInterface{}
+		fakeLog = &logfakes.FakeProdInterface{}
+		handler = throttle.NewHandlerObject(fakeLog, fakeThrottle)
+		router = chi.NewRouter()
+		router.Delete("/auth/lockouts/{kind}/{value}", handler.Unlock)
+		recorder = httptest.NewRecorder()
+	})
+
+	serve := func(path string) {
+		router.ServeHTTP(recorder, httptest.NewRequest("DELETE", path, nil))
+	}
+
+	It("should unlock the key and respond 204", func() {
+		serve("/auth/lockouts/identity/tony")
+		Expect(recorder.Code).To(Equal(http.StatusNoContent))
+		_, key := fakeThrottle.UnlockArgsForCall(0)
+		Expect(key).To(Equal("identity:tony"))
+	})
+
+	It("should unlock password reset requests for an email", func() {
+		serve("/auth/lockouts/email/tony@example.com")
+		Expect(recorder.Code).To(Equal(http.StatusNoContent))
+		_, key := fakeThrottle.UnlockArgsForCall(0)
+		Expect(key).To(Equal("email:tony@example.com"))
+	})
+
+	It("should unlock an email however it is cased or padded", func() {
+		serve("/auth/lockouts/email/Tony@Example.com%20")
+		Expect(recorder.Code).To(Equal(http.StatusNoContent))
+		_, key := fakeThrottle.UnlockArgsForCall(0)
+		Expect(key).To(Equal("email:tony@example.com"))
+	})
+
+	It("should respond 400 for an unknown kind", func() {
+		serve("/auth/lockouts/session/tony")
+		Expect(recorder.Code).To(Equal(http.StatusBadRequest))
+		Expect(fakeThrottle.UnlockCallCount()).To(Equal(0))
+	})
+
+	It("should respond 500 and log when the unlock fails", func() {
+		fakeThrottle.UnlockReturns(errors.New("db down"))
+		serve("/auth/lockouts/ip/192.0.2.1")
+		Expect(recorder.Code).To(Equal(http.StatusInternalServerError))
+		Expect(fakeLog.ErrorCallCount()).To(Equal(1))
+	})
+
+	It("should round Retry-After up to whole seconds", func() {
+		throttle.SetRetryAfter(recorder, 1500*time.Millisecond)
+		Expect(recorder.Header().Get("Retry-After")).To(Equal("2"))
+	})
+})
@@ -0,0 +1,126 @@
+package throttle_test
+
+import (
+	"context"
+	"service/auth/throttle"
+	"service/auth/throttle/throttlefakes"
+	"service/log/logfakes"
+	"time"
+
+	"go.uber.org/zap"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Throttle Specs", func() {
+	ctx := context.Background()
+	var (
+		fakeLog *logfakes.FakeProdInterface
+		store   *throttle.MemoryStore
+		limiter *throttle.Throttle
+	)
+
+	BeforeEach(func() {
+		fakeLog = &logfakes.FakeProdInterface{}
+		store = throttle.NewMemoryStore()
+		limiter = throttle.NewThrottle(fakeLog, store, throttle.Policy{
+			BaseDelay:   time.Minute,
+			MaxDelay:    4 * time.Minute,
+			Threshold:   3,
+			IPThreshold: 5,
+			Lockout:     time.Hour,
+		})
+	})
+
+	It("should let a key without failures through", func() {
+		Expect(limiter.Check(ctx, "user:tony")).To(BeZero())
+	})
+
+	It("should back off exponentially up to the max delay", func() {
+		Expect(limiter.Fail(ctx, "ip:192.0.2.1")).To(Succeed())
+		wait, err := limiter.Check(ctx, "ip:192.0.2.1")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(wait).To(BeNumerically("~", time.Minute, time.Second))
+
+		Expect(limiter.Fail(ctx, "ip:192.0.2.1")).To(Succeed())
+		wait, _ = limiter.Check(ctx, "ip:192.0.2.1")
+		Expect(wait).To(BeNumerically("~", 2*time.Minute, time.Second))
+
+		Expect(limiter.Fail(ctx, "ip:192.0.2.1", "ip:192.0.2.1")).To(Succeed())
+		wait, _ = limiter.Check(ctx, "ip:192.0.2.1")
+		Expect(wait).To(BeNumerically("~", 4*time.Minute, time.Second))
+	})
+
+	It("should report the longest wait of all the keys", func() {
+		Expect(limiter.Fail(ctx, "user:tony", "ip:192.0.2.1")).To(Succeed())
+		Expect(limiter.Fail(ctx, "user:tony")).To(Succeed())
+		wait, err := limiter.Check(ctx, "user:other", "user:tony")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(wait).To(BeNumerically("~", 2*time.Minute, time.Second))
+	})
+
+	Context("when a key reaches its threshold", func() {
+		BeforeEach(func() {
+			for i := 0; i < 3; i++ {
+				Expect(limiter.Fail(ctx, "identity:tony")).To(Succeed())
+			}
+		})
+
+		It("should lock the key out for the lockout duration", func() {
+			wait, err := limiter.Check(ctx, "identity:tony")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(wait).To(BeNumerically("~", time.Hour, time.Second))
+		})
+
+		It("should log a security event", func() {
+			Expect(fakeLog.WarnCallCount()).To(Equal(1))
+			message, fields := fakeLog.WarnArgsForCall(0)
+			Expect(message).To(Equal("login lockout"))
+			Expect(fields).To(ContainElement(zap.String("event", "auth.lockout")))
+			Expect(fields).To(ContainElement(zap.String("key", "identity:tony")))
+			Expect(fields).To(ContainElement(zap.Int("failures", 3)))
+		})
+
+		It("should lift the lockout on unlock and log it", func() {
+			Expect(limiter.Unlock(ctx, "identity:tony")).To(Succeed())
+			Expect(limiter.Check(ctx, "identity:tony")).To(BeZero())
+			_, fields := fakeLog.WarnArgsForCall(1)
+			Expect(fields).To(ContainElement(zap.String("event", "auth.unlock")))
+		})
+	})
+
+	It("should allow IP keys more failures before locking them out", func() {
+		for i := 0; i < 4; i++ {
+			Expect(limiter.Fail(ctx, "ip:192.0.2.1")).To(Succeed())
+		}
+		Expect(fakeLog.WarnCallCount()).To(Equal(0))
+		Expect(limiter.Fail(ctx, "ip:192.0.2.1")).To(Succeed())
+		Expect(fakeLog.WarnCallCount()).To(Equal(1))
+	})
+
+	It("should keep reset requests from an IP apart from its logins", func() {
+		for i := 0; i < 5; i++ {
+			Expect(limiter.Fail(ctx, throttle.ResetIPKey("192.0.2.1"))).To(Succeed())
+		}
+		Expect(fakeLog.WarnCallCount()).To(Equal(1))
+		Expect(limiter.Check(ctx, throttle.IPKey("192.0.2.1"))).To(BeZero())
+	})
+
+	It("should clear failures on success", func() {
+		Expect(limiter.Fail(ctx, "user:tony")).To(Succeed())
+		Expect(limiter.Succeed(ctx, "user:tony")).To(Succeed())
+		Expect(limiter.Check(ctx, "user:tony")).To(BeZero())
+		record, err := store.Get(ctx, "user:tony")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(record.Failures).To(BeZero())
+	})
+
+	It("should not reset keys without failures on success", func() {
+		fakeStore := &throttlefakes.FakeStore{}
+		limiter = throttle.NewThrottle(fakeLog, fakeStore, throttle.Policy{})
+		Expect(limiter.Succeed(ctx, "user:tony")).To(Succeed())
+		Expect(fakeStore.GetCallCount()).To(Equal(1))
+		Expect(fakeStore.ResetCallCount()).To(Equal(0))
+	})
+})
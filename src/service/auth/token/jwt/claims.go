@@ -0,0 +1,133 @@
+package jwt
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	jwtGo "github.com/dgrijalva/jwt-go"
+)
+
+//registeredClaims are set by the service and can't be supplied through Generate's input.
+var registeredClaims = map[string]bool{
+	"iss": true,
+	"aud": true,
+	"exp": true,
+	"nbf": true,
+	"iat": true,
+	"jti": true,
+}
+
+//IdentityClaims ...
+//Holds personalized identity information as well as the standard information.
+//StandardClaims.Id is the jti, a unique ID per token used for revocation, and
+//StandardClaims.Subject is the identity ID. Scope is the space separated list of
+//permissions granted to the holder. Custom holds every other claim.
+type IdentityClaims struct {
+	Email  string                 `json:"email,omitempty"`
+	Scope  string                 `json:"scope,omitempty"`
+	Custom map[string]interface{} `json:"-"`
+	jwtGo.StandardClaims
+}
+
+//identityClaims has the fields of IdentityClaims without its json methods.
+type identityClaims IdentityClaims
+
+func newIdentityClaims(input map[string]interface{}) (*IdentityClaims, error) {
+	claims := &IdentityClaims{}
+	for name, value := range input {
+		switch {
+		case name == "sub":
+			subject, ok := value.(string)
+			if !ok {
+				return nil, fmt.Errorf("claim sub must be a string, got %T", value)
+			}
+			claims.Subject = subject
+		case name == "email":
+			email, ok := value.(string)
+			if !ok {
+				return nil, fmt.Errorf("claim email must be a string, got %T", value)
+			}
+			claims.Email = email
+		case name == "scope":
+			switch scope := value.(type) {
+			case string:
+				claims.Scope = scope
+			case []string:
+				claims.Scope = strings.Join(scope, " ")
+			default:
+				return nil, fmt.Errorf("claim scope must be a string or []string, got %T", value)
+			}
+		case registeredClaims[name]:
+			return nil, fmt.Errorf("claim %s is set by the token service", name)
+		default:
+			if claims.Custom == nil {
+				claims.Custom = make(map[string]interface{})
+			}
+			claims.Custom[name] = value
+		}
+	}
+	return claims, nil
+}
+
+//MarshalJSON ...
+//Flattens Custom into the top level of the claims object.
+func (c IdentityClaims) MarshalJSON() ([]byte, error) {
+	known, err := json.Marshal(identityClaims(c))
+	if err != nil || len(c.Custom) == 0 {
+		return known, err
+	}
+	merged := make(map[string]interface{}, len(c.Custom))
+	for name, value := range c.Custom {
+		merged[name] = value
+	}
+	if err := json.Unmarshal(known, &merged); err != nil {
+		return nil, err
+	}
+	return json.Marshal(merged)
+}
+
+//UnmarshalJSON ...
+//Collects every claim without a field of its own into Custom.
+func (c *IdentityClaims) UnmarshalJSON(data []byte) error {
+	var known identityClaims
+	if err := json.Unmarshal(data, &known); err != nil {
+		return err
+	}
+	var all map[string]interface{}
+	if err := json.Unmarshal(data, &all); err != nil {
+		return err
+	}
+	for _, name := range []string{"email", "scope", "sub", "iss", "aud", "exp", "nbf", "iat", "jti"} {
+		delete(all, name)
+	}
+	*c = IdentityClaims(known)
+	if len(all) > 0 {
+		c.Custom = all
+	}
+	return nil
+}
+
+//Claim ...
+//Looks up a custom claim by name.
+func (c *IdentityClaims) Claim(name string) (interface{}, bool) {
+	value, ok := c.Custom[name]
+	return value, ok
+}
+
+//Scopes ...
+//The individual scopes in the scope claim.
+func (c *IdentityClaims) Scopes() []string {
+	return strings.Fields(c.Scope)
+}
+
+//HasScope ...
+//Whether the scope claim includes scope.
+func (c *IdentityClaims) HasScope(scope string) bool {
+	for _, held := range c.Scopes() {
+		if held == scope {
+			return true
+		}
+	}
+	return false
+}
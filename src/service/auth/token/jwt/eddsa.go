@@ -0,0 +1,55 @@
+package jwt
+
+import (
+	"crypto/ed25519"
+	"errors"
+
+	jwtGo "github.com/dgrijalva/jwt-go"
+)
+
+//SigningMethodEdDSA ...
+//jwt-go v3 has no Ed25519 support, so this plugs the "EdDSA" alg (RFC 8037) into it.
+type SigningMethodEdDSA struct{}
+
+//EdDSA ...
+//The registered EdDSA signing method, used the same way as jwtGo.SigningMethodRS256.
+var EdDSA = &SigningMethodEdDSA{}
+
+func init() {
+	jwtGo.RegisterSigningMethod(EdDSA.Alg(), func() jwtGo.SigningMethod {
+		return EdDSA
+	})
+}
+
+//Alg ...
+//The JWS alg header value.
+func (m *SigningMethodEdDSA) Alg() string {
+	return "EdDSA"
+}
+
+//Verify ...
+//Checks an Ed25519 signature over signingString with an ed25519.PublicKey.
+func (m *SigningMethodEdDSA) Verify(signingString, signature string, key interface{}) error {
+	publicKey, ok := key.(ed25519.PublicKey)
+	if !ok {
+		return jwtGo.ErrInvalidKeyType
+	}
+	sig, err := jwtGo.DecodeSegment(signature)
+	if err != nil {
+		return err
+	}
+	if !ed25519.Verify(publicKey, []byte(signingString), sig) {
+		return errors.New("ed25519: verification error")
+	}
+	return nil
+}
+
+//Sign ...
+//Signs signingString with an ed25519.PrivateKey.
+func (m *SigningMethodEdDSA) Sign(signingString string, key interface{}) (string, error) {
+	privateKey, ok := key.(ed25519.PrivateKey)
+	if !ok {
+		return "", jwtGo.ErrInvalidKeyType
+	}
+	return jwtGo.EncodeSegment(ed25519.Sign(privateKey, []byte(signingString))), nil
+}
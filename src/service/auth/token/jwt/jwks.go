@@ -0,0 +1,112 @@
+package jwt
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/base64"
+	"math/big"
+	"sort"
+	"time"
+)
+
+//JWK ...
+//The public half of a key as a JSON Web Key (RFC 7517, RFC 8037 for OKP).
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
+}
+
+//JWKSet ...
+//The document served from /.well-known/jwks.json.
+type JWKSet struct {
+	Keys []JWK `json:"keys"`
+}
+
+//JWK ...
+//Converts the key to a JWK. Returns false for shared secret keys, which must never be published.
+func (k *Key) JWK() (JWK, bool) {
+	jwk := JWK{
+		Kid: k.ID,
+		Use: "sig",
+		Alg: k.Method.Alg(),
+	}
+	switch public := k.Public.(type) {
+	case *rsa.PublicKey:
+		jwk.Kty = "RSA"
+		jwk.N = encodeInt(public.N)
+		jwk.E = encodeInt(big.NewInt(int64(public.E)))
+	case *ecdsa.PublicKey:
+		size := (public.Curve.Params().BitSize + 7) / 8
+		jwk.Kty = "EC"
+		jwk.Crv = public.Curve.Params().Name
+		jwk.X = encodePadded(public.X, size)
+		jwk.Y = encodePadded(public.Y, size)
+	case ed25519.PublicKey:
+		jwk.Kty = "OKP"
+		jwk.Crv = "Ed25519"
+		jwk.X = base64.RawURLEncoding.EncodeToString(public)
+	default:
+		return JWK{}, false
+	}
+	return jwk, true
+}
+
+//JWKS ...
+//Every publishable verification key in the keyring.
+func (k *Keyring) JWKS() JWKSet {
+	set := JWKSet{Keys: make([]JWK, 0)}
+	for _, key := range k.Keys() {
+		if jwk, ok := key.JWK(); ok {
+			set.Keys = append(set.Keys, jwk)
+		}
+	}
+	return set
+}
+
+//Algorithms ...
+//The distinct algs of the publishable keys in the keyring, sorted. Shared secret algs
+//such as HS256 are left out as verifiers could never check them.
+func (k *Keyring) Algorithms() []string {
+	seen := make(map[string]bool)
+	algs := make([]string, 0)
+	for _, jwk := range k.JWKS().Keys {
+		if !seen[jwk.Alg] {
+			seen[jwk.Alg] = true
+			algs = append(algs, jwk.Alg)
+		}
+	}
+	sort.Strings(algs)
+	return algs
+}
+
+//NextPromotion ...
+//When the scheduled signing key takes over, false when nothing is scheduled.
+func (k *Keyring) NextPromotion() (time.Time, bool) {
+	k.mutex.Lock()
+	defer k.mutex.Unlock()
+	k.promoteIfDue()
+	if k.next == "" {
+		return time.Time{}, false
+	}
+	return k.nextAt, true
+}
+
+func encodeInt(n *big.Int) string {
+	return base64.RawURLEncoding.EncodeToString(n.Bytes())
+}
+
+//encodePadded left pads EC coordinates to the curve size as RFC 7518 6.2.1.2 requires.
+func encodePadded(n *big.Int, size int) string {
+	buf := make([]byte, size)
+	bytes := n.Bytes()
+	copy(buf[size-len(bytes):], bytes)
+	return base64.RawURLEncoding.EncodeToString(buf)
+}
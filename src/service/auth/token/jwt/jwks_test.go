@@ -0,0 +1,71 @@
+package jwt_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"math/big"
+	"service/auth/token/jwt"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("JWK Specs", func() {
+	decode := func(value string) []byte {
+		bytes, err := base64.RawURLEncoding.DecodeString(value)
+		Expect(err).ToNot(HaveOccurred())
+		return bytes
+	}
+
+	It("should encode RSA public keys", func() {
+		rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+		Expect(err).ToNot(HaveOccurred())
+		key, err := jwt.NewKey("rsa-1", rsaKey)
+		Expect(err).ToNot(HaveOccurred())
+
+		jwk, ok := key.JWK()
+		Expect(ok).To(BeTrue())
+		Expect(jwk.Kty).To(Equal("RSA"))
+		Expect(jwk.Alg).To(Equal("RS256"))
+		Expect(jwk.Use).To(Equal("sig"))
+		Expect(new(big.Int).SetBytes(decode(jwk.N))).To(Equal(rsaKey.N))
+		Expect(jwk.E).To(Equal("AQAB"))
+	})
+
+	It("should pad EC coordinates to the curve size", func() {
+		ecKey, err := ecdsa.GenerateKey(elliptic.P521(), rand.Reader)
+		Expect(err).ToNot(HaveOccurred())
+		key, err := jwt.NewKey("ec-1", ecKey)
+		Expect(err).ToNot(HaveOccurred())
+
+		jwk, ok := key.JWK()
+		Expect(ok).To(BeTrue())
+		Expect(jwk.Crv).To(Equal("P-521"))
+		Expect(decode(jwk.X)).To(HaveLen(66))
+		Expect(decode(jwk.Y)).To(HaveLen(66))
+	})
+
+	It("should encode Ed25519 keys as OKP", func() {
+		public, private, err := ed25519.GenerateKey(rand.Reader)
+		Expect(err).ToNot(HaveOccurred())
+		key, err := jwt.NewKey("ed-1", private)
+		Expect(err).ToNot(HaveOccurred())
+
+		jwk, ok := key.JWK()
+		Expect(ok).To(BeTrue())
+		Expect(jwk.Kty).To(Equal("OKP"))
+		Expect(jwk.Crv).To(Equal("Ed25519"))
+		Expect(decode(jwk.X)).To(Equal([]byte(public)))
+	})
+
+	It("should refuse to publish shared secrets", func() {
+		key, err := jwt.NewHMACKey("hmac-1", []byte("secret"))
+		Expect(err).ToNot(HaveOccurred())
+		_, ok := key.JWK()
+		Expect(ok).To(BeFalse())
+	})
+})
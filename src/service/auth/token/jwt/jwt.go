@@ -3,10 +3,10 @@ package jwt
 import (
 	"errors"
 	"fmt"
-	"os"
 	"time"
 
 	jwtGo "github.com/dgrijalva/jwt-go"
+	"github.com/google/uuid"
 )
 
 //Interface ...
@@ -16,22 +16,38 @@ type Interface interface {
 	Generate(input map[string]interface{}) (string, error)
 }
 
+//DefaultTTL ...
+//How long access tokens are valid for, they are short lived and renewed with refresh tokens.
+const DefaultTTL = 15 * time.Minute
+
 //Service ...
 //A holder struct for a jwt impl.
 type Service struct {
+	TTL      time.Duration
+	Keys     *Keyring
+	Issuer   string
+	Audience string
+	Policy   Policy
 }
 
 //NewService ...
-//Creates a new jwt token service.
-func NewService() *Service {
-	return &Service{}
+//Creates a new jwt token service that signs and verifies with the given keyring.
+func NewService(keys *Keyring) *Service {
+	return &Service{
+		TTL:  DefaultTTL,
+		Keys: keys,
+	}
 }
 
-//IdentityClaims ...
-//Holds personalized identity information as well as the standard information.
-type IdentityClaims struct {
-	Email string `json:"email"`
-	jwtGo.StandardClaims
+//ClaimsFrom ...
+//Pulls our IdentityClaims out of a token returned by ValidateToken.
+func ClaimsFrom(token interface{}) (*IdentityClaims, bool) {
+	tokenObj, ok := token.(*jwtGo.Token)
+	if !ok || tokenObj == nil {
+		return nil, false
+	}
+	claims, ok := tokenObj.Claims.(*IdentityClaims)
+	return claims, ok
 }
 
 //ValidateToken ...
@@ -40,38 +56,58 @@ func (s *Service) ValidateToken(token string) (interface{}, bool, error) {
 	if token == "" {
 		return nil, false, errors.New("cannot validate an empty token")
 	}
-	tokenObj, err := jwtGo.ParseWithClaims(token, &IdentityClaims{}, obtainJwtKey)
+	//jwt-go's own exp/iat/nbf checks have no leeway, the policy does them instead.
+	parser := &jwtGo.Parser{SkipClaimsValidation: true}
+	tokenObj, err := parser.ParseWithClaims(token, &IdentityClaims{}, s.obtainJwtKey)
 	if err != nil {
 		return nil, false, err
 	}
+	if err := s.Policy.Validate(tokenObj.Claims.(*IdentityClaims), time.Now()); err != nil {
+		return nil, false, err
+	}
 	return tokenObj, tokenObj.Valid, nil
 }
 
 //Generate ...
-//This generates a jwt token with the passed in values.
+//This generates a jwt token with the passed in values. "sub", "email" and "scope" fill the
+//matching claims, any other non registered key is carried through as a custom claim.
 func (s *Service) Generate(input map[string]interface{}) (string, error) {
 	if len(input) == 0 {
 		return "", errors.New("can't generate a token with required claims")
 	}
-	claims := IdentityClaims{}
-	if val, ok := input["email"]; ok {
-		claims.Email = val.(string)
+	claims, err := newIdentityClaims(input)
+	if err != nil {
+		return "", err
 	}
-	claims.ExpiresAt = time.Now().Add(12 * time.Hour).UTC().Unix()
-	token := jwtGo.NewWithClaims(jwtGo.SigningMethodHS256, claims)
-	if token != nil {
-		tokenString, err := token.SignedString([]byte(os.Getenv("STAGE_JWT_SECRET")))
-		if err != nil {
-			return "", err
-		}
-		return tokenString, nil
+	now := time.Now()
+	claims.Id = uuid.New().String()
+	claims.Issuer = s.Issuer
+	claims.Audience = s.Audience
+	claims.IssuedAt = now.Unix()
+	claims.NotBefore = now.Unix()
+	claims.ExpiresAt = now.Add(s.TTL).UTC().Unix()
+	key, err := s.Keys.SigningKey()
+	if err != nil {
+		return "", err
 	}
-	return "", nil
+	token := jwtGo.NewWithClaims(key.Method, claims)
+	token.Header["kid"] = key.ID
+	return token.SignedString(key.Private)
 }
 
-func obtainJwtKey(token *jwtGo.Token) (interface{}, error) {
-	if _, ok := token.Method.(*jwtGo.SigningMethodHMAC); !ok {
+//obtainJwtKey picks the verification key named by the kid header, refusing tokens
+//whose alg does not match that key so an RSA public key can never be used as an HMAC secret.
+func (s *Service) obtainJwtKey(token *jwtGo.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	if kid == "" {
+		return nil, errors.New("token has no kid header")
+	}
+	key, err := s.Keys.VerificationKey(kid)
+	if err != nil {
+		return nil, err
+	}
+	if token.Method.Alg() != key.Method.Alg() {
 		return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
 	}
-	return []byte(os.Getenv("STAGE_JWT_SECRET")), nil
+	return key.Public, nil
 }
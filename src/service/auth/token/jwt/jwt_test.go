@@ -1,7 +1,13 @@
 package jwt_test
 
 import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
 	"service/auth/token/jwt"
+	"time"
 
 	jwt2 "github.com/dgrijalva/jwt-go"
 
@@ -13,13 +19,19 @@ var _ = Describe("JWT Service Specs", func() {
 	var jwtService *jwt.Service
 	var validTestToken string
 	var inputMap map[string]interface{}
+	var keyring *jwt.Keyring
 
 	BeforeEach(func() {
-		jwtService = jwt.NewService()
+		ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		Expect(err).ToNot(HaveOccurred())
+		key, err := jwt.NewKey("ec-1", ecKey)
+		Expect(err).ToNot(HaveOccurred())
+		keyring = jwt.NewKeyring()
+		keyring.Add(key)
+		jwtService = jwt.NewService(keyring)
 
 		inputMap = make(map[string]interface{})
 		inputMap["email"] = "test@gmail.com"
-		var err error
 		validTestToken, err = jwtService.Generate(inputMap)
 		Expect(err).ToNot(HaveOccurred())
 	})
@@ -88,6 +100,19 @@ var _ = Describe("JWT Service Specs", func() {
 				Expect(identityClaims.Email).To(Equal("test@gmail.com"))
 			})
 
+			It("should have a unique jti", func() {
+				identityClaims, ok := jwt.ClaimsFrom(expanded)
+				Expect(ok).To(BeTrue())
+				Expect(identityClaims.Id).ToNot(BeEmpty())
+
+				otherToken, err := jwtService.Generate(inputMap)
+				Expect(err).ToNot(HaveOccurred())
+				other, _, err := jwtService.ValidateToken(otherToken)
+				Expect(err).ToNot(HaveOccurred())
+				otherClaims, _ := jwt.ClaimsFrom(other)
+				Expect(otherClaims.Id).ToNot(Equal(identityClaims.Id))
+			})
+
 			It("should have an ExpiresAt field", func() {
 				claims := expanded.(*jwt2.Token).Claims
 				identityClaims, ok := claims.(*jwt.IdentityClaims)
@@ -97,10 +122,156 @@ var _ = Describe("JWT Service Specs", func() {
 		})
 	})
 
+	Context("Signing keys", func() {
+		It("should stamp the signing kid and alg on every token", func() {
+			expanded, isValid, err := jwtService.ValidateToken(validTestToken)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(isValid).To(BeTrue())
+			Expect(expanded.(*jwt2.Token).Header["kid"]).To(Equal("ec-1"))
+			Expect(expanded.(*jwt2.Token).Header["alg"]).To(Equal("ES256"))
+		})
+
+		It("should sign and verify with RSA and Ed25519 keys", func() {
+			rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+			Expect(err).ToNot(HaveOccurred())
+			_, edKey, err := ed25519.GenerateKey(rand.Reader)
+			Expect(err).ToNot(HaveOccurred())
+
+			for kid, private := range map[string]interface{}{"rsa-1": rsaKey, "ed-1": edKey} {
+				key, err := jwt.NewKey(kid, private)
+				Expect(err).ToNot(HaveOccurred())
+				keyring.Add(key)
+				Expect(keyring.Promote(kid, time.Time{})).To(Succeed())
+
+				tokenString, err := jwtService.Generate(inputMap)
+				Expect(err).ToNot(HaveOccurred())
+				expanded, isValid, err := jwtService.ValidateToken(tokenString)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(isValid).To(BeTrue())
+				Expect(expanded.(*jwt2.Token).Header["alg"]).To(Equal(key.Method.Alg()))
+			}
+		})
+
+		It("should keep verifying tokens from the previous key after a rotation", func() {
+			_, edKey, err := ed25519.GenerateKey(rand.Reader)
+			Expect(err).ToNot(HaveOccurred())
+			key, err := jwt.NewKey("ed-2", edKey)
+			Expect(err).ToNot(HaveOccurred())
+			keyring.Add(key)
+			Expect(keyring.Promote("ed-2", time.Time{})).To(Succeed())
+
+			_, isValid, err := jwtService.ValidateToken(validTestToken)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(isValid).To(BeTrue())
+
+			Expect(keyring.Remove("ec-1")).To(Succeed())
+			_, isValid, err = jwtService.ValidateToken(validTestToken)
+			Expect(err).To(HaveOccurred())
+			Expect(isValid).To(BeFalse())
+		})
+
+		It("should reject tokens without a kid", func() {
+			token := jwt2.NewWithClaims(jwt2.SigningMethodHS256, jwt.IdentityClaims{})
+			tokenString, err := token.SignedString([]byte("secret"))
+			Expect(err).ToNot(HaveOccurred())
+			_, isValid, err := jwtService.ValidateToken(tokenString)
+			Expect(err).To(MatchError(ContainSubstring("token has no kid header")))
+			Expect(isValid).To(BeFalse())
+		})
+
+		It("should reject a token whose alg does not match its key", func() {
+			hmacKey, err := jwt.NewHMACKey("hmac-1", []byte("secret"))
+			Expect(err).ToNot(HaveOccurred())
+			keyring.Add(hmacKey)
+
+			token := jwt2.NewWithClaims(jwt2.SigningMethodHS256, jwt.IdentityClaims{})
+			token.Header["kid"] = "ec-1"
+			tokenString, err := token.SignedString([]byte("secret"))
+			Expect(err).ToNot(HaveOccurred())
+			_, isValid, err := jwtService.ValidateToken(tokenString)
+			Expect(err).To(MatchError(ContainSubstring("unexpected signing method")))
+			Expect(isValid).To(BeFalse())
+		})
+
+		It("should fail to generate without a signing key", func() {
+			_, err := jwt.NewService(jwt.NewKeyring()).Generate(inputMap)
+			Expect(err).To(Equal(jwt.ErrNoSigningKey))
+		})
+	})
+
 	Context("Generate Logic", func() {
 		var tokenString string
 		var executeErr error
 
+		Context("when claims are supplied", func() {
+			var identityClaims *jwt.IdentityClaims
+
+			BeforeEach(func() {
+				jwtService.Issuer = "https://auth.example.com"
+				jwtService.Audience = "events"
+				jwtService.Policy = jwt.Policy{Issuer: jwtService.Issuer, Audience: jwtService.Audience}
+				inputMap["sub"] = "test_id"
+				inputMap["roles"] = []interface{}{"admin"}
+				inputMap["tenant"] = 7
+				inputMap["scope"] = []string{"identity:read", "events:read"}
+			})
+
+			JustBeforeEach(func() {
+				tokenString, executeErr = jwtService.Generate(inputMap)
+				Expect(executeErr).ToNot(HaveOccurred())
+				expanded, isValid, err := jwtService.ValidateToken(tokenString)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(isValid).To(BeTrue())
+				identityClaims, _ = jwt.ClaimsFrom(expanded)
+			})
+
+			It("should set the standard claims", func() {
+				Expect(identityClaims.Subject).To(Equal("test_id"))
+				Expect(identityClaims.Issuer).To(Equal("https://auth.example.com"))
+				Expect(identityClaims.Audience).To(Equal("events"))
+				Expect(identityClaims.IssuedAt).ToNot(BeZero())
+				Expect(identityClaims.NotBefore).To(Equal(identityClaims.IssuedAt))
+			})
+
+			It("should join the scopes into the scope claim", func() {
+				Expect(identityClaims.Scope).To(Equal("identity:read events:read"))
+				Expect(identityClaims.HasScope("events:read")).To(BeTrue())
+				Expect(identityClaims.HasScope("identity:write")).To(BeFalse())
+				Expect(identityClaims.Custom).ToNot(HaveKey("scope"))
+			})
+
+			It("should carry custom claims through", func() {
+				roles, ok := identityClaims.Claim("roles")
+				Expect(ok).To(BeTrue())
+				Expect(roles).To(Equal([]interface{}{"admin"}))
+				Expect(identityClaims.Custom).To(HaveKeyWithValue("tenant", BeNumerically("==", 7)))
+				Expect(identityClaims.Custom).ToNot(HaveKey("sub"))
+			})
+
+			It("should reject the token under a policy for another audience", func() {
+				jwtService.Policy.Audience = "billing"
+				_, isValid, err := jwtService.ValidateToken(tokenString)
+				Expect(err).To(Equal(jwt.ErrInvalidAudience))
+				Expect(isValid).To(BeFalse())
+			})
+		})
+
+		Context("when a claim has the wrong type", func() {
+			It("should return an error instead of panicking", func() {
+				inputMap["email"] = 42
+				_, executeErr = jwtService.Generate(inputMap)
+				Expect(executeErr).To(MatchError("claim email must be a string, got int"))
+			})
+		})
+
+		Context("when a registered claim is supplied", func() {
+			It("should refuse to override it", func() {
+				inputMap["exp"] = 0
+				_, executeErr = jwtService.Generate(inputMap)
+				Expect(executeErr).To(MatchError("claim exp is set by the token service"))
+			})
+		})
+
 		Context("when an empty input map is supplied", func() {
 
 			JustBeforeEach(func() {
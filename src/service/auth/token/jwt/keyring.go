@@ -0,0 +1,128 @@
+package jwt
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+//ErrNoSigningKey ...
+//Returned by SigningKey before a signing key has been chosen.
+var ErrNoSigningKey = errors.New("no signing key configured")
+
+//ErrUnknownKey ...
+//Returned when a kid is not in the keyring.
+var ErrUnknownKey = errors.New("unknown key id")
+
+//Keyring ...
+//Holds every key tokens may be verified with and which one new tokens are signed with.
+//Rotation is: Add the new key (it verifies straight away), Promote it at some point in
+//the future so other services have time to pick it up, then Remove the old key once
+//the last token it signed has expired.
+type Keyring struct {
+	mutex   sync.RWMutex
+	keys    map[string]*Key
+	signing string
+	next    string
+	nextAt  time.Time
+}
+
+//NewKeyring ...
+//Creates an empty keyring.
+func NewKeyring() *Keyring {
+	return &Keyring{
+		keys: make(map[string]*Key),
+	}
+}
+
+//Add ...
+//Adds or replaces a key. The first key able to sign becomes the signing key.
+func (k *Keyring) Add(key *Key) {
+	k.mutex.Lock()
+	defer k.mutex.Unlock()
+	k.keys[key.ID] = key
+	if k.signing == "" && key.CanSign() {
+		k.signing = key.ID
+	}
+}
+
+//Remove ...
+//Drops a key so tokens signed with it no longer verify. The signing key cannot be removed.
+func (k *Keyring) Remove(kid string) error {
+	k.mutex.Lock()
+	defer k.mutex.Unlock()
+	k.promoteIfDue()
+	if kid == k.signing {
+		return fmt.Errorf("cannot remove the signing key %q", kid)
+	}
+	if kid == k.next {
+		k.next, k.nextAt = "", time.Time{}
+	}
+	delete(k.keys, kid)
+	return nil
+}
+
+//Promote ...
+//Schedules kid to become the signing key at the given time; a zero or past time promotes it now.
+func (k *Keyring) Promote(kid string, at time.Time) error {
+	k.mutex.Lock()
+	defer k.mutex.Unlock()
+	key, ok := k.keys[kid]
+	if !ok {
+		return ErrUnknownKey
+	}
+	if !key.CanSign() {
+		return fmt.Errorf("key %q has no private key to sign with", kid)
+	}
+	k.next, k.nextAt = kid, at
+	k.promoteIfDue()
+	return nil
+}
+
+//SigningKey ...
+//The key new tokens should be signed with, applying any promotion that has come due.
+func (k *Keyring) SigningKey() (*Key, error) {
+	k.mutex.Lock()
+	defer k.mutex.Unlock()
+	k.promoteIfDue()
+	if k.signing == "" {
+		return nil, ErrNoSigningKey
+	}
+	return k.keys[k.signing], nil
+}
+
+//VerificationKey ...
+//Looks up the key a token's kid header refers to.
+func (k *Keyring) VerificationKey(kid string) (*Key, error) {
+	k.mutex.RLock()
+	defer k.mutex.RUnlock()
+	key, ok := k.keys[kid]
+	if !ok {
+		return nil, ErrUnknownKey
+	}
+	return key, nil
+}
+
+//Keys ...
+//Every key tokens may currently be verified with, sorted by kid.
+func (k *Keyring) Keys() []*Key {
+	k.mutex.RLock()
+	defer k.mutex.RUnlock()
+	keys := make([]*Key, 0, len(k.keys))
+	for _, key := range k.keys {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].ID < keys[j].ID
+	})
+	return keys
+}
+
+func (k *Keyring) promoteIfDue() {
+	if k.next == "" || time.Now().Before(k.nextAt) {
+		return
+	}
+	k.signing, k.next, k.nextAt = k.next, "", time.Time{}
+}
@@ -0,0 +1,81 @@
+package jwt_test
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"service/auth/token/jwt"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Keyring Specs", func() {
+	var keyring *jwt.Keyring
+
+	newKey := func(kid string) *jwt.Key {
+		_, private, err := ed25519.GenerateKey(rand.Reader)
+		Expect(err).ToNot(HaveOccurred())
+		key, err := jwt.NewKey(kid, private)
+		Expect(err).ToNot(HaveOccurred())
+		return key
+	}
+
+	BeforeEach(func() {
+		keyring = jwt.NewKeyring()
+	})
+
+	It("should have no signing key until one is added", func() {
+		_, err := keyring.SigningKey()
+		Expect(err).To(Equal(jwt.ErrNoSigningKey))
+	})
+
+	It("should sign with the first signing capable key", func() {
+		public := newKey("public-only")
+		publicOnly, err := jwt.NewKey("public-only", public.Public)
+		Expect(err).ToNot(HaveOccurred())
+		keyring.Add(publicOnly)
+		keyring.Add(newKey("first"))
+		keyring.Add(newKey("second"))
+
+		key, err := keyring.SigningKey()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(key.ID).To(Equal("first"))
+		Expect(keyring.Keys()).To(HaveLen(3))
+	})
+
+	It("should refuse to promote keys it cannot sign with", func() {
+		public := newKey("public-only")
+		publicOnly, err := jwt.NewKey("public-only", public.Public)
+		Expect(err).ToNot(HaveOccurred())
+		keyring.Add(publicOnly)
+
+		Expect(keyring.Promote("public-only", time.Time{})).ToNot(Succeed())
+		Expect(keyring.Promote("missing", time.Time{})).To(Equal(jwt.ErrUnknownKey))
+	})
+
+	It("should promote a scheduled key once its time arrives", func() {
+		keyring.Add(newKey("current"))
+		keyring.Add(newKey("next"))
+		Expect(keyring.Promote("next", time.Now().Add(50*time.Millisecond))).To(Succeed())
+
+		key, err := keyring.SigningKey()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(key.ID).To(Equal("current"))
+
+		Eventually(func() string {
+			key, _ := keyring.SigningKey()
+			return key.ID
+		}).Should(Equal("next"))
+	})
+
+	It("should not remove the signing key", func() {
+		keyring.Add(newKey("current"))
+		keyring.Add(newKey("old"))
+		Expect(keyring.Remove("current")).ToNot(Succeed())
+		Expect(keyring.Remove("old")).To(Succeed())
+
+		_, err := keyring.VerificationKey("old")
+		Expect(err).To(Equal(jwt.ErrUnknownKey))
+	})
+})
@@ -0,0 +1,166 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	jwtGo "github.com/dgrijalva/jwt-go"
+)
+
+//ErrNoPEMKey ...
+//Returned when a PEM file holds no block we know how to turn into a key.
+var ErrNoPEMKey = errors.New("no supported PEM key found")
+
+//Key ...
+//A single entry in the keyring. Private is nil for verification only keys,
+//e.g. a retired signing key kept around until its tokens have expired.
+type Key struct {
+	ID      string
+	Method  jwtGo.SigningMethod
+	Private interface{}
+	Public  interface{}
+}
+
+//CanSign ...
+//Whether the key holds the private half and so can be used as the signing key.
+func (k *Key) CanSign() bool {
+	return k.Private != nil
+}
+
+//NewKey ...
+//Builds a key from a private key (*rsa.PrivateKey, *ecdsa.PrivateKey, ed25519.PrivateKey)
+//or a public key (*rsa.PublicKey, *ecdsa.PublicKey, ed25519.PublicKey), picking the alg from its type.
+func NewKey(kid string, key interface{}) (*Key, error) {
+	if kid == "" {
+		return nil, errors.New("a key requires a kid")
+	}
+	var private, public interface{}
+	if signer, ok := key.(crypto.Signer); ok {
+		private, public = key, signer.Public()
+	} else {
+		public = key
+	}
+	method, err := methodFor(public)
+	if err != nil {
+		return nil, err
+	}
+	return &Key{
+		ID:      kid,
+		Method:  method,
+		Private: private,
+		Public:  public,
+	}, nil
+}
+
+//NewHMACKey ...
+//Builds a shared secret HS256 key. Only useful when every verifier holds the secret.
+func NewHMACKey(kid string, secret []byte) (*Key, error) {
+	if kid == "" {
+		return nil, errors.New("a key requires a kid")
+	}
+	if len(secret) == 0 {
+		return nil, errors.New("an HMAC key requires a secret")
+	}
+	return &Key{
+		ID:      kid,
+		Method:  jwtGo.SigningMethodHS256,
+		Private: secret,
+		Public:  secret,
+	}, nil
+}
+
+func methodFor(public interface{}) (jwtGo.SigningMethod, error) {
+	switch public := public.(type) {
+	case *rsa.PublicKey:
+		return jwtGo.SigningMethodRS256, nil
+	case *ecdsa.PublicKey:
+		switch public.Curve {
+		case elliptic.P256():
+			return jwtGo.SigningMethodES256, nil
+		case elliptic.P384():
+			return jwtGo.SigningMethodES384, nil
+		case elliptic.P521():
+			return jwtGo.SigningMethodES512, nil
+		}
+		return nil, fmt.Errorf("unsupported ecdsa curve: %s", public.Curve.Params().Name)
+	case ed25519.PublicKey:
+		return EdDSA, nil
+	}
+	return nil, fmt.Errorf("unsupported key type: %T", public)
+}
+
+//ParsePEM ...
+//Parses the first key in PEM data. Accepts PKCS#8 and PKIX blocks as well as
+//the older PKCS#1 "RSA PRIVATE KEY" and SEC 1 "EC PRIVATE KEY" forms.
+func ParsePEM(kid string, data []byte) (*Key, error) {
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			return nil, ErrNoPEMKey
+		}
+		var key interface{}
+		var err error
+		switch block.Type {
+		case "PRIVATE KEY":
+			key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+		case "RSA PRIVATE KEY":
+			key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+		case "EC PRIVATE KEY":
+			key, err = x509.ParseECPrivateKey(block.Bytes)
+		case "PUBLIC KEY":
+			key, err = x509.ParsePKIXPublicKey(block.Bytes)
+		case "RSA PUBLIC KEY":
+			key, err = x509.ParsePKCS1PublicKey(block.Bytes)
+		default:
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		return NewKey(kid, key)
+	}
+}
+
+//LoadPEMFile ...
+//Reads and parses a single PEM file.
+func LoadPEMFile(kid, path string) (*Key, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	key, err := ParsePEM(kid, data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	return key, nil
+}
+
+//LoadPEMDir ...
+//Loads every *.pem file in dir, using the file name without its extension as the kid.
+func LoadPEMDir(dir string) ([]*Key, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.pem"))
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]*Key, 0, len(paths))
+	for _, path := range paths {
+		kid := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+		key, err := LoadPEMFile(kid, path)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
@@ -0,0 +1,98 @@
+package jwt_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"service/auth/token/jwt"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Key Loading Specs", func() {
+	encode := func(blockType string, der []byte) []byte {
+		return pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der})
+	}
+
+	pkcs8 := func(key interface{}) []byte {
+		der, err := x509.MarshalPKCS8PrivateKey(key)
+		Expect(err).ToNot(HaveOccurred())
+		return encode("PRIVATE KEY", der)
+	}
+
+	Context("ParsePEM", func() {
+		It("should pick the alg from the key type", func() {
+			rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+			Expect(err).ToNot(HaveOccurred())
+			ecKey, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+			Expect(err).ToNot(HaveOccurred())
+			_, edKey, err := ed25519.GenerateKey(rand.Reader)
+			Expect(err).ToNot(HaveOccurred())
+			ecDER, err := x509.MarshalECPrivateKey(ecKey)
+			Expect(err).ToNot(HaveOccurred())
+
+			cases := map[string][]byte{
+				"RS256": encode("RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(rsaKey)),
+				"ES384": encode("EC PRIVATE KEY", ecDER),
+				"EdDSA": pkcs8(edKey),
+			}
+			for alg, data := range cases {
+				key, err := jwt.ParsePEM("kid", data)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(key.Method.Alg()).To(Equal(alg))
+				Expect(key.CanSign()).To(BeTrue())
+			}
+		})
+
+		It("should load public keys as verification only", func() {
+			edPublic, _, err := ed25519.GenerateKey(rand.Reader)
+			Expect(err).ToNot(HaveOccurred())
+			der, err := x509.MarshalPKIXPublicKey(edPublic)
+			Expect(err).ToNot(HaveOccurred())
+
+			key, err := jwt.ParsePEM("kid", encode("PUBLIC KEY", der))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(key.CanSign()).To(BeFalse())
+			Expect(key.Method.Alg()).To(Equal("EdDSA"))
+		})
+
+		It("should error when there is no key", func() {
+			_, err := jwt.ParsePEM("kid", []byte("not a pem file"))
+			Expect(err).To(Equal(jwt.ErrNoPEMKey))
+		})
+	})
+
+	Context("LoadPEMDir", func() {
+		var dir string
+
+		BeforeEach(func() {
+			var err error
+			dir, err = ioutil.TempDir("", "jwt-keys")
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		AfterEach(func() {
+			Expect(os.RemoveAll(dir)).To(Succeed())
+		})
+
+		It("should name each key after its file", func() {
+			_, edKey, err := ed25519.GenerateKey(rand.Reader)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(ioutil.WriteFile(filepath.Join(dir, "2019-01.pem"), pkcs8(edKey), 0600)).To(Succeed())
+			Expect(ioutil.WriteFile(filepath.Join(dir, "README"), []byte("ignored"), 0600)).To(Succeed())
+
+			keys, err := jwt.LoadPEMDir(dir)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(keys).To(HaveLen(1))
+			Expect(keys[0].ID).To(Equal("2019-01"))
+		})
+	})
+})
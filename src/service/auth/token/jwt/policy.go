@@ -0,0 +1,52 @@
+package jwt
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	//ErrTokenExpired ... the token's exp has passed.
+	ErrTokenExpired = errors.New("token is expired")
+	//ErrTokenNotYetValid ... the token's nbf or iat is in the future.
+	ErrTokenNotYetValid = errors.New("token is not valid yet")
+	//ErrTokenTooOld ... the token was issued longer ago than the policy's MaxAge.
+	ErrTokenTooOld = errors.New("token is too old")
+	//ErrInvalidIssuer ... the token's iss is not the expected issuer.
+	ErrInvalidIssuer = errors.New("token issuer is invalid")
+	//ErrInvalidAudience ... the token's aud is not the expected audience.
+	ErrInvalidAudience = errors.New("token audience is invalid")
+)
+
+//Policy ...
+//What ValidateToken requires of a token's claims beyond a good signature.
+//Empty Issuer/Audience skip those checks, Leeway absorbs clock skew between
+//services, and a non zero MaxAge rejects tokens issued longer ago than it.
+type Policy struct {
+	Issuer   string
+	Audience string
+	Leeway   time.Duration
+	MaxAge   time.Duration
+}
+
+//Validate ...
+//Checks claims against the policy at the given time.
+func (p Policy) Validate(claims *IdentityClaims, now time.Time) error {
+	if claims.ExpiresAt == 0 || now.Add(-p.Leeway).Unix() > claims.ExpiresAt {
+		return ErrTokenExpired
+	}
+	early := now.Add(p.Leeway).Unix()
+	if claims.NotBefore > early || claims.IssuedAt > early {
+		return ErrTokenNotYetValid
+	}
+	if p.MaxAge > 0 && now.Add(-p.MaxAge-p.Leeway).Unix() > claims.IssuedAt {
+		return ErrTokenTooOld
+	}
+	if p.Issuer != "" && claims.Issuer != p.Issuer {
+		return ErrInvalidIssuer
+	}
+	if p.Audience != "" && claims.Audience != p.Audience {
+		return ErrInvalidAudience
+	}
+	return nil
+}
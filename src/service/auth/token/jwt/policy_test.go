@@ -0,0 +1,75 @@
+package jwt_test
+
+import (
+	"service/auth/token/jwt"
+	"time"
+
+	jwtGo "github.com/dgrijalva/jwt-go"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Policy Specs", func() {
+	var (
+		policy jwt.Policy
+		claims *jwt.IdentityClaims
+		now    time.Time
+	)
+
+	BeforeEach(func() {
+		now = time.Now()
+		policy = jwt.Policy{
+			Issuer:   "https://auth.example.com",
+			Audience: "events",
+			Leeway:   30 * time.Second,
+			MaxAge:   time.Hour,
+		}
+		claims = &jwt.IdentityClaims{
+			StandardClaims: jwtGo.StandardClaims{
+				Issuer:    "https://auth.example.com",
+				Audience:  "events",
+				IssuedAt:  now.Unix(),
+				NotBefore: now.Unix(),
+				ExpiresAt: now.Add(15 * time.Minute).Unix(),
+			},
+		}
+	})
+
+	It("should accept claims that meet the policy", func() {
+		Expect(policy.Validate(claims, now)).To(Succeed())
+	})
+
+	It("should allow expiry within the leeway", func() {
+		Expect(policy.Validate(claims, now.Add(15*time.Minute+20*time.Second))).To(Succeed())
+		Expect(policy.Validate(claims, now.Add(15*time.Minute+time.Minute))).To(Equal(jwt.ErrTokenExpired))
+	})
+
+	It("should require an expiry", func() {
+		claims.ExpiresAt = 0
+		Expect(policy.Validate(claims, now)).To(Equal(jwt.ErrTokenExpired))
+	})
+
+	It("should reject tokens from the future beyond the leeway", func() {
+		Expect(policy.Validate(claims, now.Add(-20*time.Second))).To(Succeed())
+		Expect(policy.Validate(claims, now.Add(-time.Minute))).To(Equal(jwt.ErrTokenNotYetValid))
+	})
+
+	It("should reject tokens older than the max age", func() {
+		claims.ExpiresAt = now.Add(3 * time.Hour).Unix()
+		Expect(policy.Validate(claims, now.Add(2*time.Hour))).To(Equal(jwt.ErrTokenTooOld))
+	})
+
+	It("should enforce issuer and audience", func() {
+		claims.Issuer = "https://evil.example.com"
+		Expect(policy.Validate(claims, now)).To(Equal(jwt.ErrInvalidIssuer))
+		claims.Issuer = policy.Issuer
+		claims.Audience = "billing"
+		Expect(policy.Validate(claims, now)).To(Equal(jwt.ErrInvalidAudience))
+	})
+
+	It("should skip checks the policy leaves empty", func() {
+		claims.Issuer, claims.Audience = "", ""
+		Expect(jwt.Policy{}.Validate(claims, now)).To(Succeed())
+	})
+})
@@ -2,49 +2,53 @@
 package databasefakes
 
 import (
+	"context"
 	"database/sql"
 	"service/database"
 	"sync"
 )
 
 type FakeDBInterface struct {
-	ExecStub        func(query string, args ...interface{}) (sql.Result, error)
-	execMutex       sync.RWMutex
-	execArgsForCall []struct {
+	ExecContextStub        func(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	execContextMutex       sync.RWMutex
+	execContextArgsForCall []struct {
+		ctx   context.Context
 		query string
 		args  []interface{}
 	}
-	execReturns struct {
+	execContextReturns struct {
 		result1 sql.Result
 		result2 error
 	}
-	execReturnsOnCall map[int]struct {
+	execContextReturnsOnCall map[int]struct {
 		result1 sql.Result
 		result2 error
 	}
-	QueryRowStub        func(query string, args ...interface{}) *sql.Row
-	queryRowMutex       sync.RWMutex
-	queryRowArgsForCall []struct {
+	QueryRowContextStub        func(ctx context.Context, query string, args ...interface{}) *sql.Row
+	queryRowContextMutex       sync.RWMutex
+	queryRowContextArgsForCall []struct {
+		ctx   context.Context
 		query string
 		args  []interface{}
 	}
-	queryRowReturns struct {
+	queryRowContextReturns struct {
 		result1 *sql.Row
 	}
-	queryRowReturnsOnCall map[int]struct {
+	queryRowContextReturnsOnCall map[int]struct {
 		result1 *sql.Row
 	}
-	QueryStub        func(query string, args ...interface{}) (*sql.Rows, error)
-	queryMutex       sync.RWMutex
-	queryArgsForCall []struct {
+	QueryContextStub        func(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	queryContextMutex       sync.RWMutex
+	queryContextArgsForCall []struct {
+		ctx   context.Context
 		query string
 		args  []interface{}
 	}
-	queryReturns struct {
+	queryContextReturns struct {
 		result1 *sql.Rows
 		result2 error
 	}
-	queryReturnsOnCall map[int]struct {
+	queryContextReturnsOnCall map[int]struct {
 		result1 *sql.Rows
 		result2 error
 	}
@@ -52,154 +56,157 @@ type FakeDBInterface struct {
 	invocationsMutex sync.RWMutex
 }
 
-func (fake *FakeDBInterface) Exec(query string, args ...interface{}) (sql.Result, error) {
-	fake.execMutex.Lock()
-	ret, specificReturn := fake.execReturnsOnCall[len(fake.execArgsForCall)]
-	fake.execArgsForCall = append(fake.execArgsForCall, struct {
+func (fake *FakeDBInterface) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	fake.execContextMutex.Lock()
+	ret, specificReturn := fake.execContextReturnsOnCall[len(fake.execContextArgsForCall)]
+	fake.execContextArgsForCall = append(fake.execContextArgsForCall, struct {
+		ctx   context.Context
 		query string
 		args  []interface{}
-	}{query, args})
-	fake.recordInvocation("Exec", []interface{}{query, args})
-	fake.execMutex.Unlock()
-	if fake.ExecStub != nil {
-		return fake.ExecStub(query, args...)
+	}{ctx, query, args})
+	fake.recordInvocation("ExecContext", []interface{}{ctx, query, args})
+	fake.execContextMutex.Unlock()
+	if fake.ExecContextStub != nil {
+		return fake.ExecContextStub(ctx, query, args...)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
 	}
-	return fake.execReturns.result1, fake.execReturns.result2
+	return fake.execContextReturns.result1, fake.execContextReturns.result2
 }
 
-func (fake *FakeDBInterface) ExecCallCount() int {
-	fake.execMutex.RLock()
-	defer fake.execMutex.RUnlock()
-	return len(fake.execArgsForCall)
+func (fake *FakeDBInterface) ExecContextCallCount() int {
+	fake.execContextMutex.RLock()
+	defer fake.execContextMutex.RUnlock()
+	return len(fake.execContextArgsForCall)
 }
 
-func (fake *FakeDBInterface) ExecArgsForCall(i int) (string, []interface{}) {
-	fake.execMutex.RLock()
-	defer fake.execMutex.RUnlock()
-	return fake.execArgsForCall[i].query, fake.execArgsForCall[i].args
+func (fake *FakeDBInterface) ExecContextArgsForCall(i int) (context.Context, string, []interface{}) {
+	fake.execContextMutex.RLock()
+	defer fake.execContextMutex.RUnlock()
+	return fake.execContextArgsForCall[i].ctx, fake.execContextArgsForCall[i].query, fake.execContextArgsForCall[i].args
 }
 
-func (fake *FakeDBInterface) ExecReturns(result1 sql.Result, result2 error) {
-	fake.ExecStub = nil
-	fake.execReturns = struct {
+func (fake *FakeDBInterface) ExecContextReturns(result1 sql.Result, result2 error) {
+	fake.ExecContextStub = nil
+	fake.execContextReturns = struct {
 		result1 sql.Result
 		result2 error
 	}{result1, result2}
 }
 
-func (fake *FakeDBInterface) ExecReturnsOnCall(i int, result1 sql.Result, result2 error) {
-	fake.ExecStub = nil
-	if fake.execReturnsOnCall == nil {
-		fake.execReturnsOnCall = make(map[int]struct {
+func (fake *FakeDBInterface) ExecContextReturnsOnCall(i int, result1 sql.Result, result2 error) {
+	fake.ExecContextStub = nil
+	if fake.execContextReturnsOnCall == nil {
+		fake.execContextReturnsOnCall = make(map[int]struct {
 			result1 sql.Result
 			result2 error
 		})
 	}
-	fake.execReturnsOnCall[i] = struct {
+	fake.execContextReturnsOnCall[i] = struct {
 		result1 sql.Result
 		result2 error
 	}{result1, result2}
 }
 
-func (fake *FakeDBInterface) QueryRow(query string, args ...interface{}) *sql.Row {
-	fake.queryRowMutex.Lock()
-	ret, specificReturn := fake.queryRowReturnsOnCall[len(fake.queryRowArgsForCall)]
-	fake.queryRowArgsForCall = append(fake.queryRowArgsForCall, struct {
+func (fake *FakeDBInterface) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	fake.queryRowContextMutex.Lock()
+	ret, specificReturn := fake.queryRowContextReturnsOnCall[len(fake.queryRowContextArgsForCall)]
+	fake.queryRowContextArgsForCall = append(fake.queryRowContextArgsForCall, struct {
+		ctx   context.Context
 		query string
 		args  []interface{}
-	}{query, args})
-	fake.recordInvocation("QueryRow", []interface{}{query, args})
-	fake.queryRowMutex.Unlock()
-	if fake.QueryRowStub != nil {
-		return fake.QueryRowStub(query, args...)
+	}{ctx, query, args})
+	fake.recordInvocation("QueryRowContext", []interface{}{ctx, query, args})
+	fake.queryRowContextMutex.Unlock()
+	if fake.QueryRowContextStub != nil {
+		return fake.QueryRowContextStub(ctx, query, args...)
 	}
 	if specificReturn {
 		return ret.result1
 	}
-	return fake.queryRowReturns.result1
+	return fake.queryRowContextReturns.result1
 }
 
-func (fake *FakeDBInterface) QueryRowCallCount() int {
-	fake.queryRowMutex.RLock()
-	defer fake.queryRowMutex.RUnlock()
-	return len(fake.queryRowArgsForCall)
+func (fake *FakeDBInterface) QueryRowContextCallCount() int {
+	fake.queryRowContextMutex.RLock()
+	defer fake.queryRowContextMutex.RUnlock()
+	return len(fake.queryRowContextArgsForCall)
 }
 
-func (fake *FakeDBInterface) QueryRowArgsForCall(i int) (string, []interface{}) {
-	fake.queryRowMutex.RLock()
-	defer fake.queryRowMutex.RUnlock()
-	return fake.queryRowArgsForCall[i].query, fake.queryRowArgsForCall[i].args
+func (fake *FakeDBInterface) QueryRowContextArgsForCall(i int) (context.Context, string, []interface{}) {
+	fake.queryRowContextMutex.RLock()
+	defer fake.queryRowContextMutex.RUnlock()
+	return fake.queryRowContextArgsForCall[i].ctx, fake.queryRowContextArgsForCall[i].query, fake.queryRowContextArgsForCall[i].args
 }
 
-func (fake *FakeDBInterface) QueryRowReturns(result1 *sql.Row) {
-	fake.QueryRowStub = nil
-	fake.queryRowReturns = struct {
+func (fake *FakeDBInterface) QueryRowContextReturns(result1 *sql.Row) {
+	fake.QueryRowContextStub = nil
+	fake.queryRowContextReturns = struct {
 		result1 *sql.Row
 	}{result1}
 }
 
-func (fake *FakeDBInterface) QueryRowReturnsOnCall(i int, result1 *sql.Row) {
-	fake.QueryRowStub = nil
-	if fake.queryRowReturnsOnCall == nil {
-		fake.queryRowReturnsOnCall = make(map[int]struct {
+func (fake *FakeDBInterface) QueryRowContextReturnsOnCall(i int, result1 *sql.Row) {
+	fake.QueryRowContextStub = nil
+	if fake.queryRowContextReturnsOnCall == nil {
+		fake.queryRowContextReturnsOnCall = make(map[int]struct {
 			result1 *sql.Row
 		})
 	}
-	fake.queryRowReturnsOnCall[i] = struct {
+	fake.queryRowContextReturnsOnCall[i] = struct {
 		result1 *sql.Row
 	}{result1}
 }
 
-func (fake *FakeDBInterface) Query(query string, args ...interface{}) (*sql.Rows, error) {
-	fake.queryMutex.Lock()
-	ret, specificReturn := fake.queryReturnsOnCall[len(fake.queryArgsForCall)]
-	fake.queryArgsForCall = append(fake.queryArgsForCall, struct {
+func (fake *FakeDBInterface) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	fake.queryContextMutex.Lock()
+	ret, specificReturn := fake.queryContextReturnsOnCall[len(fake.queryContextArgsForCall)]
+	fake.queryContextArgsForCall = append(fake.queryContextArgsForCall, struct {
+		ctx   context.Context
 		query string
 		args  []interface{}
-	}{query, args})
-	fake.recordInvocation("Query", []interface{}{query, args})
-	fake.queryMutex.Unlock()
-	if fake.QueryStub != nil {
-		return fake.QueryStub(query, args...)
+	}{ctx, query, args})
+	fake.recordInvocation("QueryContext", []interface{}{ctx, query, args})
+	fake.queryContextMutex.Unlock()
+	if fake.QueryContextStub != nil {
+		return fake.QueryContextStub(ctx, query, args...)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
 	}
-	return fake.queryReturns.result1, fake.queryReturns.result2
+	return fake.queryContextReturns.result1, fake.queryContextReturns.result2
 }
 
-func (fake *FakeDBInterface) QueryCallCount() int {
-	fake.queryMutex.RLock()
-	defer fake.queryMutex.RUnlock()
-	return len(fake.queryArgsForCall)
+func (fake *FakeDBInterface) QueryContextCallCount() int {
+	fake.queryContextMutex.RLock()
+	defer fake.queryContextMutex.RUnlock()
+	return len(fake.queryContextArgsForCall)
 }
 
-func (fake *FakeDBInterface) QueryArgsForCall(i int) (string, []interface{}) {
-	fake.queryMutex.RLock()
-	defer fake.queryMutex.RUnlock()
-	return fake.queryArgsForCall[i].query, fake.queryArgsForCall[i].args
+func (fake *FakeDBInterface) QueryContextArgsForCall(i int) (context.Context, string, []interface{}) {
+	fake.queryContextMutex.RLock()
+	defer fake.queryContextMutex.RUnlock()
+	return fake.queryContextArgsForCall[i].ctx, fake.queryContextArgsForCall[i].query, fake.queryContextArgsForCall[i].args
 }
 
-func (fake *FakeDBInterface) QueryReturns(result1 *sql.Rows, result2 error) {
-	fake.QueryStub = nil
-	fake.queryReturns = struct {
+func (fake *FakeDBInterface) QueryContextReturns(result1 *sql.Rows, result2 error) {
+	fake.QueryContextStub = nil
+	fake.queryContextReturns = struct {
 		result1 *sql.Rows
 		result2 error
 	}{result1, result2}
 }
 
-func (fake *FakeDBInterface) QueryReturnsOnCall(i int, result1 *sql.Rows, result2 error) {
-	fake.QueryStub = nil
-	if fake.queryReturnsOnCall == nil {
-		fake.queryReturnsOnCall = make(map[int]struct {
+func (fake *FakeDBInterface) QueryContextReturnsOnCall(i int, result1 *sql.Rows, result2 error) {
+	fake.QueryContextStub = nil
+	if fake.queryContextReturnsOnCall == nil {
+		fake.queryContextReturnsOnCall = make(map[int]struct {
 			result1 *sql.Rows
 			result2 error
 		})
 	}
-	fake.queryReturnsOnCall[i] = struct {
+	fake.queryContextReturnsOnCall[i] = struct {
 		result1 *sql.Rows
 		result2 error
 	}{result1, result2}
@@ -208,12 +215,12 @@ func (fake *FakeDBInterface) QueryReturnsOnCall(i int, result1 *sql.Rows, result
 func (fake *FakeDBInterface) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
-	fake.execMutex.RLock()
-	defer fake.execMutex.RUnlock()
-	fake.queryRowMutex.RLock()
-	defer fake.queryRowMutex.RUnlock()
-	fake.queryMutex.RLock()
-	defer fake.queryMutex.RUnlock()
+	fake.execContextMutex.RLock()
+	defer fake.execContextMutex.RUnlock()
+	fake.queryRowContextMutex.RLock()
+	defer fake.queryRowContextMutex.RUnlock()
+	fake.queryContextMutex.RLock()
+	defer fake.queryContextMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
 	for key, value := range fake.invocations {
 		copiedInvocations[key] = value
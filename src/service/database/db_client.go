@@ -1,13 +1,17 @@
 package database
 
-import "database/sql"
+import (
+	"context"
+	"database/sql"
+)
 
-//DBInterface declares an interface that adheres with the sql lib definition
+//DBInterface declares an interface that adheres with the sql lib definition. Every call
+//takes a context so a cancelled request or an expired deadline stops its database work.
 //go:generate counterfeiter . DBInterface
 type DBInterface interface {
-	Exec(query string, args ...interface{}) (sql.Result, error)
-	QueryRow(query string, args ...interface{}) *sql.Row
-	Query(query string, args ...interface{}) (*sql.Rows, error)
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
 }
 
 //Client defines an object that binds methods using a passed in DBInterface
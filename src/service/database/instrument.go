@@ -0,0 +1,236 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"service/log"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+//DefaultSlowQuery is the latency from which a statement is logged unless SlowQuery says otherwise.
+const DefaultSlowQuery = 200 * time.Millisecond
+
+//LatencyBuckets are the upper bounds of the latency histogram kept for every statement. Slower
+//statements fall in one last, unbounded bucket.
+var LatencyBuckets = []time.Duration{
+	time.Millisecond, 5 * time.Millisecond, 10 * time.Millisecond, 25 * time.Millisecond,
+	50 * time.Millisecond, 100 * time.Millisecond, 250 * time.Millisecond,
+	500 * time.Millisecond, time.Second, 5 * time.Second,
+}
+
+//StatementStats ... is a snapshot of the metrics of one statement. Buckets counts executions by
+//LatencyBuckets, with one more bucket for anything slower.
+type StatementStats struct {
+	Query   string        `json:"query"`
+	Count   int64         `json:"count"`
+	Errors  int64         `json:"errors"`
+	Total   time.Duration `json:"totalNanos"`
+	Buckets []int64       `json:"buckets"`
+}
+
+type metrics struct {
+	mu         sync.Mutex
+	statements map[string]*StatementStats
+}
+
+//Instrumented is a DBInterface that times every statement of the client it wraps. It keeps a
+//latency histogram and error count per statement, adds to the totals of a context from
+//TrackQueries, and logs statements slower than SlowQuery with sanitized arguments.
+//Latency is measured until the statement returns, rows read later are not counted.
+type Instrumented struct {
+	SlowQuery time.Duration
+	//RequestID names the request of a slow statement in the log, see request.RetreiveRequestID.
+	RequestID func(ctx context.Context) string
+
+	db      DBInterface
+	log     log.ProdInterface
+	metrics *metrics
+}
+
+//NewInstrumented wraps db, logging statements slower than DefaultSlowQuery to logClient.
+func NewInstrumented(logClient log.ProdInterface, db DBInterface) *Instrumented {
+	return &Instrumented{
+		SlowQuery: DefaultSlowQuery,
+		db:        db,
+		log:       logClient,
+		metrics:   &metrics{statements: map[string]*StatementStats{}},
+	}
+}
+
+//ExecContext times an Exec on the wrapped client.
+func (i *Instrumented) ExecContext(ctx context.Context, query string,
+	args ...interface{}) (sql.Result, error) {
+	start := time.Now()
+	result, err := i.db.ExecContext(ctx, query, args...)
+	i.observe(ctx, query, args, start, err)
+	return result, err
+}
+
+//QueryRowContext times a QueryRow on the wrapped client. sql.ErrNoRows is not an error.
+func (i *Instrumented) QueryRowContext(ctx context.Context, query string,
+	args ...interface{}) *sql.Row {
+	start := time.Now()
+	row := i.db.QueryRowContext(ctx, query, args...)
+	var err error
+	if row != nil && row.Err() != sql.ErrNoRows {
+		err = row.Err()
+	}
+	i.observe(ctx, query, args, start, err)
+	return row
+}
+
+//QueryContext times a Query on the wrapped client.
+func (i *Instrumented) QueryContext(ctx context.Context, query string,
+	args ...interface{}) (*sql.Rows, error) {
+	start := time.Now()
+	rows, err := i.db.QueryContext(ctx, query, args...)
+	i.observe(ctx, query, args, start, err)
+	return rows, err
+}
+
+//BeginTx opens a transaction on the wrapped client. Runner times its statements through WrapTx.
+func (i *Instrumented) BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error) {
+	beginner, ok := i.db.(Beginner)
+	if !ok {
+		return nil, ErrNoTransactions
+	}
+	return beginner.BeginTx(ctx, opts)
+}
+
+//WrapTx returns a DBInterface that times the statements of tx into the metrics of i. It cannot
+//begin a transaction of its own, so a nested InTx joins tx.
+func (i *Instrumented) WrapTx(tx DBInterface) DBInterface {
+	wrapped := *i
+	wrapped.db = tx
+	return &instrumentedTx{instrumented: &wrapped}
+}
+
+type instrumentedTx struct {
+	instrumented *Instrumented
+}
+
+func (t *instrumentedTx) scopedToTx() {}
+
+func (t *instrumentedTx) ExecContext(ctx context.Context, query string,
+	args ...interface{}) (sql.Result, error) {
+	return t.instrumented.ExecContext(ctx, query, args...)
+}
+
+func (t *instrumentedTx) QueryRowContext(ctx context.Context, query string,
+	args ...interface{}) *sql.Row {
+	return t.instrumented.QueryRowContext(ctx, query, args...)
+}
+
+func (t *instrumentedTx) QueryContext(ctx context.Context, query string,
+	args ...interface{}) (*sql.Rows, error) {
+	return t.instrumented.QueryContext(ctx, query, args...)
+}
+
+func (i *Instrumented) observe(ctx context.Context, query string, args []interface{},
+	start time.Time, err error) {
+	elapsed := time.Since(start)
+	statement := strings.Join(strings.Fields(query), " ")
+	i.metrics.record(statement, elapsed, err)
+	if totals, ok := ctx.Value(queryTotalsKey{}).(*queryTotals); ok {
+		atomic.AddInt64(&totals.count, 1)
+		atomic.AddInt64(&totals.elapsed, int64(elapsed))
+	}
+	if i.SlowQuery <= 0 || elapsed < i.SlowQuery {
+		return
+	}
+	requestID := ""
+	if i.RequestID != nil {
+		requestID = i.RequestID(ctx)
+	}
+	i.log.Warn("database: slow query",
+		zap.String("requestID", requestID),
+		zap.String("query", statement),
+		zap.Strings("args", SanitizeArgs(args)),
+		zap.String("in", elapsed.String()),
+		zap.Error(err))
+}
+
+func (m *metrics) record(statement string, elapsed time.Duration, err error) {
+	bucket := sort.Search(len(LatencyBuckets), func(b int) bool {
+		return elapsed <= LatencyBuckets[b]
+	})
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	stats, ok := m.statements[statement]
+	if !ok {
+		stats = &StatementStats{Query: statement, Buckets: make([]int64, len(LatencyBuckets)+1)}
+		m.statements[statement] = stats
+	}
+	stats.Count++
+	stats.Total += elapsed
+	stats.Buckets[bucket]++
+	if err != nil {
+		stats.Errors++
+	}
+}
+
+//Stats returns a snapshot of every statement run so far, ordered by query.
+func (i *Instrumented) Stats() []StatementStats {
+	i.metrics.mu.Lock()
+	defer i.metrics.mu.Unlock()
+	snapshot := make([]StatementStats, 0, len(i.metrics.statements))
+	for _, stats := range i.metrics.statements {
+		copied := *stats
+		copied.Buckets = append([]int64(nil), stats.Buckets...)
+		snapshot = append(snapshot, copied)
+	}
+	sort.Slice(snapshot, func(a, b int) bool { return snapshot[a].Query < snapshot[b].Query })
+	return snapshot
+}
+
+//SanitizeArgs describes statement arguments for a log line without their values, which may be
+//passwords, tokens or personal data. Numbers, booleans and times are kept as they are.
+func SanitizeArgs(args []interface{}) []string {
+	sanitized := make([]string, len(args))
+	for n, arg := range args {
+		switch v := arg.(type) {
+		case nil:
+			sanitized[n] = "NULL"
+		case string:
+			sanitized[n] = fmt.Sprintf("string(%d)", len(v))
+		case []byte:
+			sanitized[n] = fmt.Sprintf("bytes(%d)", len(v))
+		case bool, int, int32, int64, uint, uint32, uint64, float32, float64:
+			sanitized[n] = fmt.Sprint(v)
+		case time.Time:
+			sanitized[n] = v.Format(time.RFC3339Nano)
+		default:
+			sanitized[n] = fmt.Sprintf("%T", v)
+		}
+	}
+	return sanitized
+}
+
+type queryTotalsKey struct{}
+
+type queryTotals struct {
+	count   int64
+	elapsed int64
+}
+
+//TrackQueries returns a context that totals the statements an Instrumented runs with it.
+func TrackQueries(ctx context.Context) context.Context {
+	return context.WithValue(ctx, queryTotalsKey{}, &queryTotals{})
+}
+
+//QueryTotals returns how many statements ran with a context from TrackQueries and their total
+//time.
+func QueryTotals(ctx context.Context) (int64, time.Duration) {
+	totals, ok := ctx.Value(queryTotalsKey{}).(*queryTotals)
+	if !ok {
+		return 0, 0
+	}
+	return atomic.LoadInt64(&totals.count), time.Duration(atomic.LoadInt64(&totals.elapsed))
+}
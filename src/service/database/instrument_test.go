@@ -0,0 +1,161 @@
+package database_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"service/database"
+	"service/database/databasefakes"
+	"service/log/logfakes"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"go.uber.org/zap/zapcore"
+	sqlmock "gopkg.in/DATA-DOG/go-sqlmock.v1"
+)
+
+var _ = Describe("Instrumented Specs", func() {
+	var (
+		ctx          context.Context
+		fakeLog      *logfakes.FakeProdInterface
+		mockDB       sqlmock.Sqlmock
+		instrumented *database.Instrumented
+	)
+
+	fieldsOf := func(fields []zapcore.Field) map[string]interface{} {
+		encoder := zapcore.NewMapObjectEncoder()
+		for _, field := range fields {
+			field.AddTo(encoder)
+		}
+		return encoder.Fields
+	}
+
+	BeforeEach(func() {
+		ctx = database.TrackQueries(context.Background())
+		fakeLog = &logfakes.FakeProdInterface{}
+		db, mock, sqlmockErr := sqlmock.New()
+		Expect(sqlmockErr).ToNot(HaveOccurred())
+		mockDB = mock
+		instrumented = database.NewInstrumented(fakeLog, db)
+		instrumented.RequestID = func(context.Context) string { return "request-1" }
+	})
+
+	AfterEach(func() {
+		Expect(mockDB.ExpectationsWereMet()).To(Succeed())
+	})
+
+	Context("when statements run", func() {
+		It("should count them and their errors per statement", func() {
+			mockDB.ExpectExec("UPDATE thing").WillReturnResult(sqlmock.NewResult(0, 1))
+			mockDB.ExpectExec("UPDATE thing").WillReturnError(sql.ErrConnDone)
+			mockDB.ExpectQuery("SELECT id FROM thing").WillReturnRows(sqlmock.NewRows([]string{"id"}))
+			mockDB.ExpectQuery("SELECT id FROM thing").WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+			_, err := instrumented.ExecContext(ctx, "UPDATE thing\n\tSET id = $1;", 2)
+			Expect(err).ToNot(HaveOccurred())
+			_, err = instrumented.ExecContext(ctx, "UPDATE thing\n\tSET id = $1;", 2)
+			Expect(err).To(Equal(sql.ErrConnDone))
+			var id int
+			Expect(instrumented.QueryRowContext(ctx, "SELECT id FROM thing;").Scan(&id)).
+				To(Equal(sql.ErrNoRows))
+			rows, err := instrumented.QueryContext(ctx, "SELECT id FROM thing;")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(rows.Close()).To(Succeed())
+
+			stats := instrumented.Stats()
+			Expect(stats).To(HaveLen(2))
+			Expect(stats[0].Query).To(Equal("SELECT id FROM thing;"))
+			Expect(stats[0].Count).To(Equal(int64(2)))
+			Expect(stats[0].Errors).To(Equal(int64(0)))
+			Expect(stats[1].Query).To(Equal("UPDATE thing SET id = $1;"))
+			Expect(stats[1].Count).To(Equal(int64(2)))
+			Expect(stats[1].Errors).To(Equal(int64(1)))
+			Expect(stats[1].Buckets).To(HaveLen(len(database.LatencyBuckets) + 1))
+			Expect(stats[1].Buckets[0]).To(Equal(int64(2)))
+		})
+
+		It("should total them on the context", func() {
+			mockDB.ExpectExec("UPDATE thing").WillReturnResult(sqlmock.NewResult(0, 1))
+			mockDB.ExpectExec("UPDATE thing").WillDelayFor(5 * time.Millisecond).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+
+			_, _ = instrumented.ExecContext(ctx, "UPDATE thing SET id = 2;")
+			_, _ = instrumented.ExecContext(ctx, "UPDATE thing SET id = 3;")
+
+			queries, elapsed := database.QueryTotals(ctx)
+			Expect(queries).To(Equal(int64(2)))
+			Expect(elapsed).To(BeNumerically(">=", 5*time.Millisecond))
+
+			queries, _ = database.QueryTotals(context.Background())
+			Expect(queries).To(BeZero())
+		})
+
+		It("should time the statements of a transaction into the same metrics", func() {
+			mockDB.ExpectBegin()
+			mockDB.ExpectExec("UPDATE thing").WillReturnResult(sqlmock.NewResult(0, 1))
+			mockDB.ExpectCommit()
+
+			Expect(database.InTx(ctx, instrumented, func(tx database.DBInterface) error {
+				Expect(database.InTx(ctx, tx, func(nested database.DBInterface) error {
+					Expect(nested).To(BeIdenticalTo(tx))
+					return nil
+				})).To(Succeed())
+				_, err := tx.ExecContext(ctx, "UPDATE thing SET id = 2;")
+				return err
+			})).To(Succeed())
+
+			Expect(instrumented.Stats()).To(HaveLen(1))
+			queries, _ := database.QueryTotals(ctx)
+			Expect(queries).To(Equal(int64(1)))
+		})
+
+		It("should refuse transactions when the client cannot begin one", func() {
+			fake := database.NewInstrumented(fakeLog, &databasefakes.FakeDBInterface{})
+			_, err := fake.BeginTx(ctx, nil)
+			Expect(err).To(Equal(database.ErrNoTransactions))
+		})
+	})
+
+	Context("when a statement is slow", func() {
+		It("should log it with the request ID and sanitized arguments", func() {
+			instrumented.SlowQuery = time.Millisecond
+			mockDB.ExpectExec("UPDATE identity").WillDelayFor(5 * time.Millisecond).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+
+			_, err := instrumented.ExecContext(ctx,
+				"UPDATE identity SET password_hash = $2 WHERE id = $1;",
+				"uuidv4", []byte("secret hash"))
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(fakeLog.WarnCallCount()).To(Equal(1))
+			msg, fields := fakeLog.WarnArgsForCall(0)
+			Expect(msg).To(Equal("database: slow query"))
+			logged := fieldsOf(fields)
+			Expect(logged["requestID"]).To(Equal("request-1"))
+			Expect(logged["query"]).To(HavePrefix("UPDATE identity"))
+			Expect(logged["args"]).To(ConsistOf("string(6)", "bytes(11)"))
+		})
+
+		It("should not log fast statements or when the log is off", func() {
+			mockDB.ExpectExec("UPDATE thing").WillReturnResult(sqlmock.NewResult(0, 1))
+			mockDB.ExpectExec("UPDATE thing").WillDelayFor(5 * time.Millisecond).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+
+			_, _ = instrumented.ExecContext(ctx, "UPDATE thing SET id = 2;")
+			instrumented.SlowQuery = 0
+			_, _ = instrumented.ExecContext(ctx, "UPDATE thing SET id = 2;")
+
+			Expect(fakeLog.WarnCallCount()).To(BeZero())
+		})
+	})
+
+	Context("SanitizeArgs", func() {
+		It("should keep numbers, booleans and times but hide text", func() {
+			at := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+			Expect(database.SanitizeArgs([]interface{}{nil, "hunter2", []byte("ab"), 42, true,
+				at, errors.New("x")})).To(Equal([]string{"NULL", "string(7)", "bytes(2)", "42",
+				"true", "2020-01-02T03:04:05Z", "*errors.errorString"}))
+		})
+	})
+})
@@ -0,0 +1,151 @@
+package database
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+//Tag is the struct tag that maps a field to a column, as in `pq:"first_name"`. A field tagged
+//`pq:"profile,json"` holds JSON; interface{} and map fields are treated as JSON without the option.
+//Fields without the tag, or tagged "-", are not mapped.
+const Tag = "pq"
+
+//ErrNotStruct is returned when a mapping function is given something other than a struct.
+var ErrNotStruct = errors.New("database: mapping needs a struct or a pointer to one")
+
+//RowScanner is implemented by *sql.Row and *sql.Rows.
+type RowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+type field struct {
+	column string
+	index  int
+	json   bool
+}
+
+var fieldCache sync.Map
+
+//fields returns the mapped fields of t in declaration order, reflecting on each type only once.
+func fields(t reflect.Type) []field {
+	if cached, ok := fieldCache.Load(t); ok {
+		return cached.([]field)
+	}
+	mapped := make([]field, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		structField := t.Field(i)
+		tag, ok := structField.Tag.Lookup(Tag)
+		if !ok || tag == "-" || structField.PkgPath != "" {
+			continue
+		}
+		parts := strings.Split(tag, ",")
+		kind := structField.Type.Kind()
+		mapped = append(mapped, field{
+			column: parts[0],
+			index:  i,
+			json:   hasOption(parts[1:], "json") || kind == reflect.Interface || kind == reflect.Map,
+		})
+	}
+	fieldCache.Store(t, mapped)
+	return mapped
+}
+
+func hasOption(options []string, option string) bool {
+	for _, o := range options {
+		if o == option {
+			return true
+		}
+	}
+	return false
+}
+
+func structType(v interface{}) (reflect.Type, error) {
+	t := reflect.TypeOf(v)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil, ErrNotStruct
+	}
+	return t, nil
+}
+
+//Columns returns the column names mapped by the struct v, in field order. It panics when v is not
+//a struct, as that is a programming error.
+func Columns(v interface{}) []string {
+	t, err := structType(v)
+	if err != nil {
+		panic(err)
+	}
+	mapped := fields(t)
+	columns := make([]string, len(mapped))
+	for i, f := range mapped {
+		columns[i] = f.column
+	}
+	return columns
+}
+
+//ColumnList returns Columns(v) joined for use in a SELECT or RETURNING clause.
+func ColumnList(v interface{}) string {
+	return strings.Join(Columns(v), ", ")
+}
+
+//ScanRow scans the current row into dest, a pointer to a struct. The query must select
+//ColumnList(dest) in that order. JSON columns are decoded into their field; NULL leaves it zero.
+func ScanRow(row RowScanner, dest interface{}) error {
+	value := reflect.ValueOf(dest)
+	if value.Kind() != reflect.Ptr || value.IsNil() || value.Elem().Kind() != reflect.Struct {
+		return ErrNotStruct
+	}
+	target := value.Elem()
+	mapped := fields(target.Type())
+	targets := make([]interface{}, len(mapped))
+	raw := make([][]byte, len(mapped))
+	for i, f := range mapped {
+		if f.json {
+			targets[i] = &raw[i]
+			continue
+		}
+		targets[i] = target.Field(f.index).Addr().Interface()
+	}
+	if err := row.Scan(targets...); err != nil {
+		return err
+	}
+	for i, f := range mapped {
+		if !f.json || len(raw[i]) == 0 {
+			continue
+		}
+		if err := json.Unmarshal(raw[i], target.Field(f.index).Addr().Interface()); err != nil {
+			return fmt.Errorf("database: decoding column %s: %w", f.column, err)
+		}
+	}
+	return nil
+}
+
+//ScanAll scans every remaining row into dest, a pointer to a slice of structs, and closes rows.
+func ScanAll(rows *sql.Rows, dest interface{}) (err error) {
+	defer func() {
+		if closeErr := rows.Close(); err == nil {
+			err = closeErr
+		}
+	}()
+	slice := reflect.ValueOf(dest)
+	if slice.Kind() != reflect.Ptr || slice.Elem().Kind() != reflect.Slice ||
+		slice.Elem().Type().Elem().Kind() != reflect.Struct {
+		return ErrNotStruct
+	}
+	slice = slice.Elem()
+	for rows.Next() {
+		item := reflect.New(slice.Type().Elem())
+		if err := ScanRow(rows, item.Interface()); err != nil {
+			return err
+		}
+		slice.Set(reflect.Append(slice, item.Elem()))
+	}
+	return rows.Err()
+}
@@ -0,0 +1,105 @@
+package database_test
+
+import (
+	"service/database"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	sqlmock "gopkg.in/DATA-DOG/go-sqlmock.v1"
+)
+
+type settings struct {
+	Theme string `json:"theme"`
+}
+
+type account struct {
+	ID        string      `pq:"id"`
+	Name      string      `pq:"name"`
+	Profile   interface{} `pq:"profile"`
+	Settings  settings    `pq:"settings,json"`
+	Tags      []string    `pq:"tags,json"`
+	DeletedAt *time.Time  `pq:"deleted_at"`
+	Transient string      `pq:"-"`
+	Untagged  string
+}
+
+var _ = Describe("Mapping Specs", func() {
+	columns := []string{"id", "name", "profile", "settings", "tags", "deleted_at"}
+
+	Context("Columns", func() {
+		It("should list the tagged fields in declaration order", func() {
+			Expect(database.Columns(account{})).To(Equal(columns))
+			Expect(database.Columns(&account{})).To(Equal(columns))
+			Expect(database.ColumnList(account{})).
+				To(Equal("id, name, profile, settings, tags, deleted_at"))
+		})
+
+		It("should panic on anything but a struct", func() {
+			var recovered interface{}
+			columns := func() {
+				defer func() {
+					recovered = recover()
+					panic(recovered)
+				}()
+				database.Columns("id")
+			}
+			Expect(columns).To(Panic())
+			Expect(recovered).To(Equal(database.ErrNotStruct))
+		})
+	})
+
+	Context("ScanRow and ScanAll", func() {
+		query := func() *sqlmock.Rows {
+			deletedAt := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+			return sqlmock.NewRows(columns).
+				AddRow("1", "ada", []byte(`{"email":"ada@example.com"}`),
+					[]byte(`{"theme":"dark"}`), []byte(`["a","b"]`), deletedAt).
+				AddRow("2", "bob", nil, nil, nil, nil)
+		}
+
+		It("should scan plain and JSON columns into a struct", func() {
+			db, mock, _ := sqlmock.New()
+			mock.ExpectQuery("SELECT").WillReturnRows(query())
+
+			var scanned account
+			Expect(database.ScanRow(db.QueryRow("SELECT 1;"), &scanned)).To(Succeed())
+			Expect(scanned.ID).To(Equal("1"))
+			Expect(scanned.Name).To(Equal("ada"))
+			Expect(scanned.Profile).To(Equal(map[string]interface{}{"email": "ada@example.com"}))
+			Expect(scanned.Settings).To(Equal(settings{Theme: "dark"}))
+			Expect(scanned.Tags).To(Equal([]string{"a", "b"}))
+			Expect(scanned.DeletedAt).ToNot(BeNil())
+		})
+
+		It("should scan every row into a slice and leave NULL columns zero", func() {
+			db, mock, _ := sqlmock.New()
+			mock.ExpectQuery("SELECT").WillReturnRows(query())
+			rows, err := db.Query("SELECT 1;")
+			Expect(err).ToNot(HaveOccurred())
+
+			var scanned []account
+			Expect(database.ScanAll(rows, &scanned)).To(Succeed())
+			Expect(scanned).To(HaveLen(2))
+			Expect(scanned[1].ID).To(Equal("2"))
+			Expect(scanned[1].Profile).To(BeNil())
+			Expect(scanned[1].Settings).To(Equal(settings{}))
+			Expect(scanned[1].DeletedAt).To(BeNil())
+		})
+
+		It("should report malformed JSON with its column", func() {
+			db, mock, _ := sqlmock.New()
+			mock.ExpectQuery("SELECT").WillReturnRows(sqlmock.NewRows(columns).
+				AddRow("1", "ada", nil, []byte(`{"theme":`), nil, nil))
+
+			var scanned account
+			err := database.ScanRow(db.QueryRow("SELECT 1;"), &scanned)
+			Expect(err).To(MatchError(ContainSubstring("settings")))
+		})
+
+		It("should reject a destination that is not a struct pointer", func() {
+			var scanned account
+			Expect(database.ScanRow(nil, scanned)).To(Equal(database.ErrNotStruct))
+		})
+	})
+})
@@ -0,0 +1,166 @@
+package migrate
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+)
+
+//DefaultDir ... is where create writes new migrations, relative to src/service.
+const DefaultDir = "database/migrate/sql"
+
+//Usage ... describes the migrate subcommands.
+const Usage = "usage: migrate up | down [steps] | status | create <name>"
+
+//ErrUsage ... is returned for an unknown subcommand or bad arguments.
+var ErrUsage = errors.New(Usage)
+
+//migrationName is what create accepts once spaces and dashes became underscores.
+var migrationName = regexp.MustCompile(`^[a-z0-9_]+$`)
+
+//Command ... runs migrate subcommands. Open is only called by subcommands that need the
+//database, so create works without one.
+type Command struct {
+	Dir  string
+	Out  io.Writer
+	Open func() (*Migrator, error)
+}
+
+//Run ... runs the subcommand named by args[0] with the rest as its arguments.
+func (c *Command) Run(args []string) error {
+	if len(args) == 0 {
+		return ErrUsage
+	}
+	switch args[0] {
+	case "up":
+		return c.up(args[1:])
+	case "down":
+		return c.down(args[1:])
+	case "status":
+		return c.status(args[1:])
+	case "create":
+		return c.create(args[1:])
+	}
+	return ErrUsage
+}
+
+func (c *Command) up(args []string) error {
+	if len(args) != 0 {
+		return ErrUsage
+	}
+	migrator, err := c.Open()
+	if err != nil {
+		return err
+	}
+	applied, err := migrator.Up()
+	for _, migration := range applied {
+		fmt.Fprintf(c.Out, "applied %04d_%s\n", migration.Version, migration.Name)
+	}
+	if err == nil && len(applied) == 0 {
+		fmt.Fprintln(c.Out, "no pending migrations")
+	}
+	return err
+}
+
+func (c *Command) down(args []string) error {
+	steps := 1
+	if len(args) > 1 {
+		return ErrUsage
+	}
+	if len(args) == 1 {
+		parsed, err := strconv.Atoi(args[0])
+		if err != nil || parsed < 1 {
+			return ErrUsage
+		}
+		steps = parsed
+	}
+	migrator, err := c.Open()
+	if err != nil {
+		return err
+	}
+	reverted, err := migrator.Down(steps)
+	for _, migration := range reverted {
+		fmt.Fprintf(c.Out, "reverted %04d_%s\n", migration.Version, migration.Name)
+	}
+	if err == nil && len(reverted) == 0 {
+		fmt.Fprintln(c.Out, "no applied migrations")
+	}
+	return err
+}
+
+func (c *Command) status(args []string) error {
+	if len(args) != 0 {
+		return ErrUsage
+	}
+	migrator, err := c.Open()
+	if err != nil {
+		return err
+	}
+	statuses, err := migrator.Status()
+	if err != nil {
+		return err
+	}
+	table := tabwriter.NewWriter(c.Out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "VERSION\tNAME\tSTATE\tAPPLIED AT")
+	for _, status := range statuses {
+		state, appliedAt := "pending", ""
+		if status.AppliedAt != nil {
+			state, appliedAt = "applied", status.AppliedAt.Format("2006-01-02 15:04:05 MST")
+		}
+		if status.Modified {
+			state = "modified"
+		}
+		if status.Unknown {
+			state = "unknown"
+		}
+		fmt.Fprintf(table, "%04d\t%s\t%s\t%s\n", status.Version, status.Name, state, appliedAt)
+	}
+	return table.Flush()
+}
+
+func (c *Command) create(args []string) error {
+	if len(args) != 1 {
+		return ErrUsage
+	}
+	up, down, err := Create(c.Dir, args[0])
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(c.Out, "created %s\ncreated %s\n", up, down)
+	return nil
+}
+
+//Create ...
+//writes empty up and down files for the version after the newest one in dir and returns
+//their paths. The name is lower cased with spaces and dashes turned into underscores.
+func Create(dir, name string) (string, string, error) {
+	name = strings.NewReplacer(" ", "_", "-", "_").Replace(strings.ToLower(strings.TrimSpace(name)))
+	if !migrationName.MatchString(name) {
+		return "", "", fmt.Errorf("migration name %q may only hold letters, digits and underscores",
+			name)
+	}
+	migrations, err := Load(os.DirFS(dir))
+	if err != nil {
+		return "", "", err
+	}
+	var version int64 = 1
+	if len(migrations) > 0 {
+		version = migrations[len(migrations)-1].Version + 1
+	}
+	base := filepath.Join(dir, fmt.Sprintf("%04d_%s", version, name))
+	up, down := base+".up.sql", base+".down.sql"
+	for _, path := range []string{up, down} {
+		header := fmt.Sprintf("-- %04d_%s\n", version, name)
+		if err := ioutil.WriteFile(path, []byte(header), 0644); err != nil {
+			return "", "", err
+		}
+	}
+	return up, down, nil
+}
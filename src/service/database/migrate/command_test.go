@@ -0,0 +1,71 @@
+package migrate_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"service/database/migrate"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Command Specs", func() {
+	var (
+		dir     string
+		out     *bytes.Buffer
+		command *migrate.Command
+		opened  bool
+	)
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "migrations")
+		Expect(err).ToNot(HaveOccurred())
+		out = &bytes.Buffer{}
+		opened = false
+		command = &migrate.Command{
+			Dir: dir,
+			Out: out,
+			Open: func() (*migrate.Migrator, error) {
+				opened = true
+				return nil, os.ErrNotExist
+			},
+		}
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(dir)).To(Succeed())
+	})
+
+	It("should create the first migration without a database", func() {
+		Expect(command.Run([]string{"create", "Add widgets"})).To(Succeed())
+		Expect(opened).To(BeFalse())
+		Expect(filepath.Join(dir, "0001_add_widgets.up.sql")).To(BeARegularFile())
+		Expect(filepath.Join(dir, "0001_add_widgets.down.sql")).To(BeARegularFile())
+	})
+
+	It("should number a new migration after the newest one", func() {
+		Expect(ioutil.WriteFile(filepath.Join(dir, "0007_add_widgets.up.sql"),
+			[]byte("CREATE TABLE widget ();"), 0644)).To(Succeed())
+		Expect(command.Run([]string{"create", "add-gadgets"})).To(Succeed())
+		Expect(out.String()).To(ContainSubstring("0008_add_gadgets.up.sql"))
+	})
+
+	It("should refuse names that aren't identifiers", func() {
+		Expect(command.Run([]string{"create", "../escape"})).ToNot(Succeed())
+	})
+
+	It("should refuse unknown subcommands and bad arguments", func() {
+		Expect(command.Run(nil)).To(Equal(migrate.ErrUsage))
+		Expect(command.Run([]string{"sideways"})).To(Equal(migrate.ErrUsage))
+		Expect(command.Run([]string{"down", "zero"})).To(Equal(migrate.ErrUsage))
+		Expect(opened).To(BeFalse())
+	})
+
+	It("should open the database for up", func() {
+		Expect(command.Run([]string{"up"})).To(Equal(os.ErrNotExist))
+		Expect(opened).To(BeTrue())
+	})
+})
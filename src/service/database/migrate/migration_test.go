@@ -0,0 +1,85 @@
+package migrate_test
+
+import (
+	"regexp"
+	"service/database/migrate"
+	"strings"
+	"testing/fstest"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Migration Specs", func() {
+	Context("Load", func() {
+		It("should pair up and down files in version order", func() {
+			migrations, err := migrate.Load(fstest.MapFS{
+				"0002_add_index.up.sql":      {Data: []byte("CREATE INDEX;")},
+				"0001_create_table.up.sql":   {Data: []byte("CREATE TABLE;")},
+				"0001_create_table.down.sql": {Data: []byte("DROP TABLE;")},
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(migrations).To(HaveLen(2))
+			Expect(migrations[0].Version).To(Equal(int64(1)))
+			Expect(migrations[0].Name).To(Equal("create_table"))
+			Expect(migrations[0].Down).To(Equal("DROP TABLE;"))
+			Expect(migrations[0].Checksum).To(HaveLen(64))
+			Expect(migrations[1].Down).To(BeEmpty())
+		})
+
+		It("should refuse a migration without an up file", func() {
+			_, err := migrate.Load(fstest.MapFS{"0001_create_table.down.sql": {Data: []byte("DROP;")}})
+			Expect(err).To(MatchError(ContainSubstring("has no up file")))
+		})
+
+		It("should refuse two names for one version", func() {
+			_, err := migrate.Load(fstest.MapFS{
+				"0001_create_table.up.sql": {Data: []byte("CREATE TABLE;")},
+				"0001_create_index.up.sql": {Data: []byte("CREATE INDEX;")},
+			})
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should refuse badly named files", func() {
+			_, err := migrate.Load(fstest.MapFS{"create_table.sql": {Data: []byte("CREATE TABLE;")}})
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("Embedded", func() {
+		var migrations []migrate.Migration
+
+		BeforeEach(func() {
+			var err error
+			migrations, err = migrate.Embedded()
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should be reversible", func() {
+			for _, migration := range migrations {
+				Expect(migration.Down).ToNot(BeEmpty(), migration.Name)
+			}
+		})
+
+		It("should create every table the service queries", func() {
+			var up, down strings.Builder
+			for _, migration := range migrations {
+				up.WriteString(migration.Up)
+				down.WriteString(migration.Down)
+			}
+			for _, table := range []string{"identity", "event", "credential", "refresh_token",
+				"revoked_token", "role", "role_permission", "identity_role", "api_key",
+				"login_attempt", "mfa_secret", "mfa_recovery_code", "mfa_challenge", "email_token",
+				"oauth_client", "oauth_code", "client_certificate", "browser_session",
+				"signing_key", "request_nonce", "credential_role"} {
+				Expect(up.String()).To(MatchRegexp(`CREATE TABLE ` + regexp.QuoteMeta(table) + ` \(`))
+				Expect(down.String()).To(ContainSubstring("DROP TABLE " + table + ";"))
+			}
+		})
+
+		It("should give identity its password and verification columns", func() {
+			Expect(migrations[0].Up).To(ContainSubstring("password_hash bytea"))
+			Expect(migrations[0].Up).To(ContainSubstring("email_verified_at timestamptz"))
+		})
+	})
+})
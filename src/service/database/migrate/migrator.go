@@ -0,0 +1,255 @@
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"service/log"
+	"sort"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+//LockID ... is the postgres advisory lock key held while migrating, so concurrently
+//starting instances apply each migration once.
+const LockID int64 = 0x6d696772617465
+
+//ErrChecksumMismatch ... is returned when an applied migration's SQL has since been edited.
+var ErrChecksumMismatch = errors.New("applied migration has been modified")
+
+//ErrUnknownVersion ... is returned when reverting a migration this build doesn't have.
+var ErrUnknownVersion = errors.New("applied migration is unknown to this build")
+
+//ErrIrreversible ... is returned when reverting a migration without a down file.
+var ErrIrreversible = errors.New("migration has no down file")
+
+//Status ... is a migration and whether it has been applied. Unknown migrations were
+//applied by a newer build, Modified ones were edited after they were applied.
+type Status struct {
+	Migration
+	AppliedAt *time.Time
+	Modified  bool
+	Unknown   bool
+}
+
+//Migrator ... applies and reverts migrations against a postgres database.
+type Migrator struct {
+	log        log.ProdInterface
+	db         *sql.DB
+	migrations []Migration
+}
+
+//NewMigrator ... returns a pointer to a Migrator for migrations, see Embedded.
+//It needs a *sql.DB rather than a DBInterface to hold the advisory lock on one connection.
+func NewMigrator(logClient log.ProdInterface, db *sql.DB, migrations []Migration) *Migrator {
+	return &Migrator{
+		log:        logClient,
+		db:         db,
+		migrations: migrations,
+	}
+}
+
+type appliedMigration struct {
+	name      string
+	checksum  string
+	appliedAt time.Time
+}
+
+//Up ...
+//applies every pending migration in version order, each in its own transaction, and
+//returns those it applied. Nothing is applied if an applied migration has been modified.
+func (m *Migrator) Up() ([]Migration, error) {
+	ran := make([]Migration, 0)
+	err := m.locked(func(conn *sql.Conn) error {
+		applied, err := m.applied(conn)
+		if err != nil {
+			return err
+		}
+		for _, migration := range m.migrations {
+			if previous, ok := applied[migration.Version]; ok &&
+				previous.checksum != migration.Checksum {
+				return fmt.Errorf("%w: %d_%s", ErrChecksumMismatch, migration.Version,
+					migration.Name)
+			}
+		}
+		for _, migration := range m.migrations {
+			if _, ok := applied[migration.Version]; ok {
+				continue
+			}
+			err := m.apply(conn, migration, migration.Up, `INSERT INTO schema_migrations
+				(version, name, checksum, applied_at) VALUES ($1, $2, $3, $4);`,
+				migration.Version, migration.Name, migration.Checksum, time.Now())
+			if err != nil {
+				return fmt.Errorf("migration %d_%s: %w", migration.Version, migration.Name, err)
+			}
+			m.log.Info("migration applied",
+				zap.Int64("version", migration.Version),
+				zap.String("name", migration.Name))
+			ran = append(ran, migration)
+		}
+		return nil
+	})
+	return ran, err
+}
+
+//Down ... reverts the latest steps applied migrations, newest first, and returns them.
+func (m *Migrator) Down(steps int) ([]Migration, error) {
+	reverted := make([]Migration, 0, steps)
+	err := m.locked(func(conn *sql.Conn) error {
+		applied, err := m.applied(conn)
+		if err != nil {
+			return err
+		}
+		versions := make([]int64, 0, len(applied))
+		for version := range applied {
+			versions = append(versions, version)
+		}
+		sort.Slice(versions, func(i, j int) bool { return versions[i] > versions[j] })
+		for i := 0; i < steps && i < len(versions); i++ {
+			migration, ok := m.find(versions[i])
+			if !ok {
+				return fmt.Errorf("%w: %d_%s", ErrUnknownVersion, versions[i],
+					applied[versions[i]].name)
+			}
+			if migration.Down == "" {
+				return fmt.Errorf("%w: %d_%s", ErrIrreversible, migration.Version, migration.Name)
+			}
+			err := m.apply(conn, migration, migration.Down,
+				"DELETE FROM schema_migrations WHERE version = $1;", migration.Version)
+			if err != nil {
+				return fmt.Errorf("migration %d_%s: %w", migration.Version, migration.Name, err)
+			}
+			m.log.Info("migration reverted",
+				zap.Int64("version", migration.Version),
+				zap.String("name", migration.Name))
+			reverted = append(reverted, migration)
+		}
+		return nil
+	})
+	return reverted, err
+}
+
+//Status ... lists every known and applied migration in version order.
+func (m *Migrator) Status() ([]Status, error) {
+	var statuses []Status
+	err := m.locked(func(conn *sql.Conn) error {
+		applied, err := m.applied(conn)
+		if err != nil {
+			return err
+		}
+		statuses = make([]Status, 0, len(m.migrations))
+		for _, migration := range m.migrations {
+			status := Status{Migration: migration}
+			if previous, ok := applied[migration.Version]; ok {
+				appliedAt := previous.appliedAt
+				status.AppliedAt = &appliedAt
+				status.Modified = previous.checksum != migration.Checksum
+				delete(applied, migration.Version)
+			}
+			statuses = append(statuses, status)
+		}
+		for version, previous := range applied {
+			appliedAt := previous.appliedAt
+			statuses = append(statuses, Status{
+				Migration: Migration{Version: version, Name: previous.name,
+					Checksum: previous.checksum},
+				AppliedAt: &appliedAt,
+				Unknown:   true,
+			})
+		}
+		sort.Slice(statuses, func(i, j int) bool {
+			return statuses[i].Version < statuses[j].Version
+		})
+		return nil
+	})
+	return statuses, err
+}
+
+//locked runs fn on a single connection holding the advisory lock, creating the
+//schema_migrations table first if this database has never been migrated.
+func (m *Migrator) locked(fn func(conn *sql.Conn) error) error {
+	ctx := context.Background()
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if closeErr := conn.Close(); closeErr != nil {
+			m.log.Error("migrate::Close", zap.Error(closeErr))
+		}
+	}()
+	if _, err := conn.ExecContext(ctx, "SELECT pg_advisory_lock($1);", LockID); err != nil {
+		return err
+	}
+	defer func() {
+		if _, unlockErr := conn.ExecContext(ctx, "SELECT pg_advisory_unlock($1);",
+			LockID); unlockErr != nil {
+			m.log.Error("migrate::Unlock", zap.Error(unlockErr))
+		}
+	}()
+	if _, err := conn.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+		version bigint PRIMARY KEY,
+		name text NOT NULL,
+		checksum text NOT NULL,
+		applied_at timestamptz NOT NULL
+	);`); err != nil {
+		return err
+	}
+	return fn(conn)
+}
+
+func (m *Migrator) applied(conn *sql.Conn) (map[int64]appliedMigration, error) {
+	rows, err := conn.QueryContext(context.Background(),
+		"SELECT version, name, checksum, applied_at FROM schema_migrations;")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	applied := make(map[int64]appliedMigration)
+	for rows.Next() {
+		var version int64
+		var previous appliedMigration
+		if err := rows.Scan(&version, &previous.name, &previous.checksum,
+			&previous.appliedAt); err != nil {
+			return nil, err
+		}
+		applied[version] = previous
+	}
+	return applied, rows.Err()
+}
+
+//apply runs statements and records it with bookkeeping in one transaction, so a failed
+//migration leaves neither its changes nor its row behind.
+func (m *Migrator) apply(conn *sql.Conn, migration Migration, statements, bookkeeping string,
+	args ...interface{}) error {
+	ctx := context.Background()
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	rollback := func(err error) error {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil {
+			m.log.Error("migrate::Rollback", zap.Int64("version", migration.Version),
+				zap.Error(rollbackErr))
+		}
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, statements); err != nil {
+		return rollback(err)
+	}
+	if _, err := tx.ExecContext(ctx, bookkeeping, args...); err != nil {
+		return rollback(err)
+	}
+	return tx.Commit()
+}
+
+func (m *Migrator) find(version int64) (Migration, bool) {
+	for _, migration := range m.migrations {
+		if migration.Version == version {
+			return migration, true
+		}
+	}
+	return Migration{}, false
+}
@@ -0,0 +1,145 @@
+package migrate_test
+
+import (
+	"errors"
+	"regexp"
+	"service/database/migrate"
+	"service/log/logfakes"
+	"service/utils/sqltest"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	sqlmock "gopkg.in/DATA-DOG/go-sqlmock.v1"
+)
+
+var _ = Describe("Migrator Specs", func() {
+	var (
+		migrator   *migrate.Migrator
+		mockDB     sqlmock.Sqlmock
+		migrations []migrate.Migration
+	)
+
+	appliedColumns := []string{"version", "name", "checksum", "applied_at"}
+
+	BeforeEach(func() {
+		db, mock, sqlmockErr := sqlmock.New()
+		Expect(sqlmockErr).ToNot(HaveOccurred())
+		mockDB = mock
+		migrations = []migrate.Migration{
+			{Version: 1, Name: "create_table", Up: "CREATE TABLE thing (id int);",
+				Down: "DROP TABLE thing;", Checksum: "sum1"},
+			{Version: 2, Name: "add_index", Up: "CREATE INDEX thing_idx ON thing (id);",
+				Down: "DROP INDEX thing_idx;", Checksum: "sum2"},
+		}
+		migrator = migrate.NewMigrator(&logfakes.FakeProdInterface{}, db, migrations)
+
+		mockDB.ExpectExec(regexp.QuoteMeta("SELECT pg_advisory_lock($1);")).
+			WithArgs(migrate.LockID).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mockDB.ExpectExec("CREATE TABLE IF NOT EXISTS schema_migrations").
+			WillReturnResult(sqlmock.NewResult(0, 0))
+	})
+
+	expectUnlock := func() {
+		mockDB.ExpectExec(regexp.QuoteMeta("SELECT pg_advisory_unlock($1);")).
+			WithArgs(migrate.LockID).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+	}
+
+	AfterEach(func() {
+		Expect(mockDB.ExpectationsWereMet()).To(Succeed())
+	})
+
+	Context("Up", func() {
+		It("should apply pending migrations under the lock", func() {
+			mockDB.ExpectQuery("SELECT (.+) FROM schema_migrations").
+				WillReturnRows(sqlmock.NewRows(appliedColumns).
+					AddRow(1, "create_table", "sum1", time.Now()))
+			mockDB.ExpectBegin()
+			mockDB.ExpectExec(regexp.QuoteMeta(migrations[1].Up)).
+				WillReturnResult(sqlmock.NewResult(0, 0))
+			mockDB.ExpectExec("INSERT INTO schema_migrations").
+				WithArgs(int64(2), "add_index", "sum2", sqltest.AnyTime{}).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+			mockDB.ExpectCommit()
+			expectUnlock()
+
+			applied, err := migrator.Up()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(applied).To(HaveLen(1))
+			Expect(applied[0].Name).To(Equal("add_index"))
+		})
+
+		It("should roll back a failed migration and stop", func() {
+			mockDB.ExpectQuery("SELECT (.+) FROM schema_migrations").
+				WillReturnRows(sqlmock.NewRows(appliedColumns))
+			mockDB.ExpectBegin()
+			mockDB.ExpectExec(regexp.QuoteMeta(migrations[0].Up)).
+				WillReturnError(errors.New("syntax error"))
+			mockDB.ExpectRollback()
+			expectUnlock()
+
+			applied, err := migrator.Up()
+			Expect(err).To(MatchError(ContainSubstring("migration 1_create_table: syntax error")))
+			Expect(applied).To(BeEmpty())
+		})
+
+		It("should refuse to run when an applied migration was modified", func() {
+			mockDB.ExpectQuery("SELECT (.+) FROM schema_migrations").
+				WillReturnRows(sqlmock.NewRows(appliedColumns).
+					AddRow(1, "create_table", "edited", time.Now()))
+			expectUnlock()
+
+			_, err := migrator.Up()
+			Expect(errors.Is(err, migrate.ErrChecksumMismatch)).To(BeTrue())
+		})
+	})
+
+	Context("Down", func() {
+		It("should revert the newest applied migration", func() {
+			mockDB.ExpectQuery("SELECT (.+) FROM schema_migrations").
+				WillReturnRows(sqlmock.NewRows(appliedColumns).
+					AddRow(1, "create_table", "sum1", time.Now()).
+					AddRow(2, "add_index", "sum2", time.Now()))
+			mockDB.ExpectBegin()
+			mockDB.ExpectExec(regexp.QuoteMeta(migrations[1].Down)).
+				WillReturnResult(sqlmock.NewResult(0, 0))
+			mockDB.ExpectExec("DELETE FROM schema_migrations").
+				WithArgs(int64(2)).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+			mockDB.ExpectCommit()
+			expectUnlock()
+
+			reverted, err := migrator.Down(1)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(reverted).To(HaveLen(1))
+			Expect(reverted[0].Version).To(Equal(int64(2)))
+		})
+
+		It("should refuse to revert a migration this build doesn't have", func() {
+			mockDB.ExpectQuery("SELECT (.+) FROM schema_migrations").
+				WillReturnRows(sqlmock.NewRows(appliedColumns).
+					AddRow(3, "from_the_future", "sum3", time.Now()))
+			expectUnlock()
+
+			_, err := migrator.Down(1)
+			Expect(errors.Is(err, migrate.ErrUnknownVersion)).To(BeTrue())
+		})
+	})
+
+	It("should report applied, pending, modified and unknown migrations", func() {
+		mockDB.ExpectQuery("SELECT (.+) FROM schema_migrations").
+			WillReturnRows(sqlmock.NewRows(appliedColumns).
+				AddRow(1, "create_table", "edited", time.Now()).
+				AddRow(3, "from_the_future", "sum3", time.Now()))
+		expectUnlock()
+
+		statuses, err := migrator.Status()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(statuses).To(HaveLen(3))
+		Expect(statuses[0].Modified).To(BeTrue())
+		Expect(statuses[1].AppliedAt).To(BeNil())
+		Expect(statuses[2].Unknown).To(BeTrue())
+	})
+})
@@ -0,0 +1,21 @@
+CREATE TABLE identity (
+	id varchar(50) PRIMARY KEY,
+	first_name text NOT NULL DEFAULT '',
+	last_name text NOT NULL DEFAULT '',
+	profile jsonb NOT NULL DEFAULT '{}',
+	password_hash bytea,
+	email_verified_at timestamptz,
+	created_at timestamptz NOT NULL,
+	updated_at timestamptz NOT NULL
+);
+
+-- Keyset pagination orders by (created_at, id), email lookups are case insensitive.
+CREATE INDEX identity_created_at_id_idx ON identity (created_at, id);
+CREATE INDEX identity_email_idx ON identity (lower(profile->>'email'));
+
+CREATE TABLE event (
+	id serial PRIMARY KEY,
+	name text NOT NULL,
+	description text NOT NULL DEFAULT '',
+	date_added timestamptz NOT NULL DEFAULT now()
+);
@@ -0,0 +1,7 @@
+CREATE TABLE credential (
+	username text PRIMARY KEY,
+	password_hash bytea NOT NULL,
+	disabled boolean NOT NULL DEFAULT false,
+	created_at timestamptz NOT NULL,
+	updated_at timestamptz NOT NULL
+);
@@ -0,0 +1,23 @@
+-- client_id and scope are empty for password logins, see refresh.Grant.
+CREATE TABLE refresh_token (
+	token_hash text PRIMARY KEY,
+	family_id text NOT NULL,
+	identity_id varchar(50) NOT NULL REFERENCES identity (id) ON DELETE CASCADE,
+	client_id text NOT NULL DEFAULT '',
+	scope text NOT NULL DEFAULT '',
+	expires_at timestamptz NOT NULL,
+	used_at timestamptz,
+	revoked_at timestamptz,
+	created_at timestamptz NOT NULL
+);
+
+CREATE INDEX refresh_token_family_id_idx ON refresh_token (family_id);
+
+-- Rows are only needed until the token they revoke would have expired anyway.
+CREATE TABLE revoked_token (
+	jti text PRIMARY KEY,
+	expires_at timestamptz NOT NULL,
+	revoked_at timestamptz NOT NULL
+);
+
+CREATE INDEX revoked_token_expires_at_idx ON revoked_token (expires_at);
@@ -0,0 +1,3 @@
+DROP TABLE identity_role;
+DROP TABLE role_permission;
+DROP TABLE role;
@@ -0,0 +1,16 @@
+CREATE TABLE role (
+	name text PRIMARY KEY
+);
+
+CREATE TABLE role_permission (
+	role text NOT NULL REFERENCES role (name) ON DELETE CASCADE,
+	permission text NOT NULL,
+	PRIMARY KEY (role, permission)
+);
+
+CREATE TABLE identity_role (
+	identity_id varchar(50) NOT NULL REFERENCES identity (id) ON DELETE CASCADE,
+	role text NOT NULL REFERENCES role (name) ON DELETE CASCADE,
+	created_at timestamptz NOT NULL,
+	PRIMARY KEY (identity_id, role)
+);
@@ -0,0 +1,14 @@
+CREATE TABLE api_key (
+	id text PRIMARY KEY,
+	identity_id varchar(50) NOT NULL REFERENCES identity (id) ON DELETE CASCADE,
+	name text NOT NULL,
+	prefix text NOT NULL UNIQUE,
+	secret_hash text NOT NULL,
+	scope text NOT NULL,
+	expires_at timestamptz,
+	last_used_at timestamptz,
+	revoked_at timestamptz,
+	created_at timestamptz NOT NULL
+);
+
+CREATE INDEX api_key_identity_id_idx ON api_key (identity_id);
@@ -0,0 +1,7 @@
+-- key is "identity:<id>" or "ip:<address>", see throttle.Throttle.
+CREATE TABLE login_attempt (
+	key text PRIMARY KEY,
+	failures integer NOT NULL,
+	last_failure_at timestamptz NOT NULL,
+	locked_until timestamptz
+);
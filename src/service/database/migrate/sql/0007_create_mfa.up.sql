@@ -0,0 +1,24 @@
+CREATE TABLE mfa_secret (
+	identity_id varchar(50) PRIMARY KEY REFERENCES identity (id) ON DELETE CASCADE,
+	secret text NOT NULL,
+	last_step bigint NOT NULL DEFAULT 0,
+	enabled_at timestamptz,
+	created_at timestamptz NOT NULL
+);
+
+CREATE TABLE mfa_recovery_code (
+	identity_id varchar(50) NOT NULL REFERENCES identity (id) ON DELETE CASCADE,
+	code_hash text NOT NULL,
+	used_at timestamptz,
+	created_at timestamptz NOT NULL,
+	PRIMARY KEY (identity_id, code_hash)
+);
+
+CREATE TABLE mfa_challenge (
+	token_hash text PRIMARY KEY,
+	identity_id varchar(50) NOT NULL REFERENCES identity (id) ON DELETE CASCADE,
+	attempts integer NOT NULL DEFAULT 0,
+	expires_at timestamptz NOT NULL,
+	used_at timestamptz,
+	created_at timestamptz NOT NULL
+);
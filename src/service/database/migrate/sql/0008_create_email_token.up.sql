@@ -0,0 +1,11 @@
+CREATE TABLE email_token (
+	id text PRIMARY KEY,
+	purpose text NOT NULL,
+	identity_id varchar(50) NOT NULL REFERENCES identity (id) ON DELETE CASCADE,
+	email text NOT NULL,
+	expires_at timestamptz NOT NULL,
+	used_at timestamptz,
+	created_at timestamptz NOT NULL
+);
+
+CREATE INDEX email_token_identity_id_purpose_idx ON email_token (identity_id, purpose);
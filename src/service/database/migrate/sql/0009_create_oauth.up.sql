@@ -0,0 +1,24 @@
+-- redirect_uris, grant_types and scope are space separated, secret_hash is empty for
+-- public clients.
+CREATE TABLE oauth_client (
+	id text PRIMARY KEY,
+	name text NOT NULL,
+	secret_hash text NOT NULL DEFAULT '',
+	redirect_uris text NOT NULL DEFAULT '',
+	grant_types text NOT NULL,
+	scope text NOT NULL DEFAULT '',
+	created_at timestamptz NOT NULL
+);
+
+CREATE TABLE oauth_code (
+	code_hash text PRIMARY KEY,
+	client_id text NOT NULL REFERENCES oauth_client (id) ON DELETE CASCADE,
+	identity_id varchar(50) NOT NULL REFERENCES identity (id) ON DELETE CASCADE,
+	redirect_uri text NOT NULL,
+	scope text NOT NULL DEFAULT '',
+	code_challenge text NOT NULL DEFAULT '',
+	code_challenge_method text NOT NULL DEFAULT '',
+	expires_at timestamptz NOT NULL,
+	used_at timestamptz,
+	created_at timestamptz NOT NULL
+);
@@ -0,0 +1,8 @@
+-- name is a normalized certificate name such as "dns:reports.internal", see mtls.Names.
+CREATE TABLE client_certificate (
+	name text PRIMARY KEY,
+	identity_id varchar(50) NOT NULL REFERENCES identity (id) ON DELETE CASCADE,
+	created_at timestamptz NOT NULL
+);
+
+CREATE INDEX client_certificate_identity_id_idx ON client_certificate (identity_id);
@@ -0,0 +1 @@
+DROP TABLE browser_session;
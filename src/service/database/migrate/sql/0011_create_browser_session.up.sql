@@ -0,0 +1,13 @@
+CREATE TABLE browser_session (
+	token_hash text PRIMARY KEY,
+	id text NOT NULL UNIQUE,
+	identity_id varchar(50) NOT NULL REFERENCES identity (id) ON DELETE CASCADE,
+	csrf_token text NOT NULL,
+	user_agent text NOT NULL DEFAULT '',
+	ip text NOT NULL DEFAULT '',
+	created_at timestamptz NOT NULL,
+	last_seen_at timestamptz NOT NULL,
+	expires_at timestamptz NOT NULL
+);
+
+CREATE INDEX browser_session_identity_id_idx ON browser_session (identity_id);
@@ -0,0 +1,2 @@
+DROP TABLE request_nonce;
+DROP TABLE signing_key;
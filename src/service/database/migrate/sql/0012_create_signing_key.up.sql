@@ -0,0 +1,17 @@
+-- HMAC verification needs the shared secret itself, so unlike api_key it is not hashed.
+CREATE TABLE signing_key (
+	id text PRIMARY KEY,
+	identity_id varchar(50) NOT NULL REFERENCES identity (id) ON DELETE CASCADE,
+	name text NOT NULL,
+	secret text NOT NULL,
+	created_at timestamptz NOT NULL
+);
+
+CREATE INDEX signing_key_identity_id_idx ON signing_key (identity_id);
+
+CREATE TABLE request_nonce (
+	key_id text NOT NULL REFERENCES signing_key (id) ON DELETE CASCADE,
+	nonce text NOT NULL,
+	expires_at timestamptz NOT NULL,
+	PRIMARY KEY (key_id, nonce)
+);
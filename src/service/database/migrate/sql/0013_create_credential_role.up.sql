@@ -0,0 +1,8 @@
+-- Basic auth credentials hold roles like identities do, so frontends only get the permissions
+-- they are granted.
+CREATE TABLE credential_role (
+	username text NOT NULL REFERENCES credential (username) ON DELETE CASCADE,
+	role text NOT NULL REFERENCES role (name) ON DELETE CASCADE,
+	created_at timestamptz NOT NULL,
+	PRIMARY KEY (username, role)
+);
@@ -0,0 +1 @@
+ALTER TABLE signing_key DROP COLUMN scope;
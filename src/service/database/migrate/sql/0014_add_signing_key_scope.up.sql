@@ -0,0 +1,3 @@
+-- Signing keys carry scopes like api_key. Keys made before this have none and grant nothing
+-- until they are replaced.
+ALTER TABLE signing_key ADD COLUMN scope text NOT NULL DEFAULT '';
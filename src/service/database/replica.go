@@ -0,0 +1,305 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"service/log"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+//Policy decides which healthy replica serves a read.
+type Policy int
+
+const (
+	//RoundRobin spreads reads over the replicas in turn.
+	RoundRobin Policy = iota
+	//LeastConnections sends a read to the replica with the fewest connections in use.
+	LeastConnections
+)
+
+//ParsePolicy reads a Policy from its config name, "round-robin" or "least-connections".
+//An empty name is RoundRobin.
+func ParsePolicy(name string) (Policy, bool) {
+	switch name {
+	case "", "round-robin":
+		return RoundRobin, true
+	case "least-connections":
+		return LeastConnections, true
+	}
+	return RoundRobin, false
+}
+
+//DefaultMaxLag is how far behind the primary a replica may fall before it stops taking reads.
+const DefaultMaxLag = 5 * time.Second
+
+//lagQuery measures how far a replica's replay is behind the primary, 0 once it is caught up.
+const lagQuery = `SELECT CASE WHEN pg_last_wal_receive_lsn() = pg_last_wal_replay_lsn() THEN 0
+	ELSE COALESCE(EXTRACT(EPOCH FROM now() - pg_last_xact_replay_timestamp()), 0) END;`
+
+type replica struct {
+	db      *sql.DB
+	healthy int32
+}
+
+//Router is a DBInterface over a primary and its read replicas. Writes, transactions and reads
+//made with a context that did not come from TrackWrites go to the primary. A tracked read goes to
+//a healthy replica unless the same context has already written, so a request reads its own writes.
+type Router struct {
+	Primary *sql.DB
+	//MaxLag ejects a replica whose replay falls further behind, DefaultMaxLag unless set and
+	//0 ignores lag.
+	MaxLag time.Duration
+
+	log      log.ProdInterface
+	replicas []*replica
+	policy   Policy
+	next     uint32
+}
+
+//NewRouter creates a Router over primary and replicas. Every replica starts out healthy.
+func NewRouter(logClient log.ProdInterface, primary *sql.DB, replicas []*sql.DB,
+	policy Policy) *Router {
+	router := &Router{Primary: primary, MaxLag: DefaultMaxLag, log: logClient, policy: policy}
+	for _, db := range replicas {
+		router.replicas = append(router.replicas, &replica{db: db, healthy: 1})
+	}
+	return router
+}
+
+type routingKey struct{}
+
+type routing struct {
+	wrote int32
+}
+
+//TrackWrites returns a context whose reads a Router may send to a replica until it writes.
+func TrackWrites(ctx context.Context) context.Context {
+	return context.WithValue(ctx, routingKey{}, &routing{})
+}
+
+//UsePrimary returns a context whose statements always go to the primary, for reads that must not
+//lag such as a SELECT calling a function with side effects. A tracked ctx stays on the primary too.
+func UsePrimary(ctx context.Context) context.Context {
+	if state, ok := ctx.Value(routingKey{}).(*routing); ok {
+		atomic.StoreInt32(&state.wrote, 1)
+		return ctx
+	}
+	return context.WithValue(ctx, routingKey{}, &routing{wrote: 1})
+}
+
+//OnPrimary wraps db so every statement goes to the primary, for state a lagging replica must not
+//serve stale such as revocations, lockouts and disabled credentials. Unlike UsePrimary it leaves
+//the rest of a tracked request free to read replicas, unless the statement writes.
+func OnPrimary(db DBInterface) DBInterface {
+	return &onPrimary{db: db}
+}
+
+type onPrimary struct {
+	db DBInterface
+}
+
+func (p *onPrimary) ExecContext(ctx context.Context, query string,
+	args ...interface{}) (sql.Result, error) {
+	return p.db.ExecContext(ctx, query, args...)
+}
+
+func (p *onPrimary) QueryRowContext(ctx context.Context, query string,
+	args ...interface{}) *sql.Row {
+	return p.db.QueryRowContext(pinned(ctx, query), query, args...)
+}
+
+func (p *onPrimary) QueryContext(ctx context.Context, query string,
+	args ...interface{}) (*sql.Rows, error) {
+	return p.db.QueryContext(pinned(ctx, query), query, args...)
+}
+
+//BeginTx opens a transaction on the wrapped db, so InTx works through OnPrimary. It returns
+//ErrNoTransactions when the wrapped db cannot begin one.
+func (p *onPrimary) BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error) {
+	beginner, ok := p.db.(Beginner)
+	if !ok {
+		return nil, ErrNoTransactions
+	}
+	return beginner.BeginTx(ctx, opts)
+}
+
+//WrapTx keeps the wrapped db decorating the transactions InTx opens through OnPrimary.
+func (p *onPrimary) WrapTx(tx DBInterface) DBInterface {
+	if wrapper, ok := p.db.(TxWrapper); ok {
+		return wrapper.WrapTx(tx)
+	}
+	return tx
+}
+
+//pinned returns a ctx routed to the primary without pinning the tracked request it came from,
+//which a write still does so the request reads it back.
+func pinned(ctx context.Context, query string) context.Context {
+	if state, ok := ctx.Value(routingKey{}).(*routing); ok && !isRead(query) {
+		atomic.StoreInt32(&state.wrote, 1)
+	}
+	return context.WithValue(ctx, routingKey{}, &routing{wrote: 1})
+}
+
+//ExecContext runs query on the primary and pins later reads of a tracked ctx to it.
+func (r *Router) ExecContext(ctx context.Context, query string,
+	args ...interface{}) (sql.Result, error) {
+	r.wrote(ctx)
+	return r.Primary.ExecContext(ctx, query, args...)
+}
+
+//QueryRowContext runs query on the db chosen by route.
+func (r *Router) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	return r.route(ctx, query).QueryRowContext(ctx, query, args...)
+}
+
+//QueryContext runs query on the db chosen by route.
+func (r *Router) QueryContext(ctx context.Context, query string,
+	args ...interface{}) (*sql.Rows, error) {
+	return r.route(ctx, query).QueryContext(ctx, query, args...)
+}
+
+//BeginTx opens a transaction on the primary, so every statement of a Runner unit of work
+//goes there.
+func (r *Router) BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error) {
+	r.wrote(ctx)
+	return r.Primary.BeginTx(ctx, opts)
+}
+
+func (r *Router) wrote(ctx context.Context) {
+	if state, ok := ctx.Value(routingKey{}).(*routing); ok {
+		atomic.StoreInt32(&state.wrote, 1)
+	}
+}
+
+//route picks the db for query. Anything but a plain SELECT, such as UPDATE ... RETURNING through
+//QueryRowContext, counts as a write.
+func (r *Router) route(ctx context.Context, query string) *sql.DB {
+	state, ok := ctx.Value(routingKey{}).(*routing)
+	if !ok || atomic.LoadInt32(&state.wrote) == 1 {
+		return r.Primary
+	}
+	if !isRead(query) {
+		atomic.StoreInt32(&state.wrote, 1)
+		return r.Primary
+	}
+	if db := r.pick(); db != nil {
+		return db
+	}
+	return r.Primary
+}
+
+func isRead(query string) bool {
+	normalized := strings.Join(strings.Fields(strings.ToUpper(query)), " ")
+	if !strings.HasPrefix(strings.TrimLeft(normalized, "( "), "SELECT") {
+		return false
+	}
+	for _, lock := range []string{"FOR UPDATE", "FOR NO KEY UPDATE", "FOR SHARE", "FOR KEY SHARE"} {
+		if strings.Contains(normalized, lock) {
+			return false
+		}
+	}
+	return true
+}
+
+//pick returns a healthy replica chosen by the policy, or nil when none is healthy.
+func (r *Router) pick() *sql.DB {
+	healthy := make([]*sql.DB, 0, len(r.replicas))
+	for _, rep := range r.replicas {
+		if atomic.LoadInt32(&rep.healthy) == 1 {
+			healthy = append(healthy, rep.db)
+		}
+	}
+	if len(healthy) == 0 {
+		return nil
+	}
+	if r.policy == LeastConnections {
+		least := healthy[0]
+		inUse := least.Stats().InUse
+		for _, db := range healthy[1:] {
+			if n := db.Stats().InUse; n < inUse {
+				least, inUse = db, n
+			}
+		}
+		return least
+	}
+	n := atomic.AddUint32(&r.next, 1)
+	return healthy[int(n-1)%len(healthy)]
+}
+
+//Healthy returns how many replicas are taking reads.
+func (r *Router) Healthy() int {
+	count := 0
+	for _, rep := range r.replicas {
+		if atomic.LoadInt32(&rep.healthy) == 1 {
+			count++
+		}
+	}
+	return count
+}
+
+//Check pings every replica and checks its lag, ejecting the ones that fail and restoring the ones
+//that pass again.
+func (r *Router) Check(ctx context.Context) {
+	for i, rep := range r.replicas {
+		err := r.check(ctx, rep.db)
+		healthy := int32(1)
+		if err != nil {
+			healthy = 0
+		}
+		if atomic.SwapInt32(&rep.healthy, healthy) == healthy {
+			continue
+		}
+		if err != nil {
+			r.log.Warn("database: replica ejected", zap.Int("replica", i), zap.Error(err))
+		} else {
+			r.log.Info("database: replica restored", zap.Int("replica", i))
+		}
+	}
+}
+
+func (r *Router) check(ctx context.Context, db *sql.DB) error {
+	if err := db.PingContext(ctx); err != nil {
+		return err
+	}
+	if r.MaxLag <= 0 {
+		return nil
+	}
+	var lag float64
+	if err := db.QueryRowContext(ctx, lagQuery).Scan(&lag); err != nil {
+		return err
+	}
+	if lagged := time.Duration(lag * float64(time.Second)); lagged > r.MaxLag {
+		return &LagError{Lag: lagged, MaxLag: r.MaxLag}
+	}
+	return nil
+}
+
+//Watch runs Check every interval until ctx is done, each check bounded by the interval.
+func (r *Router) Watch(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			checkCtx, cancel := context.WithTimeout(ctx, interval)
+			r.Check(checkCtx)
+			cancel()
+		}
+	}
+}
+
+//LagError is the health check failure of a replica too far behind the primary.
+type LagError struct {
+	Lag    time.Duration
+	MaxLag time.Duration
+}
+
+func (e *LagError) Error() string {
+	return "database: replica is " + e.Lag.String() + " behind, more than " + e.MaxLag.String()
+}
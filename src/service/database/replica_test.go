@@ -0,0 +1,251 @@
+package database_test
+
+import (
+	"context"
+	"database/sql"
+	"service/database"
+	"service/log/logfakes"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	sqlmock "gopkg.in/DATA-DOG/go-sqlmock.v1"
+)
+
+var _ = Describe("Replica Router Specs", func() {
+	var (
+		ctx        context.Context
+		fakeLog    *logfakes.FakeProdInterface
+		primary    *sql.DB
+		mockDBs    []sqlmock.Sqlmock
+		replicaDBs []*sql.DB
+		router     *database.Router
+	)
+
+	newMock := func() (*sql.DB, sqlmock.Sqlmock) {
+		db, mock, sqlmockErr := sqlmock.New()
+		Expect(sqlmockErr).ToNot(HaveOccurred())
+		return db, mock
+	}
+
+	expectSelect := func(mock sqlmock.Sqlmock) {
+		mock.ExpectQuery("SELECT id FROM thing").
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	}
+
+	selectThing := func(ctx context.Context) {
+		var id int
+		Expect(router.QueryRowContext(ctx, "SELECT id FROM thing;").Scan(&id)).To(Succeed())
+	}
+
+	BeforeEach(func() {
+		ctx = database.TrackWrites(context.Background())
+		fakeLog = &logfakes.FakeProdInterface{}
+		var primaryMock sqlmock.Sqlmock
+		primary, primaryMock = newMock()
+		mockDBs = []sqlmock.Sqlmock{primaryMock}
+		replicaDBs = nil
+		for i := 0; i < 2; i++ {
+			db, mock := newMock()
+			replicaDBs = append(replicaDBs, db)
+			mockDBs = append(mockDBs, mock)
+		}
+		router = database.NewRouter(fakeLog, primary, replicaDBs, database.RoundRobin)
+	})
+
+	AfterEach(func() {
+		for _, mock := range mockDBs {
+			Expect(mock.ExpectationsWereMet()).To(Succeed())
+		}
+	})
+
+	Context("when a request reads", func() {
+		It("should spread the reads over the replicas in turn", func() {
+			expectSelect(mockDBs[1])
+			expectSelect(mockDBs[2])
+			expectSelect(mockDBs[1])
+
+			selectThing(ctx)
+			selectThing(ctx)
+			selectThing(ctx)
+		})
+
+		It("should read the primary for contexts that do not track writes", func() {
+			expectSelect(mockDBs[0])
+
+			selectThing(context.Background())
+		})
+
+		It("should read the primary when asked to", func() {
+			expectSelect(mockDBs[0])
+
+			selectThing(database.UsePrimary(ctx))
+		})
+
+		It("should read the primary through OnPrimary without pinning the request", func() {
+			expectSelect(mockDBs[0])
+			expectSelect(mockDBs[1])
+
+			selectThing := func(db database.DBInterface) {
+				var id int
+				Expect(db.QueryRowContext(ctx, "SELECT id FROM thing;").Scan(&id)).To(Succeed())
+			}
+			selectThing(database.OnPrimary(router))
+			selectThing(router)
+		})
+
+		It("should pin the request once it writes through OnPrimary", func() {
+			mockDBs[0].ExpectQuery("UPDATE thing").
+				WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+			expectSelect(mockDBs[0])
+
+			var id int
+			Expect(database.OnPrimary(router).
+				QueryRowContext(ctx, "UPDATE thing SET id = 2 RETURNING id;").Scan(&id)).
+				To(Succeed())
+			selectThing(ctx)
+		})
+
+		It("should run transactions on the primary through OnPrimary", func() {
+			mockDBs[0].ExpectBegin()
+			mockDBs[0].ExpectExec("UPDATE thing").WillReturnResult(sqlmock.NewResult(0, 1))
+			mockDBs[0].ExpectCommit()
+
+			Expect(database.InTx(ctx, database.OnPrimary(router), func(tx database.DBInterface) error {
+				_, err := tx.ExecContext(ctx, "UPDATE thing SET id = 2;")
+				return err
+			})).To(Succeed())
+		})
+
+		It("should send locking reads to the primary", func() {
+			mockDBs[0].ExpectQuery("(?i)select id from thing for update").
+				WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+
+			rows, err := router.QueryContext(ctx, "select id\n\tFROM thing\n\tFOR   UPDATE;")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(rows.Close()).To(Succeed())
+		})
+	})
+
+	Context("when a request writes", func() {
+		It("should read the primary for the rest of the request", func() {
+			expectSelect(mockDBs[1])
+			mockDBs[0].ExpectExec("UPDATE thing").WillReturnResult(sqlmock.NewResult(0, 1))
+			expectSelect(mockDBs[0])
+
+			selectThing(ctx)
+			_, err := router.ExecContext(ctx, "UPDATE thing SET id = 2;")
+			Expect(err).ToNot(HaveOccurred())
+			selectThing(ctx)
+
+			By("leaving other requests on the replicas")
+			expectSelect(mockDBs[2])
+			selectThing(database.TrackWrites(context.Background()))
+		})
+
+		It("should treat a returning statement as a write", func() {
+			mockDBs[0].ExpectQuery("UPDATE thing").
+				WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+			expectSelect(mockDBs[0])
+
+			var id int
+			Expect(router.QueryRowContext(ctx, "UPDATE thing SET id = 2 RETURNING id;").Scan(&id)).
+				To(Succeed())
+			selectThing(ctx)
+		})
+
+		It("should run transactions on the primary", func() {
+			mockDBs[0].ExpectBegin()
+			mockDBs[0].ExpectQuery("SELECT id FROM thing").
+				WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+			mockDBs[0].ExpectCommit()
+			expectSelect(mockDBs[0])
+
+			Expect(database.InTx(ctx, router, func(tx database.DBInterface) error {
+				var id int
+				return tx.QueryRowContext(ctx, "SELECT id FROM thing;").Scan(&id)
+			})).To(Succeed())
+			selectThing(ctx)
+		})
+	})
+
+	Context("when replicas fail their health check", func() {
+		It("should eject them and fall back to the primary once none is left", func() {
+			router.MaxLag = 0
+			mockDBs[1].ExpectClose()
+			Expect(replicaDBs[0].Close()).To(Succeed())
+			router.Check(ctx)
+			Expect(router.Healthy()).To(Equal(1))
+			Expect(fakeLog.WarnCallCount()).To(Equal(1))
+
+			expectSelect(mockDBs[2])
+			expectSelect(mockDBs[2])
+			selectThing(ctx)
+			selectThing(ctx)
+
+			mockDBs[2].ExpectClose()
+			Expect(replicaDBs[1].Close()).To(Succeed())
+			router.Check(ctx)
+			Expect(router.Healthy()).To(Equal(0))
+
+			expectSelect(mockDBs[0])
+			selectThing(ctx)
+		})
+
+		It("should eject a lagging replica and restore it once it catches up", func() {
+			router.MaxLag = time.Second
+			lag := func(mock sqlmock.Sqlmock, seconds float64) {
+				mock.ExpectQuery("pg_last_wal_replay_lsn").
+					WillReturnRows(sqlmock.NewRows([]string{"lag"}).AddRow(seconds))
+			}
+			lag(mockDBs[1], 30)
+			lag(mockDBs[2], 0)
+			router.Check(ctx)
+			Expect(router.Healthy()).To(Equal(1))
+			msg, _ := fakeLog.WarnArgsForCall(0)
+			Expect(msg).To(Equal("database: replica ejected"))
+
+			lag(mockDBs[1], 0.5)
+			lag(mockDBs[2], 0)
+			router.Check(ctx)
+			Expect(router.Healthy()).To(Equal(2))
+			Expect(fakeLog.InfoCallCount()).To(Equal(1))
+		})
+	})
+
+	Context("when reads go to the least busy replica", func() {
+		It("should skip a replica whose connections are in use", func() {
+			router = database.NewRouter(fakeLog, primary, replicaDBs, database.LeastConnections)
+			mockDBs[1].ExpectBegin()
+			tx, err := replicaDBs[0].Begin()
+			Expect(err).ToNot(HaveOccurred())
+
+			expectSelect(mockDBs[2])
+			expectSelect(mockDBs[2])
+			selectThing(ctx)
+			selectThing(ctx)
+
+			mockDBs[1].ExpectRollback()
+			Expect(tx.Rollback()).To(Succeed())
+		})
+	})
+
+	Context("NewRouter", func() {
+		It("should eject lagging replicas by default", func() {
+			Expect(router.MaxLag).To(Equal(database.DefaultMaxLag))
+		})
+	})
+
+	Context("ParsePolicy", func() {
+		It("should read the configured policy names", func() {
+			policy, ok := database.ParsePolicy("")
+			Expect(policy).To(Equal(database.RoundRobin))
+			Expect(ok).To(BeTrue())
+			policy, ok = database.ParsePolicy("least-connections")
+			Expect(policy).To(Equal(database.LeastConnections))
+			Expect(ok).To(BeTrue())
+			_, ok = database.ParsePolicy("random")
+			Expect(ok).To(BeFalse())
+		})
+	})
+})
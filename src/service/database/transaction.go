@@ -0,0 +1,144 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+const (
+	//DefaultMaxAttempts is how many times a transaction is tried before its error is returned.
+	DefaultMaxAttempts = 3
+	//DefaultBackoff is the pause before the first retry. Each later retry waits one step longer.
+	DefaultBackoff = 10 * time.Millisecond
+)
+
+//Postgres error codes that mean the transaction lost a race and is safe to run again.
+const (
+	serializationFailure = "40001"
+	deadlockDetected     = "40P01"
+)
+
+//TxFunc is the unit of work run inside a transaction. Every statement it issues through tx
+//commits or rolls back together. It may be called more than once, so it should not have
+//side effects outside the database.
+type TxFunc func(tx DBInterface) error
+
+//ErrNoTransactions is returned when a client can neither begin a transaction nor is scoped to one.
+var ErrNoTransactions = errors.New("database: client cannot begin transactions")
+
+//Beginner is implemented by anything that can open a transaction, such as *sql.DB.
+type Beginner interface {
+	BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error)
+}
+
+//Runner runs units of work in a transaction. It commits when the work succeeds, rolls back when
+//it returns an error or panics, and retries serialization failures and deadlocks.
+type Runner struct {
+	DB          Beginner
+	Isolation   sql.IsolationLevel
+	ReadOnly    bool
+	MaxAttempts int
+	Backoff     time.Duration
+	//Wrap, when set, decorates each transaction before the unit of work sees it.
+	Wrap func(tx DBInterface) DBInterface
+}
+
+//NewRunner creates a Runner over db with the server's default isolation level.
+func NewRunner(db Beginner) *Runner {
+	return &Runner{
+		DB:          db,
+		Isolation:   sql.LevelDefault,
+		MaxAttempts: DefaultMaxAttempts,
+		Backoff:     DefaultBackoff,
+	}
+}
+
+//Run calls fn inside a transaction and commits it. A retryable failure from fn or from the commit
+//starts a fresh transaction until MaxAttempts is reached or ctx is done.
+func (r *Runner) Run(ctx context.Context, fn TxFunc) error {
+	attempts := r.MaxAttempts
+	if attempts < 1 {
+		attempts = 1
+	}
+	var err error
+	for attempt := 1; attempt <= attempts; attempt++ {
+		err = r.runOnce(ctx, fn)
+		if err == nil || !Retryable(err) || attempt == attempts {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(r.Backoff * time.Duration(attempt)):
+		}
+	}
+	return err
+}
+
+func (r *Runner) runOnce(ctx context.Context, fn TxFunc) (err error) {
+	tx, err := r.DB.BeginTx(ctx, &sql.TxOptions{Isolation: r.Isolation, ReadOnly: r.ReadOnly})
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if p := recover(); p != nil {
+			_ = tx.Rollback()
+			panic(p)
+		}
+	}()
+	var scoped DBInterface = tx
+	if r.Wrap != nil {
+		scoped = r.Wrap(tx)
+	}
+	if err = fn(scoped); err != nil {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil && !errors.Is(rollbackErr, sql.ErrTxDone) {
+			return fmt.Errorf("%w (rollback failed: %v)", err, rollbackErr)
+		}
+		return err
+	}
+	return tx.Commit()
+}
+
+//TxWrapper is implemented by decorators such as Instrumented that keep decorating the
+//transactions InTx opens through them.
+type TxWrapper interface {
+	WrapTx(tx DBInterface) DBInterface
+}
+
+//InTx runs fn in a new transaction when db can begin one, or joins the transaction db is already
+//scoped to so callers compose into one unit of work. Any other client returns ErrNoTransactions
+//rather than running fn without atomicity.
+func InTx(ctx context.Context, db DBInterface, fn TxFunc) error {
+	switch scoped := db.(type) {
+	case Beginner:
+		runner := NewRunner(scoped)
+		if wrapper, ok := db.(TxWrapper); ok {
+			runner.Wrap = wrapper.WrapTx
+		}
+		return runner.Run(ctx, fn)
+	case *sql.Tx, txScoped:
+		return fn(db)
+	}
+	return ErrNoTransactions
+}
+
+//txScoped is implemented by decorators of an open transaction, such as the one from
+//Instrumented.WrapTx.
+type txScoped interface {
+	scopedToTx()
+}
+
+//Retryable reports whether err is a serialization failure or deadlock that a new attempt of the
+//same transaction may not hit.
+func Retryable(err error) bool {
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) {
+		return false
+	}
+	return pqErr.Code == serializationFailure || pqErr.Code == deadlockDetected
+}
@@ -0,0 +1,241 @@
+package database_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"service/database"
+	"service/database/databasefakes"
+	"time"
+
+	"github.com/lib/pq"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	sqlmock "gopkg.in/DATA-DOG/go-sqlmock.v1"
+)
+
+var _ = Describe("Transaction Specs", func() {
+	var (
+		ctx    context.Context
+		runner *database.Runner
+		mockDB sqlmock.Sqlmock
+	)
+
+	insert := func(tx database.DBInterface) error {
+		_, err := tx.ExecContext(ctx, "INSERT INTO thing (id) VALUES ($1);", 1)
+		return err
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		db, mock, sqlmockErr := sqlmock.New()
+		Expect(sqlmockErr).ToNot(HaveOccurred())
+		mockDB = mock
+		runner = database.NewRunner(db)
+		runner.Backoff = 0
+	})
+
+	AfterEach(func() {
+		Expect(mockDB.ExpectationsWereMet()).To(Succeed())
+	})
+
+	Context("when the unit of work succeeds", func() {
+		It("should commit", func() {
+			mockDB.ExpectBegin()
+			mockDB.ExpectExec("INSERT INTO thing").WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+			mockDB.ExpectCommit()
+
+			Expect(runner.Run(ctx, insert)).To(Succeed())
+		})
+	})
+
+	Context("when the unit of work fails", func() {
+		It("should roll back and return the error", func() {
+			mockDB.ExpectBegin()
+			mockDB.ExpectExec("INSERT INTO thing").WillReturnError(sql.ErrConnDone)
+			mockDB.ExpectRollback()
+
+			Expect(runner.Run(ctx, insert)).To(Equal(sql.ErrConnDone))
+		})
+
+		It("should keep the error when the rollback fails too", func() {
+			mockDB.ExpectBegin()
+			mockDB.ExpectExec("INSERT INTO thing").WillReturnError(sql.ErrConnDone)
+			mockDB.ExpectRollback().WillReturnError(errors.New("connection reset"))
+
+			err := runner.Run(ctx, insert)
+			Expect(errors.Is(err, sql.ErrConnDone)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring("connection reset"))
+		})
+	})
+
+	Context("when the unit of work panics", func() {
+		It("should roll back and re-panic", func() {
+			mockDB.ExpectBegin()
+			mockDB.ExpectRollback()
+
+			var recovered interface{}
+			run := func() {
+				defer func() {
+					recovered = recover()
+					panic(recovered)
+				}()
+				_ = runner.Run(ctx, func(tx database.DBInterface) error {
+					panic("boom")
+				})
+			}
+			Expect(run).To(Panic())
+			Expect(recovered).To(Equal("boom"))
+		})
+	})
+
+	Context("when the transaction cannot begin", func() {
+		It("should return the error without running the work", func() {
+			mockDB.ExpectBegin().WillReturnError(sql.ErrConnDone)
+
+			called := false
+			err := runner.Run(ctx, func(tx database.DBInterface) error {
+				called = true
+				return nil
+			})
+			Expect(err).To(Equal(sql.ErrConnDone))
+			Expect(called).To(BeFalse())
+		})
+	})
+
+	Context("when the transaction hits a serialization failure", func() {
+		It("should retry in a new transaction", func() {
+			mockDB.ExpectBegin()
+			mockDB.ExpectExec("INSERT INTO thing").WillReturnError(&pq.Error{Code: "40001"})
+			mockDB.ExpectRollback()
+			mockDB.ExpectBegin()
+			mockDB.ExpectExec("INSERT INTO thing").WillReturnResult(sqlmock.NewResult(0, 1))
+			mockDB.ExpectCommit()
+
+			Expect(runner.Run(ctx, insert)).To(Succeed())
+		})
+
+		It("should retry when the commit fails", func() {
+			mockDB.ExpectBegin()
+			mockDB.ExpectExec("INSERT INTO thing").WillReturnResult(sqlmock.NewResult(0, 1))
+			mockDB.ExpectCommit().WillReturnError(&pq.Error{Code: "40001"})
+			mockDB.ExpectBegin()
+			mockDB.ExpectExec("INSERT INTO thing").WillReturnResult(sqlmock.NewResult(0, 1))
+			mockDB.ExpectCommit()
+
+			Expect(runner.Run(ctx, insert)).To(Succeed())
+		})
+
+		It("should give up after MaxAttempts", func() {
+			runner.MaxAttempts = 2
+			for i := 0; i < 2; i++ {
+				mockDB.ExpectBegin()
+				mockDB.ExpectExec("INSERT INTO thing").WillReturnError(&pq.Error{Code: "40P01"})
+				mockDB.ExpectRollback()
+			}
+
+			err := runner.Run(ctx, insert)
+			Expect(database.Retryable(err)).To(BeTrue())
+		})
+
+		It("should stop retrying once the context is done", func() {
+			cancelled, cancel := context.WithCancel(ctx)
+			runner.Backoff = time.Hour
+			mockDB.ExpectBegin()
+			mockDB.ExpectExec("INSERT INTO thing").WillReturnError(&pq.Error{Code: "40001"})
+			mockDB.ExpectRollback()
+
+			err := runner.Run(cancelled, func(tx database.DBInterface) error {
+				defer cancel()
+				return insert(tx)
+			})
+			Expect(database.Retryable(err)).To(BeTrue())
+		})
+	})
+
+	Context("when the isolation level is set", func() {
+		It("should begin the transaction with it", func() {
+			beginner := &recordingBeginner{}
+			runner.DB = beginner
+			runner.Isolation = sql.LevelSerializable
+			runner.ReadOnly = true
+
+			err := runner.Run(ctx, func(tx database.DBInterface) error { return nil })
+			Expect(err).To(Equal(sql.ErrConnDone))
+			Expect(beginner.opts).To(Equal(&sql.TxOptions{
+				Isolation: sql.LevelSerializable,
+				ReadOnly:  true,
+			}))
+		})
+	})
+
+	Context("Retryable", func() {
+		It("should match serialization failures and deadlocks only", func() {
+			Expect(database.Retryable(&pq.Error{Code: "40001"})).To(BeTrue())
+			Expect(database.Retryable(&pq.Error{Code: "40P01"})).To(BeTrue())
+			Expect(database.Retryable(&wrappedError{err: &pq.Error{Code: "40001"}})).To(BeTrue())
+			Expect(database.Retryable(&pq.Error{Code: "23505"})).To(BeFalse())
+			Expect(database.Retryable(sql.ErrNoRows)).To(BeFalse())
+			Expect(database.Retryable(nil)).To(BeFalse())
+		})
+	})
+
+	Context("InTx", func() {
+		It("should open a transaction when the client can begin one", func() {
+			db, mock, _ := sqlmock.New()
+			mock.ExpectBegin()
+			mock.ExpectCommit()
+
+			Expect(database.InTx(ctx, db, func(tx database.DBInterface) error {
+				Expect(tx).ToNot(Equal(db))
+				return nil
+			})).To(Succeed())
+			Expect(mock.ExpectationsWereMet()).To(Succeed())
+		})
+
+		It("should join the caller's transaction when the client is scoped to one", func() {
+			db, mock, _ := sqlmock.New()
+			mock.ExpectBegin()
+			tx, err := db.Begin()
+			Expect(err).ToNot(HaveOccurred())
+
+			var joined database.DBInterface
+			Expect(database.InTx(ctx, tx, func(scoped database.DBInterface) error {
+				joined = scoped
+				return nil
+			})).To(Succeed())
+			Expect(joined).To(BeIdenticalTo(tx))
+			Expect(mock.ExpectationsWereMet()).To(Succeed())
+		})
+
+		It("should refuse a client that cannot begin a transaction", func() {
+			called := false
+			err := database.InTx(ctx, &databasefakes.FakeDBInterface{},
+				func(tx database.DBInterface) error {
+					called = true
+					return nil
+				})
+			Expect(err).To(Equal(database.ErrNoTransactions))
+			Expect(called).To(BeFalse())
+		})
+	})
+})
+
+//recordingBeginner records the options of the transaction it is asked to begin, which sqlmock
+//ignores, and fails so no *sql.Tx is needed.
+type recordingBeginner struct {
+	opts *sql.TxOptions
+}
+
+func (b *recordingBeginner) BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error) {
+	b.opts = opts
+	return nil, sql.ErrConnDone
+}
+
+type wrappedError struct {
+	err error
+}
+
+func (e *wrappedError) Error() string { return "wrapped: " + e.err.Error() }
+
+func (e *wrappedError) Unwrap() error { return e.err }
@@ -0,0 +1,352 @@
+package account
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"service/auth/emailtoken"
+	"service/auth/refresh"
+	"service/auth/throttle"
+	"service/handlers/loggederror"
+	"service/identity"
+	"service/log"
+	"service/mail"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi"
+	"go.uber.org/zap"
+)
+
+//Handler ... serves the password reset and email verification flows.
+//go:generate counterfeiter . Handler
+type Handler interface {
+	RequestPasswordReset(w http.ResponseWriter, req *http.Request)
+	ConfirmPasswordReset(w http.ResponseWriter, req *http.Request)
+	RequestEmailVerification(w http.ResponseWriter, req *http.Request)
+	ConfirmEmailVerification(w http.ResponseWriter, req *http.Request)
+}
+
+//Paths on BaseURL the emailed links point at, each gets a token query parameter.
+const (
+	ResetPath  = "/reset-password"
+	VerifyPath = "/verify-email"
+)
+
+//HandlerObject ...
+//Holds what the flows need to find identities, issue tokens and send the emails, and
+//to sign out everywhere once a password is reset.
+type HandlerObject struct {
+	Log        log.ProdInterface
+	Identities identity.ServiceInterface
+	Tokens     emailtoken.Interface
+	Mailer     mail.Mailer
+	Templates  *mail.Templates
+	Throttle   throttle.Interface
+	Refresh    refresh.Interface
+	Sessions   identity.SessionRevoker
+	BaseURL    string
+}
+
+//NewHandlerObject ...
+//baseURL is the front end serving ResetPath and VerifyPath.
+func NewHandlerObject(logClient log.ProdInterface, identities identity.ServiceInterface,
+	tokens emailtoken.Interface, mailer mail.Mailer, templates *mail.Templates,
+	throttle throttle.Interface, refresh refresh.Interface, sessions identity.SessionRevoker,
+	baseURL string) *HandlerObject {
+	return &HandlerObject{
+		Log:        logClient,
+		Identities: identities,
+		Tokens:     tokens,
+		Mailer:     mailer,
+		Templates:  templates,
+		Throttle:   throttle,
+		Refresh:    refresh,
+		Sessions:   sessions,
+		BaseURL:    strings.TrimSuffix(baseURL, "/"),
+	}
+}
+
+type emailPostBody struct {
+	Email string `json:"email"`
+}
+
+type resetPostBody struct {
+	Token    string `json:"token"`
+	Password string `json:"password"`
+}
+
+type tokenPostBody struct {
+	Token string `json:"token"`
+}
+
+//templateData ... is what the mail templates can use.
+type templateData struct {
+	Name     string
+	Email    string
+	URL      string
+	ValidFor string
+}
+
+//RequestPasswordReset ...
+//POST /auth/password/reset, emails a reset link to the identity with that email. It
+//always responds 202 and mails off the request path, so callers cannot probe for
+//registered emails by the answer or its timing. Requests are throttled per email and
+//per client IP so nobody can flood an inbox, under their own IP key so they never hold
+//back that IP's logins.
+func (h *HandlerObject) RequestPasswordReset(w http.ResponseWriter, req *http.Request) {
+	var jsonDoc emailPostBody
+	if !h.decodeBody(&jsonDoc, "RequestPasswordReset", w, req) {
+		return
+	}
+	if jsonDoc.Email == "" {
+		h.softError(http.StatusBadRequest, "missing required reset params",
+			"RequestPasswordReset", w, req)
+		return
+	}
+	emailKey := throttle.EmailKey(jsonDoc.Email)
+	ipKey := throttle.ResetIPKey(throttle.ClientIP(req))
+	wait, err := h.Throttle.Check(req.Context(), emailKey, ipKey)
+	if err != nil {
+		h.internalServerError(err, "RequestPasswordReset", w, req)
+		return
+	}
+	if wait > 0 {
+		throttle.SetRetryAfter(w, wait)
+		h.softError(http.StatusTooManyRequests, "too many password reset requests",
+			"RequestPasswordReset", w, req)
+		return
+	}
+	//Every request counts, a reset is never a failure that a later success clears.
+	if err := h.Throttle.Fail(req.Context(), emailKey, ipKey); err != nil {
+		h.internalServerError(err, "RequestPasswordReset", w, req)
+		return
+	}
+	row, err := h.Identities.FindByEmail(req.Context(), jsonDoc.Email)
+	if err != nil && err != sql.ErrNoRows {
+		h.internalServerError(err, "RequestPasswordReset", w, req)
+		return
+	}
+	if err == nil {
+		go h.sendDetached(emailtoken.PurposePasswordReset, mail.TemplatePasswordReset,
+			ResetPath, row)
+	}
+	w.WriteHeader(http.StatusAccepted)
+}
+
+//ConfirmPasswordReset ...
+//POST /auth/password/reset/confirm, sets a new password with a reset token, lifts any
+//login lockout on the identity and ends its refresh tokens and sessions, as the old
+//password may have been how someone else got in.
+func (h *HandlerObject) ConfirmPasswordReset(w http.ResponseWriter, req *http.Request) {
+	var jsonDoc resetPostBody
+	if !h.decodeBody(&jsonDoc, "ConfirmPasswordReset", w, req) {
+		return
+	}
+	if jsonDoc.Token == "" {
+		h.softError(http.StatusBadRequest, "missing required reset params",
+			"ConfirmPasswordReset", w, req)
+		return
+	}
+	//Checked before the token is spent so a weak password doesn't burn it.
+	if len(jsonDoc.Password) < identity.MinPasswordLength {
+		h.softError(http.StatusBadRequest, "password must be at least 8 characters",
+			"ConfirmPasswordReset", w, req)
+		return
+	}
+	claim, err := h.Tokens.Consume(req.Context(), emailtoken.PurposePasswordReset, jsonDoc.Token)
+	if err == emailtoken.ErrInvalid {
+		h.softError(http.StatusBadRequest, err.Error(), "ConfirmPasswordReset", w, req)
+		return
+	}
+	if err != nil {
+		h.internalServerError(err, "ConfirmPasswordReset", w, req)
+		return
+	}
+	err = h.Identities.SetPassword(req.Context(), claim.IdentityID, jsonDoc.Password)
+	if err == sql.ErrNoRows {
+		h.softError(http.StatusBadRequest, emailtoken.ErrInvalid.Error(),
+			"ConfirmPasswordReset", w, req)
+		return
+	}
+	if err != nil {
+		h.internalServerError(err, "ConfirmPasswordReset", w, req)
+		return
+	}
+	if err := h.Throttle.Succeed(req.Context(), throttle.IdentityKey(claim.IdentityID)); err != nil {
+		h.internalServerError(err, "ConfirmPasswordReset", w, req)
+		return
+	}
+	if err := h.Refresh.RevokeAll(req.Context(), claim.IdentityID); err != nil {
+		h.internalServerError(err, "ConfirmPasswordReset", w, req)
+		return
+	}
+	if err := h.Sessions.RevokeAll(req.Context(), claim.IdentityID); err != nil {
+		h.internalServerError(err, "ConfirmPasswordReset", w, req)
+		return
+	}
+	h.Log.Info("password reset",
+		zap.String("event", "auth.password_reset"),
+		zap.String("identityID", claim.IdentityID))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+//RequestEmailVerification ... POST /identity/{id}/email/verify, emails a verification link.
+func (h *HandlerObject) RequestEmailVerification(w http.ResponseWriter, req *http.Request) {
+	row, err := h.Identities.Fetch(req.Context(), chi.URLParam(req, "id"))
+	if err == sql.ErrNoRows {
+		h.softError(http.StatusNotFound, "identity not found", "RequestEmailVerification", w, req)
+		return
+	}
+	if err != nil {
+		h.internalServerError(err, "RequestEmailVerification", w, req)
+		return
+	}
+	if profileEmail(row) == "" {
+		h.softError(http.StatusBadRequest, "identity has no email",
+			"RequestEmailVerification", w, req)
+		return
+	}
+	err = h.send(req.Context(), emailtoken.PurposeEmailVerify, mail.TemplateEmailVerify,
+		VerifyPath, row)
+	if err != nil {
+		h.internalServerError(err, "RequestEmailVerification", w, req)
+		return
+	}
+	w.WriteHeader(http.StatusAccepted)
+}
+
+//ConfirmEmailVerification ...
+//POST /auth/email/verify, marks the email verified if it hasn't changed since the link
+//was sent.
+func (h *HandlerObject) ConfirmEmailVerification(w http.ResponseWriter, req *http.Request) {
+	var jsonDoc tokenPostBody
+	if !h.decodeBody(&jsonDoc, "ConfirmEmailVerification", w, req) {
+		return
+	}
+	if jsonDoc.Token == "" {
+		h.softError(http.StatusBadRequest, "missing required verification params",
+			"ConfirmEmailVerification", w, req)
+		return
+	}
+	claim, err := h.Tokens.Consume(req.Context(), emailtoken.PurposeEmailVerify, jsonDoc.Token)
+	if err == emailtoken.ErrInvalid {
+		h.softError(http.StatusBadRequest, err.Error(), "ConfirmEmailVerification", w, req)
+		return
+	}
+	if err != nil {
+		h.internalServerError(err, "ConfirmEmailVerification", w, req)
+		return
+	}
+	err = h.Identities.MarkEmailVerified(req.Context(), claim.IdentityID, claim.Email)
+	if err == sql.ErrNoRows {
+		h.softError(http.StatusBadRequest, emailtoken.ErrInvalid.Error(),
+			"ConfirmEmailVerification", w, req)
+		return
+	}
+	if err != nil {
+		h.internalServerError(err, "ConfirmEmailVerification", w, req)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+//sendTimeout bounds mail sent after the response, when no request deadline applies.
+const sendTimeout = 30 * time.Second
+
+//sendDetached is send for after the response has gone, failures can only be logged as
+//answering differently would reveal the email is registered.
+func (h *HandlerObject) sendDetached(purpose, templateName, path string, row *identity.Row) {
+	ctx, cancel := context.WithTimeout(context.Background(), sendTimeout)
+	defer cancel()
+	if err := h.send(ctx, purpose, templateName, path, row); err != nil {
+		h.Log.Error("account_handler::RequestPasswordReset", zap.Error(err))
+	}
+}
+
+//send issues a token for row's email and mails it a link to path.
+func (h *HandlerObject) send(ctx context.Context, purpose, templateName, path string,
+	row *identity.Row) error {
+	email := profileEmail(row)
+	token, ttl, err := h.Tokens.Issue(ctx, purpose, row.ID, email)
+	if err != nil {
+		return err
+	}
+	name := row.FirstName
+	if name == "" {
+		name = "there"
+	}
+	msg, err := h.Templates.Render(templateName, email, &templateData{
+		Name:     name,
+		Email:    email,
+		URL:      h.BaseURL + path + "?token=" + url.QueryEscape(token),
+		ValidFor: humanize(ttl),
+	})
+	if err != nil {
+		return err
+	}
+	return h.Mailer.Send(msg)
+}
+
+//profileEmail is the "email" of the identity's profile, if it has one.
+func profileEmail(row *identity.Row) string {
+	profile, ok := row.ProfileInfo.(map[string]interface{})
+	if !ok {
+		return ""
+	}
+	email, _ := profile["email"].(string)
+	return email
+}
+
+//humanize writes whole hours or minutes the way a person would.
+func humanize(d time.Duration) string {
+	if d >= time.Hour && d%time.Hour == 0 {
+		return plural(int(d/time.Hour), "hour")
+	}
+	return plural(int(d/time.Minute), "minute")
+}
+
+func plural(n int, unit string) string {
+	if n == 1 {
+		return fmt.Sprintf("1 %s", unit)
+	}
+	return fmt.Sprintf("%d %ss", n, unit)
+}
+
+func (h *HandlerObject) decodeBody(v interface{}, source string, w http.ResponseWriter,
+	req *http.Request) bool {
+	if req.Body == nil || json.NewDecoder(req.Body).Decode(v) != nil {
+		h.softError(http.StatusBadRequest, "bad request", source, w, req)
+		return false
+	}
+	return true
+}
+
+// internalServerError is used to wrap our loggederror for this route.
+func (h *HandlerObject) internalServerError(err error, source string, w http.ResponseWriter,
+	req *http.Request) {
+	loggederror.RespondWithProperErrorAndLogIt(
+		h.Log,
+		http.StatusInternalServerError,
+		err,
+		"account_handler::"+source,
+		w,
+		req,
+	)
+}
+
+// softError is used to wrap our loggederror for expected failures on this route.
+func (h *HandlerObject) softError(status int, message, source string, w http.ResponseWriter,
+	req *http.Request) {
+	loggederror.RespondWithWithExpectedSoftError(
+		h.Log,
+		status,
+		message,
+		"account_handler::"+source,
+		w,
+		req,
+	)
+}
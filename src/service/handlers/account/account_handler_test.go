@@ -0,0 +1,232 @@
+package account_test
+
+import (
+	"bytes"
+	"database/sql"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"service/auth/emailtoken"
+	"service/auth/emailtoken/emailtokenfakes"
+	"service/auth/refresh/refreshfakes"
+	"service/auth/session/sessionfakes"
+	"service/auth/throttle/throttlefakes"
+	"service/handlers/account"
+	"service/identity"
+	"service/identity/identityfakes"
+	"service/log/logfakes"
+	"service/mail"
+	"service/mail/mailfakes"
+	"time"
+
+	"github.com/go-chi/chi"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Account Handler Specs", func() {
+	var (
+		handler        *account.HandlerObject
+		fakeIdentities *identityfakes.FakeServiceInterface
+		fakeTokens     *emailtokenfakes.FakeInterface
+		fakeMailer     *mailfakes.FakeMailer
+		fakeThrottle   *throttlefakes.FakeInterface
+		fakeRefresh    *refreshfakes.FakeInterface
+		fakeSessions   *sessionfakes.FakeInterface
+		fakeLog        *logfakes.FakeProdInterface
+		router         *chi.Mux
+		recorder       *httptest.ResponseRecorder
+	)
+
+	tony := &identity.Row{
+		ID:          "test_id",
+		FirstName:   "Tony",
+		ProfileInfo: map[string]interface{}{"email": "tony@example.com"},
+	}
+
+	BeforeEach(func() {
+		fakeIdentities = &identityfakes.FakeServiceInterface{}
+		fakeTokens = &emailtokenfakes.FakeInterface{}
+		fakeMailer = &mailfakes.FakeMailer{}
+		fakeThrottle = &throttlefakes.FakeInterface{}
+		fakeRefresh = &refreshfakes.FakeInterface{}
+		fakeSessions = &sessionfakes.FakeInterface{}
+		fakeLog = &logfakes.FakeProdInterface{}
+		templates, err := mail.NewTemplates("")
+		Expect(err).ToNot(HaveOccurred())
+		handler = account.NewHandlerObject(fakeLog, fakeIdentities, fakeTokens, fakeMailer,
+			templates, fakeThrottle, fakeRefresh, fakeSessions, "https://app.example.com/")
+		router = chi.NewRouter()
+		router.Post("/auth/password/reset", handler.RequestPasswordReset)
+		router.Post("/auth/password/reset/confirm", handler.ConfirmPasswordReset)
+		router.Post("/auth/email/verify", handler.ConfirmEmailVerification)
+		router.Post("/identity/{id}/email/verify", handler.RequestEmailVerification)
+		recorder = httptest.NewRecorder()
+		fakeTokens.IssueReturns("signed.token", time.Hour, nil)
+	})
+
+	serve := func(path, body string) {
+		request := httptest.NewRequest("POST", path, bytes.NewBufferString(body))
+		router.ServeHTTP(recorder, request)
+	}
+
+	Context("POST /auth/password/reset", func() {
+		It("should mail a reset link to a known email", func() {
+			fakeIdentities.FindByEmailReturns(tony, nil)
+			serve("/auth/password/reset", `{"email": "TONY@example.com"}`)
+			Expect(recorder.Code).To(Equal(http.StatusAccepted))
+			Eventually(fakeMailer.SendCallCount).Should(Equal(1))
+			_, purpose, id, email := fakeTokens.IssueArgsForCall(0)
+			Expect(purpose).To(Equal(emailtoken.PurposePasswordReset))
+			Expect(id).To(Equal("test_id"))
+			Expect(email).To(Equal("tony@example.com"))
+			msg := fakeMailer.SendArgsForCall(0)
+			Expect(msg.To).To(Equal("tony@example.com"))
+			Expect(msg.Body).To(ContainSubstring("Hi Tony,"))
+			Expect(msg.Body).To(ContainSubstring(
+				"https://app.example.com/reset-password?token=signed.token"))
+			Expect(msg.Body).To(ContainSubstring("within 1 hour"))
+		})
+
+		It("should answer the same for an unknown email", func() {
+			fakeIdentities.FindByEmailReturns(nil, sql.ErrNoRows)
+			serve("/auth/password/reset", `{"email": "nobody@example.com"}`)
+			Expect(recorder.Code).To(Equal(http.StatusAccepted))
+			Expect(fakeTokens.IssueCallCount()).To(Equal(0))
+			Expect(fakeMailer.SendCallCount()).To(Equal(0))
+		})
+
+		It("should log but not reveal a failed send", func() {
+			fakeIdentities.FindByEmailReturns(tony, nil)
+			fakeMailer.SendReturns(errors.New("relay down"))
+			serve("/auth/password/reset", `{"email": "tony@example.com"}`)
+			Expect(recorder.Code).To(Equal(http.StatusAccepted))
+			Eventually(fakeLog.ErrorCallCount).Should(Equal(1))
+		})
+
+		It("should count every request against the email and the client IP", func() {
+			fakeIdentities.FindByEmailReturns(nil, sql.ErrNoRows)
+			serve("/auth/password/reset", `{"email": "Tony@Example.com"}`)
+			Expect(recorder.Code).To(Equal(http.StatusAccepted))
+			_, keys := fakeThrottle.CheckArgsForCall(0)
+			Expect(keys).To(Equal([]string{"email:tony@example.com", "reset-ip:192.0.2.1"}))
+			_, keys = fakeThrottle.FailArgsForCall(0)
+			Expect(keys).To(Equal([]string{"email:tony@example.com", "reset-ip:192.0.2.1"}))
+		})
+
+		It("should respond 429 without looking the email up once throttled", func() {
+			fakeThrottle.CheckReturns(time.Minute, nil)
+			serve("/auth/password/reset", `{"email": "tony@example.com"}`)
+			Expect(recorder.Code).To(Equal(http.StatusTooManyRequests))
+			Expect(recorder.Header().Get("Retry-After")).To(Equal("60"))
+			Expect(fakeIdentities.FindByEmailCallCount()).To(Equal(0))
+			Expect(fakeThrottle.FailCallCount()).To(Equal(0))
+		})
+
+		It("should respond 400 without an email", func() {
+			serve("/auth/password/reset", `{}`)
+			Expect(recorder.Code).To(Equal(http.StatusBadRequest))
+		})
+	})
+
+	Context("POST /auth/password/reset/confirm", func() {
+		BeforeEach(func() {
+			fakeTokens.ConsumeReturns(&emailtoken.Claim{IdentityID: "test_id"}, nil)
+		})
+
+		It("should set the password, clear failed logins and respond 204", func() {
+			serve("/auth/password/reset/confirm", `{"token": "signed.token", "password": "new password"}`)
+			Expect(recorder.Code).To(Equal(http.StatusNoContent))
+			_, purpose, token := fakeTokens.ConsumeArgsForCall(0)
+			Expect(purpose).To(Equal(emailtoken.PurposePasswordReset))
+			Expect(token).To(Equal("signed.token"))
+			_, id, password := fakeIdentities.SetPasswordArgsForCall(0)
+			Expect(id).To(Equal("test_id"))
+			Expect(password).To(Equal("new password"))
+			_, keys := fakeThrottle.SucceedArgsForCall(0)
+			Expect(keys).To(Equal([]string{"identity:test_id"}))
+		})
+
+		It("should end the identity's refresh tokens and sessions", func() {
+			serve("/auth/password/reset/confirm", `{"token": "signed.token", "password": "new password"}`)
+			Expect(recorder.Code).To(Equal(http.StatusNoContent))
+			_, id := fakeRefresh.RevokeAllArgsForCall(0)
+			Expect(id).To(Equal("test_id"))
+			_, id = fakeSessions.RevokeAllArgsForCall(0)
+			Expect(id).To(Equal("test_id"))
+		})
+
+		It("should respond 500 when the refresh tokens cannot be revoked", func() {
+			fakeRefresh.RevokeAllReturns(errors.New("db down"))
+			serve("/auth/password/reset/confirm", `{"token": "signed.token", "password": "new password"}`)
+			Expect(recorder.Code).To(Equal(http.StatusInternalServerError))
+			Expect(fakeSessions.RevokeAllCallCount()).To(Equal(0))
+		})
+
+		It("should not spend the token on a short password", func() {
+			serve("/auth/password/reset/confirm", `{"token": "signed.token", "password": "short"}`)
+			Expect(recorder.Code).To(Equal(http.StatusBadRequest))
+			Expect(fakeTokens.ConsumeCallCount()).To(Equal(0))
+		})
+
+		It("should respond 400 for an invalid token", func() {
+			fakeTokens.ConsumeReturns(nil, emailtoken.ErrInvalid)
+			serve("/auth/password/reset/confirm", `{"token": "used", "password": "new password"}`)
+			Expect(recorder.Code).To(Equal(http.StatusBadRequest))
+			Expect(recorder.Body.String()).To(Equal("invalid or expired token\n"))
+			Expect(fakeIdentities.SetPasswordCallCount()).To(Equal(0))
+		})
+	})
+
+	Context("POST /identity/{id}/email/verify", func() {
+		It("should mail a verification link", func() {
+			fakeIdentities.FetchReturns(tony, nil)
+			serve("/identity/test_id/email/verify", "")
+			Expect(recorder.Code).To(Equal(http.StatusAccepted))
+			_, purpose, _, _ := fakeTokens.IssueArgsForCall(0)
+			Expect(purpose).To(Equal(emailtoken.PurposeEmailVerify))
+			Expect(fakeMailer.SendArgsForCall(0).Body).To(ContainSubstring(
+				"https://app.example.com/verify-email?token=signed.token"))
+		})
+
+		It("should respond 400 when the identity has no email", func() {
+			fakeIdentities.FetchReturns(&identity.Row{ID: "test_id"}, nil)
+			serve("/identity/test_id/email/verify", "")
+			Expect(recorder.Code).To(Equal(http.StatusBadRequest))
+			Expect(fakeTokens.IssueCallCount()).To(Equal(0))
+		})
+
+		It("should respond 404 for an unknown identity", func() {
+			fakeIdentities.FetchReturns(nil, sql.ErrNoRows)
+			serve("/identity/missing/email/verify", "")
+			Expect(recorder.Code).To(Equal(http.StatusNotFound))
+		})
+
+		It("should respond 500 when the mail can't be sent", func() {
+			fakeIdentities.FetchReturns(tony, nil)
+			fakeMailer.SendReturns(errors.New("relay down"))
+			serve("/identity/test_id/email/verify", "")
+			Expect(recorder.Code).To(Equal(http.StatusInternalServerError))
+		})
+	})
+
+	Context("POST /auth/email/verify", func() {
+		It("should mark the emailed address verified", func() {
+			fakeTokens.ConsumeReturns(&emailtoken.Claim{IdentityID: "test_id",
+				Email: "tony@example.com"}, nil)
+			serve("/auth/email/verify", `{"token": "signed.token"}`)
+			Expect(recorder.Code).To(Equal(http.StatusNoContent))
+			_, id, email := fakeIdentities.MarkEmailVerifiedArgsForCall(0)
+			Expect(id).To(Equal("test_id"))
+			Expect(email).To(Equal("tony@example.com"))
+		})
+
+		It("should respond 400 when the email changed since the link was sent", func() {
+			fakeTokens.ConsumeReturns(&emailtoken.Claim{IdentityID: "test_id",
+				Email: "old@example.com"}, nil)
+			fakeIdentities.MarkEmailVerifiedReturns(sql.ErrNoRows)
+			serve("/auth/email/verify", `{"token": "signed.token"}`)
+			Expect(recorder.Code).To(Equal(http.StatusBadRequest))
+		})
+	})
+})
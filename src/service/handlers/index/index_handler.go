@@ -5,6 +5,7 @@ import (
 	"io"
 	"net/http"
 	"service/database"
+	"service/handlers/loggederror"
 	"service/log"
 	"time"
 
@@ -55,27 +56,16 @@ func New(log log.ProdInterface, db database.DBInterface) *Index {
 }
 
 func (i *Index) indexLogic(w http.ResponseWriter, req *http.Request) {
-	rows, err := i.dbClient.Query("SELECT id, name, description, date_added FROM event;")
+	rows, err := i.dbClient.QueryContext(req.Context(),
+		"SELECT "+database.ColumnList(EventRow{})+" FROM event;")
 	if err != nil {
-		panic(err)
+		i.internalServerError(err, w, req)
+		return
 	}
-	defer func() {
-		closeErr := rows.Close()
-		if closeErr != nil {
-			panic(closeErr)
-		}
-	}()
 	eventRows := make([]EventRow, 0)
-	for rows.Next() {
-		var eventRow EventRow
-		if scanErr := rows.Scan(
-			&eventRow.ID,
-			&eventRow.Name,
-			&eventRow.Description,
-			&eventRow.DateAdded); scanErr != nil {
-			panic(scanErr)
-		}
-		eventRows = append(eventRows, eventRow)
+	if scanErr := database.ScanAll(rows, &eventRows); scanErr != nil {
+		i.internalServerError(scanErr, w, req)
+		return
 	}
 
 	jsonErr := marshalEventRows(eventRows, w)
@@ -84,6 +74,19 @@ func (i *Index) indexLogic(w http.ResponseWriter, req *http.Request) {
 	}
 }
 
+// internalServerError is used to wrap our loggederror for this route, a cancelled or
+// timed out query becomes a 503 or 504.
+func (i *Index) internalServerError(err error, w http.ResponseWriter, req *http.Request) {
+	loggederror.RespondWithProperErrorAndLogIt(
+		i.log,
+		http.StatusInternalServerError,
+		err,
+		"index_handler::Handler",
+		w,
+		req,
+	)
+}
+
 func marshalEventRows(events []EventRow, w io.Writer) error {
 	response := EventsResponse{
 		Code: http.StatusOK,
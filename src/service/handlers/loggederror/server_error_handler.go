@@ -1,6 +1,8 @@
 package loggederror
 
 import (
+	"context"
+	"errors"
 	"net/http"
 	"service/handlers/request"
 	"service/log"
@@ -10,11 +12,13 @@ import (
 
 //RespondWithProperErrorAndLogIt ...
 //will respond with an error object that is marshaled to json, and wrap the message from
-//the passed in error.
+//the passed in error. A 500 caused by the request's deadline passing becomes a 504, and
+//one caused by the request being cancelled a 503.
 func RespondWithProperErrorAndLogIt(log log.ProdInterface, status int,
 	err error, context string, w http.ResponseWriter, req *http.Request) {
 	//proper http error using standard lib.
 	requestID := request.RetreiveRequestID(req.Context())
+	status = timeoutStatus(status, err, req)
 	if err != nil {
 		log.Error(context, zap.String("requestID", requestID), zap.Error(err))
 		http.Error(w, err.Error(), status)
@@ -35,3 +39,19 @@ func RespondWithWithExpectedSoftError(log log.ProdInterface, status int, message
 		zap.String("message", message), zap.String("source", source))
 	http.Error(w, message, status)
 }
+
+//timeoutStatus checks the request's context as well as err, the postgres driver reports
+//a cancelled query as its own error rather than the context's.
+func timeoutStatus(status int, err error, req *http.Request) int {
+	if status != http.StatusInternalServerError {
+		return status
+	}
+	switch {
+	case errors.Is(err, context.DeadlineExceeded),
+		req.Context().Err() == context.DeadlineExceeded:
+		return http.StatusGatewayTimeout
+	case errors.Is(err, context.Canceled), req.Context().Err() == context.Canceled:
+		return http.StatusServiceUnavailable
+	}
+	return status
+}
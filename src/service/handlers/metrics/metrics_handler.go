@@ -0,0 +1,65 @@
+package metrics
+
+import (
+	"encoding/json"
+	"net/http"
+	"service/database"
+	"service/handlers/loggederror"
+	"service/log"
+)
+
+//Handler ... serves the metrics the service keeps about itself.
+//go:generate counterfeiter . Handler
+type Handler interface {
+	Database(w http.ResponseWriter, req *http.Request)
+}
+
+//StatsSource ... is satisfied by database.Instrumented.
+type StatsSource interface {
+	Stats() []database.StatementStats
+}
+
+//DatabaseResponse ... is the per-statement latency and error report.
+type DatabaseResponse struct {
+	Code           int                       `json:"status"`
+	LatencyBuckets []int64                   `json:"latencyBucketNanos"`
+	Statements     []database.StatementStats `json:"statements"`
+}
+
+//HandlerObject ...
+//Holds where the database metrics are read from.
+type HandlerObject struct {
+	Log   log.ProdInterface
+	Stats StatsSource
+}
+
+//NewHandlerObject ...
+//returns a HandlerObject reporting the metrics of stats.
+func NewHandlerObject(log log.ProdInterface, stats StatsSource) *HandlerObject {
+	return &HandlerObject{
+		Log:   log,
+		Stats: stats,
+	}
+}
+
+//Database ...
+//GET /metrics/database
+func (h *HandlerObject) Database(w http.ResponseWriter, req *http.Request) {
+	buckets := make([]int64, len(database.LatencyBuckets))
+	for i, bucket := range database.LatencyBuckets {
+		buckets[i] = int64(bucket)
+	}
+	body, err := json.Marshal(DatabaseResponse{
+		Code:           http.StatusOK,
+		LatencyBuckets: buckets,
+		Statements:     h.Stats.Stats(),
+	})
+	if err != nil {
+		loggederror.RespondWithProperErrorAndLogIt(h.Log, http.StatusInternalServerError,
+			err, "metrics::Database", w, req)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.Write(body)
+}
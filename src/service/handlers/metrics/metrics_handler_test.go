@@ -0,0 +1,37 @@
+package metrics_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"service/database"
+	"service/database/databasefakes"
+	"service/handlers/metrics"
+	"service/log/logfakes"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Metrics Handler Specs", func() {
+	It("should report the statement metrics of the database", func() {
+		fakeDB := &databasefakes.FakeDBInterface{}
+		instrumented := database.NewInstrumented(&logfakes.FakeProdInterface{}, fakeDB)
+		_, _ = instrumented.ExecContext(context.Background(), "DELETE FROM thing;")
+		handler := metrics.NewHandlerObject(&logfakes.FakeProdInterface{}, instrumented)
+
+		recorder := httptest.NewRecorder()
+		handler.Database(recorder, httptest.NewRequest(http.MethodGet, "/metrics/database", nil))
+
+		Expect(recorder.Code).To(Equal(http.StatusOK))
+		Expect(recorder.Header().Get("Cache-Control")).To(Equal("no-store"))
+		var response metrics.DatabaseResponse
+		Expect(json.Unmarshal(recorder.Body.Bytes(), &response)).To(Succeed())
+		Expect(response.Code).To(Equal(http.StatusOK))
+		Expect(response.LatencyBuckets).To(HaveLen(len(database.LatencyBuckets)))
+		Expect(response.Statements).To(HaveLen(1))
+		Expect(response.Statements[0].Query).To(Equal("DELETE FROM thing;"))
+		Expect(response.Statements[0].Count).To(Equal(int64(1)))
+	})
+})
@@ -0,0 +1,47 @@
+package request
+
+import (
+	"context"
+	"service/auth/token/jwt"
+)
+
+const claimsKey = key("claims")
+
+const basicUserKey = key("basicUser")
+
+//WithClaims ... returns a copy of ctx carrying the verified token claims.
+func WithClaims(ctx context.Context, claims *jwt.IdentityClaims) context.Context {
+	return context.WithValue(ctx, claimsKey, claims)
+}
+
+//RetreiveClaims ... retrieves the verified token claims stored by the bearer
+//middleware, false when the request was not authenticated with a token.
+func RetreiveClaims(ctx context.Context) (*jwt.IdentityClaims, bool) {
+	claims, ok := ctx.Value(claimsKey).(*jwt.IdentityClaims)
+	return claims, ok && claims != nil
+}
+
+//RetreiveSubject ... retrieves the identity ID (sub claim) of the token holder
+//and returns as a string, empty when there is none.
+func RetreiveSubject(ctx context.Context) string {
+	claims, ok := RetreiveClaims(ctx)
+	if !ok {
+		return ""
+	}
+	return claims.Subject
+}
+
+//WithBasicUser ... returns a copy of ctx recording the basic auth username.
+func WithBasicUser(ctx context.Context, username string) context.Context {
+	return context.WithValue(ctx, basicUserKey, username)
+}
+
+//RetreiveBasicUser ... retrieves the username stored by the basic auth middleware
+//and returns as a string, empty when the request did not use basic auth.
+func RetreiveBasicUser(ctx context.Context) string {
+	username, ok := ctx.Value(basicUserKey).(string)
+	if !ok {
+		return ""
+	}
+	return username
+}
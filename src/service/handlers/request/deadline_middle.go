@@ -0,0 +1,49 @@
+package request
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+const deadlineParentKey = key("deadlineParent")
+
+//Deadline ... bounds the database work of the requests it wraps to timeout, on top of
+//the request being cancelled when its client goes away. A route's Deadline replaces
+//its group's, so one route can be given longer than the default as well as shorter.
+//A zero timeout leaves the request unbounded, dropping any outer Deadline too.
+func Deadline(timeout time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			//Working from the context before any outer Deadline drops that deadline while
+			//keeping the client's cancellation and every value set since.
+			ctx := req.Context()
+			parent, ok := ctx.Value(deadlineParentKey).(context.Context)
+			if !ok {
+				parent = ctx
+			}
+			if timeout <= 0 {
+				if ok {
+					ctx = &valuesFrom{Context: parent, values: ctx}
+				}
+				next.ServeHTTP(w, req.WithContext(ctx))
+				return
+			}
+			timed, cancel := context.WithTimeout(parent, timeout)
+			defer cancel()
+			ctx = context.WithValue(&valuesFrom{Context: timed, values: ctx},
+				deadlineParentKey, parent)
+			next.ServeHTTP(w, req.WithContext(ctx))
+		})
+	}
+}
+
+//valuesFrom is cancelled with its embedded Context but looks values up in values.
+type valuesFrom struct {
+	context.Context
+	values context.Context
+}
+
+func (v *valuesFrom) Value(key interface{}) interface{} {
+	return v.values.Value(key)
+}
@@ -0,0 +1,59 @@
+package request_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"service/handlers/request"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+type valueKey struct{}
+
+var _ = Describe("Deadline Specs", func() {
+	var (
+		ctx      context.Context
+		deadline time.Time
+		bounded  bool
+	)
+
+	//serve runs a request through the middlewares, setting a value in between so the
+	//innermost one can be checked to keep it.
+	serve := func(outer, inner func(http.Handler) http.Handler) {
+		handler := outer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			req = req.WithContext(context.WithValue(req.Context(), valueKey{}, "kept"))
+			inner(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				ctx = req.Context()
+				deadline, bounded = ctx.Deadline()
+			})).ServeHTTP(w, req)
+		}))
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+	}
+
+	It("should bound the request to the timeout", func() {
+		serve(request.Deadline(5*time.Second), request.Deadline(5*time.Second))
+		Expect(bounded).To(BeTrue())
+		Expect(time.Until(deadline)).To(BeNumerically("~", 5*time.Second, time.Second))
+	})
+
+	It("should let a route's longer timeout replace its group's", func() {
+		serve(request.Deadline(5*time.Second), request.Deadline(15*time.Second))
+		Expect(bounded).To(BeTrue())
+		Expect(time.Until(deadline)).To(BeNumerically("~", 15*time.Second, time.Second))
+		Expect(ctx.Value(valueKey{})).To(Equal("kept"))
+	})
+
+	It("should drop the group's timeout for a route with none", func() {
+		serve(request.Deadline(5*time.Second), request.Deadline(0))
+		Expect(bounded).To(BeFalse())
+		Expect(ctx.Value(valueKey{})).To(Equal("kept"))
+	})
+
+	It("should leave a request without an outer timeout unbounded", func() {
+		serve(request.Deadline(0), request.Deadline(0))
+		Expect(bounded).To(BeFalse())
+	})
+})
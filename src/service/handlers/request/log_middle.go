@@ -3,6 +3,7 @@ package request
 import (
 	"fmt"
 	"net/http"
+	"service/database"
 	"service/log"
 	"time"
 
@@ -12,12 +13,14 @@ import (
 
 var logClient log.ProdInterface
 
-//Logger ... defines a response handler and waits for completion.
+//Logger ... defines a response handler and waits for completion. The RESPONSE line
+//counts the queries the request ran through a database.Instrumented and their time.
 func Logger(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
 		ww := middleware.NewWrapResponseWriter(w, req.ProtoMajor)
 		t1 := time.Now()
-		requestID := RetreiveRequestID(req.Context())
+		ctx := database.TrackQueries(req.Context())
+		requestID := RetreiveRequestID(ctx)
 
 		if logClient != nil {
 			logClient.Info("INCOMING", zap.String("requestID", requestID),
@@ -26,16 +29,19 @@ func Logger(next http.Handler) http.Handler {
 
 		defer func() {
 			if logClient != nil {
+				queries, dbTime := database.QueryTotals(ctx)
 				logClient.Info("RESPONSE",
 					zap.Int("status", ww.Status()),
 					zap.String("requestID", requestID),
 					zap.String("size", fmt.Sprintf("%d bytes", ww.BytesWritten())),
 					zap.String("in", time.Since(t1).String()),
+					zap.Int64("queries", queries),
+					zap.String("db", dbTime.String()),
 				)
 			}
 		}()
 
-		next.ServeHTTP(ww, req)
+		next.ServeHTTP(ww, req.WithContext(ctx))
 	})
 }
 
@@ -0,0 +1,14 @@
+package request
+
+import (
+	"net/http"
+	"service/database"
+)
+
+//TrackWrites ... lets a database.Router serve the reads of the requests it wraps from a
+//replica until the request writes, after which the rest of the request reads the primary.
+func TrackWrites(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		next.ServeHTTP(w, req.WithContext(database.TrackWrites(req.Context())))
+	})
+}
@@ -0,0 +1,136 @@
+package wellknown
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"service/auth/token/jwt"
+	"service/handlers/loggederror"
+	"service/log"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+//DefaultMaxAge ...
+//How long clients may cache the documents when no key promotion is coming up sooner.
+const DefaultMaxAge = time.Hour
+
+//Handler ... serves the public discovery documents.
+//go:generate counterfeiter . Handler
+type Handler interface {
+	JWKS(w http.ResponseWriter, req *http.Request)
+	OpenIDConfiguration(w http.ResponseWriter, req *http.Request)
+}
+
+//Configuration ...
+//The subset of OpenID Connect discovery metadata this service can honour.
+type Configuration struct {
+	Issuer                        string   `json:"issuer"`
+	JWKSURI                       string   `json:"jwks_uri"`
+	TokenEndpoint                 string   `json:"token_endpoint"`
+	AuthorizationEndpoint         string   `json:"authorization_endpoint"`
+	IntrospectionEndpoint         string   `json:"introspection_endpoint"`
+	GrantTypesSupported           []string `json:"grant_types_supported"`
+	ResponseTypesSupported        []string `json:"response_types_supported"`
+	CodeChallengeMethodsSupported []string `json:"code_challenge_methods_supported"`
+	TokenEndpointAuthMethods      []string `json:"token_endpoint_auth_methods_supported"`
+	SigningAlgValuesSupported     []string `json:"access_token_signing_alg_values_supported"`
+}
+
+//HandlerObject ...
+//Holds the keyring to publish and the issuer to advertise.
+type HandlerObject struct {
+	Log    log.ProdInterface
+	Keys   *jwt.Keyring
+	Issuer string
+	MaxAge time.Duration
+}
+
+//NewHandlerObject ...
+//An empty issuer is derived from each request's host, and the configuration is then never
+//cached as the host comes from the client. A zero maxAge uses DefaultMaxAge.
+func NewHandlerObject(log log.ProdInterface, keys *jwt.Keyring, issuer string,
+	maxAge time.Duration) *HandlerObject {
+	if maxAge <= 0 {
+		maxAge = DefaultMaxAge
+	}
+	return &HandlerObject{
+		Log:    log,
+		Keys:   keys,
+		Issuer: strings.TrimSuffix(issuer, "/"),
+		MaxAge: maxAge,
+	}
+}
+
+//JWKS ...
+//GET /.well-known/jwks.json
+func (h *HandlerObject) JWKS(w http.ResponseWriter, req *http.Request) {
+	h.respond(w, req, h.Keys.JWKS(), true, "wellknown::JWKS")
+}
+
+//OpenIDConfiguration ...
+//GET /.well-known/openid-configuration
+func (h *HandlerObject) OpenIDConfiguration(w http.ResponseWriter, req *http.Request) {
+	issuer := h.issuer(req)
+	h.respond(w, req, Configuration{
+		Issuer:                        issuer,
+		JWKSURI:                       issuer + "/.well-known/jwks.json",
+		TokenEndpoint:                 issuer + "/oauth/token",
+		AuthorizationEndpoint:         issuer + "/oauth/authorize",
+		IntrospectionEndpoint:         issuer + "/oauth/introspect",
+		GrantTypesSupported:           []string{"authorization_code", "client_credentials", "refresh_token"},
+		ResponseTypesSupported:        []string{"code"},
+		CodeChallengeMethodsSupported: []string{"S256"},
+		TokenEndpointAuthMethods:      []string{"client_secret_basic", "client_secret_post", "none"},
+		SigningAlgValuesSupported:     h.Keys.Algorithms(),
+	}, h.Issuer != "", "wellknown::OpenIDConfiguration")
+}
+
+//respond lets shared caches keep the document when cacheable, documents built from the
+//request must not be served to anyone else.
+func (h *HandlerObject) respond(w http.ResponseWriter, req *http.Request,
+	document interface{}, cacheable bool, source string) {
+	body, err := json.Marshal(document)
+	if err != nil {
+		loggederror.RespondWithProperErrorAndLogIt(h.Log, http.StatusInternalServerError,
+			err, source, w, req)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if cacheable {
+		w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", h.maxAge()/time.Second))
+	} else {
+		w.Header().Set("Cache-Control", "no-store")
+	}
+	if _, writeErr := w.Write(body); writeErr != nil {
+		h.Log.Error(source, zap.Error(writeErr))
+	}
+}
+
+//maxAge caps caching at the next scheduled promotion so verifiers refetch, and see
+//the incoming key, before any token signed with it can reach them.
+func (h *HandlerObject) maxAge() time.Duration {
+	maxAge := h.MaxAge
+	if at, ok := h.Keys.NextPromotion(); ok {
+		if untilPromotion := time.Until(at); untilPromotion < maxAge {
+			maxAge = untilPromotion
+		}
+	}
+	if maxAge < 0 {
+		return 0
+	}
+	return maxAge
+}
+
+func (h *HandlerObject) issuer(req *http.Request) string {
+	if h.Issuer != "" {
+		return h.Issuer
+	}
+	scheme := "http"
+	if req.TLS != nil {
+		scheme = "https"
+	}
+	return scheme + "://" + req.Host
+}
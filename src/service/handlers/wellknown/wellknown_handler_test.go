@@ -0,0 +1,129 @@
+package wellknown_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"service/auth/token/jwt"
+	"service/handlers/wellknown"
+	"service/log"
+	"service/log/logfakes"
+	"strconv"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Well Known Handler Specs", func() {
+	var (
+		keyring  *jwt.Keyring
+		handler  *wellknown.HandlerObject
+		recorder *httptest.ResponseRecorder
+	)
+
+	maxAge := func() int {
+		cacheControl := recorder.Header().Get("Cache-Control")
+		Expect(cacheControl).To(HavePrefix("public, max-age="))
+		seconds, err := strconv.Atoi(strings.TrimPrefix(cacheControl, "public, max-age="))
+		Expect(err).ToNot(HaveOccurred())
+		return seconds
+	}
+
+	BeforeEach(func() {
+		ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		Expect(err).ToNot(HaveOccurred())
+		key, err := jwt.NewKey("ec-1", ecKey)
+		Expect(err).ToNot(HaveOccurred())
+		hmacKey, err := jwt.NewHMACKey("hmac-1", []byte("secret"))
+		Expect(err).ToNot(HaveOccurred())
+		keyring = jwt.NewKeyring()
+		keyring.Add(key)
+		keyring.Add(hmacKey)
+
+		handler = wellknown.NewHandlerObject(log.New(&logfakes.FakeProdInterface{}), keyring,
+			"https://auth.example.com/", 0)
+		recorder = httptest.NewRecorder()
+	})
+
+	Context("JWKS", func() {
+		JustBeforeEach(func() {
+			handler.JWKS(recorder, httptest.NewRequest("GET", "/.well-known/jwks.json", nil))
+		})
+
+		It("should publish only the asymmetric public keys", func() {
+			Expect(recorder.Code).To(Equal(http.StatusOK))
+			Expect(recorder.Header().Get("Content-Type")).To(Equal("application/json"))
+
+			var set jwt.JWKSet
+			Expect(json.Unmarshal(recorder.Body.Bytes(), &set)).To(Succeed())
+			Expect(set.Keys).To(HaveLen(1))
+			Expect(set.Keys[0].Kid).To(Equal("ec-1"))
+			Expect(set.Keys[0].Kty).To(Equal("EC"))
+			Expect(set.Keys[0].Crv).To(Equal("P-256"))
+			Expect(recorder.Body.String()).ToNot(ContainSubstring("secret"))
+		})
+
+		It("should be cacheable for the default max age", func() {
+			Expect(maxAge()).To(Equal(int(wellknown.DefaultMaxAge / time.Second)))
+		})
+
+		Context("when a signing key promotion is scheduled", func() {
+			BeforeEach(func() {
+				_, edKey, err := ed25519.GenerateKey(rand.Reader)
+				Expect(err).ToNot(HaveOccurred())
+				key, err := jwt.NewKey("ed-1", edKey)
+				Expect(err).ToNot(HaveOccurred())
+				keyring.Add(key)
+				Expect(keyring.Promote("ed-1", time.Now().Add(10*time.Minute))).To(Succeed())
+			})
+
+			It("should publish the incoming key and expire the cache before it signs", func() {
+				Expect(recorder.Body.String()).To(ContainSubstring(`"kid":"ed-1"`))
+				Expect(maxAge()).To(BeNumerically("<=", 600))
+				Expect(maxAge()).To(BeNumerically(">", 590))
+			})
+		})
+	})
+
+	Context("OpenIDConfiguration", func() {
+		var configuration wellknown.Configuration
+
+		JustBeforeEach(func() {
+			handler.OpenIDConfiguration(recorder,
+				httptest.NewRequest("GET", "/.well-known/openid-configuration", nil))
+			Expect(json.Unmarshal(recorder.Body.Bytes(), &configuration)).To(Succeed())
+		})
+
+		It("should describe the issuer and its endpoints", func() {
+			Expect(recorder.Code).To(Equal(http.StatusOK))
+			Expect(configuration.Issuer).To(Equal("https://auth.example.com"))
+			Expect(configuration.JWKSURI).To(Equal("https://auth.example.com/.well-known/jwks.json"))
+			Expect(configuration.TokenEndpoint).To(Equal("https://auth.example.com/oauth/token"))
+			Expect(configuration.AuthorizationEndpoint).To(Equal("https://auth.example.com/oauth/authorize"))
+			Expect(configuration.IntrospectionEndpoint).To(Equal("https://auth.example.com/oauth/introspect"))
+			Expect(configuration.GrantTypesSupported).To(ConsistOf("authorization_code",
+				"client_credentials", "refresh_token"))
+			Expect(configuration.CodeChallengeMethodsSupported).To(Equal([]string{"S256"}))
+			Expect(recorder.Body.String()).ToNot(ContainSubstring("id_token"))
+			Expect(configuration.SigningAlgValuesSupported).To(Equal([]string{"ES256"}))
+			Expect(maxAge()).To(BeNumerically(">", 0))
+		})
+
+		Context("without a configured issuer", func() {
+			BeforeEach(func() {
+				handler.Issuer = ""
+			})
+
+			It("should derive it from the request host and never let it be cached", func() {
+				Expect(configuration.Issuer).To(Equal("http://example.com"))
+				Expect(recorder.Header().Get("Cache-Control")).To(Equal("no-store"))
+			})
+		})
+	})
+})
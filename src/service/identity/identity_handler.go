@@ -1,13 +1,21 @@
 package identity
 
 import (
+	"context"
+	"database/sql"
 	"encoding/json"
 	"io/ioutil"
 	"net/http"
 	"service/auth"
+	"service/auth/mfa"
+	"service/auth/permission"
+	"service/auth/refresh"
+	"service/auth/throttle"
 	"service/handlers/loggederror"
+	"service/handlers/request"
 	"service/log"
 
+	"github.com/go-chi/chi"
 	"go.uber.org/zap"
 )
 
@@ -16,50 +24,72 @@ import (
 type HandlerInterface interface {
 	Handler(w http.ResponseWriter, req *http.Request)
 	CreateIdentity(w http.ResponseWriter, req *http.Request)
+	UpdateIdentity(w http.ResponseWriter, req *http.Request)
+	PatchIdentity(w http.ResponseWriter, req *http.Request)
+	DeleteIdentity(w http.ResponseWriter, req *http.Request)
+	ListIdentities(w http.ResponseWriter, req *http.Request)
+	SetIdentityPassword(w http.ResponseWriter, req *http.Request)
 	AuthIdentity(w http.ResponseWriter, req *http.Request)
+	VerifyMFA(w http.ResponseWriter, req *http.Request)
+	RefreshIdentity(w http.ResponseWriter, req *http.Request)
+	LogoutIdentity(w http.ResponseWriter, req *http.Request)
+}
+
+//SessionRevoker ... ends every browser session of an identity, see session.Manager.
+type SessionRevoker interface {
+	RevokeAll(ctx context.Context, identityID string) error
 }
 
 //HandlerObject ... holds elementals for interface methods.
 type HandlerObject struct {
-	Log     log.ProdInterface
-	Service ServiceInterface
-	Auth    auth.Interface
+	Log      log.ProdInterface
+	Service  ServiceInterface
+	Auth     auth.Interface
+	Refresh  refresh.Interface
+	Roles    permission.Store
+	Throttle throttle.Interface
+	MFA      mfa.Interface
+	Sessions SessionRevoker
 }
 
 //NewHandlerObject ... returns a pointer to a new Identity Object
 func NewHandlerObject(logClient log.ProdInterface, service ServiceInterface,
-	auth auth.Interface) *HandlerObject {
+	auth auth.Interface, refresh refresh.Interface, roles permission.Store,
+	throttle throttle.Interface, mfa mfa.Interface, sessions SessionRevoker) *HandlerObject {
 	return &HandlerObject{
-		Log:     logClient,
-		Service: service,
-		Auth:    auth,
+		Log:      logClient,
+		Service:  service,
+		Auth:     auth,
+		Refresh:  refresh,
+		Roles:    roles,
+		Throttle: throttle,
+		MFA:      mfa,
+		Sessions: sessions,
 	}
 }
 
 //CreateIdentity ...
 //This is the router handler Func for identity creation.
-//This handler will validate input, validate auth, and if approved, pass
-//info from the POST body to the identity_service
+//This handler will validate input and pass the first name, last name and profile from
+//the POST body to the identity_service
 func (h *HandlerObject) CreateIdentity(w http.ResponseWriter, req *http.Request) {
-	identity, result, err := h.Service.Create("uuidv7")
+	var input Input
+	if !h.decodeBody(&input, "CreateIdentity", w, req) {
+		return
+	}
+	if input.FirstName == "" || input.LastName == "" {
+		h.badRequest("missing required identity params", "CreateIdentity", w, req)
+		return
+	}
+	identity, result, err := h.Service.Create(req.Context(), input)
 	if err != nil {
-		loggederror.RespondWithProperErrorAndLogIt(
-			h.Log,
-			http.StatusInternalServerError,
-			err,
-			"identity_handler::CreateIdentity",
-			w,
-			req,
-		)
+		h.internalServerError(err, "CreateIdentity", w, req)
 		return
 	}
 	if identity != nil {
 		h.Log.Debug("CreateIdentity", zap.Any("identity", identity),
 			zap.Any("result", result))
-		responseErr := identity.RespondWithJSON(http.StatusOK, w)
-		if responseErr != nil {
-			h.Log.Error("CreateIdentity::RespondWithJSON", zap.Error(responseErr))
-		}
+		h.respondWithRow(identity, "CreateIdentity", w)
 		return
 	}
 	loggederror.RespondWithProperErrorAndLogIt(
@@ -72,11 +102,193 @@ func (h *HandlerObject) CreateIdentity(w http.ResponseWriter, req *http.Request)
 	)
 }
 
+//UpdateIdentity ...
+//This is the router handler Func for PUT /identity/{id}, it replaces every writable
+//field of the identity with the request body.
+func (h *HandlerObject) UpdateIdentity(w http.ResponseWriter, req *http.Request) {
+	id := chi.URLParam(req, "id")
+	var input Input
+	if !h.decodeBody(&input, "UpdateIdentity", w, req) {
+		return
+	}
+	if id == "" || input.FirstName == "" || input.LastName == "" {
+		h.badRequest("missing required identity params", "UpdateIdentity", w, req)
+		return
+	}
+	h.update(id, input, "UpdateIdentity", w, req)
+}
+
+//PatchIdentity ...
+//This is the router handler Func for PATCH /identity/{id}, it only changes the
+//fields present in the request body.
+func (h *HandlerObject) PatchIdentity(w http.ResponseWriter, req *http.Request) {
+	id := chi.URLParam(req, "id")
+	var patch Patch
+	if !h.decodeBody(&patch, "PatchIdentity", w, req) {
+		return
+	}
+	if id == "" || (patch.FirstName != nil && *patch.FirstName == "") ||
+		(patch.LastName != nil && *patch.LastName == "") {
+		h.badRequest("missing required identity params", "PatchIdentity", w, req)
+		return
+	}
+	row, err := h.Service.Fetch(req.Context(), id)
+	if err != nil {
+		h.fetchError(err, "PatchIdentity", w, req)
+		return
+	}
+	h.update(id, patch.Apply(row), "PatchIdentity", w, req)
+}
+
+//DeleteIdentity ...
+//This is the router handler Func for DELETE /identity/{id}.
+func (h *HandlerObject) DeleteIdentity(w http.ResponseWriter, req *http.Request) {
+	id := chi.URLParam(req, "id")
+	if id == "" {
+		h.badRequest("missing required identity params", "DeleteIdentity", w, req)
+		return
+	}
+	err := h.Service.Delete(req.Context(), id)
+	if err != nil {
+		h.fetchError(err, "DeleteIdentity", w, req)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+//SetIdentityPassword ...
+//This is the router handler Func for PUT /identity/{id}/password, it replaces the
+//password used to authenticate the identity. The identity itself must send its current
+//password, only callers holding permission.IdentityAdmin may set another identity's.
+//Every refresh token and session of the identity is revoked afterwards.
+func (h *HandlerObject) SetIdentityPassword(w http.ResponseWriter, req *http.Request) {
+	id := chi.URLParam(req, "id")
+	var jsonDoc passwordPutBody
+	if !h.decodeBody(&jsonDoc, "SetIdentityPassword", w, req) {
+		return
+	}
+	if id == "" || len(jsonDoc.Password) < MinPasswordLength {
+		h.badRequest("password must be at least 8 characters", "SetIdentityPassword", w, req)
+		return
+	}
+	claims, hasClaims := request.RetreiveClaims(req.Context())
+	if !hasClaims || !claims.HasScope(permission.IdentityAdmin) {
+		if request.RetreiveSubject(req.Context()) != id {
+			h.forbidden("cannot set another identity's password", "SetIdentityPassword", w, req)
+			return
+		}
+		verified, err := h.Service.VerifyPassword(req.Context(), id, jsonDoc.CurrentPassword)
+		if err != nil {
+			h.internalServerError(err, "SetIdentityPassword", w, req)
+			return
+		}
+		if !verified {
+			h.forbidden("current password is incorrect", "SetIdentityPassword", w, req)
+			return
+		}
+	}
+	err := h.Service.SetPassword(req.Context(), id, jsonDoc.Password)
+	if err != nil {
+		h.fetchError(err, "SetIdentityPassword", w, req)
+		return
+	}
+	if err := h.Refresh.RevokeAll(req.Context(), id); err != nil {
+		h.internalServerError(err, "SetIdentityPassword", w, req)
+		return
+	}
+	if err := h.Sessions.RevokeAll(req.Context(), id); err != nil {
+		h.internalServerError(err, "SetIdentityPassword", w, req)
+		return
+	}
+	h.Log.Info("identity password changed",
+		zap.String("event", "auth.password.changed"),
+		zap.String("identityID", id),
+		zap.String("by", request.RetreiveSubject(req.Context())))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+//ListIdentities ...
+//This is the router handler Func for GET /identities, it returns a page of identities
+//filtered and ordered by the query string.
+func (h *HandlerObject) ListIdentities(w http.ResponseWriter, req *http.Request) {
+	query, err := ParseListQuery(req.URL.Query())
+	if err != nil {
+		h.badRequest(err.Error(), "ListIdentities", w, req)
+		return
+	}
+	page, err := h.Service.List(req.Context(), query)
+	if err != nil {
+		h.internalServerError(err, "ListIdentities", w, req)
+		return
+	}
+	responseErr := page.RespondWithJSON(http.StatusOK, w)
+	if responseErr != nil {
+		h.Log.Error("ListIdentities::RespondWithJSON", zap.Error(responseErr))
+	}
+}
+
+func (h *HandlerObject) update(id string, input Input, source string, w http.ResponseWriter,
+	req *http.Request) {
+	row, err := h.Service.Update(req.Context(), id, input)
+	if err != nil {
+		h.fetchError(err, source, w, req)
+		return
+	}
+	h.respondWithRow(row, source, w)
+}
+
+// decodeBody reads the request body as JSON into v, responding with a bad request on failure.
+func (h *HandlerObject) decodeBody(v interface{}, source string, w http.ResponseWriter,
+	req *http.Request) bool {
+	body, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		h.internalServerError(err, source, w, req)
+		return false
+	}
+	if jsonErr := json.Unmarshal(body, v); jsonErr != nil {
+		h.badRequest("bad request", source, w, req)
+		return false
+	}
+	return true
+}
+
+func (h *HandlerObject) respondWithRow(row *Row, source string, w http.ResponseWriter) {
+	responseErr := row.RespondWithJSON(http.StatusOK, w)
+	if responseErr != nil {
+		h.Log.Error(source+"::RespondWithJSON", zap.Error(responseErr))
+	}
+}
+
+// fetchError maps a missing identity to a 404 and anything else to a 500.
+func (h *HandlerObject) fetchError(err error, source string, w http.ResponseWriter,
+	req *http.Request) {
+	if err == sql.ErrNoRows {
+		loggederror.RespondWithWithExpectedSoftError(
+			h.Log,
+			http.StatusNotFound,
+			"identity not found",
+			"identity_handler::"+source,
+			w,
+			req,
+		)
+		return
+	}
+	h.internalServerError(err, source, w, req)
+}
+
 type authPostBody struct {
-	ID                string `json:"id"`
-	EncryptedPassword string `json:"password"`
+	ID       string `json:"id"`
+	Password string `json:"password"`
 }
 
+type passwordPutBody struct {
+	Password        string `json:"password"`
+	CurrentPassword string `json:"current_password"`
+}
+
+//MinPasswordLength ... is the shortest password SetIdentityPassword will accept.
+const MinPasswordLength = 8
+
 // internalServerError is used to wrap our loggederror for this route.
 func (h *HandlerObject) internalServerError(err error, source string, w http.ResponseWriter,
 	req *http.Request) {
@@ -103,81 +315,313 @@ func (h *HandlerObject) badRequest(message, source string, w http.ResponseWriter
 	)
 }
 
+// forbidden is used to wrap our loggederror for callers acting on an identity they may not.
+func (h *HandlerObject) forbidden(message, source string, w http.ResponseWriter,
+	req *http.Request) {
+	loggederror.RespondWithWithExpectedSoftError(
+		h.Log,
+		http.StatusForbidden,
+		message,
+		"identity_handler::"+source,
+		w,
+		req,
+	)
+}
+
 type authResponse struct {
-	Status int    `json:"status"`
-	ID     string `json:"id"`
-	Token  string `json:"token"`
+	Status       int    `json:"status"`
+	ID           string `json:"id"`
+	Token        string `json:"token"`
+	RefreshToken string `json:"refreshToken,omitempty"`
+}
+
+type mfaChallengeResponse struct {
+	Status      int    `json:"status"`
+	ID          string `json:"id"`
+	MFARequired bool   `json:"mfaRequired"`
+	MFAToken    string `json:"mfaToken"`
+}
+
+type mfaPostBody struct {
+	MFAToken string `json:"mfaToken"`
+	Code     string `json:"code"`
+}
+
+type refreshPostBody struct {
+	RefreshToken string `json:"refreshToken"`
 }
 
-//AuthIdentity generates a jwt token for a known identity.
+//AuthIdentity generates a jwt token for a known identity once its password is verified.
+//Identities with MFA enabled get a challenge token to exchange at VerifyMFA instead.
 func (h *HandlerObject) AuthIdentity(w http.ResponseWriter, req *http.Request) {
-	body, err := ioutil.ReadAll(req.Body)
-	if err != nil {
-		h.internalServerError(err, "AuthIdentity", w, req)
-		return
-	}
 	var jsonDoc authPostBody
-	jsonErr := json.Unmarshal(body, &jsonDoc)
-	if jsonErr != nil {
-		h.internalServerError(jsonErr, "AuthIdentity", w, req)
+	if !h.decodeBody(&jsonDoc, "AuthIdentity", w, req) {
 		return
 	}
-	if jsonDoc.ID == "" || jsonDoc.EncryptedPassword == "" {
+	if jsonDoc.ID == "" || jsonDoc.Password == "" {
 		h.badRequest("missing required auth params", "AuthIdentity", w, req)
 		return
 	}
-	defer func() {
-		closeErr := req.Body.Close()
-		if closeErr != nil {
-			h.internalServerError(closeErr, "AuthIdentity", w, req)
+	//Failures are tracked per submitted id whether or not it exists, so a lockout
+	//reveals nothing about which identities are real.
+	identityKey := throttle.IdentityKey(jsonDoc.ID)
+	ipKey := throttle.IPKey(throttle.ClientIP(req))
+	wait, throttleErr := h.Throttle.Check(req.Context(), identityKey, ipKey)
+	if throttleErr != nil {
+		h.internalServerError(throttleErr, "AuthIdentity", w, req)
+		return
+	}
+	if wait > 0 {
+		throttle.SetRetryAfter(w, wait)
+		loggederror.RespondWithWithExpectedSoftError(
+			h.Log,
+			http.StatusTooManyRequests,
+			throttle.ErrTooManyAttempts,
+			"identity_handler::AuthIdentity",
+			w,
+			req,
+		)
+		return
+	}
+	//VerifyPassword takes the same time whether or not the identity exists, and both
+	//cases share a response, so callers cannot probe for identities.
+	verified, verifyErr := h.Service.VerifyPassword(req.Context(), jsonDoc.ID, jsonDoc.Password)
+	if verifyErr != nil {
+		h.internalServerError(verifyErr, "AuthIdentity", w, req)
+		return
+	}
+	if !verified {
+		if failErr := h.Throttle.Fail(req.Context(), identityKey, ipKey); failErr != nil {
+			h.internalServerError(failErr, "AuthIdentity", w, req)
 			return
 		}
-	}()
+		loggederror.RespondWithWithExpectedSoftError(
+			h.Log,
+			http.StatusUnauthorized,
+			"invalid id or password",
+			"identity_handler::AuthIdentity",
+			w,
+			req,
+		)
+		return
+	}
+	mfaEnabled, mfaErr := h.MFA.Enabled(req.Context(), jsonDoc.ID)
+	if mfaErr != nil {
+		h.internalServerError(mfaErr, "AuthIdentity", w, req)
+		return
+	}
+	if mfaEnabled {
+		//The password failures are only cleared once the second factor is passed too.
+		h.respondWithChallenge(jsonDoc.ID, w, req)
+		return
+	}
+	h.completeLogin(jsonDoc.ID, "AuthIdentity", w, req)
+}
+
+//VerifyMFA ...
+//exchanges the challenge token AuthIdentity handed out plus a TOTP or recovery code for
+//a jwt and refresh token.
+func (h *HandlerObject) VerifyMFA(w http.ResponseWriter, req *http.Request) {
+	var jsonDoc mfaPostBody
+	if !h.decodeBody(&jsonDoc, "VerifyMFA", w, req) {
+		return
+	}
+	if jsonDoc.MFAToken == "" || jsonDoc.Code == "" {
+		h.badRequest("missing required mfa params", "VerifyMFA", w, req)
+		return
+	}
+	ipKey := throttle.IPKey(throttle.ClientIP(req))
+	wait, throttleErr := h.Throttle.Check(req.Context(), ipKey)
+	if throttleErr != nil {
+		h.internalServerError(throttleErr, "VerifyMFA", w, req)
+		return
+	}
+	if wait > 0 {
+		throttle.SetRetryAfter(w, wait)
+		loggederror.RespondWithWithExpectedSoftError(
+			h.Log,
+			http.StatusTooManyRequests,
+			throttle.ErrTooManyAttempts,
+			"identity_handler::VerifyMFA",
+			w,
+			req,
+		)
+		return
+	}
+	id, redeemErr := h.MFA.Redeem(req.Context(), jsonDoc.MFAToken, jsonDoc.Code)
+	if redeemErr == mfa.ErrInvalidChallenge || redeemErr == mfa.ErrInvalidCode {
+		failed := []string{ipKey}
+		if id != "" {
+			failed = append(failed, throttle.IdentityKey(id))
+		}
+		if failErr := h.Throttle.Fail(req.Context(), failed...); failErr != nil {
+			h.internalServerError(failErr, "VerifyMFA", w, req)
+			return
+		}
+		loggederror.RespondWithWithExpectedSoftError(
+			h.Log,
+			http.StatusUnauthorized,
+			redeemErr.Error(),
+			"identity_handler::VerifyMFA",
+			w,
+			req,
+		)
+		return
+	}
+	if redeemErr != nil {
+		h.internalServerError(redeemErr, "VerifyMFA", w, req)
+		return
+	}
+	h.completeLogin(id, "VerifyMFA", w, req)
+}
+
+//completeLogin clears the identity's failed attempts and issues its tokens.
+func (h *HandlerObject) completeLogin(id, source string, w http.ResponseWriter,
+	req *http.Request) {
+	if succeedErr := h.Throttle.Succeed(req.Context(), throttle.IdentityKey(id)); succeedErr != nil {
+		h.internalServerError(succeedErr, source, w, req)
+		return
+	}
+	refreshToken, refreshErr := h.Refresh.Issue(req.Context(), id)
+	if refreshErr != nil {
+		h.internalServerError(refreshErr, source, w, req)
+		return
+	}
+	h.respondWithTokens(id, refreshToken, source, w, req)
+}
+
+func (h *HandlerObject) respondWithChallenge(id string, w http.ResponseWriter,
+	req *http.Request) {
+	challenge, challengeErr := h.MFA.Challenge(req.Context(), id)
+	if challengeErr != nil {
+		h.internalServerError(challengeErr, "AuthIdentity", w, req)
+		return
+	}
+	response := mfaChallengeResponse{
+		Status:      http.StatusOK,
+		ID:          id,
+		MFARequired: true,
+		MFAToken:    challenge,
+	}
+	bytesArray, marshalErr := json.Marshal(&response)
+	if marshalErr != nil {
+		h.internalServerError(marshalErr, "AuthIdentity", w, req)
+		return
+	}
+	_, writeErr := w.Write(bytesArray)
+	if writeErr != nil {
+		h.internalServerError(writeErr, "AuthIdentity", w, req)
+	}
+}
+
+//RefreshIdentity ...
+//exchanges a refresh token for a new access token and a new refresh token. Each refresh
+//token is single use, replaying one revokes every token descended from the same login.
+func (h *HandlerObject) RefreshIdentity(w http.ResponseWriter, req *http.Request) {
+	var jsonDoc refreshPostBody
+	if !h.decodeBody(&jsonDoc, "RefreshIdentity", w, req) {
+		return
+	}
+	if jsonDoc.RefreshToken == "" {
+		h.badRequest("missing required refresh params", "RefreshIdentity", w, req)
+		return
+	}
+	id, refreshToken, err := h.Refresh.Rotate(req.Context(), jsonDoc.RefreshToken)
+	if err == refresh.ErrInvalid || err == refresh.ErrReused {
+		loggederror.RespondWithWithExpectedSoftError(
+			h.Log,
+			http.StatusUnauthorized,
+			refresh.ErrInvalid.Error(),
+			"identity_handler::RefreshIdentity",
+			w,
+			req,
+		)
+		return
+	}
+	if err != nil {
+		h.internalServerError(err, "RefreshIdentity", w, req)
+		return
+	}
+	h.respondWithTokens(id, refreshToken, "RefreshIdentity", w, req)
+}
+
+//LogoutIdentity ...
+//revokes the request's bearer access token so it can no longer be used.
+func (h *HandlerObject) LogoutIdentity(w http.ResponseWriter, req *http.Request) {
+	err := h.Auth.RevokeTokenHeader(req)
+	if err == auth.ErrInvalidToken || err == auth.ErrRevokedToken {
+		loggederror.RespondWithWithExpectedSoftError(
+			h.Log,
+			http.StatusUnauthorized,
+			err.Error(),
+			"identity_handler::LogoutIdentity",
+			w,
+			req,
+		)
+		return
+	}
+	if err != nil {
+		h.internalServerError(err, "LogoutIdentity", w, req)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// respondWithTokens generates an access token for id, scoped to the permissions its
+// roles currently grant, and writes it with refreshToken.
+func (h *HandlerObject) respondWithTokens(id, refreshToken, source string,
+	w http.ResponseWriter, req *http.Request) {
+	permissions, permissionsErr := h.Roles.Permissions(req.Context(), id)
+	if permissionsErr != nil {
+		h.internalServerError(permissionsErr, source, w, req)
+		return
+	}
 	input := make(map[string]interface{}, 0)
-	input["ID"] = jsonDoc.ID
-	token, tokenErr := h.Auth.GenerateToken(input)
+	input["sub"] = id
+	if len(permissions) > 0 {
+		input["scope"] = permissions
+	}
+	token, tokenErr := h.Auth.GenerateToken(req.Context(), input)
 	if tokenErr != nil {
-		h.internalServerError(tokenErr, "AuthIdentity", w, req)
+		h.internalServerError(tokenErr, source, w, req)
 		return
 	}
 
 	response := authResponse{
-		Status: http.StatusOK,
-		ID:     jsonDoc.ID,
-		Token:  token,
+		Status:       http.StatusOK,
+		ID:           id,
+		Token:        token,
+		RefreshToken: refreshToken,
 	}
 	bytesArray, marshalErr := json.Marshal(&response)
 	if marshalErr != nil {
-		h.internalServerError(marshalErr, "AuthIdentity", w, req)
+		h.internalServerError(marshalErr, source, w, req)
 		return
 	}
 
 	_, writeErr := w.Write(bytesArray)
 	if writeErr != nil {
-		h.internalServerError(writeErr, "AuthIdentity", w, req)
+		h.internalServerError(writeErr, source, w, req)
 	}
 }
 
-//Handler ... contains a handler funcction to be passed to our router.
+//Handler ... contains a handler function for GET /identity/{id} to be passed to our router.
 func (h *HandlerObject) Handler(w http.ResponseWriter, req *http.Request) {
-	row, err := h.Service.Fetch("uuidv7")
+	id := chi.URLParam(req, "id")
+	if id == "" {
+		h.badRequest("missing required identity params", "Handler", w, req)
+		return
+	}
+	row, err := h.Service.Fetch(req.Context(), id)
 	if err != nil {
-		loggederror.RespondWithProperErrorAndLogIt(h.Log, http.StatusInternalServerError,
-			err, "identity_handler::fetch from service", w, req)
+		h.fetchError(err, "Handler", w, req)
 		return
 	}
 	h.Log.Debug("identity Handler", zap.Any("row", row))
-	//h.Log.Info("we got a row!", zap.Any("row", row))
 	if row != nil {
 		err = row.RespondWithJSON(http.StatusOK, w)
 		if err != nil {
-			loggederror.RespondWithProperErrorAndLogIt(
-				h.Log,
-				http.StatusInternalServerError,
-				err,
-				"identity_handler::Handler",
-				w,
-				req)
+			h.internalServerError(err, "Handler", w, req)
 			return
 		}
 	}
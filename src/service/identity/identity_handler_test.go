@@ -2,18 +2,31 @@ package identity_test
 
 import (
 	"bytes"
+	"database/sql"
+	"encoding/json"
 	"errors"
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
 	"service/auth"
 	"service/auth/authfakes"
+	"service/auth/mfa"
+	"service/auth/mfa/mfafakes"
+	"service/auth/permission/permissionfakes"
+	"service/auth/refresh"
+	"service/auth/refresh/refreshfakes"
+	"service/auth/revocation"
+	"service/auth/session/sessionfakes"
+	"service/auth/throttle/throttlefakes"
+	"service/auth/token/jwt"
 	"service/auth/token/tokenfakes"
+	"service/handlers/request"
 	"service/identity"
 	"service/identity/identityfakes"
 	"service/log/logfakes"
 	"time"
 
+	jwtGo "github.com/dgrijalva/jwt-go"
 	"github.com/go-chi/chi"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -28,6 +41,11 @@ var _ = Describe("Identity Handler Specs", func() {
 			fakeAuth        *authfakes.FakeInterface
 			fakeToken       *tokenfakes.FakeInterface
 			fakeAuthClient  *auth.Client
+			fakeRefresh     *refreshfakes.FakeInterface
+			fakeRoles       *permissionfakes.FakeStore
+			fakeThrottle    *throttlefakes.FakeInterface
+			fakeMFA         *mfafakes.FakeInterface
+			fakeSessions    *sessionfakes.FakeInterface
 			fakeLog         *logfakes.FakeProdInterface
 			router          *chi.Mux
 			server          *httptest.Server
@@ -38,9 +56,15 @@ var _ = Describe("Identity Handler Specs", func() {
 			fakeService = &identityfakes.FakeServiceInterface{}
 			fakeAuth = &authfakes.FakeInterface{}
 			fakeToken = &tokenfakes.FakeInterface{}
-			fakeAuthClient = auth.NewClient(fakeAuth, fakeToken)
-
-			identityHandler = identity.NewHandlerObject(fakeLog, fakeService, fakeAuthClient)
+			fakeAuthClient = auth.NewClient(fakeAuth, fakeToken, revocation.NewMemoryStore())
+			fakeRefresh = &refreshfakes.FakeInterface{}
+			fakeRoles = &permissionfakes.FakeStore{}
+			fakeThrottle = &throttlefakes.FakeInterface{}
+			fakeMFA = &mfafakes.FakeInterface{}
+			fakeSessions = &sessionfakes.FakeInterface{}
+
+			identityHandler = identity.NewHandlerObject(fakeLog, fakeService, fakeAuthClient,
+				fakeRefresh, fakeRoles, fakeThrottle, fakeMFA, fakeSessions)
 		})
 
 		Context("create identity routes", func() {
@@ -66,7 +90,8 @@ var _ = Describe("Identity Handler Specs", func() {
 					)
 
 					BeforeEach(func() {
-						buffer := bytes.NewBuffer([]byte(`{"email": "test@gmail.com"}`))
+						buffer := bytes.NewBuffer([]byte(`{"firstName": "test_first", "lastName": "test_last",
+							"profile": {"email": "test@gmail.com"}}`))
 						request = httptest.NewRequest("POST", server.URL+"/identity", buffer)
 						request.Header.Set("token", "asdlkgjaskgsadgjadsglasdjgasjdglsjdasgd")
 						recorder = httptest.NewRecorder()
@@ -91,6 +116,13 @@ var _ = Describe("Identity Handler Specs", func() {
 
 					})
 
+					It("passes the post body to the service", func() {
+						_, input := fakeService.CreateArgsForCall(0)
+						Expect(input.FirstName).To(Equal("test_first"))
+						Expect(input.LastName).To(Equal("test_last"))
+						Expect(input.ProfileInfo).To(HaveKeyWithValue("email", "test@gmail.com"))
+					})
+
 					It("returns a 200 OK and a response body", func() {
 						Expect(recorder.Code).To(Equal(http.StatusOK))
 						Expect(expectedError).ToNot(HaveOccurred())
@@ -123,7 +155,8 @@ var _ = Describe("Identity Handler Specs", func() {
 					It("returns a 400 BAD REQUEST and a standard message", func() {
 						Expect(expectedError).ToNot(HaveOccurred())
 						Expect(body).ToNot(BeEmpty())
-						//Expect(recorder.Code).To(Equal(http.StatusBadRequest))
+						Expect(recorder.Code).To(Equal(http.StatusBadRequest))
+						Expect(fakeService.CreateCallCount()).To(Equal(0))
 					})
 				})
 			})
@@ -148,7 +181,7 @@ var _ = Describe("Identity Handler Specs", func() {
 
 			BeforeEach(func() {
 				router = chi.NewRouter()
-				router.Get("/identity", identityHandler.Handler)
+				router.Get("/identity/{id}", identityHandler.Handler)
 				server = httptest.NewServer(router)
 			})
 
@@ -158,9 +191,9 @@ var _ = Describe("Identity Handler Specs", func() {
 				})
 
 				JustBeforeEach(func() {
-					request = httptest.NewRequest("GET", server.URL+"/", nil)
+					request = httptest.NewRequest("GET", server.URL+"/identity/test_id", nil)
 					recorder = httptest.NewRecorder()
-					identityHandler.Handler(recorder, request)
+					router.ServeHTTP(recorder, request)
 					response = recorder.Result()
 					body, expectedError = ioutil.ReadAll(response.Body)
 					_ = expectedError
@@ -187,18 +220,165 @@ var _ = Describe("Identity Handler Specs", func() {
 				})
 
 				JustBeforeEach(func() {
-					request = httptest.NewRequest("GET", server.URL+"/", nil)
+					request = httptest.NewRequest("GET", server.URL+"/identity/test_id", nil)
 					recorder = httptest.NewRecorder()
-					identityHandler.Handler(recorder, request)
+					router.ServeHTTP(recorder, request)
 					response = recorder.Result()
 					body, expectedError = ioutil.ReadAll(response.Body)
 				})
 
 				It("should fetch the record by that ID", func() {
 					Expect(fakeService.FetchCallCount()).To(Equal(1))
+					_, id := fakeService.FetchArgsForCall(0)
+					Expect(id).To(Equal("test_id"))
 					Expect(response.StatusCode).To(Equal(http.StatusOK))
 				})
 			})
+
+			Context("when no identity has that id", func() {
+				BeforeEach(func() {
+					fakeService.FetchReturns(nil, sql.ErrNoRows)
+				})
+
+				JustBeforeEach(func() {
+					request = httptest.NewRequest("GET", server.URL+"/identity/missing", nil)
+					recorder = httptest.NewRecorder()
+					router.ServeHTTP(recorder, request)
+				})
+
+				It("should respond with a 404 and not log an error", func() {
+					Expect(recorder.Code).To(Equal(http.StatusNotFound))
+					Expect(fakeLog.ErrorCallCount()).To(Equal(0))
+				})
+			})
+		})
+
+		Context("when a user lists identities", func() {
+			var recorder *httptest.ResponseRecorder
+
+			BeforeEach(func() {
+				router = chi.NewRouter()
+				router.Get("/identities", identityHandler.ListIdentities)
+				recorder = httptest.NewRecorder()
+				fakeService.ListReturns(&identity.Page{
+					Rows: []identity.Row{{ID: "test_id"}},
+					Next: "next_cursor",
+				}, nil)
+			})
+
+			It("should pass the parsed query to the service and return a list envelope", func() {
+				request := httptest.NewRequest("GET", "/identities?limit=5&name=te", nil)
+				router.ServeHTTP(recorder, request)
+				Expect(recorder.Code).To(Equal(http.StatusOK))
+				_, query := fakeService.ListArgsForCall(0)
+				Expect(query.Limit).To(Equal(5))
+				Expect(query.NamePrefix).To(Equal("te"))
+
+				var envelope struct {
+					Status     int            `json:"status"`
+					Identities []identity.Row `json:"identities"`
+					Next       string         `json:"next"`
+				}
+				Expect(json.Unmarshal(recorder.Body.Bytes(), &envelope)).To(Succeed())
+				Expect(envelope.Status).To(Equal(http.StatusOK))
+				Expect(envelope.Identities).To(HaveLen(1))
+				Expect(envelope.Next).To(Equal("next_cursor"))
+			})
+
+			It("should respond with a 400 for an invalid query", func() {
+				request := httptest.NewRequest("GET", "/identities?limit=1000", nil)
+				router.ServeHTTP(recorder, request)
+				Expect(recorder.Code).To(Equal(http.StatusBadRequest))
+				Expect(fakeService.ListCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("when a user changes an identity", func() {
+			var (
+				recorder *httptest.ResponseRecorder
+				testRow  identity.Row
+			)
+
+			BeforeEach(func() {
+				router = chi.NewRouter()
+				router.Put("/identity/{id}", identityHandler.UpdateIdentity)
+				router.Patch("/identity/{id}", identityHandler.PatchIdentity)
+				router.Delete("/identity/{id}", identityHandler.DeleteIdentity)
+				recorder = httptest.NewRecorder()
+
+				testRow = identity.Row{
+					ID:          "test_id",
+					FirstName:   "test_first",
+					LastName:    "test_last",
+					ProfileInfo: "test_profile_info",
+				}
+				fakeService.FetchReturns(&testRow, nil)
+				fakeService.UpdateReturns(&testRow, nil)
+			})
+
+			serve := func(method, body string) {
+				request := httptest.NewRequest(method, "/identity/test_id",
+					bytes.NewBufferString(body))
+				router.ServeHTTP(recorder, request)
+			}
+
+			Context("PUT /identity/{id}", func() {
+				It("should replace the identity with the post body", func() {
+					serve("PUT", `{"firstName": "new_first", "lastName": "new_last"}`)
+					Expect(recorder.Code).To(Equal(http.StatusOK))
+					_, id, input := fakeService.UpdateArgsForCall(0)
+					Expect(id).To(Equal("test_id"))
+					Expect(input.FirstName).To(Equal("new_first"))
+					Expect(input.LastName).To(Equal("new_last"))
+					Expect(input.ProfileInfo).To(BeNil())
+				})
+
+				It("should respond with a 400 when a name is missing", func() {
+					serve("PUT", `{"firstName": "new_first"}`)
+					Expect(recorder.Code).To(Equal(http.StatusBadRequest))
+					Expect(fakeService.UpdateCallCount()).To(Equal(0))
+				})
+
+				It("should respond with a 404 when the identity does not exist", func() {
+					fakeService.UpdateReturns(nil, sql.ErrNoRows)
+					serve("PUT", `{"firstName": "new_first", "lastName": "new_last"}`)
+					Expect(recorder.Code).To(Equal(http.StatusNotFound))
+				})
+			})
+
+			Context("PATCH /identity/{id}", func() {
+				It("should only change the fields in the post body", func() {
+					serve("PATCH", `{"lastName": "new_last"}`)
+					Expect(recorder.Code).To(Equal(http.StatusOK))
+					_, id, input := fakeService.UpdateArgsForCall(0)
+					Expect(id).To(Equal("test_id"))
+					Expect(input.FirstName).To(Equal("test_first"))
+					Expect(input.LastName).To(Equal("new_last"))
+					Expect(input.ProfileInfo).To(Equal("test_profile_info"))
+				})
+
+				It("should respond with a 404 when the identity does not exist", func() {
+					fakeService.FetchReturns(nil, sql.ErrNoRows)
+					serve("PATCH", `{"lastName": "new_last"}`)
+					Expect(recorder.Code).To(Equal(http.StatusNotFound))
+					Expect(fakeService.UpdateCallCount()).To(Equal(0))
+				})
+			})
+
+			Context("DELETE /identity/{id}", func() {
+				It("should delete the identity and respond with no content", func() {
+					serve("DELETE", "")
+					Expect(recorder.Code).To(Equal(http.StatusNoContent))
+					_, id := fakeService.DeleteArgsForCall(0)
+					Expect(id).To(Equal("test_id"))
+				})
+
+				It("should respond with a 404 when the identity does not exist", func() {
+					fakeService.DeleteReturns(sql.ErrNoRows)
+					serve("DELETE", "")
+					Expect(recorder.Code).To(Equal(http.StatusNotFound))
+				})
+			})
 		})
 		Context("when a user wants to authorize an identity", func() {
 			var (
@@ -206,60 +386,442 @@ var _ = Describe("Identity Handler Specs", func() {
 				recorder *httptest.ResponseRecorder
 				response *http.Response
 				body     []byte
+				postBody string
 			)
 
 			BeforeEach(func() {
 				router = chi.NewRouter()
-				router.Get("/identity/auth", identityHandler.AuthIdentity)
+				router.Post("/auth", identityHandler.AuthIdentity)
 				server = httptest.NewServer(router)
+				recorder = httptest.NewRecorder()
+				postBody = ""
 			})
 
-			Context("when a user makes invalid requests", func() {
+			JustBeforeEach(func() {
+				request = httptest.NewRequest("POST", server.URL+"/auth",
+					bytes.NewBufferString(postBody))
+				router.ServeHTTP(recorder, request)
+				response = recorder.Result()
+				body, _ = ioutil.ReadAll(response.Body)
+			})
 
-				BeforeEach(func() {
-					request = httptest.NewRequest("POST", server.URL+"/auth", nil)
-					recorder = httptest.NewRecorder()
-				})
+			Context("when a user makes invalid requests", func() {
 
 				Context("and is missing a post body", func() {
-
-					JustBeforeEach(func() {
-						identityHandler.AuthIdentity(recorder, request)
-						response = recorder.Result()
-						body, _ = ioutil.ReadAll(response.Body)
-					})
-
 					It("should respond with a bad request and a message", func() {
-						Expect(string(body)).Should(Equal("bad request"))
+						Expect(response.StatusCode).To(Equal(http.StatusBadRequest))
+						Expect(string(body)).Should(Equal("bad request\n"))
 					})
 				})
 
 				Context("and has a bad json post body", func() {
-					It("should respond with a bad request and a message", func() {
+					BeforeEach(func() {
+						postBody = `{"id": `
+					})
 
+					It("should respond with a bad request and a message", func() {
+						Expect(response.StatusCode).To(Equal(http.StatusBadRequest))
+						Expect(string(body)).Should(Equal("bad request\n"))
 					})
 				})
 
 				Context("and is missing an id or password", func() {
-					It("should respond with a bad request and a message", func() {
+					BeforeEach(func() {
+						postBody = `{"id": "test_id"}`
+					})
 
+					It("should respond with a bad request and a message", func() {
+						Expect(response.StatusCode).To(Equal(http.StatusBadRequest))
+						Expect(string(body)).Should(Equal("missing required auth params\n"))
+						Expect(fakeService.VerifyPasswordCallCount()).To(Equal(0))
 					})
 				})
 			})
 
+			Context("when the password does not match", func() {
+				BeforeEach(func() {
+					postBody = `{"id": "test_id", "password": "wrong password"}`
+					fakeService.VerifyPasswordReturns(false, nil)
+				})
+
+				It("should respond with a 401 and not issue a token", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusUnauthorized))
+					Expect(string(body)).Should(Equal("invalid id or password\n"))
+					Expect(fakeToken.GenerateCallCount()).To(Equal(0))
+				})
+
+				It("should record a failure for the id and the client IP", func() {
+					_, keys := fakeThrottle.FailArgsForCall(0)
+					Expect(keys).To(HaveLen(2))
+					Expect(keys[0]).To(Equal("identity:test_id"))
+					Expect(keys[1]).To(HavePrefix("ip:"))
+				})
+			})
+
+			Context("when the id or client IP is throttled", func() {
+				BeforeEach(func() {
+					postBody = `{"id": "test_id", "password": "a password"}`
+					fakeThrottle.CheckReturns(30*time.Second, nil)
+				})
+
+				It("should respond with a 429 and a Retry-After without checking the password", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusTooManyRequests))
+					Expect(response.Header.Get("Retry-After")).To(Equal("30"))
+					Expect(string(body)).Should(Equal("too many failed login attempts\n"))
+					Expect(fakeService.VerifyPasswordCallCount()).To(Equal(0))
+				})
+			})
+
+			Context("when verifying the password errors", func() {
+				BeforeEach(func() {
+					postBody = `{"id": "test_id", "password": "a password"}`
+					fakeService.VerifyPasswordReturns(false, errors.New("db down"))
+				})
+
+				It("should respond with a 500 and log the error", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusInternalServerError))
+					Expect(fakeLog.ErrorCallCount()).To(Equal(1))
+					Expect(fakeToken.GenerateCallCount()).To(Equal(0))
+				})
+			})
+
 			Context("when a user has a valid identity", func() {
-				It("should generate a new access token", func() {
+				BeforeEach(func() {
+					postBody = `{"id": "test_id", "password": "a password"}`
+					fakeService.VerifyPasswordReturns(true, nil)
+					fakeToken.GenerateReturns("test_token", nil)
+					fakeRefresh.IssueReturns("test_refresh_token", nil)
+				})
+
+				It("should verify the password against the identity", func() {
+					_, id, password := fakeService.VerifyPasswordArgsForCall(0)
+					Expect(id).To(Equal("test_id"))
+					Expect(password).To(Equal("a password"))
+				})
 
+				It("should generate a new access token", func() {
+					Expect(fakeToken.GenerateCallCount()).To(Equal(1))
 				})
 
 				It("the new access token should contain all of the necessary identity data", func() {
+					Expect(fakeToken.GenerateArgsForCall(0)).To(HaveKeyWithValue("sub", "test_id"))
+				})
+
+				It("should not add a scope when the identity has no permissions", func() {
+					Expect(fakeToken.GenerateArgsForCall(0)).ToNot(HaveKey("scope"))
+				})
+
+				Context("when the identity holds roles", func() {
+					BeforeEach(func() {
+						fakeRoles.PermissionsReturns([]string{"identity:read", "identity:write"}, nil)
+					})
 
+					It("should scope the access token to the granted permissions", func() {
+						_, identityID := fakeRoles.PermissionsArgsForCall(0)
+						Expect(identityID).To(Equal("test_id"))
+						Expect(fakeToken.GenerateArgsForCall(0)).To(HaveKeyWithValue("scope",
+							[]string{"identity:read", "identity:write"}))
+					})
+				})
+
+				Context("when the permissions can't be loaded", func() {
+					BeforeEach(func() {
+						fakeRoles.PermissionsReturns(nil, errors.New("db down"))
+					})
+
+					It("should respond with a 500 and not issue a token", func() {
+						Expect(response.StatusCode).To(Equal(http.StatusInternalServerError))
+						Expect(fakeToken.GenerateCallCount()).To(Equal(0))
+					})
+				})
+
+				It("should start a refresh token family for the identity", func() {
+					_, identityID := fakeRefresh.IssueArgsForCall(0)
+					Expect(identityID).To(Equal("test_id"))
 				})
 
 				It("should return the proper response body and code", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusOK))
+					Expect(string(body)).To(MatchJSON(`{"status": 200, "id": "test_id",
+						"token": "test_token", "refreshToken": "test_refresh_token"}`))
+				})
+
+				Context("when the identity has MFA enabled", func() {
+					BeforeEach(func() {
+						fakeMFA.EnabledReturns(true, nil)
+						fakeMFA.ChallengeReturns("test_mfa_token", nil)
+					})
+
+					It("should respond with a challenge instead of tokens", func() {
+						Expect(response.StatusCode).To(Equal(http.StatusOK))
+						Expect(string(body)).To(MatchJSON(`{"status": 200, "id": "test_id",
+							"mfaRequired": true, "mfaToken": "test_mfa_token"}`))
+						_, identityID := fakeMFA.ChallengeArgsForCall(0)
+						Expect(identityID).To(Equal("test_id"))
+						Expect(fakeToken.GenerateCallCount()).To(Equal(0))
+						Expect(fakeRefresh.IssueCallCount()).To(Equal(0))
+					})
+
+					It("should not clear failed attempts until the second factor passes", func() {
+						Expect(fakeThrottle.SucceedCallCount()).To(Equal(0))
+					})
+				})
+
+				Context("when MFA status can't be loaded", func() {
+					BeforeEach(func() {
+						fakeMFA.EnabledReturns(false, errors.New("db down"))
+					})
+
+					It("should respond with a 500 and not issue a token", func() {
+						Expect(response.StatusCode).To(Equal(http.StatusInternalServerError))
+						Expect(fakeToken.GenerateCallCount()).To(Equal(0))
+					})
+				})
+			})
+		})
+
+		Context("when a user completes an MFA challenge", func() {
+			var recorder *httptest.ResponseRecorder
+
+			BeforeEach(func() {
+				router = chi.NewRouter()
+				router.Post("/auth/mfa", identityHandler.VerifyMFA)
+				recorder = httptest.NewRecorder()
+				fakeToken.GenerateReturns("test_token", nil)
+				fakeRefresh.IssueReturns("test_refresh_token", nil)
+			})
+
+			serve := func(body string) {
+				request := httptest.NewRequest("POST", "/auth/mfa", bytes.NewBufferString(body))
+				router.ServeHTTP(recorder, request)
+			}
+
+			It("should exchange the challenge and code for tokens", func() {
+				fakeMFA.RedeemReturns("test_id", nil)
+				serve(`{"mfaToken": "test_mfa_token", "code": "123456"}`)
+				Expect(recorder.Code).To(Equal(http.StatusOK))
+				_, token, code := fakeMFA.RedeemArgsForCall(0)
+				Expect(token).To(Equal("test_mfa_token"))
+				Expect(code).To(Equal("123456"))
+				_, keys := fakeThrottle.SucceedArgsForCall(0)
+				Expect(keys).To(Equal([]string{"identity:test_id"}))
+				Expect(recorder.Body.String()).To(MatchJSON(`{"status": 200, "id": "test_id",
+					"token": "test_token", "refreshToken": "test_refresh_token"}`))
+			})
+
+			It("should respond with a 400 when the code is missing", func() {
+				serve(`{"mfaToken": "test_mfa_token"}`)
+				Expect(recorder.Code).To(Equal(http.StatusBadRequest))
+				Expect(fakeMFA.RedeemCallCount()).To(Equal(0))
+			})
+
+			It("should respond with a 401 and count a failure for a wrong code", func() {
+				fakeMFA.RedeemReturns("test_id", mfa.ErrInvalidCode)
+				serve(`{"mfaToken": "test_mfa_token", "code": "000000"}`)
+				Expect(recorder.Code).To(Equal(http.StatusUnauthorized))
+				Expect(recorder.Body.String()).To(Equal("invalid mfa code\n"))
+				_, keys := fakeThrottle.FailArgsForCall(0)
+				Expect(keys).To(ContainElement("identity:test_id"))
+				Expect(keys).To(HaveLen(2))
+				Expect(fakeToken.GenerateCallCount()).To(Equal(0))
+			})
+
+			It("should respond with a 401 for an invalid challenge", func() {
+				fakeMFA.RedeemReturns("", mfa.ErrInvalidChallenge)
+				serve(`{"mfaToken": "expired", "code": "123456"}`)
+				Expect(recorder.Code).To(Equal(http.StatusUnauthorized))
+				_, keys := fakeThrottle.FailArgsForCall(0)
+				Expect(keys).To(HaveLen(1))
+				Expect(fakeToken.GenerateCallCount()).To(Equal(0))
+			})
+
+			It("should respond with a 429 when the client IP is throttled", func() {
+				fakeThrottle.CheckReturns(time.Minute, nil)
+				serve(`{"mfaToken": "test_mfa_token", "code": "123456"}`)
+				Expect(recorder.Code).To(Equal(http.StatusTooManyRequests))
+				Expect(fakeMFA.RedeemCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("when a user refreshes their tokens", func() {
+			var recorder *httptest.ResponseRecorder
+
+			BeforeEach(func() {
+				router = chi.NewRouter()
+				router.Post("/auth/refresh", identityHandler.RefreshIdentity)
+				recorder = httptest.NewRecorder()
+				fakeToken.GenerateReturns("test_token", nil)
+			})
+
+			serve := func(body string) {
+				request := httptest.NewRequest("POST", "/auth/refresh", bytes.NewBufferString(body))
+				router.ServeHTTP(recorder, request)
+			}
+
+			It("should rotate the refresh token and issue a new access token", func() {
+				fakeRefresh.RotateReturns("test_id", "next_refresh_token", nil)
+				serve(`{"refreshToken": "test_refresh_token"}`)
+				Expect(recorder.Code).To(Equal(http.StatusOK))
+				_, token := fakeRefresh.RotateArgsForCall(0)
+				Expect(token).To(Equal("test_refresh_token"))
+				Expect(fakeToken.GenerateArgsForCall(0)).To(HaveKeyWithValue("sub", "test_id"))
+				Expect(recorder.Body.String()).To(MatchJSON(`{"status": 200, "id": "test_id",
+					"token": "test_token", "refreshToken": "next_refresh_token"}`))
+			})
+
+			It("should respond with a 400 when the refresh token is missing", func() {
+				serve(`{}`)
+				Expect(recorder.Code).To(Equal(http.StatusBadRequest))
+				Expect(fakeRefresh.RotateCallCount()).To(Equal(0))
+			})
+
+			It("should respond with a 401 for an invalid refresh token", func() {
+				fakeRefresh.RotateReturns("", "", refresh.ErrInvalid)
+				serve(`{"refreshToken": "expired"}`)
+				Expect(recorder.Code).To(Equal(http.StatusUnauthorized))
+				Expect(fakeToken.GenerateCallCount()).To(Equal(0))
+			})
+
+			It("should respond with a 401 for a reused refresh token", func() {
+				fakeRefresh.RotateReturns("", "", refresh.ErrReused)
+				serve(`{"refreshToken": "replayed"}`)
+				Expect(recorder.Code).To(Equal(http.StatusUnauthorized))
+				Expect(fakeToken.GenerateCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("when a user logs out", func() {
+			var recorder *httptest.ResponseRecorder
+
+			BeforeEach(func() {
+				router = chi.NewRouter()
+				router.Post("/auth/logout", identityHandler.LogoutIdentity)
+				recorder = httptest.NewRecorder()
+			})
+
+			logout := func() {
+				request := httptest.NewRequest("POST", "/auth/logout", nil)
+				request.Header.Set("token", "test_token")
+				router.ServeHTTP(recorder, request)
+			}
+
+			Context("with a valid token", func() {
+				BeforeEach(func() {
+					fakeToken.ValidateTokenReturns(&jwtGo.Token{
+						Claims: &jwt.IdentityClaims{
+							StandardClaims: jwtGo.StandardClaims{
+								Id:        "test_jti",
+								ExpiresAt: time.Now().Add(time.Minute).Unix(),
+							},
+						},
+					}, true, nil)
+				})
+
+				It("should revoke the token and respond with no content", func() {
+					logout()
+					Expect(recorder.Code).To(Equal(http.StatusNoContent))
+
+					request := httptest.NewRequest("GET", "/", nil)
+					request.Header.Set("token", "test_token")
+					isValid, err := fakeAuthClient.ValidateTokenHeader(request)
+					Expect(isValid).To(BeFalse())
+					Expect(err).To(Equal(auth.ErrRevokedToken))
+				})
+
+				It("should respond with a 401 when logging out twice", func() {
+					logout()
+					recorder = httptest.NewRecorder()
+					logout()
+					Expect(recorder.Code).To(Equal(http.StatusUnauthorized))
+				})
+			})
+
+			Context("with an invalid token", func() {
+				BeforeEach(func() {
+					fakeToken.ValidateTokenReturns(nil, false, errors.New("token is expired"))
+				})
 
+				It("should respond with a 401", func() {
+					logout()
+					Expect(recorder.Code).To(Equal(http.StatusUnauthorized))
 				})
 			})
 		})
+
+		Context("when a user sets an identity password", func() {
+			var (
+				recorder *httptest.ResponseRecorder
+				caller   *jwt.IdentityClaims
+			)
+
+			BeforeEach(func() {
+				router = chi.NewRouter()
+				router.Put("/identity/{id}/password", identityHandler.SetIdentityPassword)
+				recorder = httptest.NewRecorder()
+				caller = &jwt.IdentityClaims{Scope: "identity:read identity:write"}
+				caller.Subject = "test_id"
+				fakeService.VerifyPasswordReturns(true, nil)
+			})
+
+			serve := func(body string) {
+				req := httptest.NewRequest("PUT", "/identity/test_id/password",
+					bytes.NewBufferString(body))
+				router.ServeHTTP(recorder, req.WithContext(request.WithClaims(req.Context(), caller)))
+			}
+
+			It("should store the password and respond with no content", func() {
+				serve(`{"password": "correct horse", "current_password": "old horse"}`)
+				Expect(recorder.Code).To(Equal(http.StatusNoContent))
+				_, id, current := fakeService.VerifyPasswordArgsForCall(0)
+				Expect(id).To(Equal("test_id"))
+				Expect(current).To(Equal("old horse"))
+				_, id, password := fakeService.SetPasswordArgsForCall(0)
+				Expect(id).To(Equal("test_id"))
+				Expect(password).To(Equal("correct horse"))
+			})
+
+			It("should revoke the identity's refresh tokens and sessions", func() {
+				serve(`{"password": "correct horse", "current_password": "old horse"}`)
+				_, refreshID := fakeRefresh.RevokeAllArgsForCall(0)
+				Expect(refreshID).To(Equal("test_id"))
+				_, sessionID := fakeSessions.RevokeAllArgsForCall(0)
+				Expect(sessionID).To(Equal("test_id"))
+			})
+
+			It("should refuse a wrong current password", func() {
+				fakeService.VerifyPasswordReturns(false, nil)
+				serve(`{"password": "correct horse", "current_password": "guess"}`)
+				Expect(recorder.Code).To(Equal(http.StatusForbidden))
+				Expect(fakeService.SetPasswordCallCount()).To(Equal(0))
+			})
+
+			It("should refuse an editor setting another identity's password", func() {
+				caller.Subject = "editor_id"
+				serve(`{"password": "correct horse", "current_password": "old horse"}`)
+				Expect(recorder.Code).To(Equal(http.StatusForbidden))
+				Expect(fakeService.SetPasswordCallCount()).To(Equal(0))
+			})
+
+			It("should let an admin reset another identity's password", func() {
+				caller = &jwt.IdentityClaims{Scope: "identity:write identity:admin"}
+				caller.Subject = "admin_id"
+				serve(`{"password": "correct horse"}`)
+				Expect(recorder.Code).To(Equal(http.StatusNoContent))
+				Expect(fakeService.VerifyPasswordCallCount()).To(Equal(0))
+				Expect(fakeRefresh.RevokeAllCallCount()).To(Equal(1))
+			})
+
+			It("should reject a short password", func() {
+				serve(`{"password": "short"}`)
+				Expect(recorder.Code).To(Equal(http.StatusBadRequest))
+				Expect(fakeService.SetPasswordCallCount()).To(Equal(0))
+			})
+
+			It("should respond with a 404 when the identity does not exist", func() {
+				fakeService.SetPasswordReturns(sql.ErrNoRows)
+				serve(`{"password": "correct horse", "current_password": "old horse"}`)
+				Expect(recorder.Code).To(Equal(http.StatusNotFound))
+			})
+		})
 	})
 })
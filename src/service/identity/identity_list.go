@@ -0,0 +1,247 @@
+package identity
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	//DefaultListLimit ... is the page size used when no limit is requested.
+	DefaultListLimit = 20
+	//MaxListLimit ... is the largest page size a caller may request.
+	MaxListLimit = 100
+
+	sortAscending  = "created_at"
+	sortDescending = "-created_at"
+
+	directionNext = "next"
+	directionPrev = "prev"
+)
+
+//Cursor ... marks a position in the (created_at, id) ordering of identities.
+type Cursor struct {
+	CreatedAt time.Time `json:"c"`
+	ID        string    `json:"i"`
+	Sort      string    `json:"s"`
+	Direction string    `json:"d"`
+}
+
+//ListQuery ... holds the filters, ordering and position of an identity listing.
+type ListQuery struct {
+	Limit         int
+	NamePrefix    string
+	CreatedAfter  *time.Time
+	CreatedBefore *time.Time
+	Descending    bool
+	Cursor        *Cursor
+}
+
+//Page ... is a single page of identities along with the cursors around it.
+type Page struct {
+	Rows []Row
+	Next string
+	Prev string
+}
+
+type listResponse struct {
+	Code     int    `json:"status"`
+	Elements []Row  `json:"identities"`
+	Next     string `json:"next,omitempty"`
+	Prev     string `json:"prev,omitempty"`
+}
+
+//RespondWithJSON ... marshals this Page into a list envelope and returns it.
+func (p *Page) RespondWithJSON(status int, w io.Writer) error {
+	response := listResponse{
+		Code:     status,
+		Elements: p.Rows,
+		Next:     p.Next,
+		Prev:     p.Prev,
+	}
+	if response.Elements == nil {
+		response.Elements = make([]Row, 0)
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(response)
+}
+
+//ParseListQuery ... builds a ListQuery from url query values, the returned error is safe to
+//show to callers.
+func ParseListQuery(values url.Values) (ListQuery, error) {
+	query := ListQuery{
+		Limit:      DefaultListLimit,
+		NamePrefix: values.Get("name"),
+	}
+	if raw := values.Get("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit < 1 || limit > MaxListLimit {
+			return query, errors.New("limit must be between 1 and " + strconv.Itoa(MaxListLimit))
+		}
+		query.Limit = limit
+	}
+	var err error
+	if query.CreatedAfter, err = parseTimeParam(values, "created_after"); err != nil {
+		return query, err
+	}
+	if query.CreatedBefore, err = parseTimeParam(values, "created_before"); err != nil {
+		return query, err
+	}
+	switch values.Get("sort") {
+	case "", sortAscending:
+	case sortDescending:
+		query.Descending = true
+	default:
+		return query, errors.New("sort must be created_at or -created_at")
+	}
+	if raw := values.Get("cursor"); raw != "" {
+		cursor, err := decodeCursor(raw)
+		if err != nil {
+			return query, errors.New("cursor is invalid")
+		}
+		if cursor.Sort != query.sort() {
+			return query, errors.New("cursor does not match sort")
+		}
+		query.Cursor = cursor
+	}
+	return query, nil
+}
+
+func parseTimeParam(values url.Values, param string) (*time.Time, error) {
+	raw := values.Get(param)
+	if raw == "" {
+		return nil, nil
+	}
+	parsed, err := time.Parse(time.RFC3339, raw)
+	if err != nil {
+		return nil, errors.New(param + " must be an RFC3339 timestamp")
+	}
+	return &parsed, nil
+}
+
+func (q *ListQuery) sort() string {
+	if q.Descending {
+		return sortDescending
+	}
+	return sortAscending
+}
+
+//backwards is true when rows must be read against the requested sort to page towards prev.
+func (q *ListQuery) backwards() bool {
+	return q.Cursor != nil && q.Cursor.Direction == directionPrev
+}
+
+func (q *ListQuery) newCursor(row Row, direction string) string {
+	encoded, _ := encodeCursor(&Cursor{
+		CreatedAt: row.CreatedAt,
+		ID:        row.ID,
+		Sort:      q.sort(),
+		Direction: direction,
+	})
+	return encoded
+}
+
+//sql builds the WHERE, ORDER BY and LIMIT clauses and their arguments for this query.
+func (q *ListQuery) sql() (string, []interface{}) {
+	var conditions []string
+	var args []interface{}
+	next := func(v interface{}) string {
+		args = append(args, v)
+		return "$" + strconv.Itoa(len(args))
+	}
+	if q.NamePrefix != "" {
+		p := next(escapeLike(q.NamePrefix) + "%")
+		conditions = append(conditions, "(first_name ILIKE "+p+" OR last_name ILIKE "+p+")")
+	}
+	if q.CreatedAfter != nil {
+		conditions = append(conditions, "created_at >= "+next(*q.CreatedAfter))
+	}
+	if q.CreatedBefore != nil {
+		conditions = append(conditions, "created_at < "+next(*q.CreatedBefore))
+	}
+
+	//Reading backwards flips both the comparison and the ordering, rows are
+	//restored to the requested order after the query.
+	descending := q.Descending != q.backwards()
+	if q.Cursor != nil {
+		comparison := ">"
+		if descending {
+			comparison = "<"
+		}
+		conditions = append(conditions, "(created_at, id) "+comparison+" ("+
+			next(q.Cursor.CreatedAt)+", "+next(q.Cursor.ID)+")")
+	}
+	order := "ASC"
+	if descending {
+		order = "DESC"
+	}
+
+	clause := ""
+	if len(conditions) > 0 {
+		clause = " WHERE " + strings.Join(conditions, " AND ")
+	}
+	clause += " ORDER BY created_at " + order + ", id " + order
+	clause += " LIMIT " + next(q.Limit+1)
+	return clause, args
+}
+
+//paginate trims the extra look-ahead row, restores ordering and sets the page cursors.
+func (q *ListQuery) paginate(rows []Row) *Page {
+	hasMore := len(rows) > q.Limit
+	if hasMore {
+		rows = rows[:q.Limit]
+	}
+	if q.backwards() {
+		for i, j := 0, len(rows)-1; i < j; i, j = i+1, j-1 {
+			rows[i], rows[j] = rows[j], rows[i]
+		}
+	}
+	page := &Page{Rows: rows}
+	if len(rows) == 0 {
+		return page
+	}
+	hasNext, hasPrev := hasMore, q.Cursor != nil
+	if q.backwards() {
+		hasNext, hasPrev = true, hasMore
+	}
+	if hasNext {
+		page.Next = q.newCursor(rows[len(rows)-1], directionNext)
+	}
+	if hasPrev {
+		page.Prev = q.newCursor(rows[0], directionPrev)
+	}
+	return page
+}
+
+func encodeCursor(cursor *Cursor) (string, error) {
+	raw, err := json.Marshal(cursor)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(raw), nil
+}
+
+func decodeCursor(encoded string) (*Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, err
+	}
+	var cursor Cursor
+	if err := json.Unmarshal(raw, &cursor); err != nil {
+		return nil, err
+	}
+	if cursor.ID == "" || (cursor.Direction != directionNext && cursor.Direction != directionPrev) {
+		return nil, errors.New("malformed cursor")
+	}
+	return &cursor, nil
+}
+
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
@@ -0,0 +1,149 @@
+package identity_test
+
+import (
+	"context"
+	"database/sql"
+	"net/url"
+	"service/identity"
+	"service/log/logfakes"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	sqlmock "gopkg.in/DATA-DOG/go-sqlmock.v1"
+)
+
+var _ = Describe("Identity List Specs", func() {
+	ctx := context.Background()
+
+	Context("ParseListQuery", func() {
+		It("should default the limit and sort ascending", func() {
+			query, err := identity.ParseListQuery(url.Values{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(query.Limit).To(Equal(identity.DefaultListLimit))
+			Expect(query.Descending).To(BeFalse())
+			Expect(query.Cursor).To(BeNil())
+		})
+
+		It("should read the filters", func() {
+			query, err := identity.ParseListQuery(url.Values{
+				"limit":          {"5"},
+				"name":           {"ad"},
+				"created_after":  {"2018-01-01T00:00:00Z"},
+				"created_before": {"2018-02-01T00:00:00Z"},
+				"sort":           {"-created_at"},
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(query.Limit).To(Equal(5))
+			Expect(query.NamePrefix).To(Equal("ad"))
+			Expect(query.CreatedAfter.Month()).To(Equal(time.January))
+			Expect(query.CreatedBefore.Month()).To(Equal(time.February))
+			Expect(query.Descending).To(BeTrue())
+		})
+
+		It("should reject limits outside of the bounds", func() {
+			_, err := identity.ParseListQuery(url.Values{"limit": {"0"}})
+			Expect(err).To(HaveOccurred())
+			_, err = identity.ParseListQuery(url.Values{"limit": {"101"}})
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should reject an unknown sort and a garbage cursor", func() {
+			_, err := identity.ParseListQuery(url.Values{"sort": {"first_name"}})
+			Expect(err).To(HaveOccurred())
+			_, err = identity.ParseListQuery(url.Values{"cursor": {"not-a-cursor"}})
+			Expect(err).To(MatchError("cursor is invalid"))
+		})
+	})
+
+	Context("ServiceObject.List", func() {
+		var (
+			identityService *identity.ServiceObject
+			db              *sql.DB
+			mockDB          sqlmock.Sqlmock
+			columns         []string
+			start           time.Time
+		)
+
+		BeforeEach(func() {
+			var sqlmockErr error
+			db, mockDB, sqlmockErr = sqlmock.New()
+			Expect(sqlmockErr).ToNot(HaveOccurred())
+			identityService = identity.NewServiceObject(&logfakes.FakeProdInterface{}, db)
+			columns = []string{"id", "first_name", "last_name", "profile", "created_at",
+				"updated_at"}
+			start = time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC)
+		})
+
+		AfterEach(func() {
+			Expect(mockDB.ExpectationsWereMet()).To(Succeed())
+		})
+
+		addRows := func(rows *sqlmock.Rows, ids ...int) *sqlmock.Rows {
+			for _, i := range ids {
+				at := start.Add(time.Duration(i) * time.Minute)
+				rows = rows.AddRow(string(rune('a'+i)), "first", "last", []byte(`{}`), at, at)
+			}
+			return rows
+		}
+
+		It("should filter by name prefix and fetch one row past the limit", func() {
+			mockDB.ExpectQuery(`FROM identity WHERE \(first_name ILIKE \$1 OR last_name ILIKE \$1\) `+
+				`ORDER BY created_at ASC, id ASC LIMIT \$2`).
+				WithArgs(`a\_d%`, 3).
+				WillReturnRows(addRows(sqlmock.NewRows(columns), 0, 1, 2))
+
+			page, err := identityService.List(ctx, identity.ListQuery{Limit: 2, NamePrefix: "a_d"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(page.Rows).To(HaveLen(2))
+			Expect(page.Next).ToNot(BeEmpty())
+			Expect(page.Prev).To(BeEmpty())
+		})
+
+		It("should page forwards and backwards with the returned cursors", func() {
+			mockDB.ExpectQuery(`ORDER BY created_at ASC, id ASC LIMIT \$1`).
+				WithArgs(3).
+				WillReturnRows(addRows(sqlmock.NewRows(columns), 0, 1, 2))
+			first, err := identityService.List(ctx, identity.ListQuery{Limit: 2})
+			Expect(err).ToNot(HaveOccurred())
+
+			query, err := identity.ParseListQuery(url.Values{"limit": {"2"}, "cursor": {first.Next}})
+			Expect(err).ToNot(HaveOccurred())
+			mockDB.ExpectQuery(`WHERE \(created_at, id\) > \(\$1, \$2\) `+
+				`ORDER BY created_at ASC, id ASC LIMIT \$3`).
+				WithArgs(start.Add(time.Minute), "b", 3).
+				WillReturnRows(addRows(sqlmock.NewRows(columns), 2))
+			second, err := identityService.List(ctx, query)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(second.Rows).To(HaveLen(1))
+			Expect(second.Rows[0].ID).To(Equal("c"))
+			Expect(second.Next).To(BeEmpty())
+			Expect(second.Prev).ToNot(BeEmpty())
+
+			query, err = identity.ParseListQuery(url.Values{"limit": {"2"}, "cursor": {second.Prev}})
+			Expect(err).ToNot(HaveOccurred())
+			mockDB.ExpectQuery(`WHERE \(created_at, id\) < \(\$1, \$2\) `+
+				`ORDER BY created_at DESC, id DESC LIMIT \$3`).
+				WithArgs(start.Add(2*time.Minute), "c", 3).
+				WillReturnRows(addRows(sqlmock.NewRows(columns), 1, 0))
+			back, err := identityService.List(ctx, query)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(back.Rows).To(HaveLen(2))
+			Expect(back.Rows[0].ID).To(Equal("a"))
+			Expect(back.Rows[1].ID).To(Equal("b"))
+			Expect(back.Next).ToNot(BeEmpty())
+			Expect(back.Prev).To(BeEmpty())
+		})
+
+		It("should reject a cursor issued for a different sort", func() {
+			mockDB.ExpectQuery(`ORDER BY created_at DESC, id DESC LIMIT \$1`).
+				WithArgs(2).
+				WillReturnRows(addRows(sqlmock.NewRows(columns), 2, 1))
+			page, err := identityService.List(ctx, identity.ListQuery{Limit: 1, Descending: true})
+			Expect(err).ToNot(HaveOccurred())
+
+			_, err = identity.ParseListQuery(url.Values{"cursor": {page.Next}})
+			Expect(err).To(MatchError("cursor does not match sort"))
+		})
+	})
+})
@@ -16,6 +16,39 @@ type Row struct {
 	UpdatedAt   time.Time   `pq:"updated_at" json:"updatedAt"`
 }
 
+//Input ... holds the caller supplied fields used to create or replace an identity.
+type Input struct {
+	FirstName   string      `json:"firstName"`
+	LastName    string      `json:"lastName"`
+	ProfileInfo interface{} `json:"profile"`
+}
+
+//Patch ... holds the caller supplied fields for a partial update, nil fields are left as is.
+type Patch struct {
+	FirstName   *string     `json:"firstName"`
+	LastName    *string     `json:"lastName"`
+	ProfileInfo interface{} `json:"profile"`
+}
+
+//Apply ... merges the set fields of a Patch over an existing Row and returns the result.
+func (p *Patch) Apply(row *Row) Input {
+	input := Input{
+		FirstName:   row.FirstName,
+		LastName:    row.LastName,
+		ProfileInfo: row.ProfileInfo,
+	}
+	if p.FirstName != nil {
+		input.FirstName = *p.FirstName
+	}
+	if p.LastName != nil {
+		input.LastName = *p.LastName
+	}
+	if p.ProfileInfo != nil {
+		input.ProfileInfo = p.ProfileInfo
+	}
+	return input
+}
+
 type singularResponse struct {
 	Code    int `json:"status"`
 	Element Row `json:"identity"`
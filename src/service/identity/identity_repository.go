@@ -0,0 +1,95 @@
+package identity
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"service/database"
+)
+
+//rowColumns is the column list of Row, generated from its pq tags.
+var rowColumns = database.ColumnList(Row{})
+
+//Repository ... reads and writes identity rows, mapping them to Row with the database package.
+//It holds no business rules, those stay in ServiceObject.
+type Repository struct {
+	db database.DBInterface
+}
+
+//NewRepository ... returns a Repository that runs its queries on db, which may be a transaction.
+func NewRepository(db database.DBInterface) *Repository {
+	return &Repository{db: db}
+}
+
+//Get ... returns the identity with the given id, or sql.ErrNoRows.
+func (r *Repository) Get(ctx context.Context, id string) (*Row, error) {
+	return r.one(r.db.QueryRowContext(ctx,
+		"SELECT "+rowColumns+" FROM identity WHERE id = $1;", id))
+}
+
+//GetByEmail ... returns the oldest identity whose profile email matches, ignoring case,
+//or sql.ErrNoRows.
+func (r *Repository) GetByEmail(ctx context.Context, email string) (*Row, error) {
+	return r.one(r.db.QueryRowContext(ctx, "SELECT "+rowColumns+` FROM identity
+		WHERE lower(profile->>'email') = lower($1) ORDER BY created_at LIMIT 1;`, email))
+}
+
+//Insert ... stores row as a new identity.
+func (r *Repository) Insert(ctx context.Context, row *Row) (sql.Result, error) {
+	profile, err := json.Marshal(row.ProfileInfo)
+	if err != nil {
+		return nil, err
+	}
+	return r.db.ExecContext(ctx, "INSERT INTO identity ("+rowColumns+`) VALUES
+		($1, $2, $3, $4, $5, $6);`,
+		row.ID,
+		row.FirstName,
+		row.LastName,
+		profile,
+		row.CreatedAt,
+		row.UpdatedAt)
+}
+
+//Update ... replaces the writable fields of the identity with row.ID and returns the stored row,
+//or sql.ErrNoRows. Changing the profile email drops its verification.
+func (r *Repository) Update(ctx context.Context, row *Row) (*Row, error) {
+	profile, err := json.Marshal(row.ProfileInfo)
+	if err != nil {
+		return nil, err
+	}
+	return r.one(r.db.QueryRowContext(ctx, `UPDATE identity
+		SET first_name = $2, last_name = $3, profile = $4, updated_at = $5,
+			email_verified_at = CASE WHEN profile->>'email' IS DISTINCT FROM $4::jsonb->>'email'
+				THEN NULL ELSE email_verified_at END
+		WHERE id = $1
+		RETURNING `+rowColumns+";",
+		row.ID,
+		row.FirstName,
+		row.LastName,
+		profile,
+		row.UpdatedAt))
+}
+
+//List ... returns the identities matching the filters, sort and cursor of query, with one row
+//past its limit so the caller can tell whether there is another page.
+func (r *Repository) List(ctx context.Context, query ListQuery) ([]Row, error) {
+	clause, args := query.sql()
+	rows, err := r.db.QueryContext(ctx, "SELECT "+rowColumns+" FROM identity"+clause+";", args...)
+	if err != nil {
+		return nil, err
+	}
+	identityRows := make([]Row, 0, query.Limit+1)
+	if err := database.ScanAll(rows, &identityRows); err != nil {
+		return nil, err
+	}
+	return identityRows, nil
+}
+
+//one scans a single identity.
+func (r *Repository) one(row *sql.Row) (*Row, error) {
+	var identityRow Row
+	if err := database.ScanRow(row, &identityRow); err != nil {
+		return nil, err
+	}
+	return &identityRow, nil
+}
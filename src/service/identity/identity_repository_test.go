@@ -0,0 +1,77 @@
+package identity_test
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"service/identity"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	sqlmock "gopkg.in/DATA-DOG/go-sqlmock.v1"
+)
+
+var _ = Describe("Identity Repository Specs", func() {
+	ctx := context.Background()
+	columns := []string{"id", "first_name", "last_name", "profile", "created_at", "updated_at"}
+
+	var (
+		repository *identity.Repository
+		mockDB     sqlmock.Sqlmock
+	)
+
+	BeforeEach(func() {
+		db, mock, sqlmockErr := sqlmock.New()
+		Expect(sqlmockErr).ToNot(HaveOccurred())
+		mockDB = mock
+		repository = identity.NewRepository(db)
+	})
+
+	AfterEach(func() {
+		Expect(mockDB.ExpectationsWereMet()).To(Succeed())
+	})
+
+	It("should select the columns generated from Row and keep created_at apart from updated_at", func() {
+		createdAt := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+		updatedAt := createdAt.Add(time.Hour)
+		mockDB.ExpectQuery(regexp.QuoteMeta(
+			"SELECT id, first_name, last_name, profile, created_at, updated_at FROM identity WHERE id = $1;")).
+			WithArgs("uuidv4").
+			WillReturnRows(sqlmock.NewRows(columns).
+				AddRow("uuidv4", "ada", "lovelace", []byte(`{"email":"ada@example.com"}`), createdAt, updatedAt))
+
+		row, err := repository.Get(ctx, "uuidv4")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(row.CreatedAt).To(Equal(createdAt))
+		Expect(row.UpdatedAt).To(Equal(updatedAt))
+		Expect(row.ProfileInfo).To(Equal(map[string]interface{}{"email": "ada@example.com"}))
+	})
+
+	It("should return sql.ErrNoRows for an unknown identity", func() {
+		mockDB.ExpectQuery("SELECT id").WithArgs("missing").WillReturnRows(sqlmock.NewRows(columns))
+
+		row, err := repository.Get(ctx, "missing")
+		Expect(row).To(BeNil())
+		Expect(err).To(Equal(sql.ErrNoRows))
+	})
+
+	It("should return sql.ErrNoRows, never a nil row, when updating an unknown identity", func() {
+		mockDB.ExpectQuery("UPDATE identity").WillReturnRows(sqlmock.NewRows(columns))
+
+		row, err := repository.Update(ctx, &identity.Row{ID: "missing", FirstName: "ada"})
+		Expect(row).To(BeNil())
+		Expect(err).To(Equal(sql.ErrNoRows))
+	})
+
+	It("should store the profile as JSON on insert", func() {
+		mockDB.ExpectExec("INSERT INTO identity").
+			WithArgs("uuidv4", "ada", "lovelace", []byte(`{"email":"ada@example.com"}`),
+				sqlmock.AnyArg(), sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		_, err := repository.Insert(ctx, &identity.Row{ID: "uuidv4", FirstName: "ada",
+			LastName: "lovelace", ProfileInfo: map[string]string{"email": "ada@example.com"}})
+		Expect(err).ToNot(HaveOccurred())
+	})
+})
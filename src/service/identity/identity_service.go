@@ -1,9 +1,11 @@
 package identity
 
 import (
+	"context"
 	"database/sql"
-	"encoding/json"
 	"errors"
+	"service/auth/credential"
+	"service/auth/permission"
 	"service/database"
 	"service/log"
 	"time"
@@ -16,15 +18,23 @@ import (
 //ServiceInterface ... defines a required interface for all identity service methods.
 //go:generate counterfeiter . ServiceInterface
 type ServiceInterface interface {
-	Fetch(id string) (*Row, error)
-	Create(id string) (*Row, sql.Result, error)
+	Fetch(ctx context.Context, id string) (*Row, error)
+	Create(ctx context.Context, input Input) (*Row, sql.Result, error)
+	Update(ctx context.Context, id string, input Input) (*Row, error)
+	Delete(ctx context.Context, id string) error
+	List(ctx context.Context, query ListQuery) (*Page, error)
+	SetPassword(ctx context.Context, id, password string) error
+	VerifyPassword(ctx context.Context, id, password string) (bool, error)
+	FindByEmail(ctx context.Context, email string) (*Row, error)
+	MarkEmailVerified(ctx context.Context, id, email string) error
 }
 
 //ServiceObject ...
 //contains all elementals needing to be injected in tests, and used to perform business.
 type ServiceObject struct {
-	log log.ProdInterface
-	db  database.DBInterface
+	log  log.ProdInterface
+	db   database.DBInterface
+	repo *Repository
 }
 
 //NewServiceObject ...
@@ -32,39 +42,50 @@ type ServiceObject struct {
 //can contain fakes or real clients and perform bound methods.
 func NewServiceObject(logClient log.ProdInterface, dbClient database.DBInterface) *ServiceObject {
 	return &ServiceObject{
-		log: logClient,
-		db:  dbClient,
+		log:  logClient,
+		db:   dbClient,
+		repo: NewRepository(dbClient),
 	}
 }
 
-type jsonObject struct {
-	Email string `json:"email"`
-}
-
 //Create ...
-//Creates a unique uuid.v4, creates a identity record, queries for the created record, and
-//returns the created record.
-func (s *ServiceObject) Create(id string) (*Row, sql.Result, error) {
-	var identity Row
-	jsonObj := jsonObject{
-		Email: "test@gmail.com",
-	}
+//Creates a unique uuid.v4, creates a identity record from input, queries for the created
+//record, and returns the created record. The identity is given permission.SelfRole with it.
+func (s *ServiceObject) Create(ctx context.Context, input Input) (*Row, sql.Result, error) {
 	generatedID := uuid.New()
 	generatedVariant := uuid2.NewV4()
 	supraID := generatedVariant.String() + "-" + generatedID.String()
 	supraID = supraID[0:50]
 	s.log.Debug("supraID:", zap.String("supraID", supraID), zap.Int("supra lenght", len(supraID)))
-	rawJSON, _ := json.Marshal(&jsonObj)
 	rightNow := time.Now()
-	result, err := s.db.Exec(`INSERT INTO identity
-		(id, first_name, last_name, profile, created_at, updated_at) VALUES
-		($1, $2, $3, $4, $5, $6);`,
-		supraID,
-		"adam",
-		"cobb",
-		rawJSON,
-		rightNow,
-		rightNow)
+	row := &Row{
+		ID:          supraID,
+		FirstName:   input.FirstName,
+		LastName:    input.LastName,
+		ProfileInfo: input.ProfileInfo,
+		CreatedAt:   rightNow,
+		UpdatedAt:   rightNow,
+	}
+	var identity *Row
+	var result sql.Result
+	//The insert, the read back and the self role share one transaction so the caller never
+	//sees a partial write, nor an identity that cannot act on itself.
+	err := database.InTx(ctx, s.db, func(tx database.DBInterface) error {
+		var txErr error
+		identity, result, txErr = insertIdentity(ctx, NewRepository(tx), row)
+		if txErr != nil {
+			return txErr
+		}
+		return permission.NewPostgresStore(tx).AssignRole(ctx, identity.ID, permission.SelfRole)
+	})
+	if err != nil {
+		return nil, result, err
+	}
+	return identity, result, nil
+}
+
+func insertIdentity(ctx context.Context, repo *Repository, row *Row) (*Row, sql.Result, error) {
+	result, err := repo.Insert(ctx, row)
 	if err != nil {
 		return nil, result, err
 	}
@@ -78,50 +99,115 @@ func (s *ServiceObject) Create(id string) (*Row, sql.Result, error) {
 		insertErr := errors.New("INSERT INTO had fatal errors")
 		return nil, nil, insertErr
 	}
-	sqlRow := s.db.QueryRow(`SELECT id, first_name, last_name, profile, created_at, updated_at
-		FROM identity WHERE id = $1`, supraID)
-	if sqlRow != nil {
-		var jsonData []byte
+	identity, getErr := repo.Get(ctx, row.ID)
+	if getErr != nil {
+		return nil, nil, getErr
+	}
+	return identity, result, nil
+}
 
-		scanErr := sqlRow.Scan(&identity.ID, &identity.FirstName, &identity.LastName,
-			&jsonData, &identity.CreatedAt, &identity.UpdatedAt)
-		if scanErr != nil {
-			return nil, nil, scanErr
-		}
+//Fetch ... is an interface method for fetching identity records.
+//Returns sql.ErrNoRows when no identity has the given id.
+func (s *ServiceObject) Fetch(ctx context.Context, id string) (*Row, error) {
+	s.log.Debug("Fetch", zap.String("id", id))
+	return s.repo.Get(ctx, id)
+}
 
-		if len(jsonData) > 0 {
-			var output interface{}
-			decodeErr := json.Unmarshal(jsonData, &output)
-			if decodeErr != nil {
-				return nil, nil, decodeErr
-			}
-			identity.ProfileInfo = output
-		}
+//Update ... replaces the writable fields of an identity record and returns the updated record.
+//Returns sql.ErrNoRows when no identity has the given id.
+func (s *ServiceObject) Update(ctx context.Context, id string, input Input) (*Row, error) {
+	return s.repo.Update(ctx, &Row{
+		ID:          id,
+		FirstName:   input.FirstName,
+		LastName:    input.LastName,
+		ProfileInfo: input.ProfileInfo,
+		UpdatedAt:   time.Now(),
+	})
+}
+
+//Delete ... removes an identity record.
+//Returns sql.ErrNoRows when no identity has the given id.
+func (s *ServiceObject) Delete(ctx context.Context, id string) error {
+	result, err := s.db.ExecContext(ctx, "DELETE FROM identity WHERE id = $1;", id)
+	if err != nil {
+		return err
 	}
-	return &identity, result, nil
+	affected, resErr := result.RowsAffected()
+	if resErr != nil {
+		return resErr
+	}
+	if affected == int64(0) {
+		return sql.ErrNoRows
+	}
+	return nil
 }
 
-//Fetch ... is an interface method for fetching identity records.
-func (s *ServiceObject) Fetch(id string) (*Row, error) {
-	row := s.db.QueryRow(
-		"SELECT id, first_name, last_name, profile, created_at, updated_at FROM identity WHERE id = $1;", id)
-	s.log.Debug("Fetch", zap.Any("row", row))
-	if row != nil {
-		var identityRow Row
-		var jsonData []byte
-		if err := row.Scan(&identityRow.ID, &identityRow.FirstName, &identityRow.LastName,
-			&jsonData, &identityRow.CreatedAt, &identityRow.CreatedAt); err != nil {
-			return nil, err
-		}
-		if len(jsonData) > 0 {
-			var output interface{}
-			decodingError := json.Unmarshal(jsonData, &output)
-			if decodingError != nil {
-				return nil, decodingError
-			}
-			identityRow.ProfileInfo = output
-		}
-		return &identityRow, nil
+//List ... returns a page of identity records matching the filters of query.
+func (s *ServiceObject) List(ctx context.Context, query ListQuery) (*Page, error) {
+	identityRows, err := s.repo.List(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	return query.paginate(identityRows), nil
+}
+
+//SetPassword ... hashes password and stores it as the password of an identity.
+//Returns sql.ErrNoRows when no identity has the given id.
+func (s *ServiceObject) SetPassword(ctx context.Context, id, password string) error {
+	hash, err := credential.HashPassword(password)
+	if err != nil {
+		return err
+	}
+	result, err := s.db.ExecContext(ctx,
+		"UPDATE identity SET password_hash = $2, updated_at = $3 WHERE id = $1;",
+		id, hash, time.Now())
+	if err != nil {
+		return err
+	}
+	affected, resErr := result.RowsAffected()
+	if resErr != nil {
+		return resErr
+	}
+	if affected == int64(0) {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+//VerifyPassword ... checks password against the stored hash of an identity. Unknown
+//identities and identities without a password are compared against a dummy hash so every
+//call costs the same.
+func (s *ServiceObject) VerifyPassword(ctx context.Context, id, password string) (bool, error) {
+	var hash []byte
+	err := s.db.QueryRowContext(ctx,
+		"SELECT password_hash FROM identity WHERE id = $1;", id).Scan(&hash)
+	if err != nil && err != sql.ErrNoRows {
+		return false, err
+	}
+	return credential.ComparePassword(hash, password), nil
+}
+
+//FindByEmail ... returns the identity whose profile email matches, ignoring case.
+//Returns sql.ErrNoRows when no identity has the email.
+func (s *ServiceObject) FindByEmail(ctx context.Context, email string) (*Row, error) {
+	return s.repo.GetByEmail(ctx, email)
+}
+
+//MarkEmailVerified ... records that the identity proved it owns email.
+//Returns sql.ErrNoRows when the identity is gone or its email has changed since.
+func (s *ServiceObject) MarkEmailVerified(ctx context.Context, id, email string) error {
+	result, err := s.db.ExecContext(ctx, `UPDATE identity SET email_verified_at = $3
+		WHERE id = $1 AND profile->>'email' = $2;`,
+		id, email, time.Now())
+	if err != nil {
+		return err
+	}
+	affected, resErr := result.RowsAffected()
+	if resErr != nil {
+		return resErr
+	}
+	if affected == int64(0) {
+		return sql.ErrNoRows
 	}
-	return nil, nil
+	return nil
 }
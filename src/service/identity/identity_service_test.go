@@ -1,8 +1,12 @@
 package identity_test
 
 import (
+	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
+	"service/auth/credential"
+	"service/auth/permission"
 	"service/identity"
 	"service/log/logfakes"
 	"service/utils/sqltest"
@@ -14,6 +18,8 @@ import (
 )
 
 var _ = Describe("Identity Service Specs", func() {
+	ctx := context.Background()
+
 	Context("identity service logic", func() {
 		var (
 			identityService *identity.ServiceObject
@@ -52,7 +58,7 @@ var _ = Describe("Identity Service Specs", func() {
 			})
 
 			JustBeforeEach(func() {
-				identityRow, err = identityService.Fetch("uuidv4")
+				identityRow, err = identityService.Fetch(ctx, "uuidv4")
 			})
 
 			It("should return with no errors", func() {
@@ -112,16 +118,29 @@ var _ = Describe("Identity Service Specs", func() {
 				mockRows = mockRows.AddRow("uuidv4", "test_first_name", "test_last_name",
 					[]byte(`{"email": "test@gmail.com"}`), rightNow, rightNow)
 
+				mockDB.ExpectBegin()
 				mockDB.ExpectExec("INSERT INTO identity").WithArgs(sqltest.AnyString{},
 					"adam", "cobb", rawJSON,
 					sqltest.AnyTime{}, sqltest.AnyTime{}).WillReturnResult(mockResult)
 				mockDB.ExpectQuery(`SELECT id, first_name, last_name, profile, created_at, updated_at`).WithArgs(sqltest.AnyString{}).WillReturnRows(mockRows)
+				mockDB.ExpectQuery("SELECT 1 FROM role").WithArgs(permission.SelfRole).
+					WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(1))
+				mockDB.ExpectExec("INSERT INTO identity_role").
+					WithArgs("uuidv4", permission.SelfRole, sqltest.AnyTime{}).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mockDB.ExpectCommit()
 			})
 
 			JustBeforeEach(func() {
 				//Doing this will pass in a properly configured mock HERE. Do not do earlier!
 				identityService = identity.NewServiceObject(fakeLog, db)
-				identityRow, result, err = identityService.Create("uuidv7")
+				m := make(map[string]string)
+				m["email"] = "test@gmail.com"
+				identityRow, result, err = identityService.Create(ctx, identity.Input{
+					FirstName:   "adam",
+					LastName:    "cobb",
+					ProfileInfo: m,
+				})
 			})
 
 			It("should return the result of the INSERT INTO and contain 1 row changed", func() {
@@ -135,6 +154,203 @@ var _ = Describe("Identity Service Specs", func() {
 				Expect(identityRow).ToNot(BeNil())
 				Expect(err).ToNot(HaveOccurred())
 			})
+
+			It("should insert, read back and give the row the self role in one transaction", func() {
+				Expect(mockDB.ExpectationsWereMet()).To(Succeed())
+			})
+		})
+
+		Context("when the read back of a created identity fails", func() {
+			BeforeEach(func() {
+				mockDB.ExpectBegin()
+				mockDB.ExpectExec("INSERT INTO identity").WillReturnResult(sqlmock.NewResult(0, 1))
+				mockDB.ExpectQuery("SELECT id, first_name, last_name, profile, created_at, updated_at").
+					WillReturnError(sql.ErrConnDone)
+				mockDB.ExpectRollback()
+				identityService = identity.NewServiceObject(fakeLog, db)
+			})
+
+			It("should roll back the insert", func() {
+				identityRow, _, err := identityService.Create(ctx, identity.Input{FirstName: "adam"})
+				Expect(identityRow).To(BeNil())
+				Expect(err).To(Equal(sql.ErrConnDone))
+				Expect(mockDB.ExpectationsWereMet()).To(Succeed())
+			})
+		})
+
+		Context("when the self role cannot be given to a created identity", func() {
+			BeforeEach(func() {
+				mockRows := sqlmock.NewRows([]string{"id", "first_name", "last_name", "profile",
+					"created_at", "updated_at"}).
+					AddRow("uuidv4", "adam", "cobb", nil, time.Now(), time.Now())
+				mockDB.ExpectBegin()
+				mockDB.ExpectExec("INSERT INTO identity").WillReturnResult(sqlmock.NewResult(0, 1))
+				mockDB.ExpectQuery("SELECT id, first_name, last_name, profile, created_at, updated_at").
+					WillReturnRows(mockRows)
+				mockDB.ExpectQuery("SELECT 1 FROM role").WithArgs(permission.SelfRole).
+					WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(1))
+				mockDB.ExpectExec("INSERT INTO identity_role").
+					WillReturnError(errors.New("connection reset"))
+				mockDB.ExpectRollback()
+				identityService = identity.NewServiceObject(fakeLog, db)
+			})
+
+			It("should roll back the identity insert", func() {
+				identityRow, _, err := identityService.Create(ctx, identity.Input{FirstName: "adam"})
+				Expect(identityRow).To(BeNil())
+				Expect(err).To(MatchError("connection reset"))
+				Expect(mockDB.ExpectationsWereMet()).To(Succeed())
+			})
+		})
+
+		Context("when a user fetches a record that does not exist", func() {
+			BeforeEach(func() {
+				mockDB.ExpectQuery("SELECT id, first_name, last_name, profile, created_at, updated_at").
+					WithArgs("missing").WillReturnError(sql.ErrNoRows)
+				identityService = identity.NewServiceObject(fakeLog, db)
+			})
+
+			It("should return sql.ErrNoRows", func() {
+				identityRow, err := identityService.Fetch(ctx, "missing")
+				Expect(identityRow).To(BeNil())
+				Expect(err).To(Equal(sql.ErrNoRows))
+			})
+		})
+
+		Context("when a user updates an identity", func() {
+			var (
+				identityRow *identity.Row
+				err         error
+			)
+
+			BeforeEach(func() {
+				rightNow := time.Now()
+				mockRows := sqlmock.NewRows([]string{"id", "first_name", "last_name", "profile",
+					"created_at", "updated_at"})
+				mockRows = mockRows.AddRow("uuidv4", "new_first_name", "new_last_name",
+					[]byte(`{"email": "new@gmail.com"}`), rightNow, rightNow)
+				mockDB.ExpectQuery("UPDATE identity").WithArgs("uuidv4", "new_first_name",
+					"new_last_name", []byte(`{"email":"new@gmail.com"}`), sqltest.AnyTime{}).
+					WillReturnRows(mockRows)
+				identityService = identity.NewServiceObject(fakeLog, db)
+			})
+
+			JustBeforeEach(func() {
+				identityRow, err = identityService.Update(ctx, "uuidv4", identity.Input{
+					FirstName:   "new_first_name",
+					LastName:    "new_last_name",
+					ProfileInfo: map[string]string{"email": "new@gmail.com"},
+				})
+			})
+
+			It("should return the updated row", func() {
+				Expect(err).ToNot(HaveOccurred())
+				Expect(mockDB.ExpectationsWereMet()).To(Succeed())
+				Expect(identityRow.FirstName).To(Equal("new_first_name"))
+				Expect(identityRow.LastName).To(Equal("new_last_name"))
+			})
+		})
+
+		Context("when a user sets and verifies a password", func() {
+			BeforeEach(func() {
+				identityService = identity.NewServiceObject(fakeLog, db)
+			})
+
+			It("should store a bcrypt hash rather than the password", func() {
+				mockDB.ExpectExec("UPDATE identity SET password_hash").
+					WithArgs("uuidv4", sqlmock.AnyArg(), sqltest.AnyTime{}).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				Expect(identityService.SetPassword(ctx, "uuidv4", "correct horse")).To(Succeed())
+				Expect(mockDB.ExpectationsWereMet()).To(Succeed())
+			})
+
+			It("should return sql.ErrNoRows when the identity does not exist", func() {
+				mockDB.ExpectExec("UPDATE identity SET password_hash").
+					WithArgs("missing", sqlmock.AnyArg(), sqltest.AnyTime{}).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				Expect(identityService.SetPassword(ctx, "missing", "correct horse")).To(Equal(sql.ErrNoRows))
+			})
+
+			It("should verify a matching password", func() {
+				hash, _ := credential.HashPassword("correct horse")
+				mockDB.ExpectQuery("SELECT password_hash FROM identity").WithArgs("uuidv4").
+					WillReturnRows(sqlmock.NewRows([]string{"password_hash"}).AddRow(hash))
+				Expect(identityService.VerifyPassword(ctx, "uuidv4", "correct horse")).To(BeTrue())
+				Expect(mockDB.ExpectationsWereMet()).To(Succeed())
+			})
+
+			It("should reject an unknown identity without an error", func() {
+				mockDB.ExpectQuery("SELECT password_hash FROM identity").WithArgs("missing").
+					WillReturnError(sql.ErrNoRows)
+				verified, err := identityService.VerifyPassword(ctx, "missing", "correct horse")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(verified).To(BeFalse())
+			})
+
+			It("should reject an identity that has no password", func() {
+				mockDB.ExpectQuery("SELECT password_hash FROM identity").WithArgs("uuidv4").
+					WillReturnRows(sqlmock.NewRows([]string{"password_hash"}).AddRow(nil))
+				Expect(identityService.VerifyPassword(ctx, "uuidv4", "")).To(BeFalse())
+			})
+		})
+
+		Context("when a user looks up and verifies an email", func() {
+			BeforeEach(func() {
+				identityService = identity.NewServiceObject(fakeLog, db)
+			})
+
+			It("should find an identity by its profile email", func() {
+				mockRows := sqlmock.NewRows([]string{"id", "first_name", "last_name", "profile",
+					"created_at", "updated_at"}).AddRow("uuidv4", "first", "last",
+					[]byte(`{"email": "Tony@Example.com"}`), time.Now(), time.Now())
+				mockDB.ExpectQuery("SELECT (.+) FROM identity WHERE lower\\(profile->>'email'\\)").
+					WithArgs("tony@example.com").WillReturnRows(mockRows)
+				row, err := identityService.FindByEmail(ctx, "tony@example.com")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(row.ID).To(Equal("uuidv4"))
+			})
+
+			It("should return sql.ErrNoRows for an unknown email", func() {
+				mockDB.ExpectQuery("SELECT (.+) FROM identity").WithArgs("nobody@example.com").
+					WillReturnError(sql.ErrNoRows)
+				_, err := identityService.FindByEmail(ctx, "nobody@example.com")
+				Expect(err).To(Equal(sql.ErrNoRows))
+			})
+
+			It("should mark the email verified while it is unchanged", func() {
+				mockDB.ExpectExec("UPDATE identity SET email_verified_at").
+					WithArgs("uuidv4", "tony@example.com", sqltest.AnyTime{}).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				Expect(identityService.MarkEmailVerified(ctx, "uuidv4", "tony@example.com")).To(Succeed())
+				Expect(mockDB.ExpectationsWereMet()).To(Succeed())
+			})
+
+			It("should return sql.ErrNoRows once the email has changed", func() {
+				mockDB.ExpectExec("UPDATE identity SET email_verified_at").
+					WithArgs("uuidv4", "old@example.com", sqltest.AnyTime{}).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				Expect(identityService.MarkEmailVerified(ctx, "uuidv4", "old@example.com")).
+					To(Equal(sql.ErrNoRows))
+			})
+		})
+
+		Context("when a user deletes an identity", func() {
+			BeforeEach(func() {
+				identityService = identity.NewServiceObject(fakeLog, db)
+			})
+
+			It("should delete the record by id", func() {
+				mockDB.ExpectExec("DELETE FROM identity").WithArgs("uuidv4").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				Expect(identityService.Delete(ctx, "uuidv4")).To(Succeed())
+				Expect(mockDB.ExpectationsWereMet()).To(Succeed())
+			})
+
+			It("should return sql.ErrNoRows when nothing was deleted", func() {
+				mockDB.ExpectExec("DELETE FROM identity").WithArgs("missing").
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				Expect(identityService.Delete(ctx, "missing")).To(Equal(sql.ErrNoRows))
+			})
 		})
 	})
 })
@@ -20,12 +20,60 @@ type FakeHandlerInterface struct {
 		w   http.ResponseWriter
 		req *http.Request
 	}
+	UpdateIdentityStub        func(w http.ResponseWriter, req *http.Request)
+	updateIdentityMutex       sync.RWMutex
+	updateIdentityArgsForCall []struct {
+		w   http.ResponseWriter
+		req *http.Request
+	}
+	PatchIdentityStub        func(w http.ResponseWriter, req *http.Request)
+	patchIdentityMutex       sync.RWMutex
+	patchIdentityArgsForCall []struct {
+		w   http.ResponseWriter
+		req *http.Request
+	}
+	DeleteIdentityStub        func(w http.ResponseWriter, req *http.Request)
+	deleteIdentityMutex       sync.RWMutex
+	deleteIdentityArgsForCall []struct {
+		w   http.ResponseWriter
+		req *http.Request
+	}
+	ListIdentitiesStub        func(w http.ResponseWriter, req *http.Request)
+	listIdentitiesMutex       sync.RWMutex
+	listIdentitiesArgsForCall []struct {
+		w   http.ResponseWriter
+		req *http.Request
+	}
+	SetIdentityPasswordStub        func(w http.ResponseWriter, req *http.Request)
+	setIdentityPasswordMutex       sync.RWMutex
+	setIdentityPasswordArgsForCall []struct {
+		w   http.ResponseWriter
+		req *http.Request
+	}
 	AuthIdentityStub        func(w http.ResponseWriter, req *http.Request)
 	authIdentityMutex       sync.RWMutex
 	authIdentityArgsForCall []struct {
 		w   http.ResponseWriter
 		req *http.Request
 	}
+	VerifyMFAStub        func(w http.ResponseWriter, req *http.Request)
+	verifyMFAMutex       sync.RWMutex
+	verifyMFAArgsForCall []struct {
+		w   http.ResponseWriter
+		req *http.Request
+	}
+	RefreshIdentityStub        func(w http.ResponseWriter, req *http.Request)
+	refreshIdentityMutex       sync.RWMutex
+	refreshIdentityArgsForCall []struct {
+		w   http.ResponseWriter
+		req *http.Request
+	}
+	LogoutIdentityStub        func(w http.ResponseWriter, req *http.Request)
+	logoutIdentityMutex       sync.RWMutex
+	logoutIdentityArgsForCall []struct {
+		w   http.ResponseWriter
+		req *http.Request
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
@@ -80,6 +128,131 @@ func (fake *FakeHandlerInterface) CreateIdentityArgsForCall(i int) (http.Respons
 	return fake.createIdentityArgsForCall[i].w, fake.createIdentityArgsForCall[i].req
 }
 
+func (fake *FakeHandlerInterface) UpdateIdentity(w http.ResponseWriter, req *http.Request) {
+	fake.updateIdentityMutex.Lock()
+	fake.updateIdentityArgsForCall = append(fake.updateIdentityArgsForCall, struct {
+		w   http.ResponseWriter
+		req *http.Request
+	}{w, req})
+	fake.recordInvocation("UpdateIdentity", []interface{}{w, req})
+	fake.updateIdentityMutex.Unlock()
+	if fake.UpdateIdentityStub != nil {
+		fake.UpdateIdentityStub(w, req)
+	}
+}
+
+func (fake *FakeHandlerInterface) UpdateIdentityCallCount() int {
+	fake.updateIdentityMutex.RLock()
+	defer fake.updateIdentityMutex.RUnlock()
+	return len(fake.updateIdentityArgsForCall)
+}
+
+func (fake *FakeHandlerInterface) UpdateIdentityArgsForCall(i int) (http.ResponseWriter, *http.Request) {
+	fake.updateIdentityMutex.RLock()
+	defer fake.updateIdentityMutex.RUnlock()
+	return fake.updateIdentityArgsForCall[i].w, fake.updateIdentityArgsForCall[i].req
+}
+
+func (fake *FakeHandlerInterface) PatchIdentity(w http.ResponseWriter, req *http.Request) {
+	fake.patchIdentityMutex.Lock()
+	fake.patchIdentityArgsForCall = append(fake.patchIdentityArgsForCall, struct {
+		w   http.ResponseWriter
+		req *http.Request
+	}{w, req})
+	fake.recordInvocation("PatchIdentity", []interface{}{w, req})
+	fake.patchIdentityMutex.Unlock()
+	if fake.PatchIdentityStub != nil {
+		fake.PatchIdentityStub(w, req)
+	}
+}
+
+func (fake *FakeHandlerInterface) PatchIdentityCallCount() int {
+	fake.patchIdentityMutex.RLock()
+	defer fake.patchIdentityMutex.RUnlock()
+	return len(fake.patchIdentityArgsForCall)
+}
+
+func (fake *FakeHandlerInterface) PatchIdentityArgsForCall(i int) (http.ResponseWriter, *http.Request) {
+	fake.patchIdentityMutex.RLock()
+	defer fake.patchIdentityMutex.RUnlock()
+	return fake.patchIdentityArgsForCall[i].w, fake.patchIdentityArgsForCall[i].req
+}
+
+func (fake *FakeHandlerInterface) DeleteIdentity(w http.ResponseWriter, req *http.Request) {
+	fake.deleteIdentityMutex.Lock()
+	fake.deleteIdentityArgsForCall = append(fake.deleteIdentityArgsForCall, struct {
+		w   http.ResponseWriter
+		req *http.Request
+	}{w, req})
+	fake.recordInvocation("DeleteIdentity", []interface{}{w, req})
+	fake.deleteIdentityMutex.Unlock()
+	if fake.DeleteIdentityStub != nil {
+		fake.DeleteIdentityStub(w, req)
+	}
+}
+
+func (fake *FakeHandlerInterface) DeleteIdentityCallCount() int {
+	fake.deleteIdentityMutex.RLock()
+	defer fake.deleteIdentityMutex.RUnlock()
+	return len(fake.deleteIdentityArgsForCall)
+}
+
+func (fake *FakeHandlerInterface) DeleteIdentityArgsForCall(i int) (http.ResponseWriter, *http.Request) {
+	fake.deleteIdentityMutex.RLock()
+	defer fake.deleteIdentityMutex.RUnlock()
+	return fake.deleteIdentityArgsForCall[i].w, fake.deleteIdentityArgsForCall[i].req
+}
+
+func (fake *FakeHandlerInterface) ListIdentities(w http.ResponseWriter, req *http.Request) {
+	fake.listIdentitiesMutex.Lock()
+	fake.listIdentitiesArgsForCall = append(fake.listIdentitiesArgsForCall, struct {
+		w   http.ResponseWriter
+		req *http.Request
+	}{w, req})
+	fake.recordInvocation("ListIdentities", []interface{}{w, req})
+	fake.listIdentitiesMutex.Unlock()
+	if fake.ListIdentitiesStub != nil {
+		fake.ListIdentitiesStub(w, req)
+	}
+}
+
+func (fake *FakeHandlerInterface) ListIdentitiesCallCount() int {
+	fake.listIdentitiesMutex.RLock()
+	defer fake.listIdentitiesMutex.RUnlock()
+	return len(fake.listIdentitiesArgsForCall)
+}
+
+func (fake *FakeHandlerInterface) ListIdentitiesArgsForCall(i int) (http.ResponseWriter, *http.Request) {
+	fake.listIdentitiesMutex.RLock()
+	defer fake.listIdentitiesMutex.RUnlock()
+	return fake.listIdentitiesArgsForCall[i].w, fake.listIdentitiesArgsForCall[i].req
+}
+
+func (fake *FakeHandlerInterface) SetIdentityPassword(w http.ResponseWriter, req *http.Request) {
+	fake.setIdentityPasswordMutex.Lock()
+	fake.setIdentityPasswordArgsForCall = append(fake.setIdentityPasswordArgsForCall, struct {
+		w   http.ResponseWriter
+		req *http.Request
+	}{w, req})
+	fake.recordInvocation("SetIdentityPassword", []interface{}{w, req})
+	fake.setIdentityPasswordMutex.Unlock()
+	if fake.SetIdentityPasswordStub != nil {
+		fake.SetIdentityPasswordStub(w, req)
+	}
+}
+
+func (fake *FakeHandlerInterface) SetIdentityPasswordCallCount() int {
+	fake.setIdentityPasswordMutex.RLock()
+	defer fake.setIdentityPasswordMutex.RUnlock()
+	return len(fake.setIdentityPasswordArgsForCall)
+}
+
+func (fake *FakeHandlerInterface) SetIdentityPasswordArgsForCall(i int) (http.ResponseWriter, *http.Request) {
+	fake.setIdentityPasswordMutex.RLock()
+	defer fake.setIdentityPasswordMutex.RUnlock()
+	return fake.setIdentityPasswordArgsForCall[i].w, fake.setIdentityPasswordArgsForCall[i].req
+}
+
 func (fake *FakeHandlerInterface) AuthIdentity(w http.ResponseWriter, req *http.Request) {
 	fake.authIdentityMutex.Lock()
 	fake.authIdentityArgsForCall = append(fake.authIdentityArgsForCall, struct {
@@ -105,6 +278,81 @@ func (fake *FakeHandlerInterface) AuthIdentityArgsForCall(i int) (http.ResponseW
 	return fake.authIdentityArgsForCall[i].w, fake.authIdentityArgsForCall[i].req
 }
 
+func (fake *FakeHandlerInterface) VerifyMFA(w http.ResponseWriter, req *http.Request) {
+	fake.verifyMFAMutex.Lock()
+	fake.verifyMFAArgsForCall = append(fake.verifyMFAArgsForCall, struct {
+		w   http.ResponseWriter
+		req *http.Request
+	}{w, req})
+	fake.recordInvocation("VerifyMFA", []interface{}{w, req})
+	fake.verifyMFAMutex.Unlock()
+	if fake.VerifyMFAStub != nil {
+		fake.VerifyMFAStub(w, req)
+	}
+}
+
+func (fake *FakeHandlerInterface) VerifyMFACallCount() int {
+	fake.verifyMFAMutex.RLock()
+	defer fake.verifyMFAMutex.RUnlock()
+	return len(fake.verifyMFAArgsForCall)
+}
+
+func (fake *FakeHandlerInterface) VerifyMFAArgsForCall(i int) (http.ResponseWriter, *http.Request) {
+	fake.verifyMFAMutex.RLock()
+	defer fake.verifyMFAMutex.RUnlock()
+	return fake.verifyMFAArgsForCall[i].w, fake.verifyMFAArgsForCall[i].req
+}
+
+func (fake *FakeHandlerInterface) RefreshIdentity(w http.ResponseWriter, req *http.Request) {
+	fake.refreshIdentityMutex.Lock()
+	fake.refreshIdentityArgsForCall = append(fake.refreshIdentityArgsForCall, struct {
+		w   http.ResponseWriter
+		req *http.Request
+	}{w, req})
+	fake.recordInvocation("RefreshIdentity", []interface{}{w, req})
+	fake.refreshIdentityMutex.Unlock()
+	if fake.RefreshIdentityStub != nil {
+		fake.RefreshIdentityStub(w, req)
+	}
+}
+
+func (fake *FakeHandlerInterface) RefreshIdentityCallCount() int {
+	fake.refreshIdentityMutex.RLock()
+	defer fake.refreshIdentityMutex.RUnlock()
+	return len(fake.refreshIdentityArgsForCall)
+}
+
+func (fake *FakeHandlerInterface) RefreshIdentityArgsForCall(i int) (http.ResponseWriter, *http.Request) {
+	fake.refreshIdentityMutex.RLock()
+	defer fake.refreshIdentityMutex.RUnlock()
+	return fake.refreshIdentityArgsForCall[i].w, fake.refreshIdentityArgsForCall[i].req
+}
+
+func (fake *FakeHandlerInterface) LogoutIdentity(w http.ResponseWriter, req *http.Request) {
+	fake.logoutIdentityMutex.Lock()
+	fake.logoutIdentityArgsForCall = append(fake.logoutIdentityArgsForCall, struct {
+		w   http.ResponseWriter
+		req *http.Request
+	}{w, req})
+	fake.recordInvocation("LogoutIdentity", []interface{}{w, req})
+	fake.logoutIdentityMutex.Unlock()
+	if fake.LogoutIdentityStub != nil {
+		fake.LogoutIdentityStub(w, req)
+	}
+}
+
+func (fake *FakeHandlerInterface) LogoutIdentityCallCount() int {
+	fake.logoutIdentityMutex.RLock()
+	defer fake.logoutIdentityMutex.RUnlock()
+	return len(fake.logoutIdentityArgsForCall)
+}
+
+func (fake *FakeHandlerInterface) LogoutIdentityArgsForCall(i int) (http.ResponseWriter, *http.Request) {
+	fake.logoutIdentityMutex.RLock()
+	defer fake.logoutIdentityMutex.RUnlock()
+	return fake.logoutIdentityArgsForCall[i].w, fake.logoutIdentityArgsForCall[i].req
+}
+
 func (fake *FakeHandlerInterface) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
@@ -112,8 +360,24 @@ func (fake *FakeHandlerInterface) Invocations() map[string][][]interface{} {
 	defer fake.handlerMutex.RUnlock()
 	fake.createIdentityMutex.RLock()
 	defer fake.createIdentityMutex.RUnlock()
+	fake.updateIdentityMutex.RLock()
+	defer fake.updateIdentityMutex.RUnlock()
+	fake.patchIdentityMutex.RLock()
+	defer fake.patchIdentityMutex.RUnlock()
+	fake.deleteIdentityMutex.RLock()
+	defer fake.deleteIdentityMutex.RUnlock()
+	fake.listIdentitiesMutex.RLock()
+	defer fake.listIdentitiesMutex.RUnlock()
+	fake.setIdentityPasswordMutex.RLock()
+	defer fake.setIdentityPasswordMutex.RUnlock()
 	fake.authIdentityMutex.RLock()
 	defer fake.authIdentityMutex.RUnlock()
+	fake.verifyMFAMutex.RLock()
+	defer fake.verifyMFAMutex.RUnlock()
+	fake.refreshIdentityMutex.RLock()
+	defer fake.refreshIdentityMutex.RUnlock()
+	fake.logoutIdentityMutex.RLock()
+	defer fake.logoutIdentityMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
 	for key, value := range fake.invocations {
 		copiedInvocations[key] = value
@@ -2,16 +2,18 @@
 package identityfakes
 
 import (
+	"context"
 	"database/sql"
 	"service/identity"
 	"sync"
 )
 
 type FakeServiceInterface struct {
-	FetchStub        func(id string) (*identity.Row, error)
+	FetchStub        func(ctx context.Context, id string) (*identity.Row, error)
 	fetchMutex       sync.RWMutex
 	fetchArgsForCall []struct {
-		id string
+		ctx context.Context
+		id  string
 	}
 	fetchReturns struct {
 		result1 *identity.Row
@@ -21,10 +23,11 @@ type FakeServiceInterface struct {
 		result1 *identity.Row
 		result2 error
 	}
-	CreateStub        func(id string) (*identity.Row, sql.Result, error)
+	CreateStub        func(ctx context.Context, input identity.Input) (*identity.Row, sql.Result, error)
 	createMutex       sync.RWMutex
 	createArgsForCall []struct {
-		id string
+		ctx   context.Context
+		input identity.Input
 	}
 	createReturns struct {
 		result1 *identity.Row
@@ -36,20 +39,117 @@ type FakeServiceInterface struct {
 		result2 sql.Result
 		result3 error
 	}
+	UpdateStub        func(ctx context.Context, id string, input identity.Input) (*identity.Row, error)
+	updateMutex       sync.RWMutex
+	updateArgsForCall []struct {
+		ctx   context.Context
+		id    string
+		input identity.Input
+	}
+	updateReturns struct {
+		result1 *identity.Row
+		result2 error
+	}
+	updateReturnsOnCall map[int]struct {
+		result1 *identity.Row
+		result2 error
+	}
+	DeleteStub        func(ctx context.Context, id string) error
+	deleteMutex       sync.RWMutex
+	deleteArgsForCall []struct {
+		ctx context.Context
+		id  string
+	}
+	deleteReturns struct {
+		result1 error
+	}
+	deleteReturnsOnCall map[int]struct {
+		result1 error
+	}
+	ListStub        func(ctx context.Context, query identity.ListQuery) (*identity.Page, error)
+	listMutex       sync.RWMutex
+	listArgsForCall []struct {
+		ctx   context.Context
+		query identity.ListQuery
+	}
+	listReturns struct {
+		result1 *identity.Page
+		result2 error
+	}
+	listReturnsOnCall map[int]struct {
+		result1 *identity.Page
+		result2 error
+	}
+	SetPasswordStub        func(ctx context.Context, id string, password string) error
+	setPasswordMutex       sync.RWMutex
+	setPasswordArgsForCall []struct {
+		ctx      context.Context
+		id       string
+		password string
+	}
+	setPasswordReturns struct {
+		result1 error
+	}
+	setPasswordReturnsOnCall map[int]struct {
+		result1 error
+	}
+	VerifyPasswordStub        func(ctx context.Context, id string, password string) (bool, error)
+	verifyPasswordMutex       sync.RWMutex
+	verifyPasswordArgsForCall []struct {
+		ctx      context.Context
+		id       string
+		password string
+	}
+	verifyPasswordReturns struct {
+		result1 bool
+		result2 error
+	}
+	verifyPasswordReturnsOnCall map[int]struct {
+		result1 bool
+		result2 error
+	}
+	FindByEmailStub        func(ctx context.Context, email string) (*identity.Row, error)
+	findByEmailMutex       sync.RWMutex
+	findByEmailArgsForCall []struct {
+		ctx   context.Context
+		email string
+	}
+	findByEmailReturns struct {
+		result1 *identity.Row
+		result2 error
+	}
+	findByEmailReturnsOnCall map[int]struct {
+		result1 *identity.Row
+		result2 error
+	}
+	MarkEmailVerifiedStub        func(ctx context.Context, id string, email string) error
+	markEmailVerifiedMutex       sync.RWMutex
+	markEmailVerifiedArgsForCall []struct {
+		ctx   context.Context
+		id    string
+		email string
+	}
+	markEmailVerifiedReturns struct {
+		result1 error
+	}
+	markEmailVerifiedReturnsOnCall map[int]struct {
+		result1 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
 
-func (fake *FakeServiceInterface) Fetch(id string) (*identity.Row, error) {
+func (fake *FakeServiceInterface) Fetch(ctx context.Context, id string) (*identity.Row, error) {
 	fake.fetchMutex.Lock()
 	ret, specificReturn := fake.fetchReturnsOnCall[len(fake.fetchArgsForCall)]
 	fake.fetchArgsForCall = append(fake.fetchArgsForCall, struct {
-		id string
-	}{id})
-	fake.recordInvocation("Fetch", []interface{}{id})
+		ctx context.Context
+		id  string
+	}{ctx, id})
+	fake.recordInvocation("Fetch", []interface{}{ctx, id})
 	fake.fetchMutex.Unlock()
 	if fake.FetchStub != nil {
-		return fake.FetchStub(id)
+		return fake.FetchStub(ctx, id)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -63,10 +163,10 @@ func (fake *FakeServiceInterface) FetchCallCount() int {
 	return len(fake.fetchArgsForCall)
 }
 
-func (fake *FakeServiceInterface) FetchArgsForCall(i int) string {
+func (fake *FakeServiceInterface) FetchArgsForCall(i int) (context.Context, string) {
 	fake.fetchMutex.RLock()
 	defer fake.fetchMutex.RUnlock()
-	return fake.fetchArgsForCall[i].id
+	return fake.fetchArgsForCall[i].ctx, fake.fetchArgsForCall[i].id
 }
 
 func (fake *FakeServiceInterface) FetchReturns(result1 *identity.Row, result2 error) {
@@ -91,16 +191,17 @@ func (fake *FakeServiceInterface) FetchReturnsOnCall(i int, result1 *identity.Ro
 	}{result1, result2}
 }
 
-func (fake *FakeServiceInterface) Create(id string) (*identity.Row, sql.Result, error) {
+func (fake *FakeServiceInterface) Create(ctx context.Context, input identity.Input) (*identity.Row, sql.Result, error) {
 	fake.createMutex.Lock()
 	ret, specificReturn := fake.createReturnsOnCall[len(fake.createArgsForCall)]
 	fake.createArgsForCall = append(fake.createArgsForCall, struct {
-		id string
-	}{id})
-	fake.recordInvocation("Create", []interface{}{id})
+		ctx   context.Context
+		input identity.Input
+	}{ctx, input})
+	fake.recordInvocation("Create", []interface{}{ctx, input})
 	fake.createMutex.Unlock()
 	if fake.CreateStub != nil {
-		return fake.CreateStub(id)
+		return fake.CreateStub(ctx, input)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2, ret.result3
@@ -114,10 +215,10 @@ func (fake *FakeServiceInterface) CreateCallCount() int {
 	return len(fake.createArgsForCall)
 }
 
-func (fake *FakeServiceInterface) CreateArgsForCall(i int) string {
+func (fake *FakeServiceInterface) CreateArgsForCall(i int) (context.Context, identity.Input) {
 	fake.createMutex.RLock()
 	defer fake.createMutex.RUnlock()
-	return fake.createArgsForCall[i].id
+	return fake.createArgsForCall[i].ctx, fake.createArgsForCall[i].input
 }
 
 func (fake *FakeServiceInterface) CreateReturns(result1 *identity.Row, result2 sql.Result, result3 error) {
@@ -145,6 +246,365 @@ func (fake *FakeServiceInterface) CreateReturnsOnCall(i int, result1 *identity.R
 	}{result1, result2, result3}
 }
 
+func (fake *FakeServiceInterface) Update(ctx context.Context, id string, input identity.Input) (*identity.Row, error) {
+	fake.updateMutex.Lock()
+	ret, specificReturn := fake.updateReturnsOnCall[len(fake.updateArgsForCall)]
+	fake.updateArgsForCall = append(fake.updateArgsForCall, struct {
+		ctx   context.Context
+		id    string
+		input identity.Input
+	}{ctx, id, input})
+	fake.recordInvocation("Update", []interface{}{ctx, id, input})
+	fake.updateMutex.Unlock()
+	if fake.UpdateStub != nil {
+		return fake.UpdateStub(ctx, id, input)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.updateReturns.result1, fake.updateReturns.result2
+}
+
+func (fake *FakeServiceInterface) UpdateCallCount() int {
+	fake.updateMutex.RLock()
+	defer fake.updateMutex.RUnlock()
+	return len(fake.updateArgsForCall)
+}
+
+func (fake *FakeServiceInterface) UpdateArgsForCall(i int) (context.Context, string, identity.Input) {
+	fake.updateMutex.RLock()
+	defer fake.updateMutex.RUnlock()
+	return fake.updateArgsForCall[i].ctx, fake.updateArgsForCall[i].id, fake.updateArgsForCall[i].input
+}
+
+func (fake *FakeServiceInterface) UpdateReturns(result1 *identity.Row, result2 error) {
+	fake.UpdateStub = nil
+	fake.updateReturns = struct {
+		result1 *identity.Row
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeServiceInterface) UpdateReturnsOnCall(i int, result1 *identity.Row, result2 error) {
+	fake.UpdateStub = nil
+	if fake.updateReturnsOnCall == nil {
+		fake.updateReturnsOnCall = make(map[int]struct {
+			result1 *identity.Row
+			result2 error
+		})
+	}
+	fake.updateReturnsOnCall[i] = struct {
+		result1 *identity.Row
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeServiceInterface) Delete(ctx context.Context, id string) error {
+	fake.deleteMutex.Lock()
+	ret, specificReturn := fake.deleteReturnsOnCall[len(fake.deleteArgsForCall)]
+	fake.deleteArgsForCall = append(fake.deleteArgsForCall, struct {
+		ctx context.Context
+		id  string
+	}{ctx, id})
+	fake.recordInvocation("Delete", []interface{}{ctx, id})
+	fake.deleteMutex.Unlock()
+	if fake.DeleteStub != nil {
+		return fake.DeleteStub(ctx, id)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fake.deleteReturns.result1
+}
+
+func (fake *FakeServiceInterface) DeleteCallCount() int {
+	fake.deleteMutex.RLock()
+	defer fake.deleteMutex.RUnlock()
+	return len(fake.deleteArgsForCall)
+}
+
+func (fake *FakeServiceInterface) DeleteArgsForCall(i int) (context.Context, string) {
+	fake.deleteMutex.RLock()
+	defer fake.deleteMutex.RUnlock()
+	return fake.deleteArgsForCall[i].ctx, fake.deleteArgsForCall[i].id
+}
+
+func (fake *FakeServiceInterface) DeleteReturns(result1 error) {
+	fake.DeleteStub = nil
+	fake.deleteReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeServiceInterface) DeleteReturnsOnCall(i int, result1 error) {
+	fake.DeleteStub = nil
+	if fake.deleteReturnsOnCall == nil {
+		fake.deleteReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.deleteReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeServiceInterface) List(ctx context.Context, query identity.ListQuery) (*identity.Page, error) {
+	fake.listMutex.Lock()
+	ret, specificReturn := fake.listReturnsOnCall[len(fake.listArgsForCall)]
+	fake.listArgsForCall = append(fake.listArgsForCall, struct {
+		ctx   context.Context
+		query identity.ListQuery
+	}{ctx, query})
+	fake.recordInvocation("List", []interface{}{ctx, query})
+	fake.listMutex.Unlock()
+	if fake.ListStub != nil {
+		return fake.ListStub(ctx, query)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.listReturns.result1, fake.listReturns.result2
+}
+
+func (fake *FakeServiceInterface) ListCallCount() int {
+	fake.listMutex.RLock()
+	defer fake.listMutex.RUnlock()
+	return len(fake.listArgsForCall)
+}
+
+func (fake *FakeServiceInterface) ListArgsForCall(i int) (context.Context, identity.ListQuery) {
+	fake.listMutex.RLock()
+	defer fake.listMutex.RUnlock()
+	return fake.listArgsForCall[i].ctx, fake.listArgsForCall[i].query
+}
+
+func (fake *FakeServiceInterface) ListReturns(result1 *identity.Page, result2 error) {
+	fake.ListStub = nil
+	fake.listReturns = struct {
+		result1 *identity.Page
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeServiceInterface) ListReturnsOnCall(i int, result1 *identity.Page, result2 error) {
+	fake.ListStub = nil
+	if fake.listReturnsOnCall == nil {
+		fake.listReturnsOnCall = make(map[int]struct {
+			result1 *identity.Page
+			result2 error
+		})
+	}
+	fake.listReturnsOnCall[i] = struct {
+		result1 *identity.Page
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeServiceInterface) SetPassword(ctx context.Context, id string, password string) error {
+	fake.setPasswordMutex.Lock()
+	ret, specificReturn := fake.setPasswordReturnsOnCall[len(fake.setPasswordArgsForCall)]
+	fake.setPasswordArgsForCall = append(fake.setPasswordArgsForCall, struct {
+		ctx      context.Context
+		id       string
+		password string
+	}{ctx, id, password})
+	fake.recordInvocation("SetPassword", []interface{}{ctx, id, password})
+	fake.setPasswordMutex.Unlock()
+	if fake.SetPasswordStub != nil {
+		return fake.SetPasswordStub(ctx, id, password)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fake.setPasswordReturns.result1
+}
+
+func (fake *FakeServiceInterface) SetPasswordCallCount() int {
+	fake.setPasswordMutex.RLock()
+	defer fake.setPasswordMutex.RUnlock()
+	return len(fake.setPasswordArgsForCall)
+}
+
+func (fake *FakeServiceInterface) SetPasswordArgsForCall(i int) (context.Context, string, string) {
+	fake.setPasswordMutex.RLock()
+	defer fake.setPasswordMutex.RUnlock()
+	return fake.setPasswordArgsForCall[i].ctx, fake.setPasswordArgsForCall[i].id, fake.setPasswordArgsForCall[i].password
+}
+
+func (fake *FakeServiceInterface) SetPasswordReturns(result1 error) {
+	fake.SetPasswordStub = nil
+	fake.setPasswordReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeServiceInterface) SetPasswordReturnsOnCall(i int, result1 error) {
+	fake.SetPasswordStub = nil
+	if fake.setPasswordReturnsOnCall == nil {
+		fake.setPasswordReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.setPasswordReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeServiceInterface) VerifyPassword(ctx context.Context, id string, password string) (bool, error) {
+	fake.verifyPasswordMutex.Lock()
+	ret, specificReturn := fake.verifyPasswordReturnsOnCall[len(fake.verifyPasswordArgsForCall)]
+	fake.verifyPasswordArgsForCall = append(fake.verifyPasswordArgsForCall, struct {
+		ctx      context.Context
+		id       string
+		password string
+	}{ctx, id, password})
+	fake.recordInvocation("VerifyPassword", []interface{}{ctx, id, password})
+	fake.verifyPasswordMutex.Unlock()
+	if fake.VerifyPasswordStub != nil {
+		return fake.VerifyPasswordStub(ctx, id, password)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.verifyPasswordReturns.result1, fake.verifyPasswordReturns.result2
+}
+
+func (fake *FakeServiceInterface) VerifyPasswordCallCount() int {
+	fake.verifyPasswordMutex.RLock()
+	defer fake.verifyPasswordMutex.RUnlock()
+	return len(fake.verifyPasswordArgsForCall)
+}
+
+func (fake *FakeServiceInterface) VerifyPasswordArgsForCall(i int) (context.Context, string, string) {
+	fake.verifyPasswordMutex.RLock()
+	defer fake.verifyPasswordMutex.RUnlock()
+	return fake.verifyPasswordArgsForCall[i].ctx, fake.verifyPasswordArgsForCall[i].id, fake.verifyPasswordArgsForCall[i].password
+}
+
+func (fake *FakeServiceInterface) VerifyPasswordReturns(result1 bool, result2 error) {
+	fake.VerifyPasswordStub = nil
+	fake.verifyPasswordReturns = struct {
+		result1 bool
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeServiceInterface) VerifyPasswordReturnsOnCall(i int, result1 bool, result2 error) {
+	fake.VerifyPasswordStub = nil
+	if fake.verifyPasswordReturnsOnCall == nil {
+		fake.verifyPasswordReturnsOnCall = make(map[int]struct {
+			result1 bool
+			result2 error
+		})
+	}
+	fake.verifyPasswordReturnsOnCall[i] = struct {
+		result1 bool
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeServiceInterface) FindByEmail(ctx context.Context, email string) (*identity.Row, error) {
+	fake.findByEmailMutex.Lock()
+	ret, specificReturn := fake.findByEmailReturnsOnCall[len(fake.findByEmailArgsForCall)]
+	fake.findByEmailArgsForCall = append(fake.findByEmailArgsForCall, struct {
+		ctx   context.Context
+		email string
+	}{ctx, email})
+	fake.recordInvocation("FindByEmail", []interface{}{ctx, email})
+	fake.findByEmailMutex.Unlock()
+	if fake.FindByEmailStub != nil {
+		return fake.FindByEmailStub(ctx, email)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.findByEmailReturns.result1, fake.findByEmailReturns.result2
+}
+
+func (fake *FakeServiceInterface) FindByEmailCallCount() int {
+	fake.findByEmailMutex.RLock()
+	defer fake.findByEmailMutex.RUnlock()
+	return len(fake.findByEmailArgsForCall)
+}
+
+func (fake *FakeServiceInterface) FindByEmailArgsForCall(i int) (context.Context, string) {
+	fake.findByEmailMutex.RLock()
+	defer fake.findByEmailMutex.RUnlock()
+	return fake.findByEmailArgsForCall[i].ctx, fake.findByEmailArgsForCall[i].email
+}
+
+func (fake *FakeServiceInterface) FindByEmailReturns(result1 *identity.Row, result2 error) {
+	fake.FindByEmailStub = nil
+	fake.findByEmailReturns = struct {
+		result1 *identity.Row
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeServiceInterface) FindByEmailReturnsOnCall(i int, result1 *identity.Row, result2 error) {
+	fake.FindByEmailStub = nil
+	if fake.findByEmailReturnsOnCall == nil {
+		fake.findByEmailReturnsOnCall = make(map[int]struct {
+			result1 *identity.Row
+			result2 error
+		})
+	}
+	fake.findByEmailReturnsOnCall[i] = struct {
+		result1 *identity.Row
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeServiceInterface) MarkEmailVerified(ctx context.Context, id string, email string) error {
+	fake.markEmailVerifiedMutex.Lock()
+	ret, specificReturn := fake.markEmailVerifiedReturnsOnCall[len(fake.markEmailVerifiedArgsForCall)]
+	fake.markEmailVerifiedArgsForCall = append(fake.markEmailVerifiedArgsForCall, struct {
+		ctx   context.Context
+		id    string
+		email string
+	}{ctx, id, email})
+	fake.recordInvocation("MarkEmailVerified", []interface{}{ctx, id, email})
+	fake.markEmailVerifiedMutex.Unlock()
+	if fake.MarkEmailVerifiedStub != nil {
+		return fake.MarkEmailVerifiedStub(ctx, id, email)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fake.markEmailVerifiedReturns.result1
+}
+
+func (fake *FakeServiceInterface) MarkEmailVerifiedCallCount() int {
+	fake.markEmailVerifiedMutex.RLock()
+	defer fake.markEmailVerifiedMutex.RUnlock()
+	return len(fake.markEmailVerifiedArgsForCall)
+}
+
+func (fake *FakeServiceInterface) MarkEmailVerifiedArgsForCall(i int) (context.Context, string, string) {
+	fake.markEmailVerifiedMutex.RLock()
+	defer fake.markEmailVerifiedMutex.RUnlock()
+	return fake.markEmailVerifiedArgsForCall[i].ctx, fake.markEmailVerifiedArgsForCall[i].id, fake.markEmailVerifiedArgsForCall[i].email
+}
+
+func (fake *FakeServiceInterface) MarkEmailVerifiedReturns(result1 error) {
+	fake.MarkEmailVerifiedStub = nil
+	fake.markEmailVerifiedReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeServiceInterface) MarkEmailVerifiedReturnsOnCall(i int, result1 error) {
+	fake.MarkEmailVerifiedStub = nil
+	if fake.markEmailVerifiedReturnsOnCall == nil {
+		fake.markEmailVerifiedReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.markEmailVerifiedReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeServiceInterface) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
@@ -152,6 +612,20 @@ func (fake *FakeServiceInterface) Invocations() map[string][][]interface{} {
 	defer fake.fetchMutex.RUnlock()
 	fake.createMutex.RLock()
 	defer fake.createMutex.RUnlock()
+	fake.updateMutex.RLock()
+	defer fake.updateMutex.RUnlock()
+	fake.deleteMutex.RLock()
+	defer fake.deleteMutex.RUnlock()
+	fake.listMutex.RLock()
+	defer fake.listMutex.RUnlock()
+	fake.setPasswordMutex.RLock()
+	defer fake.setPasswordMutex.RUnlock()
+	fake.verifyPasswordMutex.RLock()
+	defer fake.verifyPasswordMutex.RUnlock()
+	fake.findByEmailMutex.RLock()
+	defer fake.findByEmailMutex.RUnlock()
+	fake.markEmailVerifiedMutex.RLock()
+	defer fake.markEmailVerifiedMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
 	for key, value := range fake.invocations {
 		copiedInvocations[key] = value
@@ -0,0 +1,49 @@
+package mail
+
+import (
+	"bytes"
+	"fmt"
+	"mime"
+	"net/mail"
+	"strings"
+	"time"
+)
+
+//Message ... is a plain text email.
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+//Mailer ... defines sending a Message.
+//go:generate counterfeiter . Mailer
+type Mailer interface {
+	Send(msg Message) error
+}
+
+//Format ... renders the message as an RFC 5322 email from the given sender.
+func (m *Message) Format(from string, at time.Time) []byte {
+	var buffer bytes.Buffer
+	fmt.Fprintf(&buffer, "From: %s\r\n", from)
+	fmt.Fprintf(&buffer, "To: %s\r\n", m.To)
+	fmt.Fprintf(&buffer, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", m.Subject))
+	fmt.Fprintf(&buffer, "Date: %s\r\n", at.Format(time.RFC1123Z))
+	buffer.WriteString("MIME-Version: 1.0\r\n")
+	buffer.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	buffer.WriteString("\r\n")
+	body := strings.Replace(m.Body, "\r\n", "\n", -1)
+	buffer.WriteString(strings.Replace(body, "\n", "\r\n", -1))
+	return buffer.Bytes()
+}
+
+//validate rejects recipients that could inject extra headers or aren't addresses at all.
+func (m *Message) validate() error {
+	if strings.ContainsAny(m.To+m.Subject, "\r\n") {
+		return fmt.Errorf("mail: header contains a line break")
+	}
+	if _, err := mail.ParseAddress(m.To); err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,151 @@
+package mail_test
+
+import (
+	"bufio"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"service/log/logfakes"
+	"service/mail"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Mail Specs", func() {
+	msg := mail.Message{
+		To:      "tony@example.com",
+		Subject: "Reset your password",
+		Body:    "line one\nline two\n",
+	}
+
+	It("should format a plain text email with CRLF line endings", func() {
+		at := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+		formatted := string(msg.Format("no-reply@example.com", at))
+		Expect(formatted).To(HavePrefix("From: no-reply@example.com\r\nTo: tony@example.com\r\n"))
+		Expect(formatted).To(ContainSubstring("Subject: Reset your password\r\n"))
+		Expect(formatted).To(ContainSubstring("Date: Thu, 02 Jan 2020 03:04:05 +0000\r\n"))
+		Expect(formatted).To(HaveSuffix("\r\n\r\nline one\r\nline two\r\n"))
+	})
+
+	Context("SinkMailer", func() {
+		var (
+			fakeLog *logfakes.FakeProdInterface
+			dir     string
+		)
+
+		BeforeEach(func() {
+			fakeLog = &logfakes.FakeProdInterface{}
+			var err error
+			dir, err = ioutil.TempDir("", "mail")
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		AfterEach(func() {
+			os.RemoveAll(dir)
+		})
+
+		It("should log and append every message to the sink file", func() {
+			path := filepath.Join(dir, "mail.log")
+			sink := mail.NewSinkMailer(fakeLog, path)
+			Expect(sink.Send(msg)).To(Succeed())
+			Expect(sink.Send(msg)).To(Succeed())
+			raw, err := ioutil.ReadFile(path)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(strings.Count(string(raw), "To: tony@example.com")).To(Equal(2))
+			Expect(fakeLog.InfoCallCount()).To(Equal(2))
+		})
+
+		It("should only log without a path", func() {
+			Expect(mail.NewSinkMailer(fakeLog, "").Send(msg)).To(Succeed())
+			Expect(fakeLog.InfoCallCount()).To(Equal(1))
+		})
+
+		It("should refuse recipients that would inject headers", func() {
+			bad := msg
+			bad.To = "tony@example.com\r\nBcc: everyone@example.com"
+			Expect(mail.NewSinkMailer(fakeLog, "").Send(bad)).ToNot(Succeed())
+			Expect(fakeLog.InfoCallCount()).To(Equal(0))
+		})
+	})
+
+	Context("SMTPMailer", func() {
+		var (
+			listener net.Listener
+			received chan string
+		)
+
+		//serveOnce plays a minimal SMTP relay for a single message.
+		serveOnce := func() {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+			reader := bufio.NewReader(conn)
+			reply := func(line string) { conn.Write([]byte(line + "\r\n")) }
+			reply("220 localhost ready")
+			var data []string
+			inData := false
+			for {
+				line, err := reader.ReadString('\n')
+				if err != nil {
+					return
+				}
+				line = strings.TrimRight(line, "\r\n")
+				if inData {
+					if line == "." {
+						inData = false
+						received <- strings.Join(data, "\n")
+						reply("250 queued")
+						continue
+					}
+					data = append(data, line)
+					continue
+				}
+				switch {
+				case strings.HasPrefix(line, "EHLO"):
+					reply("250 localhost")
+				case line == "DATA":
+					inData = true
+					reply("354 go ahead")
+				case line == "QUIT":
+					reply("221 bye")
+					return
+				default:
+					reply("250 ok")
+				}
+			}
+		}
+
+		BeforeEach(func() {
+			var err error
+			listener, err = net.Listen("tcp", "127.0.0.1:0")
+			Expect(err).ToNot(HaveOccurred())
+			received = make(chan string, 1)
+			go serveOnce()
+		})
+
+		AfterEach(func() {
+			listener.Close()
+		})
+
+		It("should deliver the message to the relay", func() {
+			mailer, err := mail.NewSMTPMailer(listener.Addr().String(), "no-reply@example.com", "", "")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(mailer.Send(msg)).To(Succeed())
+			var data string
+			Eventually(received).Should(Receive(&data))
+			Expect(data).To(ContainSubstring("To: tony@example.com"))
+			Expect(data).To(ContainSubstring("line two"))
+		})
+
+		It("should reject an address without a port", func() {
+			_, err := mail.NewSMTPMailer("localhost", "no-reply@example.com", "", "")
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
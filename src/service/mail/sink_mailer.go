@@ -0,0 +1,55 @@
+package mail
+
+import (
+	"os"
+	"service/log"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+//SinkFrom ... is the sender written on sunk messages.
+const SinkFrom = "no-reply@localhost"
+
+//SinkMailer ...
+//never delivers anything. It logs each message and, when given a path, appends the
+//formatted message to that file so local development and tests can read the links.
+type SinkMailer struct {
+	mutex sync.Mutex
+	log   log.ProdInterface
+	path  string
+}
+
+//NewSinkMailer ... returns a pointer to a new SinkMailer, an empty path only logs.
+func NewSinkMailer(logClient log.ProdInterface, path string) *SinkMailer {
+	return &SinkMailer{
+		log:  logClient,
+		path: path,
+	}
+}
+
+//Send ... logs msg and appends it to the sink file.
+func (s *SinkMailer) Send(msg Message) error {
+	if err := msg.validate(); err != nil {
+		return err
+	}
+	s.log.Info("mail sink",
+		zap.String("to", msg.To),
+		zap.String("subject", msg.Subject))
+	if s.path == "" {
+		s.log.Debug("mail sink body", zap.String("body", msg.Body))
+		return nil
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	file, err := os.OpenFile(s.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err = file.Write(append(msg.Format(SinkFrom, time.Now()), "\r\n\r\n"...)); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
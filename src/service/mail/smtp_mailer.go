@@ -0,0 +1,41 @@
+package mail
+
+import (
+	"net"
+	"net/smtp"
+	"time"
+)
+
+//SMTPMailer ... sends messages through an SMTP relay, using STARTTLS when offered.
+type SMTPMailer struct {
+	addr string
+	from string
+	auth smtp.Auth
+}
+
+//NewSMTPMailer ...
+//returns a pointer to a new SMTPMailer for the relay at addr ("host:port"). Username
+//may be empty for relays that don't require auth.
+func NewSMTPMailer(addr, from, username, password string) (*SMTPMailer, error) {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, err
+	}
+	var auth smtp.Auth
+	if username != "" {
+		auth = smtp.PlainAuth("", username, password, host)
+	}
+	return &SMTPMailer{
+		addr: addr,
+		from: from,
+		auth: auth,
+	}, nil
+}
+
+//Send ... delivers msg to the relay.
+func (s *SMTPMailer) Send(msg Message) error {
+	if err := msg.validate(); err != nil {
+		return err
+	}
+	return smtp.SendMail(s.addr, s.auth, s.from, []string{msg.To}, msg.Format(s.from, time.Now()))
+}
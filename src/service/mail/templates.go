@@ -0,0 +1,124 @@
+package mail
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+//Template names used by the account flows.
+const (
+	TemplatePasswordReset = "password_reset"
+	TemplateEmailVerify   = "email_verify"
+)
+
+//ErrUnknownTemplate ... is returned when rendering a template that was never defined.
+var ErrUnknownTemplate = errors.New("unknown mail template")
+
+//DefaultTemplates ...
+//are used for any template a template directory doesn't override. A template is a
+//"Subject: " line, a blank line and the body, all text/template.
+var DefaultTemplates = map[string]string{
+	TemplatePasswordReset: `Subject: Reset your password
+
+Hi {{.Name}},
+
+Someone asked to reset the password for your account. If it was you, follow
+the link below within {{.ValidFor}}:
+
+{{.URL}}
+
+If you didn't ask for this you can ignore this email, your password is unchanged.
+`,
+	TemplateEmailVerify: `Subject: Verify your email address
+
+Hi {{.Name}},
+
+Please confirm {{.Email}} is your email address by following the link below
+within {{.ValidFor}}:
+
+{{.URL}}
+`,
+}
+
+//Templates ... renders named templates into messages.
+type Templates struct {
+	subjects map[string]*template.Template
+	bodies   map[string]*template.Template
+}
+
+//NewTemplates ...
+//parses DefaultTemplates overridden by any "<name>.tmpl" file in dir, an empty dir uses
+//the defaults alone.
+func NewTemplates(dir string) (*Templates, error) {
+	sources := make(map[string]string, len(DefaultTemplates))
+	for name, source := range DefaultTemplates {
+		sources[name] = source
+	}
+	if dir != "" {
+		paths, err := filepath.Glob(filepath.Join(dir, "*.tmpl"))
+		if err != nil {
+			return nil, err
+		}
+		for _, path := range paths {
+			raw, err := ioutil.ReadFile(path)
+			if err != nil {
+				return nil, err
+			}
+			sources[strings.TrimSuffix(filepath.Base(path), ".tmpl")] = string(raw)
+		}
+	}
+	templates := &Templates{
+		subjects: make(map[string]*template.Template, len(sources)),
+		bodies:   make(map[string]*template.Template, len(sources)),
+	}
+	for name, source := range sources {
+		if err := templates.parse(name, source); err != nil {
+			return nil, err
+		}
+	}
+	return templates, nil
+}
+
+//Render ... fills in the named template with data, addressed to to.
+func (t *Templates) Render(name, to string, data interface{}) (Message, error) {
+	subject, ok := t.subjects[name]
+	if !ok {
+		return Message{}, ErrUnknownTemplate
+	}
+	var subjectBuffer, bodyBuffer bytes.Buffer
+	if err := subject.Execute(&subjectBuffer, data); err != nil {
+		return Message{}, err
+	}
+	if err := t.bodies[name].Execute(&bodyBuffer, data); err != nil {
+		return Message{}, err
+	}
+	return Message{
+		To:      to,
+		Subject: subjectBuffer.String(),
+		Body:    bodyBuffer.String(),
+	}, nil
+}
+
+func (t *Templates) parse(name, source string) error {
+	source = strings.Replace(source, "\r\n", "\n", -1)
+	parts := strings.SplitN(source, "\n\n", 2)
+	if len(parts) != 2 || !strings.HasPrefix(parts[0], "Subject: ") || strings.Contains(parts[0], "\n") {
+		return fmt.Errorf("mail: template %q must start with a Subject line", name)
+	}
+	subject, err := template.New(name).Parse(strings.TrimPrefix(parts[0], "Subject: "))
+	if err != nil {
+		return err
+	}
+	body, err := template.New(name).Parse(parts[1])
+	if err != nil {
+		return err
+	}
+	t.subjects[name] = subject
+	t.bodies[name] = body
+	return nil
+}
@@ -0,0 +1,81 @@
+package mail_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"service/mail"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Templates Specs", func() {
+	data := map[string]string{
+		"Name":     "Tony",
+		"Email":    "tony@example.com",
+		"URL":      "https://example.com/reset-password?token=abc",
+		"ValidFor": "1 hour",
+	}
+
+	It("should render the default templates", func() {
+		templates, err := mail.NewTemplates("")
+		Expect(err).ToNot(HaveOccurred())
+		msg, err := templates.Render(mail.TemplatePasswordReset, "tony@example.com", data)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(msg.To).To(Equal("tony@example.com"))
+		Expect(msg.Subject).To(Equal("Reset your password"))
+		Expect(msg.Body).To(HavePrefix("Hi Tony,"))
+		Expect(msg.Body).To(ContainSubstring("https://example.com/reset-password?token=abc"))
+		Expect(msg.Body).To(ContainSubstring("within 1 hour"))
+	})
+
+	It("should report unknown templates", func() {
+		templates, _ := mail.NewTemplates("")
+		_, err := templates.Render("welcome", "tony@example.com", data)
+		Expect(err).To(Equal(mail.ErrUnknownTemplate))
+	})
+
+	Context("with a template directory", func() {
+		var dir string
+
+		BeforeEach(func() {
+			var err error
+			dir, err = ioutil.TempDir("", "templates")
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		AfterEach(func() {
+			os.RemoveAll(dir)
+		})
+
+		write := func(name, source string) {
+			Expect(ioutil.WriteFile(filepath.Join(dir, name), []byte(source), 0600)).To(Succeed())
+		}
+
+		It("should override and add templates", func() {
+			write("email_verify.tmpl", "Subject: Confirm {{.Email}}\n\nClick {{.URL}}\n")
+			write("welcome.tmpl", "Subject: Welcome\n\nHello {{.Name}}\n")
+			templates, err := mail.NewTemplates(dir)
+			Expect(err).ToNot(HaveOccurred())
+
+			msg, err := templates.Render(mail.TemplateEmailVerify, "tony@example.com", data)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(msg.Subject).To(Equal("Confirm tony@example.com"))
+			Expect(msg.Body).To(Equal("Click https://example.com/reset-password?token=abc\n"))
+
+			msg, err = templates.Render("welcome", "tony@example.com", data)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(msg.Body).To(Equal("Hello Tony\n"))
+
+			_, err = templates.Render(mail.TemplatePasswordReset, "tony@example.com", data)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should reject a template without a subject line", func() {
+			write("welcome.tmpl", "Hello {{.Name}}\n")
+			_, err := mail.NewTemplates(dir)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
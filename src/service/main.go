@@ -1,20 +1,43 @@
 package main
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	osLog "log"
 	"net/http"
 	"os"
 	"service/auth"
+	"service/auth/apikey"
 	"service/auth/basic"
+	"service/auth/bearer"
+	"service/auth/credential"
+	"service/auth/either"
+	"service/auth/emailtoken"
+	"service/auth/mfa"
+	"service/auth/mtls"
+	"service/auth/oauth"
+	"service/auth/permission"
+	"service/auth/refresh"
+	"service/auth/revocation"
+	"service/auth/session"
+	"service/auth/signature"
+	"service/auth/throttle"
 	"service/auth/token/jwt"
 	"service/database"
+	"service/database/migrate"
+	"service/handlers/account"
 	"service/handlers/index"
+	"service/handlers/metrics"
 	"service/handlers/recovery"
 	"service/handlers/request"
+	"service/handlers/wellknown"
 	"service/identity"
 	"service/log"
+	"service/mail"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/go-chi/chi"
 	"github.com/joho/godotenv"
@@ -39,13 +62,21 @@ func main() {
 	//dotenv first
 	dotEnv()
 
+	//"service migrate ..." manages the schema instead of serving.
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := runMigrate(os.Args[2:]); err != nil {
+			osLog.Fatal(err)
+		}
+		return
+	}
+
 	//Determine if server is running prod.
 	isProd := checkForProd()
 
 	//Initialize db client
-	db := setupDBClient()
+	primaryDB := setupDBClient()
 	defer func() {
-		closeErr := db.Close()
+		closeErr := primaryDB.Close()
 		if closeErr != nil {
 			panic(closeErr)
 		}
@@ -54,37 +85,399 @@ func main() {
 	//Initialize log client
 	logger := setupLogClient(isProd)
 
-	//Initialize auth client
-	authClient := setupAuthClient()
+	//Apply pending migrations when asked to
+	if os.Getenv("MIGRATE_ON_START") == "true" {
+		migrateOnStart(logger, primaryDB)
+	}
+
+	//Initialize read replicas, without DB_REPLICA_ADDRS every query goes to the primary
+	routedDB, closeReplicas := setupReplicas(logger, primaryDB)
+	defer closeReplicas()
+
+	//Time every query for /metrics/database, the slow query log and the RESPONSE line
+	db := setupInstrumentedDB(logger, routedDB)
+
+	//Auth state is read from the primary, a lagging replica would honour revoked tokens,
+	//sessions and keys, old passwords, disabled credentials and MFA, and lifted roles, and miss
+	//fresh lockouts.
+	authDB := database.OnPrimary(db)
+
+	//Initialize signing keys and auth client
+	keyring := setupKeyring()
+	authClient := setupAuthClient(authDB, keyring)
+
+	//Initialize roles and permissions
+	roleStore := setupRoleStore(authDB)
+
+	//Initialize basic auth credential store
+	credentialStore := setupCredentialStore(authDB, roleStore)
+
+	//Initialize failed login tracking
+	loginThrottle := setupThrottle(logger, authDB)
+
+	//Initialize second factor
+	mfaService := mfa.NewService(logger, authDB, mfaIssuer())
+
+	//Initialize browser sessions
+	sessionManager := setupSessions(logger, authDB)
+
+	//Initialize account emails
+	accountRoute := setupAccount(logger, authDB, loginThrottle, sessionManager)
 
 	//Initialize route handlers
 	indexRoute := index.New(logger, db)
-	identityRoute := setupIdentity(logger, db, authClient)
+	mfaRoute := mfa.NewHandlerObject(logger, mfaService, loginThrottle)
+	roleRoute := permission.NewHandlerObject(logger, roleStore)
+	apiKeyService := apikey.NewService(logger, authDB)
+	apiKeyRoute := apikey.NewHandlerObject(logger, apiKeyService, roleStore)
+	credentialRoute := credential.NewHandlerObject(logger, credentialStore)
+	lockoutRoute := throttle.NewHandlerObject(logger, loginThrottle)
+	identityRoute := setupIdentity(logger, authDB, authClient, roleStore, loginThrottle, mfaService,
+		sessionManager)
+	sessionRoute := session.NewHandlerObject(logger, sessionManager,
+		identity.NewServiceObject(logger, authDB), mfaService, loginThrottle)
+	certStore := mtls.NewPostgresStore(authDB)
+	certRoute := mtls.NewHandlerObject(logger, certStore)
+	signingKeyStore := signature.NewPostgresStore(authDB)
+	signingKeyRoute := signature.NewHandlerObject(logger, signingKeyStore, roleStore)
+	oauthRoute := setupOAuth(logger, authDB, authClient, roleStore, loginThrottle, mfaService)
+	metricsRoute := metrics.NewHandlerObject(logger, db)
+	wellKnownRoute := wellknown.NewHandlerObject(logger, keyring,
+		os.Getenv("JWT_ISSUER"), envDuration("JWKS_MAX_AGE"))
 
 	//Configure chi router
-	router := setupChiRouter(authClient, logger)
+	router := setupChiRouter(authClient, credentialStore, roleStore, loginThrottle, logger)
+	apikey.SetupAuthMiddleware(apikey.NewAuth(apiKeyService), roleStore, logger)
+	mtls.SetupAuthMiddleware(mtls.NewAuth(certStore), roleStore, logger)
+	session.SetupAuthMiddleware(sessionManager, roleStore, logger)
+	signature.SetupAuthMiddleware(setupSignatureAuth(signingKeyStore), roleStore, logger)
+
+	//Configure public routes
+	router.Get("/.well-known/jwks.json", wellKnownRoute.JWKS)
+	router.Get("/.well-known/openid-configuration", wellKnownRoute.OpenIDConfiguration)
+	router.Post("/oauth/token", oauthRoute.Token)
+	router.Post("/oauth/introspect", oauthRoute.Introspect)
+	router.Get("/oauth/authorize", oauthRoute.Authorize)
+	router.Post("/oauth/authorize", oauthRoute.Authorize)
+	router.Post("/session", sessionRoute.Login)
+
+	//Configure routes behind basic auth
+	router.Group(func(router chi.Router) {
+		router.Use(basic.AuthMiddleware)
+		router.Post("/auth", identityRoute.AuthIdentity)
+		router.Post("/auth/mfa", identityRoute.VerifyMFA)
+		router.Post("/auth/password/reset", accountRoute.RequestPasswordReset)
+		router.Post("/auth/password/reset/confirm", accountRoute.ConfirmPasswordReset)
+		router.Post("/auth/email/verify", accountRoute.ConfirmEmailVerification)
+		router.Post("/auth/refresh", identityRoute.RefreshIdentity)
+		credentials := permission.Require(permission.CredentialsAdmin)
+		router.With(credentials).Post("/credentials", credentialRoute.AddCredential)
+		router.With(credentials).Put("/credentials/{username}", credentialRoute.RotateCredential)
+		router.With(credentials).Delete("/credentials/{username}", credentialRoute.DisableCredential)
+		router.With(permission.Require(permission.IdentityAdmin)).
+			Delete("/auth/lockouts/{kind}/{value}", lockoutRoute.Unlock)
+	})
+
+	//Configure routes behind bearer auth
+	router.Group(func(router chi.Router) {
+		router.Use(bearer.AuthMiddleware)
+		router.Post("/auth/logout", identityRoute.LogoutIdentity)
+	})
 
-	//Configure routes
-	router.Get("/", indexRoute.Handler)
-	router.Get("/identity", identityRoute.Handler)
-	router.Post("/identity", identityRoute.CreateIdentity)
-	router.Post("/auth", identityRoute.AuthIdentity)
+	//Configure routes behind a session cookie
+	router.Group(func(router chi.Router) {
+		router.Use(session.AuthMiddleware)
+		router.Use(session.CSRFMiddleware)
+		router.Get("/session", sessionRoute.Current)
+		router.Delete("/session", sessionRoute.Logout)
+	})
+
+	//Configure routes accepting either, each guarded by the permission it needs. Routes on
+	//one identity also need to be made by that identity or an admin, and listing or creating
+	//identities is for admins alone.
+	read := permission.Require(permission.IdentityRead)
+	write := permission.Require(permission.IdentityWrite)
+	self := permission.RequireSelfOr(permission.IdentityAdmin)
+	slowRoute := request.Deadline(queryTimeout("SLOW_QUERY_TIMEOUT", slowQueryTimeout))
+	router.Group(func(router chi.Router) {
+		router.Use(either.AuthMiddleware)
+		router.With(permission.Require(permission.EventsRead), slowRoute).Get("/", indexRoute.Handler)
+		mountIdentityRoutes(router, identityRoute, slowRoute)
+		router.With(write, self).Post("/identity/{id}/email/verify",
+			accountRoute.RequestEmailVerification)
+		router.With(write, self).Post("/identity/{id}/mfa", mfaRoute.Enroll)
+		router.With(write, self).Post("/identity/{id}/mfa/confirm", mfaRoute.Confirm)
+		router.With(write, self).Delete("/identity/{id}/mfa", mfaRoute.Disable)
+		router.With(read, self).Get("/identity/{id}/keys", apiKeyRoute.ListKeys)
+		router.With(write, self).Post("/identity/{id}/keys", apiKeyRoute.CreateKey)
+		router.With(write, self).Delete("/identity/{id}/keys/{keyID}", apiKeyRoute.RevokeKey)
+		router.With(read, self).Get("/identity/{id}/signing-keys", signingKeyRoute.ListKeys)
+		router.With(write, self).Post("/identity/{id}/signing-keys", signingKeyRoute.CreateKey)
+		router.With(write, self).Delete("/identity/{id}/signing-keys/{keyID}",
+			signingKeyRoute.DeleteKey)
+		router.With(read, self).Get("/identity/{id}/sessions", sessionRoute.ListSessions)
+		router.With(write, self).Delete("/identity/{id}/sessions", sessionRoute.RevokeSessions)
+		router.With(write, self).Delete("/identity/{id}/sessions/{sessionID}",
+			sessionRoute.RevokeSession)
+		router.With(read, self).Get("/identity/{id}/roles", roleRoute.ListRoles)
+		roles := permission.Require(permission.RolesAdmin)
+		router.With(roles).Put("/identity/{id}/roles/{role}", roleRoute.AssignRole)
+		router.With(roles).Delete("/identity/{id}/roles/{role}", roleRoute.RemoveRole)
+		router.With(roles).Get("/credentials/{username}/roles", roleRoute.ListCredentialRoles)
+		router.With(roles).Put("/credentials/{username}/roles/{role}",
+			roleRoute.AssignCredentialRole)
+		router.With(roles).Delete("/credentials/{username}/roles/{role}",
+			roleRoute.RemoveCredentialRole)
+		clients := permission.Require(permission.ClientsAdmin)
+		router.With(clients).Get("/oauth/clients", oauthRoute.ListClients)
+		router.With(clients).Post("/oauth/clients", oauthRoute.CreateClient)
+		router.With(clients).Delete("/oauth/clients/{clientID}", oauthRoute.DeleteClient)
+		certificates := permission.Require(permission.CertificatesAdmin)
+		router.With(certificates).Get("/identity/{id}/certificates", certRoute.ListBindings)
+		router.With(certificates).Post("/identity/{id}/certificates", certRoute.Bind)
+		router.With(certificates).Delete("/identity/{id}/certificates", certRoute.Unbind)
+		router.With(permission.Require(permission.MetricsRead)).
+			Get("/metrics/database", metricsRoute.Database)
+	})
 
 	//Serve
-	fmt.Println("Starting up server @ localhost:9000/")
-	err := http.ListenAndServe(":9000", router)
+	err := serve(router)
 	if err != nil {
 		osLog.Fatal(err)
 	}
 }
 
-func setupAuthClient() *auth.Client {
+//identityRoutes ... the identity CRUD handlers mountIdentityRoutes guards.
+type identityRoutes interface {
+	ListIdentities(w http.ResponseWriter, req *http.Request)
+	CreateIdentity(w http.ResponseWriter, req *http.Request)
+	Handler(w http.ResponseWriter, req *http.Request)
+	UpdateIdentity(w http.ResponseWriter, req *http.Request)
+	PatchIdentity(w http.ResponseWriter, req *http.Request)
+	DeleteIdentity(w http.ResponseWriter, req *http.Request)
+	SetIdentityPassword(w http.ResponseWriter, req *http.Request)
+}
+
+//mountIdentityRoutes mounts identity CRUD behind the router's auth middleware. Every
+//identity holds identity:write on itself through its self role, so listing and creating
+//identities need identity:admin.
+func mountIdentityRoutes(router chi.Router, routes identityRoutes,
+	slowRoute func(http.Handler) http.Handler) {
+	read := permission.Require(permission.IdentityRead)
+	write := permission.Require(permission.IdentityWrite)
+	admin := permission.Require(permission.IdentityAdmin)
+	self := permission.RequireSelfOr(permission.IdentityAdmin)
+	router.With(admin, slowRoute).Get("/identities", routes.ListIdentities)
+	router.With(write, admin).Post("/identity", routes.CreateIdentity)
+	router.With(read, self).Get("/identity/{id}", routes.Handler)
+	router.With(write, self).Put("/identity/{id}", routes.UpdateIdentity)
+	router.With(write, self).Patch("/identity/{id}", routes.PatchIdentity)
+	router.With(write, self).Delete("/identity/{id}", routes.DeleteIdentity)
+	router.With(write, self).Put("/identity/{id}/password", routes.SetIdentityPassword)
+}
+
+//serve uses TLS when TLS_CERT_FILE and TLS_KEY_FILE are set, verifying any client
+//certificates against TLS_CLIENT_CA_FILE.
+func serve(handler http.Handler) error {
+	certFile, keyFile := os.Getenv("TLS_CERT_FILE"), os.Getenv("TLS_KEY_FILE")
+	clientCAFile := os.Getenv("TLS_CLIENT_CA_FILE")
+	if certFile == "" || keyFile == "" {
+		if clientCAFile != "" {
+			return fmt.Errorf("TLS_CLIENT_CA_FILE requires TLS_CERT_FILE and TLS_KEY_FILE")
+		}
+		fmt.Println("Starting up server @ localhost:9000/")
+		return http.ListenAndServe(":9000", handler)
+	}
+	tlsConfig, err := mtls.ServerTLSConfig(clientCAFile)
+	if err != nil {
+		return err
+	}
+	server := &http.Server{
+		Addr:      ":9000",
+		Handler:   handler,
+		TLSConfig: tlsConfig,
+	}
+	fmt.Println("Starting up server @ https://localhost:9000/")
+	return server.ListenAndServeTLS(certFile, keyFile)
+}
+
+func setupAuthClient(db database.DBInterface, keyring *jwt.Keyring) *auth.Client {
 	basicAuth := basic.NewAuth()
-	jwtService := jwt.NewService()
-	return auth.NewClient(basicAuth, jwtService)
+	jwtService := jwt.NewService(keyring)
+	if ttl := envDuration("ACCESS_TOKEN_TTL"); ttl > 0 {
+		jwtService.TTL = ttl
+	}
+	jwtService.Issuer = os.Getenv("JWT_ISSUER")
+	jwtService.Audience = os.Getenv("JWT_AUDIENCE")
+	jwtService.Policy = jwt.Policy{
+		Issuer:   jwtService.Issuer,
+		Audience: jwtService.Audience,
+		Leeway:   envDuration("JWT_LEEWAY"),
+		MaxAge:   envDuration("JWT_MAX_AGE"),
+	}
+	revoked := revocation.NewCachedStore(revocation.NewPostgresStore(db),
+		envDuration("REVOCATION_CACHE_TTL"))
+	authClient := auth.NewClient(basicAuth, jwtService, revoked)
+	authClient.Leeway = jwtService.Policy.Leeway
+	return authClient
+}
+
+//setupKeyring loads the PEM keys in JWT_KEYS_DIR, signing with JWT_SIGNING_KEY_ID and
+//promoting JWT_NEXT_SIGNING_KEY_ID at JWT_NEXT_SIGNING_AT (RFC3339). Without a key
+//directory it falls back to an HS256 key built from STAGE_JWT_SECRET for local development.
+func setupKeyring() *jwt.Keyring {
+	keyring := jwt.NewKeyring()
+	dir := os.Getenv("JWT_KEYS_DIR")
+	if dir == "" {
+		key, err := jwt.NewHMACKey("stage", []byte(os.Getenv("STAGE_JWT_SECRET")))
+		if err != nil {
+			panic(err)
+		}
+		keyring.Add(key)
+		return keyring
+	}
+	keys, err := jwt.LoadPEMDir(dir)
+	if err != nil {
+		panic(err)
+	}
+	for _, key := range keys {
+		keyring.Add(key)
+	}
+	if kid := os.Getenv("JWT_SIGNING_KEY_ID"); kid != "" {
+		if err := keyring.Promote(kid, time.Time{}); err != nil {
+			panic(err)
+		}
+	}
+	if kid := os.Getenv("JWT_NEXT_SIGNING_KEY_ID"); kid != "" {
+		at, err := time.Parse(time.RFC3339, os.Getenv("JWT_NEXT_SIGNING_AT"))
+		if err != nil {
+			panic(err)
+		}
+		if err := keyring.Promote(kid, at); err != nil {
+			panic(err)
+		}
+	}
+	if _, err := keyring.SigningKey(); err != nil {
+		panic(err)
+	}
+	return keyring
+}
+
+//envDuration parses an optional duration env var such as "15m", returning 0 when unset.
+func envDuration(key string) time.Duration {
+	raw := os.Getenv(key)
+	if raw == "" {
+		return 0
+	}
+	duration, err := time.ParseDuration(raw)
+	if err != nil {
+		panic(err)
+	}
+	return duration
+}
+
+//envInt parses an optional integer env var, returning 0 when unset.
+func envInt(key string) int {
+	raw := os.Getenv(key)
+	if raw == "" {
+		return 0
+	}
+	value, err := strconv.Atoi(raw)
+	if err != nil {
+		panic(err)
+	}
+	return value
+}
+
+func setupThrottle(logger log.ProdInterface, db database.DBInterface) *throttle.Throttle {
+	return throttle.NewThrottle(logger, throttle.NewPostgresStore(db), throttle.Policy{
+		BaseDelay:   envDuration("LOGIN_BACKOFF_BASE"),
+		MaxDelay:    envDuration("LOGIN_BACKOFF_MAX"),
+		Threshold:   envInt("LOGIN_LOCKOUT_THRESHOLD"),
+		IPThreshold: envInt("LOGIN_LOCKOUT_IP_THRESHOLD"),
+		Lockout:     envDuration("LOGIN_LOCKOUT_DURATION"),
+	})
+}
+
+func setupAccount(logger log.ProdInterface, db database.DBInterface,
+	throttle throttle.Interface, sessions identity.SessionRevoker) *account.HandlerObject {
+	//Like the keyring, local development falls back to STAGE_JWT_SECRET.
+	secret := os.Getenv("EMAIL_TOKEN_SECRET")
+	if secret == "" {
+		secret = os.Getenv("STAGE_JWT_SECRET")
+	}
+	if secret == "" {
+		panic("EMAIL_TOKEN_SECRET is required")
+	}
+	templates, err := mail.NewTemplates(os.Getenv("MAIL_TEMPLATE_DIR"))
+	if err != nil {
+		panic(err)
+	}
+	return account.NewHandlerObject(logger, identity.NewServiceObject(logger, db),
+		emailtoken.NewService(db, []byte(secret)), setupMailer(logger), templates, throttle,
+		refresh.NewService(logger, db, envDuration("REFRESH_TOKEN_TTL")), sessions,
+		os.Getenv("APP_BASE_URL"))
+}
+
+//setupMailer sends through MAIL_SMTP_ADDR when set, otherwise to the log and MAIL_SINK_PATH.
+func setupMailer(logger log.ProdInterface) mail.Mailer {
+	addr := os.Getenv("MAIL_SMTP_ADDR")
+	if addr == "" {
+		return mail.NewSinkMailer(logger, os.Getenv("MAIL_SINK_PATH"))
+	}
+	mailer, err := mail.NewSMTPMailer(addr, os.Getenv("MAIL_FROM"),
+		os.Getenv("MAIL_SMTP_USER"), os.Getenv("MAIL_SMTP_PASSWORD"))
+	if err != nil {
+		panic(err)
+	}
+	return mailer
+}
+
+//mfaIssuer names the service in authenticator apps, MFA_ISSUER or else the JWT issuer.
+func mfaIssuer() string {
+	if issuer := os.Getenv("MFA_ISSUER"); issuer != "" {
+		return issuer
+	}
+	if issuer := os.Getenv("JWT_ISSUER"); issuer != "" {
+		return issuer
+	}
+	return "service"
+}
+
+func setupCredentialStore(db database.DBInterface,
+	roles permission.Store) *credential.PostgresStore {
+	store := credential.NewPostgresStore(db)
+	//Seed a bootstrap credential so the management routes can be reached. It holds
+	//BASIC_AUTH_ROLE, admin unless set, other credentials hold only the roles assigned to them.
+	user, password := os.Getenv("BASIC_AUTH_USER"), os.Getenv("BASIC_AUTH_PASSWORD")
+	if user != "" && password != "" {
+		err := store.Add(context.Background(), user, password)
+		if err != nil && err != credential.ErrExists {
+			panic(err)
+		}
+		role := os.Getenv("BASIC_AUTH_ROLE")
+		if role == "" {
+			role = "admin"
+		}
+		if err := roles.AssignCredentialRole(context.Background(), user, role); err != nil {
+			panic(err)
+		}
+	}
+	return store
+}
+
+func setupRoleStore(db database.DBInterface) *permission.PostgresStore {
+	store := permission.NewPostgresStore(db)
+	for role, permissions := range permission.DefaultRoles {
+		if err := store.DefineRole(context.Background(), role, permissions); err != nil {
+			panic(err)
+		}
+	}
+	return store
 }
 
-func setupChiRouter(auth *auth.Client, log log.ProdInterface) *chi.Mux {
+func setupChiRouter(auth *auth.Client, store credential.Store, roles permission.Store,
+	throttle throttle.Interface, log log.ProdInterface) *chi.Mux {
 	router := chi.NewRouter()
 
 	router.Use(request.GenerateRequestIDMiddle)
@@ -92,15 +485,37 @@ func setupChiRouter(auth *auth.Client, log log.ProdInterface) *chi.Mux {
 	request.SetupLogger(log)
 	router.Use(request.Logger)
 
-	basic.SetupAuthMiddleware(auth, log)
-	router.Use(basic.AuthMiddleware)
+	//Auth middleware is applied per route group so each route picks basic, bearer or either.
+	basic.SetupAuthMiddleware(auth, store, roles, throttle, log)
+	bearer.SetupAuthMiddleware(auth, log)
+	permission.SetupPolicyMiddleware(log)
 
 	recovery.SetupRecover(log)
 	router.Use(recovery.Recover)
 
+	//Reads may go to a replica until the request writes.
+	router.Use(request.TrackWrites)
+
+	//Every route's queries are bounded, see slowRoute for the exceptions.
+	router.Use(request.Deadline(queryTimeout("QUERY_TIMEOUT", defaultQueryTimeout)))
+
 	return router
 }
 
+//Query timeouts used unless QUERY_TIMEOUT or SLOW_QUERY_TIMEOUT say otherwise.
+const (
+	defaultQueryTimeout = 5 * time.Second
+	slowQueryTimeout    = 15 * time.Second
+)
+
+//queryTimeout reads a query timeout from key, "0s" turns the timeout off.
+func queryTimeout(key string, fallback time.Duration) time.Duration {
+	if os.Getenv(key) == "" {
+		return fallback
+	}
+	return envDuration(key)
+}
+
 func setupDBClient() *sql.DB {
 	db, err := sql.Open("postgres", os.Getenv("DB_ADDR"))
 	if err != nil {
@@ -111,10 +526,160 @@ func setupDBClient() *sql.DB {
 	return db
 }
 
+//Replica health check interval unless DB_REPLICA_CHECK_INTERVAL says otherwise.
+const defaultReplicaCheckInterval = 5 * time.Second
+
+//setupReplicas routes reads to the comma separated DB_REPLICA_ADDRS using DB_REPLICA_POLICY,
+//ejecting replicas more than DB_REPLICA_MAX_LAG behind, "0s" keeps lagging replicas. The
+//returned func stops the health checks and closes the replicas.
+func setupReplicas(logger log.ProdInterface,
+	primary *sql.DB) (database.DBInterface, func()) {
+	if os.Getenv("DB_REPLICA_ADDRS") == "" {
+		return primary, func() {}
+	}
+	policy, ok := database.ParsePolicy(os.Getenv("DB_REPLICA_POLICY"))
+	if !ok {
+		panic("unknown DB_REPLICA_POLICY " + os.Getenv("DB_REPLICA_POLICY"))
+	}
+	var replicas []*sql.DB
+	for _, addr := range strings.Split(os.Getenv("DB_REPLICA_ADDRS"), ",") {
+		replica, err := sql.Open("postgres", strings.TrimSpace(addr))
+		if err != nil {
+			panic(err)
+		}
+		replica.SetMaxIdleConns(5)
+		replica.SetMaxOpenConns(25)
+		replicas = append(replicas, replica)
+	}
+	router := database.NewRouter(logger, primary, replicas, policy)
+	if os.Getenv("DB_REPLICA_MAX_LAG") != "" {
+		router.MaxLag = envDuration("DB_REPLICA_MAX_LAG")
+	}
+	interval := envDuration("DB_REPLICA_CHECK_INTERVAL")
+	if interval == 0 {
+		interval = defaultReplicaCheckInterval
+	}
+	ctx, stop := context.WithCancel(context.Background())
+	router.Check(ctx)
+	go router.Watch(ctx, interval)
+	return router, func() {
+		stop()
+		for _, replica := range replicas {
+			if closeErr := replica.Close(); closeErr != nil {
+				logger.Error("setupReplicas::Close", zap.Error(closeErr))
+			}
+		}
+	}
+}
+
+//setupInstrumentedDB logs queries slower than SLOW_QUERY_LOG, "0s" turns the log off.
+func setupInstrumentedDB(logger log.ProdInterface,
+	db database.DBInterface) *database.Instrumented {
+	instrumented := database.NewInstrumented(logger, db)
+	instrumented.RequestID = request.RetreiveRequestID
+	if os.Getenv("SLOW_QUERY_LOG") != "" {
+		instrumented.SlowQuery = envDuration("SLOW_QUERY_LOG")
+	}
+	return instrumented
+}
+
+//setupMigrator returns a func that opens a Migrator for the embedded migrations.
+func setupMigrator(logger log.ProdInterface, db *sql.DB) func() (*migrate.Migrator, error) {
+	return func() (*migrate.Migrator, error) {
+		migrations, err := migrate.Embedded()
+		if err != nil {
+			return nil, err
+		}
+		return migrate.NewMigrator(logger, db, migrations), nil
+	}
+}
+
+//migrateOnStart applies pending migrations before serving, refusing to start when they fail.
+func migrateOnStart(logger log.ProdInterface, db *sql.DB) {
+	migrator, err := setupMigrator(logger, db)()
+	if err != nil {
+		panic(err)
+	}
+	if _, err := migrator.Up(); err != nil {
+		panic(err)
+	}
+}
+
+//runMigrate runs a migrate subcommand, create writes to MIGRATIONS_DIR when set.
+func runMigrate(args []string) error {
+	dir := os.Getenv("MIGRATIONS_DIR")
+	if dir == "" {
+		dir = migrate.DefaultDir
+	}
+	var db *sql.DB
+	defer func() {
+		if db != nil {
+			if closeErr := db.Close(); closeErr != nil {
+				osLog.Println(closeErr)
+			}
+		}
+	}()
+	command := &migrate.Command{
+		Dir: dir,
+		Out: os.Stdout,
+		Open: func() (*migrate.Migrator, error) {
+			db = setupDBClient()
+			return setupMigrator(setupLogClient(checkForProd()), db)()
+		},
+	}
+	return command.Run(args)
+}
+
 func setupIdentity(logger log.ProdInterface, db database.DBInterface,
-	auth auth.Interface) *identity.HandlerObject {
+	auth auth.Interface, roles permission.Store, throttle throttle.Interface,
+	mfa mfa.Interface, sessions identity.SessionRevoker) *identity.HandlerObject {
 	identityService := identity.NewServiceObject(logger, db)
-	return identity.NewHandlerObject(logger, identityService, auth)
+	refreshService := refresh.NewService(logger, db, envDuration("REFRESH_TOKEN_TTL"))
+	return identity.NewHandlerObject(logger, identityService, auth, refreshService, roles,
+		throttle, mfa, sessions)
+}
+
+//setupOAuth reports ACCESS_TOKEN_TTL as expires_in, as setupAuthClient uses it for the jwt.
+func setupOAuth(logger log.ProdInterface, db database.DBInterface, authClient *auth.Client,
+	roles permission.Store, throttle throttle.Interface,
+	mfa mfa.Interface) *oauth.HandlerObject {
+	accessTTL := envDuration("ACCESS_TOKEN_TTL")
+	if accessTTL <= 0 {
+		accessTTL = jwt.DefaultTTL
+	}
+	return oauth.NewHandlerObject(logger, oauth.NewPostgresClientStore(db),
+		oauth.NewPostgresCodeStore(db), authClient, accessTTL,
+		refresh.NewService(logger, db, envDuration("REFRESH_TOKEN_TTL")),
+		identity.NewServiceObject(logger, db), roles, mfa, throttle)
+}
+
+//setupSessions reads SESSION_IDLE_TIMEOUT, SESSION_ABSOLUTE_TIMEOUT and SESSION_SAME_SITE
+//(lax or strict). SESSION_INSECURE_COOKIE=true allows sessions over plain http locally.
+func setupSessions(logger log.ProdInterface, db database.DBInterface) *session.Manager {
+	policy := session.Policy{
+		IdleTimeout:     envDuration("SESSION_IDLE_TIMEOUT"),
+		AbsoluteTimeout: envDuration("SESSION_ABSOLUTE_TIMEOUT"),
+		Insecure:        os.Getenv("SESSION_INSECURE_COOKIE") == "true",
+	}
+	switch sameSite := os.Getenv("SESSION_SAME_SITE"); sameSite {
+	case "", "lax":
+		policy.SameSite = http.SameSiteLaxMode
+	case "strict":
+		policy.SameSite = http.SameSiteStrictMode
+	default:
+		panic(fmt.Sprintf("SESSION_SAME_SITE must be lax or strict, got %q", sameSite))
+	}
+	return session.NewManager(logger, session.NewPostgresStore(db), policy)
+}
+
+//setupSignatureAuth reads SIGNATURE_MAX_SKEW, how far a signed request's timestamp may
+//drift from our clock.
+func setupSignatureAuth(keys signature.Store) *signature.Auth {
+	signatureAuth := signature.NewAuth(keys)
+	if maxSkew := envDuration("SIGNATURE_MAX_SKEW"); maxSkew > 0 {
+		signatureAuth.MaxSkew = maxSkew
+	}
+	return signatureAuth
 }
 
 func setupLogClient(prod bool) *zap.Logger {
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"service/auth/permission"
+	"service/auth/token/jwt"
+	"service/handlers/request"
+	"service/log"
+	"service/log/logfakes"
+
+	"github.com/go-chi/chi"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+//stubIdentityRoutes answers every identity route with 204 and remembers that it ran.
+type stubIdentityRoutes struct {
+	called bool
+}
+
+func (s *stubIdentityRoutes) serve(w http.ResponseWriter, req *http.Request) {
+	s.called = true
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *stubIdentityRoutes) ListIdentities(w http.ResponseWriter, req *http.Request) {
+	s.serve(w, req)
+}
+
+func (s *stubIdentityRoutes) CreateIdentity(w http.ResponseWriter, req *http.Request) {
+	s.serve(w, req)
+}
+
+func (s *stubIdentityRoutes) Handler(w http.ResponseWriter, req *http.Request) {
+	s.serve(w, req)
+}
+
+func (s *stubIdentityRoutes) UpdateIdentity(w http.ResponseWriter, req *http.Request) {
+	s.serve(w, req)
+}
+
+func (s *stubIdentityRoutes) PatchIdentity(w http.ResponseWriter, req *http.Request) {
+	s.serve(w, req)
+}
+
+func (s *stubIdentityRoutes) DeleteIdentity(w http.ResponseWriter, req *http.Request) {
+	s.serve(w, req)
+}
+
+func (s *stubIdentityRoutes) SetIdentityPassword(w http.ResponseWriter, req *http.Request) {
+	s.serve(w, req)
+}
+
+var _ = Describe("Identity Route Specs", func() {
+	var (
+		routes   *stubIdentityRoutes
+		router   *chi.Mux
+		recorder *httptest.ResponseRecorder
+	)
+
+	BeforeEach(func() {
+		permission.SetupPolicyMiddleware(log.New(&logfakes.FakeProdInterface{}))
+		routes = &stubIdentityRoutes{}
+		router = chi.NewRouter()
+		passThrough := func(next http.Handler) http.Handler { return next }
+		mountIdentityRoutes(router, routes, passThrough)
+		recorder = httptest.NewRecorder()
+	})
+
+	serveAs := func(claims *jwt.IdentityClaims, method, path string) {
+		req := httptest.NewRequest(method, path, nil)
+		router.ServeHTTP(recorder, req.WithContext(request.WithClaims(req.Context(), claims)))
+	}
+
+	Context("POST /identity", func() {
+		It("should forbid an identity holding only its self role permissions", func() {
+			serveAs(&jwt.IdentityClaims{Scope: permission.IdentityRead + " " +
+				permission.IdentityWrite}, "POST", "/identity")
+			Expect(recorder.Code).To(Equal(http.StatusForbidden))
+			Expect(routes.called).To(BeFalse())
+		})
+
+		It("should let an admin create identities", func() {
+			serveAs(&jwt.IdentityClaims{Scope: permission.IdentityWrite + " " +
+				permission.IdentityAdmin}, "POST", "/identity")
+			Expect(recorder.Code).To(Equal(http.StatusNoContent))
+			Expect(routes.called).To(BeTrue())
+		})
+	})
+
+	Context("PUT /identity/{id}", func() {
+		It("should let an identity update itself", func() {
+			claims := &jwt.IdentityClaims{Scope: permission.IdentityWrite}
+			claims.Subject = "test_id"
+			serveAs(claims, "PUT", "/identity/test_id")
+			Expect(recorder.Code).To(Equal(http.StatusNoContent))
+		})
+	})
+})
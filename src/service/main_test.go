@@ -1,6 +1,7 @@
 package main_test
 
 import (
+	"context"
 	"database/sql"
 	"encoding/json"
 	"io/ioutil"
@@ -9,6 +10,10 @@ import (
 	"service/auth"
 	"service/auth/authfakes"
 	"service/auth/basic"
+	"service/auth/credential/credentialfakes"
+	"service/auth/permission/permissionfakes"
+	"service/auth/revocation"
+	"service/auth/throttle/throttlefakes"
 	"service/auth/token/tokenfakes"
 	"service/database/databasefakes"
 	"service/handlers/index"
@@ -34,6 +39,7 @@ var _ = Describe("Main Specs", func() {
 			fakeDB       *databasefakes.FakeDBInterface
 			authFake     *authfakes.FakeInterface
 			tokenFake    *tokenfakes.FakeInterface
+			storeFake    *credentialfakes.FakeStore
 			db           *sql.DB
 			mockDB       sqlmock.Sqlmock
 			err          error
@@ -53,14 +59,16 @@ var _ = Describe("Main Specs", func() {
 
 			authFake = &authfakes.FakeInterface{}
 			tokenFake = &tokenfakes.FakeInterface{}
-			fakeAuthClient := auth.NewClient(authFake, tokenFake)
+			fakeAuthClient := auth.NewClient(authFake, tokenFake, revocation.NewMemoryStore())
+			storeFake = &credentialfakes.FakeStore{}
 
 			router.Use(request.GenerateRequestIDMiddle)
 
 			request.SetupLogger(logClient)
 			router.Use(request.Logger)
 
-			basic.SetupAuthMiddleware(fakeAuthClient, logClient)
+			basic.SetupAuthMiddleware(fakeAuthClient, storeFake, &permissionfakes.FakeStore{},
+				&throttlefakes.FakeInterface{}, logClient)
 			router.Use(basic.AuthMiddleware)
 
 			server = httptest.NewServer(router)
@@ -81,13 +89,14 @@ var _ = Describe("Main Specs", func() {
 				mockRows = mockRows.AddRow(0, "test concert", "test description", now)
 				mockDB.ExpectQuery("SELECT id, name, description FROM event;").WillReturnRows(mockRows)
 				rows, _ := db.Query("SELECT id, name, description FROM event;")
-				fakeDB.QueryReturns(rows, nil)
+				fakeDB.QueryContextReturns(rows, nil)
 			})
 
 			Context("when a user has the right credentials", func() {
 				BeforeEach(func() {
 
 					authFake.AuthorizeReturns("tony", "house", true)
+					storeFake.VerifyReturns(true, nil)
 
 					router.Get("/", indexHandler.Handler)
 					request = httptest.NewRequest("GET", server.URL+"/", nil)
@@ -124,12 +133,41 @@ var _ = Describe("Main Specs", func() {
 				})
 			})
 
+			Context("when the events query times out", func() {
+				BeforeEach(func() {
+					fakeDB.QueryContextReturns(nil, context.DeadlineExceeded)
+					authFake.AuthorizeReturns("tony", "house", true)
+					storeFake.VerifyReturns(true, nil)
+
+					router.Get("/", indexHandler.Handler)
+					request = httptest.NewRequest("GET", server.URL+"/", nil)
+					request.SetBasicAuth("tony", "house")
+					recorder = httptest.NewRecorder()
+				})
+
+				JustBeforeEach(func() {
+					router.ServeHTTP(recorder, request)
+					response = recorder.Result()
+				})
+
+				It("should respond with a 504", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusGatewayTimeout))
+					Expect(logFake.ErrorCallCount()).To(Equal(1))
+				})
+
+				It("should pass the request's context to the query", func() {
+					ctx, _, _ := fakeDB.QueryContextArgsForCall(0)
+					Expect(chi.RouteContext(ctx)).ToNot(BeNil())
+				})
+			})
+
 			Context("when a user has incorrect credentials", func() {
 				BeforeEach(func() {
 					router.Get("/", indexHandler.Handler)
 					request = httptest.NewRequest("GET", server.URL+"/", nil)
 					request.SetBasicAuth("tony", "house1")
-					authFake.AuthorizeReturns("tony", "house1", false)
+					authFake.AuthorizeReturns("tony", "house1", true)
+					storeFake.VerifyReturns(false, nil)
 					recorder = httptest.NewRecorder()
 				})
 
@@ -156,4 +194,46 @@ var _ = Describe("Main Specs", func() {
 			})
 		})
 	})
+	Context("request deadlines", func() {
+		var (
+			router   *chi.Mux
+			deadline time.Time
+			bounded  bool
+			id       string
+		)
+
+		BeforeEach(func() {
+			router = chi.NewRouter()
+			router.Use(request.GenerateRequestIDMiddle)
+			router.Use(request.Deadline(time.Minute))
+			capture := func(w http.ResponseWriter, req *http.Request) {
+				deadline, bounded = req.Context().Deadline()
+				id = request.RetreiveRequestID(req.Context())
+			}
+			router.Get("/default", capture)
+			router.With(request.Deadline(time.Hour)).Get("/slow", capture)
+			router.With(request.Deadline(time.Second)).Get("/fast", capture)
+		})
+
+		serve := func(path string) {
+			router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", path, nil))
+		}
+
+		It("should bound every route by the default", func() {
+			serve("/default")
+			Expect(bounded).To(BeTrue())
+			Expect(deadline).To(BeTemporally("~", time.Now().Add(time.Minute), time.Second))
+		})
+
+		It("should let a route have longer than the default", func() {
+			serve("/slow")
+			Expect(deadline).To(BeTemporally("~", time.Now().Add(time.Hour), time.Second))
+			Expect(id).ToNot(BeEmpty())
+		})
+
+		It("should let a route have less than the default", func() {
+			serve("/fast")
+			Expect(deadline).To(BeTemporally("~", time.Now().Add(time.Second), time.Second))
+		})
+	})
 })
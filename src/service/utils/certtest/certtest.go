@@ -0,0 +1,136 @@
+package certtest
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+)
+
+//CA ... is a throwaway certificate authority for tests.
+type CA struct {
+	Cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+}
+
+//Leaf ... is a certificate issued by a CA along with its key.
+type Leaf struct {
+	Cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+}
+
+//NewCA ... creates a self signed CA valid for a day.
+func NewCA(commonName string) (*CA, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: commonName},
+		NotBefore:             time.Now().Add(-time.Minute),
+		NotAfter:              time.Now().Add(24 * time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	cert, err := sign(template, template, &key.PublicKey, key)
+	if err != nil {
+		return nil, err
+	}
+	return &CA{Cert: cert, key: key}, nil
+}
+
+//PEM ... the CA certificate PEM encoded, as a client CA bundle.
+func (c *CA) PEM() []byte {
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: c.Cert.Raw})
+}
+
+//Pool ... a cert pool trusting only this CA.
+func (c *CA) Pool() *x509.CertPool {
+	pool := x509.NewCertPool()
+	pool.AddCert(c.Cert)
+	return pool
+}
+
+//Client ... issues a client certificate. sans may hold DNS names, IPs, emails
+//(containing "@") and URIs (containing "://").
+func (c *CA) Client(commonName string, sans ...string) (*Leaf, error) {
+	return c.issue(commonName, x509.ExtKeyUsageClientAuth, sans)
+}
+
+//Server ... issues a server certificate, typically for "localhost" and "127.0.0.1".
+func (c *CA) Server(sans ...string) (*Leaf, error) {
+	return c.issue("server", x509.ExtKeyUsageServerAuth, sans)
+}
+
+func (c *CA) issue(commonName string, usage x509.ExtKeyUsage, sans []string) (*Leaf, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 64))
+	if err != nil {
+		return nil, err
+	}
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Minute),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+	}
+	for _, san := range sans {
+		if ip := net.ParseIP(san); ip != nil {
+			template.IPAddresses = append(template.IPAddresses, ip)
+		} else if uri, err := url.Parse(san); err == nil && uri.Scheme != "" && uri.Host != "" {
+			template.URIs = append(template.URIs, uri)
+		} else if strings.Contains(san, "@") {
+			template.EmailAddresses = append(template.EmailAddresses, san)
+		} else {
+			template.DNSNames = append(template.DNSNames, san)
+		}
+	}
+	cert, err := sign(template, c.Cert, &key.PublicKey, c.key)
+	if err != nil {
+		return nil, err
+	}
+	return &Leaf{Cert: cert, key: key}, nil
+}
+
+//TLS ... the certificate and key for a tls.Config.
+func (l *Leaf) TLS() tls.Certificate {
+	return tls.Certificate{
+		Certificate: [][]byte{l.Cert.Raw},
+		PrivateKey:  l.key,
+		Leaf:        l.Cert,
+	}
+}
+
+//PEM ... the certificate and its key PEM encoded, for writing to cert and key files.
+func (l *Leaf) PEM() ([]byte, []byte, error) {
+	der, err := x509.MarshalECPrivateKey(l.key)
+	if err != nil {
+		return nil, nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: l.Cert.Raw}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der}), nil
+}
+
+func sign(template, parent *x509.Certificate, pub *ecdsa.PublicKey,
+	priv *ecdsa.PrivateKey) (*x509.Certificate, error) {
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, pub, priv)
+	if err != nil {
+		return nil, err
+	}
+	return x509.ParseCertificate(der)
+}